@@ -76,6 +76,160 @@ var WebitelAPI = WebitelServicesInfo{
 					},
 				},
 			},
+			"CreateScreenrecordingZipExport": WebitelMethod{
+				Access: 0,
+				Input:  "CreateScreenrecordingRequest",
+				Output: "ExportTask",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/agents/{agent_id}/exports/zip/screenrecordings",
+						Method: "POST",
+					},
+				},
+			},
+			"CreateCallZipExport": WebitelMethod{
+				Access: 0,
+				Input:  "CreateCallExportRequest",
+				Output: "ExportTask",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/calls/{call_id}/exports/zip",
+						Method: "POST",
+					},
+				},
+			},
+			"CreateCallTranscriptExport": WebitelMethod{
+				Access: 0,
+				Input:  "CreateCallTranscriptExportRequest",
+				Output: "ExportTask",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/calls/{call_id}/exports/transcript",
+						Method: "POST",
+					},
+				},
+			},
+			"CreateCallDossierExport": WebitelMethod{
+				Access: 0,
+				Input:  "CreateCallDossierExportRequest",
+				Output: "ExportTask",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/calls/{call_id}/exports/dossier",
+						Method: "POST",
+					},
+				},
+			},
+			"CreateCallAudioExport": WebitelMethod{
+				Access: 0,
+				Input:  "CreateCallAudioExportRequest",
+				Output: "ExportTask",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/calls/exports/audio",
+						Method: "POST",
+					},
+				},
+			},
+			"CreateBatchExport": WebitelMethod{
+				Access: 0,
+				Input:  "CreateBatchExportRequest",
+				Output: "BatchExportTask",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/exports/batch",
+						Method: "POST",
+					},
+				},
+			},
+			"CreateScreenrecordingVideoExport": WebitelMethod{
+				Access: 0,
+				Input:  "CreateScreenrecordingVideoRequest",
+				Output: "ExportTask",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/agents/{agent_id}/exports/video/screenrecordings",
+						Method: "POST",
+					},
+				},
+			},
+			"CreateCallVideoExport": WebitelMethod{
+				Access: 0,
+				Input:  "CreateCallVideoRequest",
+				Output: "ExportTask",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/calls/{call_id}/exports/video",
+						Method: "POST",
+					},
+				},
+			},
+			"GetExport": WebitelMethod{
+				Access: 0,
+				Input:  "GetExportRequest",
+				Output: "ExportRecord",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/exports/pdf/tasks/{task_id}",
+						Method: "GET",
+					},
+				},
+			},
+			"GetExportByHistory": WebitelMethod{
+				Access: 0,
+				Input:  "GetExportByHistoryRequest",
+				Output: "ExportRecord",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/exports/pdf/history/{id}",
+						Method: "GET",
+					},
+				},
+			},
+			"WatchExport": WebitelMethod{
+				Access: 0,
+				Input:  "WatchExportRequest",
+				Output: "ExportProgress",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/exports/pdf/tasks/{task_id}/watch",
+						Method: "GET",
+					},
+				},
+			},
+			"CancelExport": WebitelMethod{
+				Access: 0,
+				Input:  "CancelExportRequest",
+				Output: "ExportRecord",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/exports/pdf/tasks/{task_id}/cancel",
+						Method: "POST",
+					},
+				},
+			},
+			"RetryExport": WebitelMethod{
+				Access: 0,
+				Input:  "RetryExportRequest",
+				Output: "ExportTask",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/exports/pdf/history/{id}/retry",
+						Method: "POST",
+					},
+				},
+			},
+			"VerifyExport": WebitelMethod{
+				Access: 0,
+				Input:  "VerifyExportRequest",
+				Output: "VerifyExportResponse",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/exports/pdf/verify",
+						Method: "POST",
+					},
+				},
+			},
 			"DeleteExport": WebitelMethod{
 				Access: 0,
 				Input:  "DeleteExportRequest",
@@ -87,6 +241,61 @@ var WebitelAPI = WebitelServicesInfo{
 					},
 				},
 			},
+			"CreateExportSchedule": WebitelMethod{
+				Access: 0,
+				Input:  "CreateExportScheduleRequest",
+				Output: "ExportSchedule",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/exports/schedules",
+						Method: "POST",
+					},
+				},
+			},
+			"ListExportSchedules": WebitelMethod{
+				Access: 0,
+				Input:  "ListExportSchedulesRequest",
+				Output: "ListExportSchedulesResponse",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/exports/schedules",
+						Method: "GET",
+					},
+				},
+			},
+			"GetExportSchedule": WebitelMethod{
+				Access: 0,
+				Input:  "GetExportScheduleRequest",
+				Output: "ExportSchedule",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/exports/schedules/{id}",
+						Method: "GET",
+					},
+				},
+			},
+			"UpdateExportSchedule": WebitelMethod{
+				Access: 0,
+				Input:  "UpdateExportScheduleRequest",
+				Output: "ExportSchedule",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/exports/schedules/{id}",
+						Method: "PUT",
+					},
+				},
+			},
+			"DeleteExportSchedule": WebitelMethod{
+				Access: 0,
+				Input:  "DeleteExportScheduleRequest",
+				Output: "DeleteExportScheduleResponse",
+				HttpBindings: []*HttpBinding{
+					{
+						Path:   "/exports/schedules/{id}",
+						Method: "DELETE",
+					},
+				},
+			},
 		},
 	},
 }
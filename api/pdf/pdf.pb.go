@@ -31,6 +31,7 @@ const (
 	ExportStatus_PROCESSING                ExportStatus = 2 // PDF is currently being rendered.
 	ExportStatus_DONE                      ExportStatus = 3 // Export finished successfully.
 	ExportStatus_FAILED                    ExportStatus = 4 // Export failed during generation.
+	ExportStatus_CANCELLED                 ExportStatus = 5 // Export was cancelled by the user.
 )
 
 // Enum value maps for ExportStatus.
@@ -41,6 +42,7 @@ var (
 		2: "PROCESSING",
 		3: "DONE",
 		4: "FAILED",
+		5: "CANCELLED",
 	}
 	ExportStatus_value = map[string]int32{
 		"EXPORT_STATUS_UNSPECIFIED": 0,
@@ -48,6 +50,7 @@ var (
 		"PROCESSING":                2,
 		"DONE":                      3,
 		"FAILED":                    4,
+		"CANCELLED":                 5,
 	}
 )
 
@@ -78,32 +81,2610 @@ func (ExportStatus) EnumDescriptor() ([]byte, []int) {
 	return file_pdf_proto_rawDescGZIP(), []int{0}
 }
 
+// Stage of the export pipeline reported by WatchExport.
+type ExportStage int32
+
+const (
+	ExportStage_EXPORT_STAGE_UNSPECIFIED ExportStage = 0
+	ExportStage_STAGE_QUEUED             ExportStage = 1 // Waiting in the queue, including a delayed retry.
+	ExportStage_STAGE_SEARCHING          ExportStage = 2 // Looking up the files to export.
+	ExportStage_STAGE_DOWNLOADING        ExportStage = 3 // Downloading files, current/total are files.
+	ExportStage_STAGE_RENDERING          ExportStage = 4 // Rendering the document, current/total are pages.
+	ExportStage_STAGE_UPLOADING          ExportStage = 5 // Uploading the result, current/total are bytes.
+	ExportStage_STAGE_COMPLETED          ExportStage = 6 // Export finished, see status for the outcome.
+	ExportStage_STAGE_WAITING            ExportStage = 7 // A batch export waits for its exports, current/total are finished/all exports.
+)
+
+// Enum value maps for ExportStage.
+var (
+	ExportStage_name = map[int32]string{
+		0: "EXPORT_STAGE_UNSPECIFIED",
+		1: "STAGE_QUEUED",
+		2: "STAGE_SEARCHING",
+		3: "STAGE_DOWNLOADING",
+		4: "STAGE_RENDERING",
+		5: "STAGE_UPLOADING",
+		6: "STAGE_COMPLETED",
+		7: "STAGE_WAITING",
+	}
+	ExportStage_value = map[string]int32{
+		"EXPORT_STAGE_UNSPECIFIED": 0,
+		"STAGE_QUEUED":             1,
+		"STAGE_SEARCHING":          2,
+		"STAGE_DOWNLOADING":        3,
+		"STAGE_RENDERING":          4,
+		"STAGE_UPLOADING":          5,
+		"STAGE_COMPLETED":          6,
+		"STAGE_WAITING":            7,
+	}
+)
+
+func (x ExportStage) Enum() *ExportStage {
+	p := new(ExportStage)
+	*p = x
+	return p
+}
+
+func (x ExportStage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExportStage) Descriptor() protoreflect.EnumDescriptor {
+	return file_pdf_proto_enumTypes[1].Descriptor()
+}
+
+func (ExportStage) Type() protoreflect.EnumType {
+	return &file_pdf_proto_enumTypes[1]
+}
+
+func (x ExportStage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExportStage.Descriptor instead.
+func (ExportStage) EnumDescriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{1}
+}
+
+// Format of an audio export.
+type AudioFormat int32
+
+const (
+	AudioFormat_AUDIO_FORMAT_UNSPECIFIED AudioFormat = 0 // The original recordings, unchanged, in a ZIP archive with a manifest.
+	AudioFormat_AUDIO_FORMAT_MP3         AudioFormat = 1 // The recordings joined into a single MP3 file.
+	AudioFormat_AUDIO_FORMAT_WAV         AudioFormat = 2 // The recordings joined into a single 16-bit PCM WAV file.
+	AudioFormat_AUDIO_FORMAT_OPUS        AudioFormat = 3 // The recordings joined into a single Opus file in an Ogg container.
+)
+
+// Enum value maps for AudioFormat.
+var (
+	AudioFormat_name = map[int32]string{
+		0: "AUDIO_FORMAT_UNSPECIFIED",
+		1: "AUDIO_FORMAT_MP3",
+		2: "AUDIO_FORMAT_WAV",
+		3: "AUDIO_FORMAT_OPUS",
+	}
+	AudioFormat_value = map[string]int32{
+		"AUDIO_FORMAT_UNSPECIFIED": 0,
+		"AUDIO_FORMAT_MP3":         1,
+		"AUDIO_FORMAT_WAV":         2,
+		"AUDIO_FORMAT_OPUS":        3,
+	}
+)
+
+func (x AudioFormat) Enum() *AudioFormat {
+	p := new(AudioFormat)
+	*p = x
+	return p
+}
+
+func (x AudioFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AudioFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_pdf_proto_enumTypes[2].Descriptor()
+}
+
+func (AudioFormat) Type() protoreflect.EnumType {
+	return &file_pdf_proto_enumTypes[2]
+}
+
+func (x AudioFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AudioFormat.Descriptor instead.
+func (AudioFormat) EnumDescriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{2}
+}
+
+// Format of the exports of a batch export.
+type BatchExportFormat int32
+
+const (
+	BatchExportFormat_BATCH_EXPORT_FORMAT_UNSPECIFIED BatchExportFormat = 0 // PDF.
+	BatchExportFormat_BATCH_EXPORT_FORMAT_PDF         BatchExportFormat = 1
+	BatchExportFormat_BATCH_EXPORT_FORMAT_ZIP         BatchExportFormat = 2
+	BatchExportFormat_BATCH_EXPORT_FORMAT_MP4         BatchExportFormat = 3
+	BatchExportFormat_BATCH_EXPORT_FORMAT_WEBM        BatchExportFormat = 4
+)
+
+// Enum value maps for BatchExportFormat.
+var (
+	BatchExportFormat_name = map[int32]string{
+		0: "BATCH_EXPORT_FORMAT_UNSPECIFIED",
+		1: "BATCH_EXPORT_FORMAT_PDF",
+		2: "BATCH_EXPORT_FORMAT_ZIP",
+		3: "BATCH_EXPORT_FORMAT_MP4",
+		4: "BATCH_EXPORT_FORMAT_WEBM",
+	}
+	BatchExportFormat_value = map[string]int32{
+		"BATCH_EXPORT_FORMAT_UNSPECIFIED": 0,
+		"BATCH_EXPORT_FORMAT_PDF":         1,
+		"BATCH_EXPORT_FORMAT_ZIP":         2,
+		"BATCH_EXPORT_FORMAT_MP4":         3,
+		"BATCH_EXPORT_FORMAT_WEBM":        4,
+	}
+)
+
+func (x BatchExportFormat) Enum() *BatchExportFormat {
+	p := new(BatchExportFormat)
+	*p = x
+	return p
+}
+
+func (x BatchExportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BatchExportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_pdf_proto_enumTypes[3].Descriptor()
+}
+
+func (BatchExportFormat) Type() protoreflect.EnumType {
+	return &file_pdf_proto_enumTypes[3]
+}
+
+func (x BatchExportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BatchExportFormat.Descriptor instead.
+func (BatchExportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{3}
+}
+
+// Order of the screenshots of an export. Screenshots without an upload time go last.
+type ExportSort int32
+
+const (
+	ExportSort_EXPORT_SORT_UNSPECIFIED ExportSort = 0 // The default of the export format.
+	ExportSort_SORT_UPLOADED_DESC      ExportSort = 1 // Newest first.
+	ExportSort_SORT_UPLOADED_ASC       ExportSort = 2 // Oldest first, a chronological timeline.
+	ExportSort_SORT_NAME               ExportSort = 3 // By file name.
+)
+
+// Enum value maps for ExportSort.
+var (
+	ExportSort_name = map[int32]string{
+		0: "EXPORT_SORT_UNSPECIFIED",
+		1: "SORT_UPLOADED_DESC",
+		2: "SORT_UPLOADED_ASC",
+		3: "SORT_NAME",
+	}
+	ExportSort_value = map[string]int32{
+		"EXPORT_SORT_UNSPECIFIED": 0,
+		"SORT_UPLOADED_DESC":      1,
+		"SORT_UPLOADED_ASC":       2,
+		"SORT_NAME":               3,
+	}
+)
+
+func (x ExportSort) Enum() *ExportSort {
+	p := new(ExportSort)
+	*p = x
+	return p
+}
+
+func (x ExportSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExportSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_pdf_proto_enumTypes[4].Descriptor()
+}
+
+func (ExportSort) Type() protoreflect.EnumType {
+	return &file_pdf_proto_enumTypes[4]
+}
+
+func (x ExportSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExportSort.Descriptor instead.
+func (ExportSort) EnumDescriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{4}
+}
+
+// Grouping of the screenshots of an export. Hours and days are in the PDF time zone, UTC for ZIP exports.
+type ExportGrouping int32
+
+const (
+	ExportGrouping_GROUP_BY_NONE    ExportGrouping = 0
+	ExportGrouping_GROUP_BY_HOUR    ExportGrouping = 1
+	ExportGrouping_GROUP_BY_DAY     ExportGrouping = 2
+	ExportGrouping_GROUP_BY_SESSION ExportGrouping = 3 // Runs of screenshots without a pause longer than session_gap_ms.
+)
+
+// Enum value maps for ExportGrouping.
+var (
+	ExportGrouping_name = map[int32]string{
+		0: "GROUP_BY_NONE",
+		1: "GROUP_BY_HOUR",
+		2: "GROUP_BY_DAY",
+		3: "GROUP_BY_SESSION",
+	}
+	ExportGrouping_value = map[string]int32{
+		"GROUP_BY_NONE":    0,
+		"GROUP_BY_HOUR":    1,
+		"GROUP_BY_DAY":     2,
+		"GROUP_BY_SESSION": 3,
+	}
+)
+
+func (x ExportGrouping) Enum() *ExportGrouping {
+	p := new(ExportGrouping)
+	*p = x
+	return p
+}
+
+func (x ExportGrouping) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExportGrouping) Descriptor() protoreflect.EnumDescriptor {
+	return file_pdf_proto_enumTypes[5].Descriptor()
+}
+
+func (ExportGrouping) Type() protoreflect.EnumType {
+	return &file_pdf_proto_enumTypes[5]
+}
+
+func (x ExportGrouping) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExportGrouping.Descriptor instead.
+func (ExportGrouping) EnumDescriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{5}
+}
+
+// Page size of a PDF export.
+type PdfPageSize int32
+
+const (
+	PdfPageSize_PDF_PAGE_SIZE_UNSPECIFIED PdfPageSize = 0 // A4.
+	PdfPageSize_PDF_PAGE_SIZE_A4          PdfPageSize = 1
+	PdfPageSize_PDF_PAGE_SIZE_LETTER      PdfPageSize = 2
+)
+
+// Enum value maps for PdfPageSize.
+var (
+	PdfPageSize_name = map[int32]string{
+		0: "PDF_PAGE_SIZE_UNSPECIFIED",
+		1: "PDF_PAGE_SIZE_A4",
+		2: "PDF_PAGE_SIZE_LETTER",
+	}
+	PdfPageSize_value = map[string]int32{
+		"PDF_PAGE_SIZE_UNSPECIFIED": 0,
+		"PDF_PAGE_SIZE_A4":          1,
+		"PDF_PAGE_SIZE_LETTER":      2,
+	}
+)
+
+func (x PdfPageSize) Enum() *PdfPageSize {
+	p := new(PdfPageSize)
+	*p = x
+	return p
+}
+
+func (x PdfPageSize) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PdfPageSize) Descriptor() protoreflect.EnumDescriptor {
+	return file_pdf_proto_enumTypes[6].Descriptor()
+}
+
+func (PdfPageSize) Type() protoreflect.EnumType {
+	return &file_pdf_proto_enumTypes[6]
+}
+
+func (x PdfPageSize) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PdfPageSize.Descriptor instead.
+func (PdfPageSize) EnumDescriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{6}
+}
+
+// Page orientation of a PDF export.
+type PdfOrientation int32
+
+const (
+	PdfOrientation_PDF_ORIENTATION_UNSPECIFIED PdfOrientation = 0 // Portrait.
+	PdfOrientation_PDF_ORIENTATION_PORTRAIT    PdfOrientation = 1
+	PdfOrientation_PDF_ORIENTATION_LANDSCAPE   PdfOrientation = 2
+)
+
+// Enum value maps for PdfOrientation.
+var (
+	PdfOrientation_name = map[int32]string{
+		0: "PDF_ORIENTATION_UNSPECIFIED",
+		1: "PDF_ORIENTATION_PORTRAIT",
+		2: "PDF_ORIENTATION_LANDSCAPE",
+	}
+	PdfOrientation_value = map[string]int32{
+		"PDF_ORIENTATION_UNSPECIFIED": 0,
+		"PDF_ORIENTATION_PORTRAIT":    1,
+		"PDF_ORIENTATION_LANDSCAPE":   2,
+	}
+)
+
+func (x PdfOrientation) Enum() *PdfOrientation {
+	p := new(PdfOrientation)
+	*p = x
+	return p
+}
+
+func (x PdfOrientation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PdfOrientation) Descriptor() protoreflect.EnumDescriptor {
+	return file_pdf_proto_enumTypes[7].Descriptor()
+}
+
+func (PdfOrientation) Type() protoreflect.EnumType {
+	return &file_pdf_proto_enumTypes[7]
+}
+
+func (x PdfOrientation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PdfOrientation.Descriptor instead.
+func (PdfOrientation) EnumDescriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{7}
+}
+
+// Grouping of the pages in the table of contents of a PDF export.
+type PdfContents int32
+
+const (
+	PdfContents_PDF_CONTENTS_UNSPECIFIED PdfContents = 0 // No table of contents.
+	PdfContents_PDF_CONTENTS_HOUR        PdfContents = 1 // One entry per hour of capture time.
+	PdfContents_PDF_CONTENTS_DAY         PdfContents = 2 // One entry per day of capture time.
+)
+
+// Enum value maps for PdfContents.
+var (
+	PdfContents_name = map[int32]string{
+		0: "PDF_CONTENTS_UNSPECIFIED",
+		1: "PDF_CONTENTS_HOUR",
+		2: "PDF_CONTENTS_DAY",
+	}
+	PdfContents_value = map[string]int32{
+		"PDF_CONTENTS_UNSPECIFIED": 0,
+		"PDF_CONTENTS_HOUR":        1,
+		"PDF_CONTENTS_DAY":         2,
+	}
+)
+
+func (x PdfContents) Enum() *PdfContents {
+	p := new(PdfContents)
+	*p = x
+	return p
+}
+
+func (x PdfContents) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PdfContents) Descriptor() protoreflect.EnumDescriptor {
+	return file_pdf_proto_enumTypes[8].Descriptor()
+}
+
+func (PdfContents) Type() protoreflect.EnumType {
+	return &file_pdf_proto_enumTypes[8]
+}
+
+func (x PdfContents) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PdfContents.Descriptor instead.
+func (PdfContents) EnumDescriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{8}
+}
+
+// Container of a time-lapse video export.
+type VideoFormat int32
+
+const (
+	VideoFormat_VIDEO_FORMAT_UNSPECIFIED VideoFormat = 0 // MP4.
+	VideoFormat_VIDEO_FORMAT_MP4         VideoFormat = 1
+	VideoFormat_VIDEO_FORMAT_WEBM        VideoFormat = 2
+)
+
+// Enum value maps for VideoFormat.
+var (
+	VideoFormat_name = map[int32]string{
+		0: "VIDEO_FORMAT_UNSPECIFIED",
+		1: "VIDEO_FORMAT_MP4",
+		2: "VIDEO_FORMAT_WEBM",
+	}
+	VideoFormat_value = map[string]int32{
+		"VIDEO_FORMAT_UNSPECIFIED": 0,
+		"VIDEO_FORMAT_MP4":         1,
+		"VIDEO_FORMAT_WEBM":        2,
+	}
+)
+
+func (x VideoFormat) Enum() *VideoFormat {
+	p := new(VideoFormat)
+	*p = x
+	return p
+}
+
+func (x VideoFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (VideoFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_pdf_proto_enumTypes[9].Descriptor()
+}
+
+func (VideoFormat) Type() protoreflect.EnumType {
+	return &file_pdf_proto_enumTypes[9]
+}
+
+func (x VideoFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use VideoFormat.Descriptor instead.
+func (VideoFormat) EnumDescriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{9}
+}
+
+// Unit of the time range of a scheduled export.
+type ScheduleRangeUnit int32
+
+const (
+	ScheduleRangeUnit_SCHEDULE_RANGE_UNIT_UNSPECIFIED ScheduleRangeUnit = 0 // Day.
+	ScheduleRangeUnit_SCHEDULE_RANGE_HOUR             ScheduleRangeUnit = 1
+	ScheduleRangeUnit_SCHEDULE_RANGE_DAY              ScheduleRangeUnit = 2
+	ScheduleRangeUnit_SCHEDULE_RANGE_WEEK             ScheduleRangeUnit = 3 // Weeks start on Monday.
+	ScheduleRangeUnit_SCHEDULE_RANGE_MONTH            ScheduleRangeUnit = 4
+)
+
+// Enum value maps for ScheduleRangeUnit.
+var (
+	ScheduleRangeUnit_name = map[int32]string{
+		0: "SCHEDULE_RANGE_UNIT_UNSPECIFIED",
+		1: "SCHEDULE_RANGE_HOUR",
+		2: "SCHEDULE_RANGE_DAY",
+		3: "SCHEDULE_RANGE_WEEK",
+		4: "SCHEDULE_RANGE_MONTH",
+	}
+	ScheduleRangeUnit_value = map[string]int32{
+		"SCHEDULE_RANGE_UNIT_UNSPECIFIED": 0,
+		"SCHEDULE_RANGE_HOUR":             1,
+		"SCHEDULE_RANGE_DAY":              2,
+		"SCHEDULE_RANGE_WEEK":             3,
+		"SCHEDULE_RANGE_MONTH":            4,
+	}
+)
+
+func (x ScheduleRangeUnit) Enum() *ScheduleRangeUnit {
+	p := new(ScheduleRangeUnit)
+	*p = x
+	return p
+}
+
+func (x ScheduleRangeUnit) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ScheduleRangeUnit) Descriptor() protoreflect.EnumDescriptor {
+	return file_pdf_proto_enumTypes[10].Descriptor()
+}
+
+func (ScheduleRangeUnit) Type() protoreflect.EnumType {
+	return &file_pdf_proto_enumTypes[10]
+}
+
+func (x ScheduleRangeUnit) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ScheduleRangeUnit.Descriptor instead.
+func (ScheduleRangeUnit) EnumDescriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{10}
+}
+
+// What a schedule does about the runs missed while no instance of the service was running.
+type ScheduleCatchUp int32
+
+const (
+	ScheduleCatchUp_SCHEDULE_CATCH_UP_UNSPECIFIED ScheduleCatchUp = 0 // Latest.
+	ScheduleCatchUp_SCHEDULE_CATCH_UP_SKIP        ScheduleCatchUp = 1 // Missed runs are dropped, the schedule waits for its next occurrence.
+	ScheduleCatchUp_SCHEDULE_CATCH_UP_LATEST      ScheduleCatchUp = 2 // Only the latest missed run is made.
+	ScheduleCatchUp_SCHEDULE_CATCH_UP_ALL         ScheduleCatchUp = 3 // Every missed run is made, up to 100, each with its own range.
+)
+
+// Enum value maps for ScheduleCatchUp.
+var (
+	ScheduleCatchUp_name = map[int32]string{
+		0: "SCHEDULE_CATCH_UP_UNSPECIFIED",
+		1: "SCHEDULE_CATCH_UP_SKIP",
+		2: "SCHEDULE_CATCH_UP_LATEST",
+		3: "SCHEDULE_CATCH_UP_ALL",
+	}
+	ScheduleCatchUp_value = map[string]int32{
+		"SCHEDULE_CATCH_UP_UNSPECIFIED": 0,
+		"SCHEDULE_CATCH_UP_SKIP":        1,
+		"SCHEDULE_CATCH_UP_LATEST":      2,
+		"SCHEDULE_CATCH_UP_ALL":         3,
+	}
+)
+
+func (x ScheduleCatchUp) Enum() *ScheduleCatchUp {
+	p := new(ScheduleCatchUp)
+	*p = x
+	return p
+}
+
+func (x ScheduleCatchUp) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ScheduleCatchUp) Descriptor() protoreflect.EnumDescriptor {
+	return file_pdf_proto_enumTypes[11].Descriptor()
+}
+
+func (ScheduleCatchUp) Type() protoreflect.EnumType {
+	return &file_pdf_proto_enumTypes[11]
+}
+
+func (x ScheduleCatchUp) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ScheduleCatchUp.Descriptor instead.
+func (ScheduleCatchUp) EnumDescriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{11}
+}
+
 // Request for generating a screen recording PDF.
 type CreateScreenrecordingRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`        // Unique identifier of the agent.
-	From          int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`                             // Start timestamp of the range (Unix millis).
-	To            int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`                                 // End timestamp of the range (Unix millis).
-	FileIds       []int64                `protobuf:"varint,4,rep,packed,name=file_ids,json=fileIds,proto3" json:"file_ids,omitempty"` // Optional: specific file IDs to include in the PDF.
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                                            // Unique identifier of the agent.
+	From          int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`                                                                 // Start timestamp of the range (Unix millis).
+	To            int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`                                                                     // End timestamp of the range (Unix millis).
+	FileIds       []int64                `protobuf:"varint,4,rep,packed,name=file_ids,json=fileIds,proto3" json:"file_ids,omitempty"`                                     // Optional: specific file IDs to include in the PDF.
+	Pdf           *PdfOptions            `protobuf:"bytes,5,opt,name=pdf,proto3" json:"pdf,omitempty"`                                                                    // Optional: page options, ignored by ZIP exports.
+	Sort          ExportSort             `protobuf:"varint,6,opt,name=sort,proto3,enum=webitel_media_exporter.ExportSort" json:"sort,omitempty"`                          // Order of the screenshots; newest first if unspecified.
+	GroupBy       ExportGrouping         `protobuf:"varint,7,opt,name=group_by,json=groupBy,proto3,enum=webitel_media_exporter.ExportGrouping" json:"group_by,omitempty"` // Groups of screenshots: PDF bookmarks and table of contents, ZIP folders.
+	SessionGapMs  int64                  `protobuf:"varint,8,opt,name=session_gap_ms,json=sessionGapMs,proto3" json:"session_gap_ms,omitempty"`                           // Pause between screenshots that starts a new session with GROUP_BY_SESSION; 10 minutes if 0.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateScreenrecordingRequest) Reset() {
+	*x = CreateScreenrecordingRequest{}
+	mi := &file_pdf_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateScreenrecordingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateScreenrecordingRequest) ProtoMessage() {}
+
+func (x *CreateScreenrecordingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateScreenrecordingRequest.ProtoReflect.Descriptor instead.
+func (*CreateScreenrecordingRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateScreenrecordingRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *CreateScreenrecordingRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *CreateScreenrecordingRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *CreateScreenrecordingRequest) GetFileIds() []int64 {
+	if x != nil {
+		return x.FileIds
+	}
+	return nil
+}
+
+func (x *CreateScreenrecordingRequest) GetPdf() *PdfOptions {
+	if x != nil {
+		return x.Pdf
+	}
+	return nil
+}
+
+func (x *CreateScreenrecordingRequest) GetSort() ExportSort {
+	if x != nil {
+		return x.Sort
+	}
+	return ExportSort_EXPORT_SORT_UNSPECIFIED
+}
+
+func (x *CreateScreenrecordingRequest) GetGroupBy() ExportGrouping {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ExportGrouping_GROUP_BY_NONE
+}
+
+func (x *CreateScreenrecordingRequest) GetSessionGapMs() int64 {
+	if x != nil {
+		return x.SessionGapMs
+	}
+	return 0
+}
+
+// Request for generating a call media PDF.
+type CreateCallExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CallId        string                 `protobuf:"bytes,1,opt,name=call_id,json=callId,proto3" json:"call_id,omitempty"`                                                // Unique identifier of the call.
+	From          int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`                                                                 // Start timestamp of the range (Unix millis).
+	To            int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`                                                                     // End timestamp of the range (Unix millis).
+	FileIds       []int64                `protobuf:"varint,5,rep,packed,name=file_ids,json=fileIds,proto3" json:"file_ids,omitempty"`                                     // Optional: specific file IDs to include in the PDF.
+	Pdf           *PdfOptions            `protobuf:"bytes,6,opt,name=pdf,proto3" json:"pdf,omitempty"`                                                                    // Optional: page options, ignored by ZIP exports.
+	Sort          ExportSort             `protobuf:"varint,7,opt,name=sort,proto3,enum=webitel_media_exporter.ExportSort" json:"sort,omitempty"`                          // Order of the screenshots; newest first if unspecified.
+	GroupBy       ExportGrouping         `protobuf:"varint,8,opt,name=group_by,json=groupBy,proto3,enum=webitel_media_exporter.ExportGrouping" json:"group_by,omitempty"` // Groups of screenshots: PDF bookmarks and table of contents, ZIP folders.
+	SessionGapMs  int64                  `protobuf:"varint,9,opt,name=session_gap_ms,json=sessionGapMs,proto3" json:"session_gap_ms,omitempty"`                           // Pause between screenshots that starts a new session with GROUP_BY_SESSION; 10 minutes if 0.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCallExportRequest) Reset() {
+	*x = CreateCallExportRequest{}
+	mi := &file_pdf_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCallExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCallExportRequest) ProtoMessage() {}
+
+func (x *CreateCallExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCallExportRequest.ProtoReflect.Descriptor instead.
+func (*CreateCallExportRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateCallExportRequest) GetCallId() string {
+	if x != nil {
+		return x.CallId
+	}
+	return ""
+}
+
+func (x *CreateCallExportRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *CreateCallExportRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *CreateCallExportRequest) GetFileIds() []int64 {
+	if x != nil {
+		return x.FileIds
+	}
+	return nil
+}
+
+func (x *CreateCallExportRequest) GetPdf() *PdfOptions {
+	if x != nil {
+		return x.Pdf
+	}
+	return nil
+}
+
+func (x *CreateCallExportRequest) GetSort() ExportSort {
+	if x != nil {
+		return x.Sort
+	}
+	return ExportSort_EXPORT_SORT_UNSPECIFIED
+}
+
+func (x *CreateCallExportRequest) GetGroupBy() ExportGrouping {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ExportGrouping_GROUP_BY_NONE
+}
+
+func (x *CreateCallExportRequest) GetSessionGapMs() int64 {
+	if x != nil {
+		return x.SessionGapMs
+	}
+	return 0
+}
+
+// Request for generating a transcript PDF of a call.
+type CreateCallTranscriptExportRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	CallId  string                 `protobuf:"bytes,1,opt,name=call_id,json=callId,proto3" json:"call_id,omitempty"`            // Unique identifier of the call.
+	From    int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`                             // Start timestamp of the range of recording uploads (Unix millis).
+	To      int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`                                 // End timestamp of the range of recording uploads (Unix millis).
+	FileIds []int64                `protobuf:"varint,4,rep,packed,name=file_ids,json=fileIds,proto3" json:"file_ids,omitempty"` // Optional: specific recordings to include.
+	// Optional: page options. The options of screenshot grids do not apply, a signed manifest lists the recordings.
+	Pdf *PdfOptions `protobuf:"bytes,5,opt,name=pdf,proto3" json:"pdf,omitempty"`
+	// Only the phrases overlapping this window are included, in milliseconds from the start of every recording.
+	WindowFromMs  int64 `protobuf:"varint,6,opt,name=window_from_ms,json=windowFromMs,proto3" json:"window_from_ms,omitempty"`
+	WindowToMs    int64 `protobuf:"varint,7,opt,name=window_to_ms,json=windowToMs,proto3" json:"window_to_ms,omitempty"` // Not limited if 0.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCallTranscriptExportRequest) Reset() {
+	*x = CreateCallTranscriptExportRequest{}
+	mi := &file_pdf_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCallTranscriptExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCallTranscriptExportRequest) ProtoMessage() {}
+
+func (x *CreateCallTranscriptExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCallTranscriptExportRequest.ProtoReflect.Descriptor instead.
+func (*CreateCallTranscriptExportRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateCallTranscriptExportRequest) GetCallId() string {
+	if x != nil {
+		return x.CallId
+	}
+	return ""
+}
+
+func (x *CreateCallTranscriptExportRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *CreateCallTranscriptExportRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *CreateCallTranscriptExportRequest) GetFileIds() []int64 {
+	if x != nil {
+		return x.FileIds
+	}
+	return nil
+}
+
+func (x *CreateCallTranscriptExportRequest) GetPdf() *PdfOptions {
+	if x != nil {
+		return x.Pdf
+	}
+	return nil
+}
+
+func (x *CreateCallTranscriptExportRequest) GetWindowFromMs() int64 {
+	if x != nil {
+		return x.WindowFromMs
+	}
+	return 0
+}
+
+func (x *CreateCallTranscriptExportRequest) GetWindowToMs() int64 {
+	if x != nil {
+		return x.WindowToMs
+	}
+	return 0
+}
+
+// Request for generating a dossier PDF of a call.
+type CreateCallDossierExportRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	CallId string                 `protobuf:"bytes,1,opt,name=call_id,json=callId,proto3" json:"call_id,omitempty"` // Unique identifier of the call.
+	From   int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`                  // Start timestamp of the range of screenshots and files (Unix millis).
+	To     int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`                      // End timestamp of the range of screenshots and files (Unix millis).
+	// Optional: page options. The options of screenshot grids do not apply, a signed manifest
+	// lists the screenshots and recordings.
+	Pdf           *PdfOptions `protobuf:"bytes,4,opt,name=pdf,proto3" json:"pdf,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCallDossierExportRequest) Reset() {
+	*x = CreateCallDossierExportRequest{}
+	mi := &file_pdf_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCallDossierExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCallDossierExportRequest) ProtoMessage() {}
+
+func (x *CreateCallDossierExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCallDossierExportRequest.ProtoReflect.Descriptor instead.
+func (*CreateCallDossierExportRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateCallDossierExportRequest) GetCallId() string {
+	if x != nil {
+		return x.CallId
+	}
+	return ""
+}
+
+func (x *CreateCallDossierExportRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *CreateCallDossierExportRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *CreateCallDossierExportRequest) GetPdf() *PdfOptions {
+	if x != nil {
+		return x.Pdf
+	}
+	return nil
+}
+
+// Request for exporting the audio recordings of calls.
+type CreateCallAudioExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CallIds       []string               `protobuf:"bytes,1,rep,name=call_ids,json=callIds,proto3" json:"call_ids,omitempty"`         // Calls whose recordings are exported, at least one.
+	From          int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`                             // Start timestamp of the range of recording uploads (Unix millis).
+	To            int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`                                 // End timestamp of the range of recording uploads (Unix millis).
+	FileIds       []int64                `protobuf:"varint,4,rep,packed,name=file_ids,json=fileIds,proto3" json:"file_ids,omitempty"` // Optional: specific recordings to include.
+	Format        AudioFormat            `protobuf:"varint,5,opt,name=format,proto3,enum=webitel_media_exporter.AudioFormat" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCallAudioExportRequest) Reset() {
+	*x = CreateCallAudioExportRequest{}
+	mi := &file_pdf_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCallAudioExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCallAudioExportRequest) ProtoMessage() {}
+
+func (x *CreateCallAudioExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCallAudioExportRequest.ProtoReflect.Descriptor instead.
+func (*CreateCallAudioExportRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateCallAudioExportRequest) GetCallIds() []string {
+	if x != nil {
+		return x.CallIds
+	}
+	return nil
+}
+
+func (x *CreateCallAudioExportRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *CreateCallAudioExportRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *CreateCallAudioExportRequest) GetFileIds() []int64 {
+	if x != nil {
+		return x.FileIds
+	}
+	return nil
+}
+
+func (x *CreateCallAudioExportRequest) GetFormat() AudioFormat {
+	if x != nil {
+		return x.Format
+	}
+	return AudioFormat_AUDIO_FORMAT_UNSPECIFIED
+}
+
+// Request for exporting the screenshots of many agents or calls at once.
+type CreateBatchExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentIds      []int64                `protobuf:"varint,1,rep,packed,name=agent_ids,json=agentIds,proto3" json:"agent_ids,omitempty"` // Agents to export, one export each. Excludes call_ids.
+	CallIds       []string               `protobuf:"bytes,2,rep,name=call_ids,json=callIds,proto3" json:"call_ids,omitempty"`            // Calls to export, one export each. Excludes agent_ids.
+	From          int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`                                // Start timestamp of the range of every export (Unix millis).
+	To            int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`                                    // End timestamp of the range of every export (Unix millis).
+	Format        BatchExportFormat      `protobuf:"varint,5,opt,name=format,proto3,enum=webitel_media_exporter.BatchExportFormat" json:"format,omitempty"`
+	Pdf           *PdfOptions            `protobuf:"bytes,6,opt,name=pdf,proto3" json:"pdf,omitempty"`                                                                    // Optional: page options of PDF exports.
+	Video         *VideoOptions          `protobuf:"bytes,7,opt,name=video,proto3" json:"video,omitempty"`                                                                // Optional: options of video exports; the container is taken from format.
+	Sort          ExportSort             `protobuf:"varint,8,opt,name=sort,proto3,enum=webitel_media_exporter.ExportSort" json:"sort,omitempty"`                          // Order of the screenshots; the default of the format if unspecified.
+	GroupBy       ExportGrouping         `protobuf:"varint,9,opt,name=group_by,json=groupBy,proto3,enum=webitel_media_exporter.ExportGrouping" json:"group_by,omitempty"` // Groups of screenshots: PDF bookmarks and table of contents, ZIP folders.
+	SessionGapMs  int64                  `protobuf:"varint,10,opt,name=session_gap_ms,json=sessionGapMs,proto3" json:"session_gap_ms,omitempty"`                          // Pause between screenshots that starts a new session with GROUP_BY_SESSION; 10 minutes if 0.
+	Combine       bool                   `protobuf:"varint,11,opt,name=combine,proto3" json:"combine,omitempty"`                                                          // Once all exports finished, pack their files into one ZIP archive, the file of the batch.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBatchExportRequest) Reset() {
+	*x = CreateBatchExportRequest{}
+	mi := &file_pdf_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBatchExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBatchExportRequest) ProtoMessage() {}
+
+func (x *CreateBatchExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBatchExportRequest.ProtoReflect.Descriptor instead.
+func (*CreateBatchExportRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateBatchExportRequest) GetAgentIds() []int64 {
+	if x != nil {
+		return x.AgentIds
+	}
+	return nil
+}
+
+func (x *CreateBatchExportRequest) GetCallIds() []string {
+	if x != nil {
+		return x.CallIds
+	}
+	return nil
+}
+
+func (x *CreateBatchExportRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *CreateBatchExportRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *CreateBatchExportRequest) GetFormat() BatchExportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return BatchExportFormat_BATCH_EXPORT_FORMAT_UNSPECIFIED
+}
+
+func (x *CreateBatchExportRequest) GetPdf() *PdfOptions {
+	if x != nil {
+		return x.Pdf
+	}
+	return nil
+}
+
+func (x *CreateBatchExportRequest) GetVideo() *VideoOptions {
+	if x != nil {
+		return x.Video
+	}
+	return nil
+}
+
+func (x *CreateBatchExportRequest) GetSort() ExportSort {
+	if x != nil {
+		return x.Sort
+	}
+	return ExportSort_EXPORT_SORT_UNSPECIFIED
+}
+
+func (x *CreateBatchExportRequest) GetGroupBy() ExportGrouping {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ExportGrouping_GROUP_BY_NONE
+}
+
+func (x *CreateBatchExportRequest) GetSessionGapMs() int64 {
+	if x != nil {
+		return x.SessionGapMs
+	}
+	return 0
+}
+
+func (x *CreateBatchExportRequest) GetCombine() bool {
+	if x != nil {
+		return x.Combine
+	}
+	return false
+}
+
+// A batch export and the exports it is made of.
+type BatchExportTask struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Batch         *ExportTask            `protobuf:"bytes,1,opt,name=batch,proto3" json:"batch,omitempty"`     // The parent export that tracks the others.
+	Exports       []*ExportTask          `protobuf:"bytes,2,rep,name=exports,proto3" json:"exports,omitempty"` // One export per agent or call, in the order of the request.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchExportTask) Reset() {
+	*x = BatchExportTask{}
+	mi := &file_pdf_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchExportTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchExportTask) ProtoMessage() {}
+
+func (x *BatchExportTask) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchExportTask.ProtoReflect.Descriptor instead.
+func (*BatchExportTask) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BatchExportTask) GetBatch() *ExportTask {
+	if x != nil {
+		return x.Batch
+	}
+	return nil
+}
+
+func (x *BatchExportTask) GetExports() []*ExportTask {
+	if x != nil {
+		return x.Exports
+	}
+	return nil
+}
+
+// Page options of a PDF export.
+type PdfOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Captions      bool                   `protobuf:"varint,1,opt,name=captions,proto3" json:"captions,omitempty"`                                         // Print the capture time, agent, file name/ID and SHA-256 under every screenshot.
+	HeaderFooter  bool                   `protobuf:"varint,2,opt,name=header_footer,json=headerFooter,proto3" json:"header_footer,omitempty"`             // Print a running header with the source and time range, and "page X of Y" in the footer.
+	Timezone      string                 `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`                                          // IANA time zone of the printed times, e.g. "Europe/Kyiv"; UTC if empty.
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`                                                // Title in the header; a description of the export source if empty.
+	CoverPage     bool                   `protobuf:"varint,5,opt,name=cover_page,json=coverPage,proto3" json:"cover_page,omitempty"`                      // Start with a cover page: domain, source, time range, requester, and included/skipped screenshot counts.
+	Contents      PdfContents            `protobuf:"varint,6,opt,name=contents,proto3,enum=webitel_media_exporter.PdfContents" json:"contents,omitempty"` // Table of contents linking to the first page of every group of the request's group_by, or of this grouping if group_by is not set.
+	PageSize      PdfPageSize            `protobuf:"varint,7,opt,name=page_size,json=pageSize,proto3,enum=webitel_media_exporter.PdfPageSize" json:"page_size,omitempty"`
+	Orientation   PdfOrientation         `protobuf:"varint,8,opt,name=orientation,proto3,enum=webitel_media_exporter.PdfOrientation" json:"orientation,omitempty"`
+	ImagesPerPage int32                  `protobuf:"varint,9,opt,name=images_per_page,json=imagesPerPage,proto3" json:"images_per_page,omitempty"` // Screenshots per page in a grid: 1, 2, 4, 6 or 9; 1 if 0. Images keep their aspect ratio.
+	ContactSheet  bool                   `protobuf:"varint,10,opt,name=contact_sheet,json=contactSheet,proto3" json:"contact_sheet,omitempty"`     // Grid of small thumbnails labeled with the capture time and file ID; excludes images_per_page.
+	Watermark     bool                   `protobuf:"varint,11,opt,name=watermark,proto3" json:"watermark,omitempty"`                               // Draw the requesting user, domain, export ID and creation time across every page.
+	Encryption    *PdfEncryption         `protobuf:"bytes,12,opt,name=encryption,proto3" json:"encryption,omitempty"`                              // Encrypt the document with AES-256; not encrypted if not set.
+	// Attach manifest.json listing the included storage files (ID, name, SHA-256, upload time),
+	// its Ed25519 signature manifest.json.sig and the certificate or public key of the signer signer.pem.
+	SignedManifest bool `protobuf:"varint,13,opt,name=signed_manifest,json=signedManifest,proto3" json:"signed_manifest,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PdfOptions) Reset() {
+	*x = PdfOptions{}
+	mi := &file_pdf_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PdfOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PdfOptions) ProtoMessage() {}
+
+func (x *PdfOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PdfOptions.ProtoReflect.Descriptor instead.
+func (*PdfOptions) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PdfOptions) GetCaptions() bool {
+	if x != nil {
+		return x.Captions
+	}
+	return false
+}
+
+func (x *PdfOptions) GetHeaderFooter() bool {
+	if x != nil {
+		return x.HeaderFooter
+	}
+	return false
+}
+
+func (x *PdfOptions) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *PdfOptions) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *PdfOptions) GetCoverPage() bool {
+	if x != nil {
+		return x.CoverPage
+	}
+	return false
+}
+
+func (x *PdfOptions) GetContents() PdfContents {
+	if x != nil {
+		return x.Contents
+	}
+	return PdfContents_PDF_CONTENTS_UNSPECIFIED
+}
+
+func (x *PdfOptions) GetPageSize() PdfPageSize {
+	if x != nil {
+		return x.PageSize
+	}
+	return PdfPageSize_PDF_PAGE_SIZE_UNSPECIFIED
+}
+
+func (x *PdfOptions) GetOrientation() PdfOrientation {
+	if x != nil {
+		return x.Orientation
+	}
+	return PdfOrientation_PDF_ORIENTATION_UNSPECIFIED
+}
+
+func (x *PdfOptions) GetImagesPerPage() int32 {
+	if x != nil {
+		return x.ImagesPerPage
+	}
+	return 0
+}
+
+func (x *PdfOptions) GetContactSheet() bool {
+	if x != nil {
+		return x.ContactSheet
+	}
+	return false
+}
+
+func (x *PdfOptions) GetWatermark() bool {
+	if x != nil {
+		return x.Watermark
+	}
+	return false
+}
+
+func (x *PdfOptions) GetEncryption() *PdfEncryption {
+	if x != nil {
+		return x.Encryption
+	}
+	return nil
+}
+
+func (x *PdfOptions) GetSignedManifest() bool {
+	if x != nil {
+		return x.SignedManifest
+	}
+	return false
+}
+
+// Password protection of a PDF export. The document opens with the user password only and can be
+// viewed but not printed, copied or changed. The password is never stored in plaintext.
+type PdfEncryption struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// User password, at most 127 bytes. If empty, a password is generated and returned once,
+	// in the first GetExport response to the requesting user.
+	Password      string `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PdfEncryption) Reset() {
+	*x = PdfEncryption{}
+	mi := &file_pdf_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PdfEncryption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PdfEncryption) ProtoMessage() {}
+
+func (x *PdfEncryption) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PdfEncryption.ProtoReflect.Descriptor instead.
+func (*PdfEncryption) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PdfEncryption) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// Options of a time-lapse video export.
+type VideoOptions struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Format           VideoFormat            `protobuf:"varint,1,opt,name=format,proto3,enum=webitel_media_exporter.VideoFormat" json:"format,omitempty"`
+	FrameDurationMs  int64                  `protobuf:"varint,2,opt,name=frame_duration_ms,json=frameDurationMs,proto3" json:"frame_duration_ms,omitempty"`  // How long every screenshot is shown; the service default if 0.
+	TimestampOverlay bool                   `protobuf:"varint,3,opt,name=timestamp_overlay,json=timestampOverlay,proto3" json:"timestamp_overlay,omitempty"` // Draw the capture time over every screenshot.
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *VideoOptions) Reset() {
+	*x = VideoOptions{}
+	mi := &file_pdf_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VideoOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VideoOptions) ProtoMessage() {}
+
+func (x *VideoOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VideoOptions.ProtoReflect.Descriptor instead.
+func (*VideoOptions) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *VideoOptions) GetFormat() VideoFormat {
+	if x != nil {
+		return x.Format
+	}
+	return VideoFormat_VIDEO_FORMAT_UNSPECIFIED
+}
+
+func (x *VideoOptions) GetFrameDurationMs() int64 {
+	if x != nil {
+		return x.FrameDurationMs
+	}
+	return 0
+}
+
+func (x *VideoOptions) GetTimestampOverlay() bool {
+	if x != nil {
+		return x.TimestampOverlay
+	}
+	return false
+}
+
+// Request for generating a time-lapse video of an agent's screenshots.
+type CreateScreenrecordingVideoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`        // Unique identifier of the agent.
+	From          int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`                             // Start timestamp of the range (Unix millis).
+	To            int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`                                 // End timestamp of the range (Unix millis).
+	FileIds       []int64                `protobuf:"varint,4,rep,packed,name=file_ids,json=fileIds,proto3" json:"file_ids,omitempty"` // Optional: specific file IDs to include in the video.
+	Video         *VideoOptions          `protobuf:"bytes,5,opt,name=video,proto3" json:"video,omitempty"`
+	Sort          ExportSort             `protobuf:"varint,6,opt,name=sort,proto3,enum=webitel_media_exporter.ExportSort" json:"sort,omitempty"`                          // Order of the frames; oldest first if unspecified.
+	GroupBy       ExportGrouping         `protobuf:"varint,7,opt,name=group_by,json=groupBy,proto3,enum=webitel_media_exporter.ExportGrouping" json:"group_by,omitempty"` // Groups of screenshots, as with the other formats; a video has no sections, so only sort changes its frames.
+	SessionGapMs  int64                  `protobuf:"varint,8,opt,name=session_gap_ms,json=sessionGapMs,proto3" json:"session_gap_ms,omitempty"`                           // Pause between screenshots that starts a new session with GROUP_BY_SESSION; 10 minutes if 0.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateScreenrecordingVideoRequest) Reset() {
+	*x = CreateScreenrecordingVideoRequest{}
+	mi := &file_pdf_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateScreenrecordingVideoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateScreenrecordingVideoRequest) ProtoMessage() {}
+
+func (x *CreateScreenrecordingVideoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateScreenrecordingVideoRequest.ProtoReflect.Descriptor instead.
+func (*CreateScreenrecordingVideoRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateScreenrecordingVideoRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *CreateScreenrecordingVideoRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *CreateScreenrecordingVideoRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *CreateScreenrecordingVideoRequest) GetFileIds() []int64 {
+	if x != nil {
+		return x.FileIds
+	}
+	return nil
+}
+
+func (x *CreateScreenrecordingVideoRequest) GetVideo() *VideoOptions {
+	if x != nil {
+		return x.Video
+	}
+	return nil
+}
+
+func (x *CreateScreenrecordingVideoRequest) GetSort() ExportSort {
+	if x != nil {
+		return x.Sort
+	}
+	return ExportSort_EXPORT_SORT_UNSPECIFIED
+}
+
+func (x *CreateScreenrecordingVideoRequest) GetGroupBy() ExportGrouping {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ExportGrouping_GROUP_BY_NONE
+}
+
+func (x *CreateScreenrecordingVideoRequest) GetSessionGapMs() int64 {
+	if x != nil {
+		return x.SessionGapMs
+	}
+	return 0
+}
+
+// Request for generating a time-lapse video of the screenshots of a call.
+type CreateCallVideoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CallId        string                 `protobuf:"bytes,1,opt,name=call_id,json=callId,proto3" json:"call_id,omitempty"`            // Unique identifier of the call.
+	From          int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`                             // Start timestamp of the range (Unix millis).
+	To            int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`                                 // End timestamp of the range (Unix millis).
+	FileIds       []int64                `protobuf:"varint,4,rep,packed,name=file_ids,json=fileIds,proto3" json:"file_ids,omitempty"` // Optional: specific file IDs to include in the video.
+	Video         *VideoOptions          `protobuf:"bytes,5,opt,name=video,proto3" json:"video,omitempty"`
+	Sort          ExportSort             `protobuf:"varint,6,opt,name=sort,proto3,enum=webitel_media_exporter.ExportSort" json:"sort,omitempty"`                          // Order of the frames; oldest first if unspecified.
+	GroupBy       ExportGrouping         `protobuf:"varint,7,opt,name=group_by,json=groupBy,proto3,enum=webitel_media_exporter.ExportGrouping" json:"group_by,omitempty"` // Groups of screenshots, as with the other formats; a video has no sections, so only sort changes its frames.
+	SessionGapMs  int64                  `protobuf:"varint,8,opt,name=session_gap_ms,json=sessionGapMs,proto3" json:"session_gap_ms,omitempty"`                           // Pause between screenshots that starts a new session with GROUP_BY_SESSION; 10 minutes if 0.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCallVideoRequest) Reset() {
+	*x = CreateCallVideoRequest{}
+	mi := &file_pdf_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCallVideoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCallVideoRequest) ProtoMessage() {}
+
+func (x *CreateCallVideoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCallVideoRequest.ProtoReflect.Descriptor instead.
+func (*CreateCallVideoRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateCallVideoRequest) GetCallId() string {
+	if x != nil {
+		return x.CallId
+	}
+	return ""
+}
+
+func (x *CreateCallVideoRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *CreateCallVideoRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *CreateCallVideoRequest) GetFileIds() []int64 {
+	if x != nil {
+		return x.FileIds
+	}
+	return nil
+}
+
+func (x *CreateCallVideoRequest) GetVideo() *VideoOptions {
+	if x != nil {
+		return x.Video
+	}
+	return nil
+}
+
+func (x *CreateCallVideoRequest) GetSort() ExportSort {
+	if x != nil {
+		return x.Sort
+	}
+	return ExportSort_EXPORT_SORT_UNSPECIFIED
+}
+
+func (x *CreateCallVideoRequest) GetGroupBy() ExportGrouping {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ExportGrouping_GROUP_BY_NONE
+}
+
+func (x *CreateCallVideoRequest) GetSessionGapMs() int64 {
+	if x != nil {
+		return x.SessionGapMs
+	}
+	return 0
+}
+
+// Request for retrieving paginated export history for an agent.
+type ListScreenrecordingHistoryRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Page    int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"` // Page number (1-based).
+	Size    int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"` // Number of items per page.]
+	// SQL-like ordering: "updated_at desc", "name asc"
+	Sort          string `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScreenrecordingHistoryRequest) Reset() {
+	*x = ListScreenrecordingHistoryRequest{}
+	mi := &file_pdf_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScreenrecordingHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScreenrecordingHistoryRequest) ProtoMessage() {}
+
+func (x *ListScreenrecordingHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScreenrecordingHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListScreenrecordingHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListScreenrecordingHistoryRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *ListScreenrecordingHistoryRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListScreenrecordingHistoryRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ListScreenrecordingHistoryRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+// Request for retrieving paginated export history for a call.
+type ListCallHistoryRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	CallId string                 `protobuf:"bytes,1,opt,name=call_id,json=callId,proto3" json:"call_id,omitempty"`
+	Page   int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"` // Page number (1-based).
+	Size   int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"` // Number of items per page.
+	// SQL-like ordering: "updated_at desc", "name asc"
+	Sort          string `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCallHistoryRequest) Reset() {
+	*x = ListCallHistoryRequest{}
+	mi := &file_pdf_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCallHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCallHistoryRequest) ProtoMessage() {}
+
+func (x *ListCallHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCallHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListCallHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListCallHistoryRequest) GetCallId() string {
+	if x != nil {
+		return x.CallId
+	}
+	return ""
+}
+
+func (x *ListCallHistoryRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListCallHistoryRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ListCallHistoryRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+// Response containing a page of export history records.
+type ListExportsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`  // Current page number.
+	Next          bool                   `protobuf:"varint,2,opt,name=next,proto3" json:"next,omitempty"`  // Indicates if there are more records available.
+	Items         []*ExportRecord        `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"` // List of export records.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExportsResponse) Reset() {
+	*x = ListExportsResponse{}
+	mi := &file_pdf_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExportsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExportsResponse) ProtoMessage() {}
+
+func (x *ListExportsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExportsResponse.ProtoReflect.Descriptor instead.
+func (*ListExportsResponse) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListExportsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListExportsResponse) GetNext() bool {
+	if x != nil {
+		return x.Next
+	}
+	return false
+}
+
+func (x *ListExportsResponse) GetItems() []*ExportRecord {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// Metadata about an export task immediately after creation.
+type ExportTask struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`                             // Unique ID to track the background task.
+	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`                       // Target name of the PDF file.
+	MimeType      string                 `protobuf:"bytes,3,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`                       // MIME type (usually application/pdf).
+	Status        ExportStatus           `protobuf:"varint,4,opt,name=status,proto3,enum=webitel_media_exporter.ExportStatus" json:"status,omitempty"` // Current lifecycle status of the task.
+	Size          int64                  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`                                              // File size in bytes (0 if not yet generated).
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportTask) Reset() {
+	*x = ExportTask{}
+	mi := &file_pdf_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTask) ProtoMessage() {}
+
+func (x *ExportTask) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTask.ProtoReflect.Descriptor instead.
+func (*ExportTask) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExportTask) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ExportTask) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *ExportTask) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *ExportTask) GetStatus() ExportStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ExportStatus_EXPORT_STATUS_UNSPECIFIED
+}
+
+func (x *ExportTask) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// Represents a persisted record of a PDF export.
+type ExportRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                                  // Internal database record ID.
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                               // Display name of the export.
+	FileId        int64                  `protobuf:"varint,3,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`                            // Reference to the file in the storage system.
+	MimeType      string                 `protobuf:"bytes,4,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`                       // MIME type of the generated file.
+	CreatedAt     int64                  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                   // Creation timestamp (Unix millis).
+	UpdatedAt     int64                  `protobuf:"varint,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`                   // Last update timestamp (Unix millis).
+	CreatedBy     int64                  `protobuf:"varint,7,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`                   // User ID who initiated the export.
+	UpdatedBy     int64                  `protobuf:"varint,8,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`                   // User ID who last modified the record.
+	Status        ExportStatus           `protobuf:"varint,9,opt,name=status,proto3,enum=webitel_media_exporter.ExportStatus" json:"status,omitempty"` // Final status of the export process.
+	Attempts      int32                  `protobuf:"varint,10,opt,name=attempts,proto3" json:"attempts,omitempty"`                                     // Number of failed generation attempts.
+	LastError     string                 `protobuf:"bytes,11,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`                   // Reason of the last failed attempt, if any.
+	TaskId        string                 `protobuf:"bytes,12,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`                            // ID of the background task that produced the export.
+	Size          int64                  `protobuf:"varint,13,opt,name=size,proto3" json:"size,omitempty"`                                             // File size in bytes (0 if not yet generated).
+	RetryOf       int64                  `protobuf:"varint,14,opt,name=retry_of,json=retryOf,proto3" json:"retry_of,omitempty"`                        // ID of the export record this one retries, if any.
+	TraceHash     string                 `protobuf:"bytes,15,opt,name=trace_hash,json=traceHash,proto3" json:"trace_hash,omitempty"`                   // Hash embedded into the metadata of the exported document, to trace a leaked copy back to this export.
+	Password      string                 `protobuf:"bytes,16,opt,name=password,proto3" json:"password,omitempty"`                                      // Generated password of an encrypted PDF export, set in the first GetExport response to the requester only.
+	Sha256        string                 `protobuf:"bytes,17,opt,name=sha256,proto3" json:"sha256,omitempty"`                                          // SHA-256 of the generated document, checked by VerifyExport.
+	ParentId      int64                  `protobuf:"varint,18,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`                     // ID of the batch export this one is part of, if any.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRecord) Reset() {
+	*x = ExportRecord{}
+	mi := &file_pdf_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRecord) ProtoMessage() {}
+
+func (x *ExportRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRecord.ProtoReflect.Descriptor instead.
+func (*ExportRecord) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ExportRecord) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ExportRecord) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExportRecord) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *ExportRecord) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *ExportRecord) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ExportRecord) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *ExportRecord) GetCreatedBy() int64 {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return 0
+}
+
+func (x *ExportRecord) GetUpdatedBy() int64 {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return 0
+}
+
+func (x *ExportRecord) GetStatus() ExportStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ExportStatus_EXPORT_STATUS_UNSPECIFIED
+}
+
+func (x *ExportRecord) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *ExportRecord) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *ExportRecord) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ExportRecord) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ExportRecord) GetRetryOf() int64 {
+	if x != nil {
+		return x.RetryOf
+	}
+	return 0
+}
+
+func (x *ExportRecord) GetTraceHash() string {
+	if x != nil {
+		return x.TraceHash
+	}
+	return ""
+}
+
+func (x *ExportRecord) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *ExportRecord) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *ExportRecord) GetParentId() int64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+// Part of a document to verify against its export.
+type VerifyExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`      // History record ID of the export, read from the first message.
+	Chunk         []byte                 `protobuf:"bytes,2,opt,name=chunk,proto3" json:"chunk,omitempty"` // Next part of the document.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyExportRequest) Reset() {
+	*x = VerifyExportRequest{}
+	mi := &file_pdf_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyExportRequest) ProtoMessage() {}
+
+func (x *VerifyExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyExportRequest.ProtoReflect.Descriptor instead.
+func (*VerifyExportRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *VerifyExportRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *VerifyExportRequest) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+// Result of checking a document against the digest recorded for its export.
+type VerifyExportResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Valid          bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`                                         // The document is the one the export produced, unmodified.
+	Sha256         string                 `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"`                                        // SHA-256 of the checked document.
+	ExpectedSha256 string                 `protobuf:"bytes,3,opt,name=expected_sha256,json=expectedSha256,proto3" json:"expected_sha256,omitempty"`  // SHA-256 recorded when the export was produced.
+	Size           int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`                                           // Size of the checked document in bytes.
+	SignatureValid bool                   `protobuf:"varint,5,opt,name=signature_valid,json=signatureValid,proto3" json:"signature_valid,omitempty"` // The recorded digest carries a valid signature of the service key.
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *VerifyExportResponse) Reset() {
+	*x = VerifyExportResponse{}
+	mi := &file_pdf_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyExportResponse) ProtoMessage() {}
+
+func (x *VerifyExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyExportResponse.ProtoReflect.Descriptor instead.
+func (*VerifyExportResponse) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *VerifyExportResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyExportResponse) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *VerifyExportResponse) GetExpectedSha256() string {
+	if x != nil {
+		return x.ExpectedSha256
+	}
+	return ""
+}
+
+func (x *VerifyExportResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *VerifyExportResponse) GetSignatureValid() bool {
+	if x != nil {
+		return x.SignatureValid
+	}
+	return false
+}
+
+// Request to get an export by the task ID returned on creation.
+type GetExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExportRequest) Reset() {
+	*x = GetExportRequest{}
+	mi := &file_pdf_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExportRequest) ProtoMessage() {}
+
+func (x *GetExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExportRequest.ProtoReflect.Descriptor instead.
+func (*GetExportRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetExportRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+// Request to get an export by its history record ID.
+type GetExportByHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExportByHistoryRequest) Reset() {
+	*x = GetExportByHistoryRequest{}
+	mi := &file_pdf_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExportByHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExportByHistoryRequest) ProtoMessage() {}
+
+func (x *GetExportByHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExportByHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetExportByHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetExportByHistoryRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Request to watch the progress of an export task.
+type WatchExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchExportRequest) Reset() {
+	*x = WatchExportRequest{}
+	mi := &file_pdf_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchExportRequest) ProtoMessage() {}
+
+func (x *WatchExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchExportRequest.ProtoReflect.Descriptor instead.
+func (*WatchExportRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *WatchExportRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+// Progress event of an export task.
+type ExportProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Status        ExportStatus           `protobuf:"varint,2,opt,name=status,proto3,enum=webitel_media_exporter.ExportStatus" json:"status,omitempty"` // Lifecycle status of the task.
+	Stage         ExportStage            `protobuf:"varint,3,opt,name=stage,proto3,enum=webitel_media_exporter.ExportStage" json:"stage,omitempty"`    // Current stage of the pipeline.
+	Current       int64                  `protobuf:"varint,4,opt,name=current,proto3" json:"current,omitempty"`                                        // Units processed in the current stage.
+	Total         int64                  `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`                                            // Total units of the current stage (0 if unknown).
+	FileId        int64                  `protobuf:"varint,6,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`                            // Reference to the generated file once done.
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`                                             // Failure reason, if any.
+	Timestamp     int64                  `protobuf:"varint,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                    // Event time (Unix millis).
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportProgress) Reset() {
+	*x = ExportProgress{}
+	mi := &file_pdf_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportProgress) ProtoMessage() {}
+
+func (x *ExportProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportProgress.ProtoReflect.Descriptor instead.
+func (*ExportProgress) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ExportProgress) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ExportProgress) GetStatus() ExportStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ExportStatus_EXPORT_STATUS_UNSPECIFIED
+}
+
+func (x *ExportProgress) GetStage() ExportStage {
+	if x != nil {
+		return x.Stage
+	}
+	return ExportStage_EXPORT_STAGE_UNSPECIFIED
+}
+
+func (x *ExportProgress) GetCurrent() int64 {
+	if x != nil {
+		return x.Current
+	}
+	return 0
+}
+
+func (x *ExportProgress) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ExportProgress) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *ExportProgress) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ExportProgress) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+// Request to cancel an export task.
+type CancelExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelExportRequest) Reset() {
+	*x = CancelExportRequest{}
+	mi := &file_pdf_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelExportRequest) ProtoMessage() {}
+
+func (x *CancelExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelExportRequest.ProtoReflect.Descriptor instead.
+func (*CancelExportRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CancelExportRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+// Request to retry an export by its history record ID.
+type RetryExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryExportRequest) Reset() {
+	*x = RetryExportRequest{}
+	mi := &file_pdf_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryExportRequest) ProtoMessage() {}
+
+func (x *RetryExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryExportRequest.ProtoReflect.Descriptor instead.
+func (*RetryExportRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RetryExportRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Request to delete a history record.
+type DeleteExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // ID of the record to remove.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateScreenrecordingRequest) Reset() {
-	*x = CreateScreenrecordingRequest{}
-	mi := &file_pdf_proto_msgTypes[0]
+func (x *DeleteExportRequest) Reset() {
+	*x = DeleteExportRequest{}
+	mi := &file_pdf_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateScreenrecordingRequest) String() string {
+func (x *DeleteExportRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateScreenrecordingRequest) ProtoMessage() {}
+func (*DeleteExportRequest) ProtoMessage() {}
 
-func (x *CreateScreenrecordingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pdf_proto_msgTypes[0]
+func (x *DeleteExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -114,65 +2695,163 @@ func (x *CreateScreenrecordingRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateScreenrecordingRequest.ProtoReflect.Descriptor instead.
-func (*CreateScreenrecordingRequest) Descriptor() ([]byte, []int) {
-	return file_pdf_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use DeleteExportRequest.ProtoReflect.Descriptor instead.
+func (*DeleteExportRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *CreateScreenrecordingRequest) GetAgentId() int64 {
+func (x *DeleteExportRequest) GetId() int64 {
 	if x != nil {
-		return x.AgentId
+		return x.Id
 	}
 	return 0
 }
 
-func (x *CreateScreenrecordingRequest) GetFrom() int64 {
+// Response confirming the deletion of a record.
+type DeleteExportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // ID of the deleted record.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteExportResponse) Reset() {
+	*x = DeleteExportResponse{}
+	mi := &file_pdf_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteExportResponse) ProtoMessage() {}
+
+func (x *DeleteExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[26]
 	if x != nil {
-		return x.From
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteExportResponse.ProtoReflect.Descriptor instead.
+func (*DeleteExportResponse) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DeleteExportResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
 	}
 	return 0
 }
 
-func (x *CreateScreenrecordingRequest) GetTo() int64 {
+// Time range of every run of a schedule, relative to the time of the run in the time zone of the schedule.
+type ScheduleRange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Unit  ScheduleRangeUnit      `protobuf:"varint,1,opt,name=unit,proto3,enum=webitel_media_exporter.ScheduleRangeUnit" json:"unit,omitempty"`
+	Count int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"` // Number of units in the range; 1 if 0.
+	// Ends the range at the time of the run. Otherwise the range is made of whole units and ends at
+	// the start of the current one, e.g. "last week" for a run on Monday with unit WEEK and count 1.
+	Rolling       bool `protobuf:"varint,3,opt,name=rolling,proto3" json:"rolling,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleRange) Reset() {
+	*x = ScheduleRange{}
+	mi := &file_pdf_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleRange) ProtoMessage() {}
+
+func (x *ScheduleRange) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[27]
 	if x != nil {
-		return x.To
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleRange.ProtoReflect.Descriptor instead.
+func (*ScheduleRange) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ScheduleRange) GetUnit() ScheduleRangeUnit {
+	if x != nil {
+		return x.Unit
+	}
+	return ScheduleRangeUnit_SCHEDULE_RANGE_UNIT_UNSPECIFIED
+}
+
+func (x *ScheduleRange) GetCount() int32 {
+	if x != nil {
+		return x.Count
 	}
 	return 0
 }
 
-func (x *CreateScreenrecordingRequest) GetFileIds() []int64 {
+func (x *ScheduleRange) GetRolling() bool {
 	if x != nil {
-		return x.FileIds
+		return x.Rolling
 	}
-	return nil
+	return false
 }
 
-// Request for generating a call media PDF.
-type CreateCallExportRequest struct {
+// What an export schedule exports, and when.
+type ExportScheduleSpec struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CallId        string                 `protobuf:"bytes,1,opt,name=call_id,json=callId,proto3" json:"call_id,omitempty"`            // Unique identifier of the call.
-	From          int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`                             // Start timestamp of the range (Unix millis).
-	To            int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`                                 // End timestamp of the range (Unix millis).
-	FileIds       []int64                `protobuf:"varint,5,rep,packed,name=file_ids,json=fileIds,proto3" json:"file_ids,omitempty"` // Optional: specific file IDs to include in the PDF.
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Enabled       bool                   `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Cron          string                 `protobuf:"bytes,3,opt,name=cron,proto3" json:"cron,omitempty"`         // Standard five-field cron expression, e.g. "0 6 * * MON", or a descriptor like "@daily".
+	Timezone      string                 `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"` // IANA time zone of the cron expression and the range; UTC if empty.
+	Range         *ScheduleRange         `protobuf:"bytes,5,opt,name=range,proto3" json:"range,omitempty"`
+	CatchUp       ScheduleCatchUp        `protobuf:"varint,6,opt,name=catch_up,json=catchUp,proto3,enum=webitel_media_exporter.ScheduleCatchUp" json:"catch_up,omitempty"`
+	AgentIds      []int64                `protobuf:"varint,7,rep,packed,name=agent_ids,json=agentIds,proto3" json:"agent_ids,omitempty"` // Agents to export.
+	TeamIds       []int64                `protobuf:"varint,8,rep,packed,name=team_ids,json=teamIds,proto3" json:"team_ids,omitempty"`    // Teams whose agents are exported, resolved on every run.
+	Format        BatchExportFormat      `protobuf:"varint,9,opt,name=format,proto3,enum=webitel_media_exporter.BatchExportFormat" json:"format,omitempty"`
+	Pdf           *PdfOptions            `protobuf:"bytes,10,opt,name=pdf,proto3" json:"pdf,omitempty"`     // Optional: page options of PDF exports. A password cannot be set, leave it empty to generate one per export.
+	Video         *VideoOptions          `protobuf:"bytes,11,opt,name=video,proto3" json:"video,omitempty"` // Optional: options of video exports; the container is taken from format.
+	Sort          ExportSort             `protobuf:"varint,12,opt,name=sort,proto3,enum=webitel_media_exporter.ExportSort" json:"sort,omitempty"`
+	GroupBy       ExportGrouping         `protobuf:"varint,13,opt,name=group_by,json=groupBy,proto3,enum=webitel_media_exporter.ExportGrouping" json:"group_by,omitempty"`
+	SessionGapMs  int64                  `protobuf:"varint,14,opt,name=session_gap_ms,json=sessionGapMs,proto3" json:"session_gap_ms,omitempty"`
+	Combine       bool                   `protobuf:"varint,15,opt,name=combine,proto3" json:"combine,omitempty"` // Pack the files of every run into one ZIP archive.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateCallExportRequest) Reset() {
-	*x = CreateCallExportRequest{}
-	mi := &file_pdf_proto_msgTypes[1]
+func (x *ExportScheduleSpec) Reset() {
+	*x = ExportScheduleSpec{}
+	mi := &file_pdf_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateCallExportRequest) String() string {
+func (x *ExportScheduleSpec) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateCallExportRequest) ProtoMessage() {}
+func (*ExportScheduleSpec) ProtoMessage() {}
 
-func (x *CreateCallExportRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pdf_proto_msgTypes[1]
+func (x *ExportScheduleSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -183,66 +2862,148 @@ func (x *CreateCallExportRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateCallExportRequest.ProtoReflect.Descriptor instead.
-func (*CreateCallExportRequest) Descriptor() ([]byte, []int) {
-	return file_pdf_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use ExportScheduleSpec.ProtoReflect.Descriptor instead.
+func (*ExportScheduleSpec) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *CreateCallExportRequest) GetCallId() string {
+func (x *ExportScheduleSpec) GetName() string {
 	if x != nil {
-		return x.CallId
+		return x.Name
 	}
 	return ""
 }
 
-func (x *CreateCallExportRequest) GetFrom() int64 {
+func (x *ExportScheduleSpec) GetEnabled() bool {
 	if x != nil {
-		return x.From
+		return x.Enabled
 	}
-	return 0
+	return false
 }
 
-func (x *CreateCallExportRequest) GetTo() int64 {
+func (x *ExportScheduleSpec) GetCron() string {
 	if x != nil {
-		return x.To
+		return x.Cron
 	}
-	return 0
+	return ""
 }
 
-func (x *CreateCallExportRequest) GetFileIds() []int64 {
+func (x *ExportScheduleSpec) GetTimezone() string {
 	if x != nil {
-		return x.FileIds
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *ExportScheduleSpec) GetRange() *ScheduleRange {
+	if x != nil {
+		return x.Range
 	}
 	return nil
 }
 
-// Request for retrieving paginated export history for an agent.
-type ListScreenrecordingHistoryRequest struct {
-	state   protoimpl.MessageState `protogen:"open.v1"`
-	AgentId int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	Page    int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"` // Page number (1-based).
-	Size    int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"` // Number of items per page.]
-	// SQL-like ordering: "updated_at desc", "name asc"
-	Sort          string `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+func (x *ExportScheduleSpec) GetCatchUp() ScheduleCatchUp {
+	if x != nil {
+		return x.CatchUp
+	}
+	return ScheduleCatchUp_SCHEDULE_CATCH_UP_UNSPECIFIED
+}
+
+func (x *ExportScheduleSpec) GetAgentIds() []int64 {
+	if x != nil {
+		return x.AgentIds
+	}
+	return nil
+}
+
+func (x *ExportScheduleSpec) GetTeamIds() []int64 {
+	if x != nil {
+		return x.TeamIds
+	}
+	return nil
+}
+
+func (x *ExportScheduleSpec) GetFormat() BatchExportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return BatchExportFormat_BATCH_EXPORT_FORMAT_UNSPECIFIED
+}
+
+func (x *ExportScheduleSpec) GetPdf() *PdfOptions {
+	if x != nil {
+		return x.Pdf
+	}
+	return nil
+}
+
+func (x *ExportScheduleSpec) GetVideo() *VideoOptions {
+	if x != nil {
+		return x.Video
+	}
+	return nil
+}
+
+func (x *ExportScheduleSpec) GetSort() ExportSort {
+	if x != nil {
+		return x.Sort
+	}
+	return ExportSort_EXPORT_SORT_UNSPECIFIED
+}
+
+func (x *ExportScheduleSpec) GetGroupBy() ExportGrouping {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ExportGrouping_GROUP_BY_NONE
+}
+
+func (x *ExportScheduleSpec) GetSessionGapMs() int64 {
+	if x != nil {
+		return x.SessionGapMs
+	}
+	return 0
+}
+
+func (x *ExportScheduleSpec) GetCombine() bool {
+	if x != nil {
+		return x.Combine
+	}
+	return false
+}
+
+// A persisted export schedule.
+type ExportSchedule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Spec          *ExportScheduleSpec    `protobuf:"bytes,2,opt,name=spec,proto3" json:"spec,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`     // Unix millis.
+	UpdatedAt     int64                  `protobuf:"varint,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`     // Unix millis.
+	CreatedBy     int64                  `protobuf:"varint,5,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`     // User ID who created the schedule.
+	UpdatedBy     int64                  `protobuf:"varint,6,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`     // User ID who last saved the schedule, on whose behalf it runs.
+	NextRunAt     int64                  `protobuf:"varint,7,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"`   // Next occurrence of the cron expression (Unix millis); 0 if disabled.
+	LastRunAt     int64                  `protobuf:"varint,8,opt,name=last_run_at,json=lastRunAt,proto3" json:"last_run_at,omitempty"`   // Occurrence of the last run (Unix millis); 0 if it never ran.
+	LastTaskId    string                 `protobuf:"bytes,9,opt,name=last_task_id,json=lastTaskId,proto3" json:"last_task_id,omitempty"` // Task ID of the batch export of the last run.
+	LastError     string                 `protobuf:"bytes,10,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`     // Why the last run failed, if it did.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListScreenrecordingHistoryRequest) Reset() {
-	*x = ListScreenrecordingHistoryRequest{}
-	mi := &file_pdf_proto_msgTypes[2]
+func (x *ExportSchedule) Reset() {
+	*x = ExportSchedule{}
+	mi := &file_pdf_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListScreenrecordingHistoryRequest) String() string {
+func (x *ExportSchedule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListScreenrecordingHistoryRequest) ProtoMessage() {}
+func (*ExportSchedule) ProtoMessage() {}
 
-func (x *ListScreenrecordingHistoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pdf_proto_msgTypes[2]
+func (x *ExportSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -253,66 +3014,104 @@ func (x *ListScreenrecordingHistoryRequest) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListScreenrecordingHistoryRequest.ProtoReflect.Descriptor instead.
-func (*ListScreenrecordingHistoryRequest) Descriptor() ([]byte, []int) {
-	return file_pdf_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ExportSchedule.ProtoReflect.Descriptor instead.
+func (*ExportSchedule) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ExportSchedule) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ExportSchedule) GetSpec() *ExportScheduleSpec {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+func (x *ExportSchedule) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ExportSchedule) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *ExportSchedule) GetCreatedBy() int64 {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return 0
+}
+
+func (x *ExportSchedule) GetUpdatedBy() int64 {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return 0
 }
 
-func (x *ListScreenrecordingHistoryRequest) GetAgentId() int64 {
+func (x *ExportSchedule) GetNextRunAt() int64 {
 	if x != nil {
-		return x.AgentId
+		return x.NextRunAt
 	}
 	return 0
 }
 
-func (x *ListScreenrecordingHistoryRequest) GetPage() int32 {
+func (x *ExportSchedule) GetLastRunAt() int64 {
 	if x != nil {
-		return x.Page
+		return x.LastRunAt
 	}
 	return 0
 }
 
-func (x *ListScreenrecordingHistoryRequest) GetSize() int32 {
+func (x *ExportSchedule) GetLastTaskId() string {
 	if x != nil {
-		return x.Size
+		return x.LastTaskId
 	}
-	return 0
+	return ""
 }
 
-func (x *ListScreenrecordingHistoryRequest) GetSort() string {
+func (x *ExportSchedule) GetLastError() string {
 	if x != nil {
-		return x.Sort
+		return x.LastError
 	}
 	return ""
 }
 
-// Request for retrieving paginated export history for a call.
-type ListCallHistoryRequest struct {
-	state  protoimpl.MessageState `protogen:"open.v1"`
-	CallId string                 `protobuf:"bytes,1,opt,name=call_id,json=callId,proto3" json:"call_id,omitempty"`
-	Page   int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"` // Page number (1-based).
-	Size   int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"` // Number of items per page.
-	// SQL-like ordering: "updated_at desc", "name asc"
-	Sort          string `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+// Request to create an export schedule.
+type CreateExportScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Spec          *ExportScheduleSpec    `protobuf:"bytes,1,opt,name=spec,proto3" json:"spec,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListCallHistoryRequest) Reset() {
-	*x = ListCallHistoryRequest{}
-	mi := &file_pdf_proto_msgTypes[3]
+func (x *CreateExportScheduleRequest) Reset() {
+	*x = CreateExportScheduleRequest{}
+	mi := &file_pdf_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListCallHistoryRequest) String() string {
+func (x *CreateExportScheduleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListCallHistoryRequest) ProtoMessage() {}
+func (*CreateExportScheduleRequest) ProtoMessage() {}
 
-func (x *ListCallHistoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pdf_proto_msgTypes[3]
+func (x *CreateExportScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -323,64 +3122,42 @@ func (x *ListCallHistoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListCallHistoryRequest.ProtoReflect.Descriptor instead.
-func (*ListCallHistoryRequest) Descriptor() ([]byte, []int) {
-	return file_pdf_proto_rawDescGZIP(), []int{3}
-}
-
-func (x *ListCallHistoryRequest) GetCallId() string {
-	if x != nil {
-		return x.CallId
-	}
-	return ""
-}
-
-func (x *ListCallHistoryRequest) GetPage() int32 {
-	if x != nil {
-		return x.Page
-	}
-	return 0
-}
-
-func (x *ListCallHistoryRequest) GetSize() int32 {
-	if x != nil {
-		return x.Size
-	}
-	return 0
+// Deprecated: Use CreateExportScheduleRequest.ProtoReflect.Descriptor instead.
+func (*CreateExportScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *ListCallHistoryRequest) GetSort() string {
+func (x *CreateExportScheduleRequest) GetSpec() *ExportScheduleSpec {
 	if x != nil {
-		return x.Sort
+		return x.Spec
 	}
-	return ""
+	return nil
 }
 
-// Response containing a page of export history records.
-type ListExportsResponse struct {
+// Request for a page of the export schedules of the domain.
+type ListExportSchedulesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`  // Current page number.
-	Next          bool                   `protobuf:"varint,2,opt,name=next,proto3" json:"next,omitempty"`  // Indicates if there are more records available.
-	Items         []*ExportRecord        `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"` // List of export records.
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"` // Page number (1-based).
+	Size          int32                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"` // Number of items per page.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListExportsResponse) Reset() {
-	*x = ListExportsResponse{}
-	mi := &file_pdf_proto_msgTypes[4]
+func (x *ListExportSchedulesRequest) Reset() {
+	*x = ListExportSchedulesRequest{}
+	mi := &file_pdf_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListExportsResponse) String() string {
+func (x *ListExportSchedulesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListExportsResponse) ProtoMessage() {}
+func (*ListExportSchedulesRequest) ProtoMessage() {}
 
-func (x *ListExportsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pdf_proto_msgTypes[4]
+func (x *ListExportSchedulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -391,59 +3168,50 @@ func (x *ListExportsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListExportsResponse.ProtoReflect.Descriptor instead.
-func (*ListExportsResponse) Descriptor() ([]byte, []int) {
-	return file_pdf_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ListExportSchedulesRequest.ProtoReflect.Descriptor instead.
+func (*ListExportSchedulesRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *ListExportsResponse) GetPage() int32 {
+func (x *ListExportSchedulesRequest) GetPage() int32 {
 	if x != nil {
 		return x.Page
 	}
 	return 0
 }
 
-func (x *ListExportsResponse) GetNext() bool {
-	if x != nil {
-		return x.Next
-	}
-	return false
-}
-
-func (x *ListExportsResponse) GetItems() []*ExportRecord {
+func (x *ListExportSchedulesRequest) GetSize() int32 {
 	if x != nil {
-		return x.Items
+		return x.Size
 	}
-	return nil
+	return 0
 }
 
-// Metadata about an export task immediately after creation.
-type ExportTask struct {
+// Response containing a page of export schedules.
+type ListExportSchedulesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`                             // Unique ID to track the background task.
-	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`                       // Target name of the PDF file.
-	MimeType      string                 `protobuf:"bytes,3,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`                       // MIME type (usually application/pdf).
-	Status        ExportStatus           `protobuf:"varint,4,opt,name=status,proto3,enum=webitel_media_exporter.ExportStatus" json:"status,omitempty"` // Current lifecycle status of the task.
-	Size          int64                  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`                                              // File size in bytes (0 if not yet generated).
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Next          bool                   `protobuf:"varint,2,opt,name=next,proto3" json:"next,omitempty"`
+	Items         []*ExportSchedule      `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExportTask) Reset() {
-	*x = ExportTask{}
-	mi := &file_pdf_proto_msgTypes[5]
+func (x *ListExportSchedulesResponse) Reset() {
+	*x = ListExportSchedulesResponse{}
+	mi := &file_pdf_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExportTask) String() string {
+func (x *ListExportSchedulesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExportTask) ProtoMessage() {}
+func (*ListExportSchedulesResponse) ProtoMessage() {}
 
-func (x *ExportTask) ProtoReflect() protoreflect.Message {
-	mi := &file_pdf_proto_msgTypes[5]
+func (x *ListExportSchedulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -454,77 +3222,55 @@ func (x *ExportTask) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExportTask.ProtoReflect.Descriptor instead.
-func (*ExportTask) Descriptor() ([]byte, []int) {
-	return file_pdf_proto_rawDescGZIP(), []int{5}
-}
-
-func (x *ExportTask) GetTaskId() string {
-	if x != nil {
-		return x.TaskId
-	}
-	return ""
-}
-
-func (x *ExportTask) GetFileName() string {
-	if x != nil {
-		return x.FileName
-	}
-	return ""
+// Deprecated: Use ListExportSchedulesResponse.ProtoReflect.Descriptor instead.
+func (*ListExportSchedulesResponse) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *ExportTask) GetMimeType() string {
+func (x *ListExportSchedulesResponse) GetPage() int32 {
 	if x != nil {
-		return x.MimeType
+		return x.Page
 	}
-	return ""
+	return 0
 }
 
-func (x *ExportTask) GetStatus() ExportStatus {
+func (x *ListExportSchedulesResponse) GetNext() bool {
 	if x != nil {
-		return x.Status
+		return x.Next
 	}
-	return ExportStatus_EXPORT_STATUS_UNSPECIFIED
+	return false
 }
 
-func (x *ExportTask) GetSize() int64 {
+func (x *ListExportSchedulesResponse) GetItems() []*ExportSchedule {
 	if x != nil {
-		return x.Size
+		return x.Items
 	}
-	return 0
+	return nil
 }
 
-// Represents a persisted record of a PDF export.
-type ExportRecord struct {
+// Request to get an export schedule by its ID.
+type GetExportScheduleRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                                  // Internal database record ID.
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                               // Display name of the export.
-	FileId        int64                  `protobuf:"varint,3,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`                            // Reference to the file in the storage system.
-	MimeType      string                 `protobuf:"bytes,4,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`                       // MIME type of the generated file.
-	CreatedAt     int64                  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                   // Creation timestamp (Unix millis).
-	UpdatedAt     int64                  `protobuf:"varint,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`                   // Last update timestamp (Unix millis).
-	CreatedBy     int64                  `protobuf:"varint,7,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`                   // User ID who initiated the export.
-	UpdatedBy     int64                  `protobuf:"varint,8,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`                   // User ID who last modified the record.
-	Status        ExportStatus           `protobuf:"varint,9,opt,name=status,proto3,enum=webitel_media_exporter.ExportStatus" json:"status,omitempty"` // Final status of the export process.
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExportRecord) Reset() {
-	*x = ExportRecord{}
-	mi := &file_pdf_proto_msgTypes[6]
+func (x *GetExportScheduleRequest) Reset() {
+	*x = GetExportScheduleRequest{}
+	mi := &file_pdf_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExportRecord) String() string {
+func (x *GetExportScheduleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExportRecord) ProtoMessage() {}
+func (*GetExportScheduleRequest) ProtoMessage() {}
 
-func (x *ExportRecord) ProtoReflect() protoreflect.Message {
-	mi := &file_pdf_proto_msgTypes[6]
+func (x *GetExportScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -535,97 +3281,94 @@ func (x *ExportRecord) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExportRecord.ProtoReflect.Descriptor instead.
-func (*ExportRecord) Descriptor() ([]byte, []int) {
-	return file_pdf_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use GetExportScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetExportScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *ExportRecord) GetId() int64 {
+func (x *GetExportScheduleRequest) GetId() int64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *ExportRecord) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
+// Request to replace the spec of an export schedule.
+type UpdateExportScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Spec          *ExportScheduleSpec    `protobuf:"bytes,2,opt,name=spec,proto3" json:"spec,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExportRecord) GetFileId() int64 {
-	if x != nil {
-		return x.FileId
-	}
-	return 0
+func (x *UpdateExportScheduleRequest) Reset() {
+	*x = UpdateExportScheduleRequest{}
+	mi := &file_pdf_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExportRecord) GetMimeType() string {
-	if x != nil {
-		return x.MimeType
-	}
-	return ""
+func (x *UpdateExportScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *ExportRecord) GetCreatedAt() int64 {
-	if x != nil {
-		return x.CreatedAt
-	}
-	return 0
-}
+func (*UpdateExportScheduleRequest) ProtoMessage() {}
 
-func (x *ExportRecord) GetUpdatedAt() int64 {
+func (x *UpdateExportScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[34]
 	if x != nil {
-		return x.UpdatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *ExportRecord) GetCreatedBy() int64 {
-	if x != nil {
-		return x.CreatedBy
-	}
-	return 0
+// Deprecated: Use UpdateExportScheduleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateExportScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *ExportRecord) GetUpdatedBy() int64 {
+func (x *UpdateExportScheduleRequest) GetId() int64 {
 	if x != nil {
-		return x.UpdatedBy
+		return x.Id
 	}
 	return 0
 }
 
-func (x *ExportRecord) GetStatus() ExportStatus {
+func (x *UpdateExportScheduleRequest) GetSpec() *ExportScheduleSpec {
 	if x != nil {
-		return x.Status
+		return x.Spec
 	}
-	return ExportStatus_EXPORT_STATUS_UNSPECIFIED
+	return nil
 }
 
-// Request to delete a history record.
-type DeleteExportRequest struct {
+// Request to delete an export schedule.
+type DeleteExportScheduleRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // ID of the record to remove.
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteExportRequest) Reset() {
-	*x = DeleteExportRequest{}
-	mi := &file_pdf_proto_msgTypes[7]
+func (x *DeleteExportScheduleRequest) Reset() {
+	*x = DeleteExportScheduleRequest{}
+	mi := &file_pdf_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteExportRequest) String() string {
+func (x *DeleteExportScheduleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteExportRequest) ProtoMessage() {}
+func (*DeleteExportScheduleRequest) ProtoMessage() {}
 
-func (x *DeleteExportRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pdf_proto_msgTypes[7]
+func (x *DeleteExportScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -636,41 +3379,41 @@ func (x *DeleteExportRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteExportRequest.ProtoReflect.Descriptor instead.
-func (*DeleteExportRequest) Descriptor() ([]byte, []int) {
-	return file_pdf_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use DeleteExportScheduleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteExportScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *DeleteExportRequest) GetId() int64 {
+func (x *DeleteExportScheduleRequest) GetId() int64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-// Response confirming the deletion of a record.
-type DeleteExportResponse struct {
+// Response confirming the deletion of an export schedule.
+type DeleteExportScheduleResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // ID of the deleted record.
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteExportResponse) Reset() {
-	*x = DeleteExportResponse{}
-	mi := &file_pdf_proto_msgTypes[8]
+func (x *DeleteExportScheduleResponse) Reset() {
+	*x = DeleteExportScheduleResponse{}
+	mi := &file_pdf_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteExportResponse) String() string {
+func (x *DeleteExportScheduleResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteExportResponse) ProtoMessage() {}
+func (*DeleteExportScheduleResponse) ProtoMessage() {}
 
-func (x *DeleteExportResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pdf_proto_msgTypes[8]
+func (x *DeleteExportScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -681,12 +3424,12 @@ func (x *DeleteExportResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteExportResponse.ProtoReflect.Descriptor instead.
-func (*DeleteExportResponse) Descriptor() ([]byte, []int) {
-	return file_pdf_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use DeleteExportScheduleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteExportScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_pdf_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *DeleteExportResponse) GetId() int64 {
+func (x *DeleteExportScheduleResponse) GetId() int64 {
 	if x != nil {
 		return x.Id
 	}
@@ -697,17 +3440,104 @@ var File_pdf_proto protoreflect.FileDescriptor
 
 const file_pdf_proto_rawDesc = "" +
 	"\n" +
-	"\tpdf.proto\x12\x16webitel_media_exporter\x1a\x1cgoogle/api/annotations.proto\"x\n" +
+	"\tpdf.proto\x12\x16webitel_media_exporter\x1a\x1cgoogle/api/annotations.proto\"\xcf\x02\n" +
 	"\x1cCreateScreenrecordingRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
 	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
 	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x19\n" +
-	"\bfile_ids\x18\x04 \x03(\x03R\afileIds\"q\n" +
+	"\bfile_ids\x18\x04 \x03(\x03R\afileIds\x124\n" +
+	"\x03pdf\x18\x05 \x01(\v2\".webitel_media_exporter.PdfOptionsR\x03pdf\x126\n" +
+	"\x04sort\x18\x06 \x01(\x0e2\".webitel_media_exporter.ExportSortR\x04sort\x12A\n" +
+	"\bgroup_by\x18\a \x01(\x0e2&.webitel_media_exporter.ExportGroupingR\agroupBy\x12$\n" +
+	"\x0esession_gap_ms\x18\b \x01(\x03R\fsessionGapMs\"\xc8\x02\n" +
 	"\x17CreateCallExportRequest\x12\x17\n" +
 	"\acall_id\x18\x01 \x01(\tR\x06callId\x12\x12\n" +
 	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
 	"\x02to\x18\x04 \x01(\x03R\x02to\x12\x19\n" +
-	"\bfile_ids\x18\x05 \x03(\x03R\afileIds\"z\n" +
+	"\bfile_ids\x18\x05 \x03(\x03R\afileIds\x124\n" +
+	"\x03pdf\x18\x06 \x01(\v2\".webitel_media_exporter.PdfOptionsR\x03pdf\x126\n" +
+	"\x04sort\x18\a \x01(\x0e2\".webitel_media_exporter.ExportSortR\x04sort\x12A\n" +
+	"\bgroup_by\x18\b \x01(\x0e2&.webitel_media_exporter.ExportGroupingR\agroupBy\x12$\n" +
+	"\x0esession_gap_ms\x18\t \x01(\x03R\fsessionGapMs\"\xf9\x01\n" +
+	"!CreateCallTranscriptExportRequest\x12\x17\n" +
+	"\acall_id\x18\x01 \x01(\tR\x06callId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x19\n" +
+	"\bfile_ids\x18\x04 \x03(\x03R\afileIds\x124\n" +
+	"\x03pdf\x18\x05 \x01(\v2\".webitel_media_exporter.PdfOptionsR\x03pdf\x12$\n" +
+	"\x0ewindow_from_ms\x18\x06 \x01(\x03R\fwindowFromMs\x12 \n" +
+	"\fwindow_to_ms\x18\a \x01(\x03R\n" +
+	"windowToMs\"\x93\x01\n" +
+	"\x1eCreateCallDossierExportRequest\x12\x17\n" +
+	"\acall_id\x18\x01 \x01(\tR\x06callId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x124\n" +
+	"\x03pdf\x18\x04 \x01(\v2\".webitel_media_exporter.PdfOptionsR\x03pdf\"\xb5\x01\n" +
+	"\x1cCreateCallAudioExportRequest\x12\x19\n" +
+	"\bcall_ids\x18\x01 \x03(\tR\acallIds\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x19\n" +
+	"\bfile_ids\x18\x04 \x03(\x03R\afileIds\x12;\n" +
+	"\x06format\x18\x05 \x01(\x0e2#.webitel_media_exporter.AudioFormatR\x06format\"\xe6\x03\n" +
+	"\x18CreateBatchExportRequest\x12\x1b\n" +
+	"\tagent_ids\x18\x01 \x03(\x03R\bagentIds\x12\x19\n" +
+	"\bcall_ids\x18\x02 \x03(\tR\acallIds\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12A\n" +
+	"\x06format\x18\x05 \x01(\x0e2).webitel_media_exporter.BatchExportFormatR\x06format\x124\n" +
+	"\x03pdf\x18\x06 \x01(\v2\".webitel_media_exporter.PdfOptionsR\x03pdf\x12:\n" +
+	"\x05video\x18\a \x01(\v2$.webitel_media_exporter.VideoOptionsR\x05video\x126\n" +
+	"\x04sort\x18\b \x01(\x0e2\".webitel_media_exporter.ExportSortR\x04sort\x12A\n" +
+	"\bgroup_by\x18\t \x01(\x0e2&.webitel_media_exporter.ExportGroupingR\agroupBy\x12$\n" +
+	"\x0esession_gap_ms\x18\n" +
+	" \x01(\x03R\fsessionGapMs\x12\x18\n" +
+	"\acombine\x18\v \x01(\bR\acombine\"\x89\x01\n" +
+	"\x0fBatchExportTask\x128\n" +
+	"\x05batch\x18\x01 \x01(\v2\".webitel_media_exporter.ExportTaskR\x05batch\x12<\n" +
+	"\aexports\x18\x02 \x03(\v2\".webitel_media_exporter.ExportTaskR\aexports\"\xc6\x04\n" +
+	"\n" +
+	"PdfOptions\x12\x1a\n" +
+	"\bcaptions\x18\x01 \x01(\bR\bcaptions\x12#\n" +
+	"\rheader_footer\x18\x02 \x01(\bR\fheaderFooter\x12\x1a\n" +
+	"\btimezone\x18\x03 \x01(\tR\btimezone\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x1d\n" +
+	"\n" +
+	"cover_page\x18\x05 \x01(\bR\tcoverPage\x12?\n" +
+	"\bcontents\x18\x06 \x01(\x0e2#.webitel_media_exporter.PdfContentsR\bcontents\x12@\n" +
+	"\tpage_size\x18\a \x01(\x0e2#.webitel_media_exporter.PdfPageSizeR\bpageSize\x12H\n" +
+	"\vorientation\x18\b \x01(\x0e2&.webitel_media_exporter.PdfOrientationR\vorientation\x12&\n" +
+	"\x0fimages_per_page\x18\t \x01(\x05R\rimagesPerPage\x12#\n" +
+	"\rcontact_sheet\x18\n" +
+	" \x01(\bR\fcontactSheet\x12\x1c\n" +
+	"\twatermark\x18\v \x01(\bR\twatermark\x12E\n" +
+	"\n" +
+	"encryption\x18\f \x01(\v2%.webitel_media_exporter.PdfEncryptionR\n" +
+	"encryption\x12'\n" +
+	"\x0fsigned_manifest\x18\r \x01(\bR\x0esignedManifest\"+\n" +
+	"\rPdfEncryption\x12\x1a\n" +
+	"\bpassword\x18\x01 \x01(\tR\bpassword\"\xa4\x01\n" +
+	"\fVideoOptions\x12;\n" +
+	"\x06format\x18\x01 \x01(\x0e2#.webitel_media_exporter.VideoFormatR\x06format\x12*\n" +
+	"\x11frame_duration_ms\x18\x02 \x01(\x03R\x0fframeDurationMs\x12+\n" +
+	"\x11timestamp_overlay\x18\x03 \x01(\bR\x10timestampOverlay\"\xda\x02\n" +
+	"!CreateScreenrecordingVideoRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x19\n" +
+	"\bfile_ids\x18\x04 \x03(\x03R\afileIds\x12:\n" +
+	"\x05video\x18\x05 \x01(\v2$.webitel_media_exporter.VideoOptionsR\x05video\x126\n" +
+	"\x04sort\x18\x06 \x01(\x0e2\".webitel_media_exporter.ExportSortR\x04sort\x12A\n" +
+	"\bgroup_by\x18\a \x01(\x0e2&.webitel_media_exporter.ExportGroupingR\agroupBy\x12$\n" +
+	"\x0esession_gap_ms\x18\b \x01(\x03R\fsessionGapMs\"\xcd\x02\n" +
+	"\x16CreateCallVideoRequest\x12\x17\n" +
+	"\acall_id\x18\x01 \x01(\tR\x06callId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x19\n" +
+	"\bfile_ids\x18\x04 \x03(\x03R\afileIds\x12:\n" +
+	"\x05video\x18\x05 \x01(\v2$.webitel_media_exporter.VideoOptionsR\x05video\x126\n" +
+	"\x04sort\x18\x06 \x01(\x0e2\".webitel_media_exporter.ExportSortR\x04sort\x12A\n" +
+	"\bgroup_by\x18\a \x01(\x0e2&.webitel_media_exporter.ExportGroupingR\agroupBy\x12$\n" +
+	"\x0esession_gap_ms\x18\b \x01(\x03R\fsessionGapMs\"z\n" +
 	"!ListScreenrecordingHistoryRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
 	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x12\n" +
@@ -728,7 +3558,7 @@ const file_pdf_proto_rawDesc = "" +
 	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x1b\n" +
 	"\tmime_type\x18\x03 \x01(\tR\bmimeType\x12<\n" +
 	"\x06status\x18\x04 \x01(\x0e2$.webitel_media_exporter.ExportStatusR\x06status\x12\x12\n" +
-	"\x04size\x18\x05 \x01(\x03R\x04size\"\xa2\x02\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\"\x95\x04\n" +
 	"\fExportRecord\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x17\n" +
@@ -742,11 +3572,108 @@ const file_pdf_proto_rawDesc = "" +
 	"created_by\x18\a \x01(\x03R\tcreatedBy\x12\x1d\n" +
 	"\n" +
 	"updated_by\x18\b \x01(\x03R\tupdatedBy\x12<\n" +
-	"\x06status\x18\t \x01(\x0e2$.webitel_media_exporter.ExportStatusR\x06status\"%\n" +
+	"\x06status\x18\t \x01(\x0e2$.webitel_media_exporter.ExportStatusR\x06status\x12\x1a\n" +
+	"\battempts\x18\n" +
+	" \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\v \x01(\tR\tlastError\x12\x17\n" +
+	"\atask_id\x18\f \x01(\tR\x06taskId\x12\x12\n" +
+	"\x04size\x18\r \x01(\x03R\x04size\x12\x19\n" +
+	"\bretry_of\x18\x0e \x01(\x03R\aretryOf\x12\x1d\n" +
+	"\n" +
+	"trace_hash\x18\x0f \x01(\tR\ttraceHash\x12\x1a\n" +
+	"\bpassword\x18\x10 \x01(\tR\bpassword\x12\x16\n" +
+	"\x06sha256\x18\x11 \x01(\tR\x06sha256\x12\x1b\n" +
+	"\tparent_id\x18\x12 \x01(\x03R\bparentId\";\n" +
+	"\x13VerifyExportRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05chunk\x18\x02 \x01(\fR\x05chunk\"\xaa\x01\n" +
+	"\x14VerifyExportResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x16\n" +
+	"\x06sha256\x18\x02 \x01(\tR\x06sha256\x12'\n" +
+	"\x0fexpected_sha256\x18\x03 \x01(\tR\x0eexpectedSha256\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12'\n" +
+	"\x0fsignature_valid\x18\x05 \x01(\bR\x0esignatureValid\"+\n" +
+	"\x10GetExportRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"+\n" +
+	"\x19GetExportByHistoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"-\n" +
+	"\x12WatchExportRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"\x9f\x02\n" +
+	"\x0eExportProgress\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12<\n" +
+	"\x06status\x18\x02 \x01(\x0e2$.webitel_media_exporter.ExportStatusR\x06status\x129\n" +
+	"\x05stage\x18\x03 \x01(\x0e2#.webitel_media_exporter.ExportStageR\x05stage\x12\x18\n" +
+	"\acurrent\x18\x04 \x01(\x03R\acurrent\x12\x14\n" +
+	"\x05total\x18\x05 \x01(\x03R\x05total\x12\x17\n" +
+	"\afile_id\x18\x06 \x01(\x03R\x06fileId\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x1c\n" +
+	"\ttimestamp\x18\b \x01(\x03R\ttimestamp\".\n" +
+	"\x13CancelExportRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"$\n" +
+	"\x12RetryExportRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"%\n" +
 	"\x13DeleteExportRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\"&\n" +
 	"\x14DeleteExportResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id*`\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"~\n" +
+	"\rScheduleRange\x12=\n" +
+	"\x04unit\x18\x01 \x01(\x0e2).webitel_media_exporter.ScheduleRangeUnitR\x04unit\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x18\n" +
+	"\arolling\x18\x03 \x01(\bR\arolling\"\x9b\x05\n" +
+	"\x12ExportScheduleSpec\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aenabled\x18\x02 \x01(\bR\aenabled\x12\x12\n" +
+	"\x04cron\x18\x03 \x01(\tR\x04cron\x12\x1a\n" +
+	"\btimezone\x18\x04 \x01(\tR\btimezone\x12;\n" +
+	"\x05range\x18\x05 \x01(\v2%.webitel_media_exporter.ScheduleRangeR\x05range\x12B\n" +
+	"\bcatch_up\x18\x06 \x01(\x0e2'.webitel_media_exporter.ScheduleCatchUpR\acatchUp\x12\x1b\n" +
+	"\tagent_ids\x18\a \x03(\x03R\bagentIds\x12\x19\n" +
+	"\bteam_ids\x18\b \x03(\x03R\ateamIds\x12A\n" +
+	"\x06format\x18\t \x01(\x0e2).webitel_media_exporter.BatchExportFormatR\x06format\x124\n" +
+	"\x03pdf\x18\n" +
+	" \x01(\v2\".webitel_media_exporter.PdfOptionsR\x03pdf\x12:\n" +
+	"\x05video\x18\v \x01(\v2$.webitel_media_exporter.VideoOptionsR\x05video\x126\n" +
+	"\x04sort\x18\f \x01(\x0e2\".webitel_media_exporter.ExportSortR\x04sort\x12A\n" +
+	"\bgroup_by\x18\r \x01(\x0e2&.webitel_media_exporter.ExportGroupingR\agroupBy\x12$\n" +
+	"\x0esession_gap_ms\x18\x0e \x01(\x03R\fsessionGapMs\x12\x18\n" +
+	"\acombine\x18\x0f \x01(\bR\acombine\"\xdd\x02\n" +
+	"\x0eExportSchedule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12>\n" +
+	"\x04spec\x18\x02 \x01(\v2*.webitel_media_exporter.ExportScheduleSpecR\x04spec\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\x03R\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x05 \x01(\x03R\tcreatedBy\x12\x1d\n" +
+	"\n" +
+	"updated_by\x18\x06 \x01(\x03R\tupdatedBy\x12\x1e\n" +
+	"\vnext_run_at\x18\a \x01(\x03R\tnextRunAt\x12\x1e\n" +
+	"\vlast_run_at\x18\b \x01(\x03R\tlastRunAt\x12 \n" +
+	"\flast_task_id\x18\t \x01(\tR\n" +
+	"lastTaskId\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\n" +
+	" \x01(\tR\tlastError\"]\n" +
+	"\x1bCreateExportScheduleRequest\x12>\n" +
+	"\x04spec\x18\x01 \x01(\v2*.webitel_media_exporter.ExportScheduleSpecR\x04spec\"D\n" +
+	"\x1aListExportSchedulesRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x05R\x04size\"\x83\x01\n" +
+	"\x1bListExportSchedulesResponse\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x12\n" +
+	"\x04next\x18\x02 \x01(\bR\x04next\x12<\n" +
+	"\x05items\x18\x03 \x03(\v2&.webitel_media_exporter.ExportScheduleR\x05items\"*\n" +
+	"\x18GetExportScheduleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"m\n" +
+	"\x1bUpdateExportScheduleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12>\n" +
+	"\x04spec\x18\x02 \x01(\v2*.webitel_media_exporter.ExportScheduleSpecR\x04spec\"-\n" +
+	"\x1bDeleteExportScheduleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\".\n" +
+	"\x1cDeleteExportScheduleResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id*o\n" +
 	"\fExportStatus\x12\x1d\n" +
 	"\x19EXPORT_STATUS_UNSPECIFIED\x10\x00\x12\v\n" +
 	"\aPENDING\x10\x01\x12\x0e\n" +
@@ -754,14 +3681,92 @@ const file_pdf_proto_rawDesc = "" +
 	"PROCESSING\x10\x02\x12\b\n" +
 	"\x04DONE\x10\x03\x12\n" +
 	"\n" +
-	"\x06FAILED\x10\x042\xbb\x06\n" +
+	"\x06FAILED\x10\x04\x12\r\n" +
+	"\tCANCELLED\x10\x05*\xbb\x01\n" +
+	"\vExportStage\x12\x1c\n" +
+	"\x18EXPORT_STAGE_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fSTAGE_QUEUED\x10\x01\x12\x13\n" +
+	"\x0fSTAGE_SEARCHING\x10\x02\x12\x15\n" +
+	"\x11STAGE_DOWNLOADING\x10\x03\x12\x13\n" +
+	"\x0fSTAGE_RENDERING\x10\x04\x12\x13\n" +
+	"\x0fSTAGE_UPLOADING\x10\x05\x12\x13\n" +
+	"\x0fSTAGE_COMPLETED\x10\x06\x12\x11\n" +
+	"\rSTAGE_WAITING\x10\a*n\n" +
+	"\vAudioFormat\x12\x1c\n" +
+	"\x18AUDIO_FORMAT_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10AUDIO_FORMAT_MP3\x10\x01\x12\x14\n" +
+	"\x10AUDIO_FORMAT_WAV\x10\x02\x12\x15\n" +
+	"\x11AUDIO_FORMAT_OPUS\x10\x03*\xad\x01\n" +
+	"\x11BatchExportFormat\x12#\n" +
+	"\x1fBATCH_EXPORT_FORMAT_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17BATCH_EXPORT_FORMAT_PDF\x10\x01\x12\x1b\n" +
+	"\x17BATCH_EXPORT_FORMAT_ZIP\x10\x02\x12\x1b\n" +
+	"\x17BATCH_EXPORT_FORMAT_MP4\x10\x03\x12\x1c\n" +
+	"\x18BATCH_EXPORT_FORMAT_WEBM\x10\x04*g\n" +
+	"\n" +
+	"ExportSort\x12\x1b\n" +
+	"\x17EXPORT_SORT_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12SORT_UPLOADED_DESC\x10\x01\x12\x15\n" +
+	"\x11SORT_UPLOADED_ASC\x10\x02\x12\r\n" +
+	"\tSORT_NAME\x10\x03*^\n" +
+	"\x0eExportGrouping\x12\x11\n" +
+	"\rGROUP_BY_NONE\x10\x00\x12\x11\n" +
+	"\rGROUP_BY_HOUR\x10\x01\x12\x10\n" +
+	"\fGROUP_BY_DAY\x10\x02\x12\x14\n" +
+	"\x10GROUP_BY_SESSION\x10\x03*\\\n" +
+	"\vPdfPageSize\x12\x1d\n" +
+	"\x19PDF_PAGE_SIZE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10PDF_PAGE_SIZE_A4\x10\x01\x12\x18\n" +
+	"\x14PDF_PAGE_SIZE_LETTER\x10\x02*n\n" +
+	"\x0ePdfOrientation\x12\x1f\n" +
+	"\x1bPDF_ORIENTATION_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18PDF_ORIENTATION_PORTRAIT\x10\x01\x12\x1d\n" +
+	"\x19PDF_ORIENTATION_LANDSCAPE\x10\x02*X\n" +
+	"\vPdfContents\x12\x1c\n" +
+	"\x18PDF_CONTENTS_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11PDF_CONTENTS_HOUR\x10\x01\x12\x14\n" +
+	"\x10PDF_CONTENTS_DAY\x10\x02*X\n" +
+	"\vVideoFormat\x12\x1c\n" +
+	"\x18VIDEO_FORMAT_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10VIDEO_FORMAT_MP4\x10\x01\x12\x15\n" +
+	"\x11VIDEO_FORMAT_WEBM\x10\x02*\x9c\x01\n" +
+	"\x11ScheduleRangeUnit\x12#\n" +
+	"\x1fSCHEDULE_RANGE_UNIT_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13SCHEDULE_RANGE_HOUR\x10\x01\x12\x16\n" +
+	"\x12SCHEDULE_RANGE_DAY\x10\x02\x12\x17\n" +
+	"\x13SCHEDULE_RANGE_WEEK\x10\x03\x12\x18\n" +
+	"\x14SCHEDULE_RANGE_MONTH\x10\x04*\x89\x01\n" +
+	"\x0fScheduleCatchUp\x12!\n" +
+	"\x1dSCHEDULE_CATCH_UP_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16SCHEDULE_CATCH_UP_SKIP\x10\x01\x12\x1c\n" +
+	"\x18SCHEDULE_CATCH_UP_LATEST\x10\x02\x12\x19\n" +
+	"\x15SCHEDULE_CATCH_UP_ALL\x10\x032\xb2\x1d\n" +
 	"\n" +
 	"PdfService\x12\xb3\x01\n" +
 	"\x1bCreateScreenrecordingExport\x124.webitel_media_exporter.CreateScreenrecordingRequest\x1a\".webitel_media_exporter.ExportTask\":\x82\xd3\xe4\x93\x024:\x01*\"//agents/{agent_id}/exports/pdf/screenrecordings\x12\xbd\x01\n" +
 	"\x1aListScreenrecordingExports\x129.webitel_media_exporter.ListScreenrecordingHistoryRequest\x1a+.webitel_media_exporter.ListExportsResponse\"7\x82\xd3\xe4\x93\x021\x12//agents/{agent_id}/exports/pdf/screenrecordings\x12\x90\x01\n" +
 	"\x10CreateCallExport\x12/.webitel_media_exporter.CreateCallExportRequest\x1a\".webitel_media_exporter.ExportTask\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/calls/{call_id}/exports/pdf\x12\x94\x01\n" +
-	"\x0fListCallExports\x12..webitel_media_exporter.ListCallHistoryRequest\x1a+.webitel_media_exporter.ListExportsResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/calls/{call_id}/exports/pdf\x12\x8c\x01\n" +
-	"\fDeleteExport\x12+.webitel_media_exporter.DeleteExportRequest\x1a,.webitel_media_exporter.DeleteExportResponse\"!\x82\xd3\xe4\x93\x02\x1b*\x19/exports/pdf/history/{id}B\xba\x01\n" +
+	"\x0fListCallExports\x12..webitel_media_exporter.ListCallHistoryRequest\x1a+.webitel_media_exporter.ListExportsResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/calls/{call_id}/exports/pdf\x12\xb6\x01\n" +
+	"\x1eCreateScreenrecordingZipExport\x124.webitel_media_exporter.CreateScreenrecordingRequest\x1a\".webitel_media_exporter.ExportTask\":\x82\xd3\xe4\x93\x024:\x01*\"//agents/{agent_id}/exports/zip/screenrecordings\x12\x93\x01\n" +
+	"\x13CreateCallZipExport\x12/.webitel_media_exporter.CreateCallExportRequest\x1a\".webitel_media_exporter.ExportTask\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/calls/{call_id}/exports/zip\x12\xab\x01\n" +
+	"\x1aCreateCallTranscriptExport\x129.webitel_media_exporter.CreateCallTranscriptExportRequest\x1a\".webitel_media_exporter.ExportTask\".\x82\xd3\xe4\x93\x02(:\x01*\"#/calls/{call_id}/exports/transcript\x12\xa2\x01\n" +
+	"\x17CreateCallDossierExport\x126.webitel_media_exporter.CreateCallDossierExportRequest\x1a\".webitel_media_exporter.ExportTask\"+\x82\xd3\xe4\x93\x02%:\x01*\" /calls/{call_id}/exports/dossier\x12\x92\x01\n" +
+	"\x15CreateCallAudioExport\x124.webitel_media_exporter.CreateCallAudioExportRequest\x1a\".webitel_media_exporter.ExportTask\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/calls/exports/audio\x12\x89\x01\n" +
+	"\x11CreateBatchExport\x120.webitel_media_exporter.CreateBatchExportRequest\x1a'.webitel_media_exporter.BatchExportTask\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/exports/batch\x12\xbf\x01\n" +
+	" CreateScreenrecordingVideoExport\x129.webitel_media_exporter.CreateScreenrecordingVideoRequest\x1a\".webitel_media_exporter.ExportTask\"<\x82\xd3\xe4\x93\x026:\x01*\"1/agents/{agent_id}/exports/video/screenrecordings\x12\x96\x01\n" +
+	"\x15CreateCallVideoExport\x12..webitel_media_exporter.CreateCallVideoRequest\x1a\".webitel_media_exporter.ExportTask\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/calls/{call_id}/exports/video\x12\x81\x01\n" +
+	"\tGetExport\x12(.webitel_media_exporter.GetExportRequest\x1a$.webitel_media_exporter.ExportRecord\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/exports/pdf/tasks/{task_id}\x12\x90\x01\n" +
+	"\x12GetExportByHistory\x121.webitel_media_exporter.GetExportByHistoryRequest\x1a$.webitel_media_exporter.ExportRecord\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/exports/pdf/history/{id}\x12\x8f\x01\n" +
+	"\vWatchExport\x12*.webitel_media_exporter.WatchExportRequest\x1a&.webitel_media_exporter.ExportProgress\"*\x82\xd3\xe4\x93\x02$\x12\"/exports/pdf/tasks/{task_id}/watch0\x01\x12\x8e\x01\n" +
+	"\fCancelExport\x12+.webitel_media_exporter.CancelExportRequest\x1a$.webitel_media_exporter.ExportRecord\"+\x82\xd3\xe4\x93\x02%\"#/exports/pdf/tasks/{task_id}/cancel\x12\x86\x01\n" +
+	"\vRetryExport\x12*.webitel_media_exporter.RetryExportRequest\x1a\".webitel_media_exporter.ExportTask\"'\x82\xd3\xe4\x93\x02!\"\x1f/exports/pdf/history/{id}/retry\x12\x8b\x01\n" +
+	"\fVerifyExport\x12+.webitel_media_exporter.VerifyExportRequest\x1a,.webitel_media_exporter.VerifyExportResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/exports/pdf/verify(\x01\x12\x8c\x01\n" +
+	"\fDeleteExport\x12+.webitel_media_exporter.DeleteExportRequest\x1a,.webitel_media_exporter.DeleteExportResponse\"!\x82\xd3\xe4\x93\x02\x1b*\x19/exports/pdf/history/{id}\x12\x95\x01\n" +
+	"\x14CreateExportSchedule\x123.webitel_media_exporter.CreateExportScheduleRequest\x1a&.webitel_media_exporter.ExportSchedule\" \x82\xd3\xe4\x93\x02\x1a:\x04spec\"\x12/exports/schedules\x12\x9a\x01\n" +
+	"\x13ListExportSchedules\x122.webitel_media_exporter.ListExportSchedulesRequest\x1a3.webitel_media_exporter.ListExportSchedulesResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/exports/schedules\x12\x8e\x01\n" +
+	"\x11GetExportSchedule\x120.webitel_media_exporter.GetExportScheduleRequest\x1a&.webitel_media_exporter.ExportSchedule\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/exports/schedules/{id}\x12\x9a\x01\n" +
+	"\x14UpdateExportSchedule\x123.webitel_media_exporter.UpdateExportScheduleRequest\x1a&.webitel_media_exporter.ExportSchedule\"%\x82\xd3\xe4\x93\x02\x1f:\x04spec\x1a\x17/exports/schedules/{id}\x12\xa2\x01\n" +
+	"\x14DeleteExportSchedule\x123.webitel_media_exporter.DeleteExportScheduleRequest\x1a4.webitel_media_exporter.DeleteExportScheduleResponse\"\x1f\x82\xd3\xe4\x93\x02\x19*\x17/exports/schedules/{id}B\xba\x01\n" +
 	"\x1acom.webitel_media_exporterB\bPdfProtoP\x01Z\"github.com/webitel/pdf/api/pdf;pdf\xa2\x02\x03WXX\xaa\x02\x14WebitelMediaExporter\xca\x02\x14WebitelMediaExporter\xe2\x02 WebitelMediaExporter\\GPBMetadata\xea\x02\x14WebitelMediaExporterb\x06proto3"
 
 var (
@@ -776,39 +3781,157 @@ func file_pdf_proto_rawDescGZIP() []byte {
 	return file_pdf_proto_rawDescData
 }
 
-var file_pdf_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pdf_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_pdf_proto_enumTypes = make([]protoimpl.EnumInfo, 12)
+var file_pdf_proto_msgTypes = make([]protoimpl.MessageInfo, 37)
 var file_pdf_proto_goTypes = []any{
 	(ExportStatus)(0),                         // 0: webitel_media_exporter.ExportStatus
-	(*CreateScreenrecordingRequest)(nil),      // 1: webitel_media_exporter.CreateScreenrecordingRequest
-	(*CreateCallExportRequest)(nil),           // 2: webitel_media_exporter.CreateCallExportRequest
-	(*ListScreenrecordingHistoryRequest)(nil), // 3: webitel_media_exporter.ListScreenrecordingHistoryRequest
-	(*ListCallHistoryRequest)(nil),            // 4: webitel_media_exporter.ListCallHistoryRequest
-	(*ListExportsResponse)(nil),               // 5: webitel_media_exporter.ListExportsResponse
-	(*ExportTask)(nil),                        // 6: webitel_media_exporter.ExportTask
-	(*ExportRecord)(nil),                      // 7: webitel_media_exporter.ExportRecord
-	(*DeleteExportRequest)(nil),               // 8: webitel_media_exporter.DeleteExportRequest
-	(*DeleteExportResponse)(nil),              // 9: webitel_media_exporter.DeleteExportResponse
+	(ExportStage)(0),                          // 1: webitel_media_exporter.ExportStage
+	(AudioFormat)(0),                          // 2: webitel_media_exporter.AudioFormat
+	(BatchExportFormat)(0),                    // 3: webitel_media_exporter.BatchExportFormat
+	(ExportSort)(0),                           // 4: webitel_media_exporter.ExportSort
+	(ExportGrouping)(0),                       // 5: webitel_media_exporter.ExportGrouping
+	(PdfPageSize)(0),                          // 6: webitel_media_exporter.PdfPageSize
+	(PdfOrientation)(0),                       // 7: webitel_media_exporter.PdfOrientation
+	(PdfContents)(0),                          // 8: webitel_media_exporter.PdfContents
+	(VideoFormat)(0),                          // 9: webitel_media_exporter.VideoFormat
+	(ScheduleRangeUnit)(0),                    // 10: webitel_media_exporter.ScheduleRangeUnit
+	(ScheduleCatchUp)(0),                      // 11: webitel_media_exporter.ScheduleCatchUp
+	(*CreateScreenrecordingRequest)(nil),      // 12: webitel_media_exporter.CreateScreenrecordingRequest
+	(*CreateCallExportRequest)(nil),           // 13: webitel_media_exporter.CreateCallExportRequest
+	(*CreateCallTranscriptExportRequest)(nil), // 14: webitel_media_exporter.CreateCallTranscriptExportRequest
+	(*CreateCallDossierExportRequest)(nil),    // 15: webitel_media_exporter.CreateCallDossierExportRequest
+	(*CreateCallAudioExportRequest)(nil),      // 16: webitel_media_exporter.CreateCallAudioExportRequest
+	(*CreateBatchExportRequest)(nil),          // 17: webitel_media_exporter.CreateBatchExportRequest
+	(*BatchExportTask)(nil),                   // 18: webitel_media_exporter.BatchExportTask
+	(*PdfOptions)(nil),                        // 19: webitel_media_exporter.PdfOptions
+	(*PdfEncryption)(nil),                     // 20: webitel_media_exporter.PdfEncryption
+	(*VideoOptions)(nil),                      // 21: webitel_media_exporter.VideoOptions
+	(*CreateScreenrecordingVideoRequest)(nil), // 22: webitel_media_exporter.CreateScreenrecordingVideoRequest
+	(*CreateCallVideoRequest)(nil),            // 23: webitel_media_exporter.CreateCallVideoRequest
+	(*ListScreenrecordingHistoryRequest)(nil), // 24: webitel_media_exporter.ListScreenrecordingHistoryRequest
+	(*ListCallHistoryRequest)(nil),            // 25: webitel_media_exporter.ListCallHistoryRequest
+	(*ListExportsResponse)(nil),               // 26: webitel_media_exporter.ListExportsResponse
+	(*ExportTask)(nil),                        // 27: webitel_media_exporter.ExportTask
+	(*ExportRecord)(nil),                      // 28: webitel_media_exporter.ExportRecord
+	(*VerifyExportRequest)(nil),               // 29: webitel_media_exporter.VerifyExportRequest
+	(*VerifyExportResponse)(nil),              // 30: webitel_media_exporter.VerifyExportResponse
+	(*GetExportRequest)(nil),                  // 31: webitel_media_exporter.GetExportRequest
+	(*GetExportByHistoryRequest)(nil),         // 32: webitel_media_exporter.GetExportByHistoryRequest
+	(*WatchExportRequest)(nil),                // 33: webitel_media_exporter.WatchExportRequest
+	(*ExportProgress)(nil),                    // 34: webitel_media_exporter.ExportProgress
+	(*CancelExportRequest)(nil),               // 35: webitel_media_exporter.CancelExportRequest
+	(*RetryExportRequest)(nil),                // 36: webitel_media_exporter.RetryExportRequest
+	(*DeleteExportRequest)(nil),               // 37: webitel_media_exporter.DeleteExportRequest
+	(*DeleteExportResponse)(nil),              // 38: webitel_media_exporter.DeleteExportResponse
+	(*ScheduleRange)(nil),                     // 39: webitel_media_exporter.ScheduleRange
+	(*ExportScheduleSpec)(nil),                // 40: webitel_media_exporter.ExportScheduleSpec
+	(*ExportSchedule)(nil),                    // 41: webitel_media_exporter.ExportSchedule
+	(*CreateExportScheduleRequest)(nil),       // 42: webitel_media_exporter.CreateExportScheduleRequest
+	(*ListExportSchedulesRequest)(nil),        // 43: webitel_media_exporter.ListExportSchedulesRequest
+	(*ListExportSchedulesResponse)(nil),       // 44: webitel_media_exporter.ListExportSchedulesResponse
+	(*GetExportScheduleRequest)(nil),          // 45: webitel_media_exporter.GetExportScheduleRequest
+	(*UpdateExportScheduleRequest)(nil),       // 46: webitel_media_exporter.UpdateExportScheduleRequest
+	(*DeleteExportScheduleRequest)(nil),       // 47: webitel_media_exporter.DeleteExportScheduleRequest
+	(*DeleteExportScheduleResponse)(nil),      // 48: webitel_media_exporter.DeleteExportScheduleResponse
 }
 var file_pdf_proto_depIdxs = []int32{
-	7, // 0: webitel_media_exporter.ListExportsResponse.items:type_name -> webitel_media_exporter.ExportRecord
-	0, // 1: webitel_media_exporter.ExportTask.status:type_name -> webitel_media_exporter.ExportStatus
-	0, // 2: webitel_media_exporter.ExportRecord.status:type_name -> webitel_media_exporter.ExportStatus
-	1, // 3: webitel_media_exporter.PdfService.CreateScreenrecordingExport:input_type -> webitel_media_exporter.CreateScreenrecordingRequest
-	3, // 4: webitel_media_exporter.PdfService.ListScreenrecordingExports:input_type -> webitel_media_exporter.ListScreenrecordingHistoryRequest
-	2, // 5: webitel_media_exporter.PdfService.CreateCallExport:input_type -> webitel_media_exporter.CreateCallExportRequest
-	4, // 6: webitel_media_exporter.PdfService.ListCallExports:input_type -> webitel_media_exporter.ListCallHistoryRequest
-	8, // 7: webitel_media_exporter.PdfService.DeleteExport:input_type -> webitel_media_exporter.DeleteExportRequest
-	6, // 8: webitel_media_exporter.PdfService.CreateScreenrecordingExport:output_type -> webitel_media_exporter.ExportTask
-	5, // 9: webitel_media_exporter.PdfService.ListScreenrecordingExports:output_type -> webitel_media_exporter.ListExportsResponse
-	6, // 10: webitel_media_exporter.PdfService.CreateCallExport:output_type -> webitel_media_exporter.ExportTask
-	5, // 11: webitel_media_exporter.PdfService.ListCallExports:output_type -> webitel_media_exporter.ListExportsResponse
-	9, // 12: webitel_media_exporter.PdfService.DeleteExport:output_type -> webitel_media_exporter.DeleteExportResponse
-	8, // [8:13] is the sub-list for method output_type
-	3, // [3:8] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	19, // 0: webitel_media_exporter.CreateScreenrecordingRequest.pdf:type_name -> webitel_media_exporter.PdfOptions
+	4,  // 1: webitel_media_exporter.CreateScreenrecordingRequest.sort:type_name -> webitel_media_exporter.ExportSort
+	5,  // 2: webitel_media_exporter.CreateScreenrecordingRequest.group_by:type_name -> webitel_media_exporter.ExportGrouping
+	19, // 3: webitel_media_exporter.CreateCallExportRequest.pdf:type_name -> webitel_media_exporter.PdfOptions
+	4,  // 4: webitel_media_exporter.CreateCallExportRequest.sort:type_name -> webitel_media_exporter.ExportSort
+	5,  // 5: webitel_media_exporter.CreateCallExportRequest.group_by:type_name -> webitel_media_exporter.ExportGrouping
+	19, // 6: webitel_media_exporter.CreateCallTranscriptExportRequest.pdf:type_name -> webitel_media_exporter.PdfOptions
+	19, // 7: webitel_media_exporter.CreateCallDossierExportRequest.pdf:type_name -> webitel_media_exporter.PdfOptions
+	2,  // 8: webitel_media_exporter.CreateCallAudioExportRequest.format:type_name -> webitel_media_exporter.AudioFormat
+	3,  // 9: webitel_media_exporter.CreateBatchExportRequest.format:type_name -> webitel_media_exporter.BatchExportFormat
+	19, // 10: webitel_media_exporter.CreateBatchExportRequest.pdf:type_name -> webitel_media_exporter.PdfOptions
+	21, // 11: webitel_media_exporter.CreateBatchExportRequest.video:type_name -> webitel_media_exporter.VideoOptions
+	4,  // 12: webitel_media_exporter.CreateBatchExportRequest.sort:type_name -> webitel_media_exporter.ExportSort
+	5,  // 13: webitel_media_exporter.CreateBatchExportRequest.group_by:type_name -> webitel_media_exporter.ExportGrouping
+	27, // 14: webitel_media_exporter.BatchExportTask.batch:type_name -> webitel_media_exporter.ExportTask
+	27, // 15: webitel_media_exporter.BatchExportTask.exports:type_name -> webitel_media_exporter.ExportTask
+	8,  // 16: webitel_media_exporter.PdfOptions.contents:type_name -> webitel_media_exporter.PdfContents
+	6,  // 17: webitel_media_exporter.PdfOptions.page_size:type_name -> webitel_media_exporter.PdfPageSize
+	7,  // 18: webitel_media_exporter.PdfOptions.orientation:type_name -> webitel_media_exporter.PdfOrientation
+	20, // 19: webitel_media_exporter.PdfOptions.encryption:type_name -> webitel_media_exporter.PdfEncryption
+	9,  // 20: webitel_media_exporter.VideoOptions.format:type_name -> webitel_media_exporter.VideoFormat
+	21, // 21: webitel_media_exporter.CreateScreenrecordingVideoRequest.video:type_name -> webitel_media_exporter.VideoOptions
+	4,  // 22: webitel_media_exporter.CreateScreenrecordingVideoRequest.sort:type_name -> webitel_media_exporter.ExportSort
+	5,  // 23: webitel_media_exporter.CreateScreenrecordingVideoRequest.group_by:type_name -> webitel_media_exporter.ExportGrouping
+	21, // 24: webitel_media_exporter.CreateCallVideoRequest.video:type_name -> webitel_media_exporter.VideoOptions
+	4,  // 25: webitel_media_exporter.CreateCallVideoRequest.sort:type_name -> webitel_media_exporter.ExportSort
+	5,  // 26: webitel_media_exporter.CreateCallVideoRequest.group_by:type_name -> webitel_media_exporter.ExportGrouping
+	28, // 27: webitel_media_exporter.ListExportsResponse.items:type_name -> webitel_media_exporter.ExportRecord
+	0,  // 28: webitel_media_exporter.ExportTask.status:type_name -> webitel_media_exporter.ExportStatus
+	0,  // 29: webitel_media_exporter.ExportRecord.status:type_name -> webitel_media_exporter.ExportStatus
+	0,  // 30: webitel_media_exporter.ExportProgress.status:type_name -> webitel_media_exporter.ExportStatus
+	1,  // 31: webitel_media_exporter.ExportProgress.stage:type_name -> webitel_media_exporter.ExportStage
+	10, // 32: webitel_media_exporter.ScheduleRange.unit:type_name -> webitel_media_exporter.ScheduleRangeUnit
+	39, // 33: webitel_media_exporter.ExportScheduleSpec.range:type_name -> webitel_media_exporter.ScheduleRange
+	11, // 34: webitel_media_exporter.ExportScheduleSpec.catch_up:type_name -> webitel_media_exporter.ScheduleCatchUp
+	3,  // 35: webitel_media_exporter.ExportScheduleSpec.format:type_name -> webitel_media_exporter.BatchExportFormat
+	19, // 36: webitel_media_exporter.ExportScheduleSpec.pdf:type_name -> webitel_media_exporter.PdfOptions
+	21, // 37: webitel_media_exporter.ExportScheduleSpec.video:type_name -> webitel_media_exporter.VideoOptions
+	4,  // 38: webitel_media_exporter.ExportScheduleSpec.sort:type_name -> webitel_media_exporter.ExportSort
+	5,  // 39: webitel_media_exporter.ExportScheduleSpec.group_by:type_name -> webitel_media_exporter.ExportGrouping
+	40, // 40: webitel_media_exporter.ExportSchedule.spec:type_name -> webitel_media_exporter.ExportScheduleSpec
+	40, // 41: webitel_media_exporter.CreateExportScheduleRequest.spec:type_name -> webitel_media_exporter.ExportScheduleSpec
+	41, // 42: webitel_media_exporter.ListExportSchedulesResponse.items:type_name -> webitel_media_exporter.ExportSchedule
+	40, // 43: webitel_media_exporter.UpdateExportScheduleRequest.spec:type_name -> webitel_media_exporter.ExportScheduleSpec
+	12, // 44: webitel_media_exporter.PdfService.CreateScreenrecordingExport:input_type -> webitel_media_exporter.CreateScreenrecordingRequest
+	24, // 45: webitel_media_exporter.PdfService.ListScreenrecordingExports:input_type -> webitel_media_exporter.ListScreenrecordingHistoryRequest
+	13, // 46: webitel_media_exporter.PdfService.CreateCallExport:input_type -> webitel_media_exporter.CreateCallExportRequest
+	25, // 47: webitel_media_exporter.PdfService.ListCallExports:input_type -> webitel_media_exporter.ListCallHistoryRequest
+	12, // 48: webitel_media_exporter.PdfService.CreateScreenrecordingZipExport:input_type -> webitel_media_exporter.CreateScreenrecordingRequest
+	13, // 49: webitel_media_exporter.PdfService.CreateCallZipExport:input_type -> webitel_media_exporter.CreateCallExportRequest
+	14, // 50: webitel_media_exporter.PdfService.CreateCallTranscriptExport:input_type -> webitel_media_exporter.CreateCallTranscriptExportRequest
+	15, // 51: webitel_media_exporter.PdfService.CreateCallDossierExport:input_type -> webitel_media_exporter.CreateCallDossierExportRequest
+	16, // 52: webitel_media_exporter.PdfService.CreateCallAudioExport:input_type -> webitel_media_exporter.CreateCallAudioExportRequest
+	17, // 53: webitel_media_exporter.PdfService.CreateBatchExport:input_type -> webitel_media_exporter.CreateBatchExportRequest
+	22, // 54: webitel_media_exporter.PdfService.CreateScreenrecordingVideoExport:input_type -> webitel_media_exporter.CreateScreenrecordingVideoRequest
+	23, // 55: webitel_media_exporter.PdfService.CreateCallVideoExport:input_type -> webitel_media_exporter.CreateCallVideoRequest
+	31, // 56: webitel_media_exporter.PdfService.GetExport:input_type -> webitel_media_exporter.GetExportRequest
+	32, // 57: webitel_media_exporter.PdfService.GetExportByHistory:input_type -> webitel_media_exporter.GetExportByHistoryRequest
+	33, // 58: webitel_media_exporter.PdfService.WatchExport:input_type -> webitel_media_exporter.WatchExportRequest
+	35, // 59: webitel_media_exporter.PdfService.CancelExport:input_type -> webitel_media_exporter.CancelExportRequest
+	36, // 60: webitel_media_exporter.PdfService.RetryExport:input_type -> webitel_media_exporter.RetryExportRequest
+	29, // 61: webitel_media_exporter.PdfService.VerifyExport:input_type -> webitel_media_exporter.VerifyExportRequest
+	37, // 62: webitel_media_exporter.PdfService.DeleteExport:input_type -> webitel_media_exporter.DeleteExportRequest
+	42, // 63: webitel_media_exporter.PdfService.CreateExportSchedule:input_type -> webitel_media_exporter.CreateExportScheduleRequest
+	43, // 64: webitel_media_exporter.PdfService.ListExportSchedules:input_type -> webitel_media_exporter.ListExportSchedulesRequest
+	45, // 65: webitel_media_exporter.PdfService.GetExportSchedule:input_type -> webitel_media_exporter.GetExportScheduleRequest
+	46, // 66: webitel_media_exporter.PdfService.UpdateExportSchedule:input_type -> webitel_media_exporter.UpdateExportScheduleRequest
+	47, // 67: webitel_media_exporter.PdfService.DeleteExportSchedule:input_type -> webitel_media_exporter.DeleteExportScheduleRequest
+	27, // 68: webitel_media_exporter.PdfService.CreateScreenrecordingExport:output_type -> webitel_media_exporter.ExportTask
+	26, // 69: webitel_media_exporter.PdfService.ListScreenrecordingExports:output_type -> webitel_media_exporter.ListExportsResponse
+	27, // 70: webitel_media_exporter.PdfService.CreateCallExport:output_type -> webitel_media_exporter.ExportTask
+	26, // 71: webitel_media_exporter.PdfService.ListCallExports:output_type -> webitel_media_exporter.ListExportsResponse
+	27, // 72: webitel_media_exporter.PdfService.CreateScreenrecordingZipExport:output_type -> webitel_media_exporter.ExportTask
+	27, // 73: webitel_media_exporter.PdfService.CreateCallZipExport:output_type -> webitel_media_exporter.ExportTask
+	27, // 74: webitel_media_exporter.PdfService.CreateCallTranscriptExport:output_type -> webitel_media_exporter.ExportTask
+	27, // 75: webitel_media_exporter.PdfService.CreateCallDossierExport:output_type -> webitel_media_exporter.ExportTask
+	27, // 76: webitel_media_exporter.PdfService.CreateCallAudioExport:output_type -> webitel_media_exporter.ExportTask
+	18, // 77: webitel_media_exporter.PdfService.CreateBatchExport:output_type -> webitel_media_exporter.BatchExportTask
+	27, // 78: webitel_media_exporter.PdfService.CreateScreenrecordingVideoExport:output_type -> webitel_media_exporter.ExportTask
+	27, // 79: webitel_media_exporter.PdfService.CreateCallVideoExport:output_type -> webitel_media_exporter.ExportTask
+	28, // 80: webitel_media_exporter.PdfService.GetExport:output_type -> webitel_media_exporter.ExportRecord
+	28, // 81: webitel_media_exporter.PdfService.GetExportByHistory:output_type -> webitel_media_exporter.ExportRecord
+	34, // 82: webitel_media_exporter.PdfService.WatchExport:output_type -> webitel_media_exporter.ExportProgress
+	28, // 83: webitel_media_exporter.PdfService.CancelExport:output_type -> webitel_media_exporter.ExportRecord
+	27, // 84: webitel_media_exporter.PdfService.RetryExport:output_type -> webitel_media_exporter.ExportTask
+	30, // 85: webitel_media_exporter.PdfService.VerifyExport:output_type -> webitel_media_exporter.VerifyExportResponse
+	38, // 86: webitel_media_exporter.PdfService.DeleteExport:output_type -> webitel_media_exporter.DeleteExportResponse
+	41, // 87: webitel_media_exporter.PdfService.CreateExportSchedule:output_type -> webitel_media_exporter.ExportSchedule
+	44, // 88: webitel_media_exporter.PdfService.ListExportSchedules:output_type -> webitel_media_exporter.ListExportSchedulesResponse
+	41, // 89: webitel_media_exporter.PdfService.GetExportSchedule:output_type -> webitel_media_exporter.ExportSchedule
+	41, // 90: webitel_media_exporter.PdfService.UpdateExportSchedule:output_type -> webitel_media_exporter.ExportSchedule
+	48, // 91: webitel_media_exporter.PdfService.DeleteExportSchedule:output_type -> webitel_media_exporter.DeleteExportScheduleResponse
+	68, // [68:92] is the sub-list for method output_type
+	44, // [44:68] is the sub-list for method input_type
+	44, // [44:44] is the sub-list for extension type_name
+	44, // [44:44] is the sub-list for extension extendee
+	0,  // [0:44] is the sub-list for field type_name
 }
 
 func init() { file_pdf_proto_init() }
@@ -821,8 +3944,8 @@ func file_pdf_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pdf_proto_rawDesc), len(file_pdf_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   9,
+			NumEnums:      12,
+			NumMessages:   37,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
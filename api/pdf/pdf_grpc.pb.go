@@ -19,11 +19,30 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PdfService_CreateScreenrecordingExport_FullMethodName = "/webitel_media_exporter.PdfService/CreateScreenrecordingExport"
-	PdfService_ListScreenrecordingExports_FullMethodName  = "/webitel_media_exporter.PdfService/ListScreenrecordingExports"
-	PdfService_CreateCallExport_FullMethodName            = "/webitel_media_exporter.PdfService/CreateCallExport"
-	PdfService_ListCallExports_FullMethodName             = "/webitel_media_exporter.PdfService/ListCallExports"
-	PdfService_DeleteExport_FullMethodName                = "/webitel_media_exporter.PdfService/DeleteExport"
+	PdfService_CreateScreenrecordingExport_FullMethodName      = "/webitel_media_exporter.PdfService/CreateScreenrecordingExport"
+	PdfService_ListScreenrecordingExports_FullMethodName       = "/webitel_media_exporter.PdfService/ListScreenrecordingExports"
+	PdfService_CreateCallExport_FullMethodName                 = "/webitel_media_exporter.PdfService/CreateCallExport"
+	PdfService_ListCallExports_FullMethodName                  = "/webitel_media_exporter.PdfService/ListCallExports"
+	PdfService_CreateScreenrecordingZipExport_FullMethodName   = "/webitel_media_exporter.PdfService/CreateScreenrecordingZipExport"
+	PdfService_CreateCallZipExport_FullMethodName              = "/webitel_media_exporter.PdfService/CreateCallZipExport"
+	PdfService_CreateCallTranscriptExport_FullMethodName       = "/webitel_media_exporter.PdfService/CreateCallTranscriptExport"
+	PdfService_CreateCallDossierExport_FullMethodName          = "/webitel_media_exporter.PdfService/CreateCallDossierExport"
+	PdfService_CreateCallAudioExport_FullMethodName            = "/webitel_media_exporter.PdfService/CreateCallAudioExport"
+	PdfService_CreateBatchExport_FullMethodName                = "/webitel_media_exporter.PdfService/CreateBatchExport"
+	PdfService_CreateScreenrecordingVideoExport_FullMethodName = "/webitel_media_exporter.PdfService/CreateScreenrecordingVideoExport"
+	PdfService_CreateCallVideoExport_FullMethodName            = "/webitel_media_exporter.PdfService/CreateCallVideoExport"
+	PdfService_GetExport_FullMethodName                        = "/webitel_media_exporter.PdfService/GetExport"
+	PdfService_GetExportByHistory_FullMethodName               = "/webitel_media_exporter.PdfService/GetExportByHistory"
+	PdfService_WatchExport_FullMethodName                      = "/webitel_media_exporter.PdfService/WatchExport"
+	PdfService_CancelExport_FullMethodName                     = "/webitel_media_exporter.PdfService/CancelExport"
+	PdfService_RetryExport_FullMethodName                      = "/webitel_media_exporter.PdfService/RetryExport"
+	PdfService_VerifyExport_FullMethodName                     = "/webitel_media_exporter.PdfService/VerifyExport"
+	PdfService_DeleteExport_FullMethodName                     = "/webitel_media_exporter.PdfService/DeleteExport"
+	PdfService_CreateExportSchedule_FullMethodName             = "/webitel_media_exporter.PdfService/CreateExportSchedule"
+	PdfService_ListExportSchedules_FullMethodName              = "/webitel_media_exporter.PdfService/ListExportSchedules"
+	PdfService_GetExportSchedule_FullMethodName                = "/webitel_media_exporter.PdfService/GetExportSchedule"
+	PdfService_UpdateExportSchedule_FullMethodName             = "/webitel_media_exporter.PdfService/UpdateExportSchedule"
+	PdfService_DeleteExportSchedule_FullMethodName             = "/webitel_media_exporter.PdfService/DeleteExportSchedule"
 )
 
 // PdfServiceClient is the client API for PdfService service.
@@ -43,8 +62,58 @@ type PdfServiceClient interface {
 	CreateCallExport(ctx context.Context, in *CreateCallExportRequest, opts ...grpc.CallOption) (*ExportTask, error)
 	// Lists the history of PDF exports for a specific call ID.
 	ListCallExports(ctx context.Context, in *ListCallHistoryRequest, opts ...grpc.CallOption) (*ListExportsResponse, error)
+	// Creates a new task to bundle the original screenshots of an agent into a ZIP archive.
+	// The archive contains a manifest.json describing every included file.
+	CreateScreenrecordingZipExport(ctx context.Context, in *CreateScreenrecordingRequest, opts ...grpc.CallOption) (*ExportTask, error)
+	// Creates a new task to bundle the original screenshots of a call into a ZIP archive.
+	CreateCallZipExport(ctx context.Context, in *CreateCallExportRequest, opts ...grpc.CallOption) (*ExportTask, error)
+	// Creates a task to render the transcripts of the recordings of a call into a PDF:
+	// the phrases of every recording with their speaker channel and start and end offsets.
+	CreateCallTranscriptExport(ctx context.Context, in *CreateCallTranscriptExportRequest, opts ...grpc.CallOption) (*ExportTask, error)
+	// Creates a task to collect everything about a call into one PDF: the screenshots of the call
+	// and the phrases of its recordings on a single timeline, followed by an inventory of its files.
+	CreateCallDossierExport(ctx context.Context, in *CreateCallDossierExportRequest, opts ...grpc.CallOption) (*ExportTask, error)
+	// Creates a task to collect the audio recordings of one or more calls, oldest first, into a single
+	// MP3, WAV or Opus file, or into a ZIP archive of the unchanged originals.
+	CreateCallAudioExport(ctx context.Context, in *CreateCallAudioExportRequest, opts ...grpc.CallOption) (*ExportTask, error)
+	// Creates one export per agent or call of the request over a shared time range, tracked by a parent
+	// batch export. GetExport and WatchExport of the batch report how many of its exports finished;
+	// the batch is done once all of them are, optionally with a ZIP archive of all their files.
+	CreateBatchExport(ctx context.Context, in *CreateBatchExportRequest, opts ...grpc.CallOption) (*BatchExportTask, error)
+	// Creates a task to encode an agent's screenshots into a time-lapse video (MP4 or WebM).
+	CreateScreenrecordingVideoExport(ctx context.Context, in *CreateScreenrecordingVideoRequest, opts ...grpc.CallOption) (*ExportTask, error)
+	// Creates a task to encode the screenshots of a call into a time-lapse video (MP4 or WebM).
+	CreateCallVideoExport(ctx context.Context, in *CreateCallVideoRequest, opts ...grpc.CallOption) (*ExportTask, error)
+	// Returns the current state of a single export task.
+	// The live status of the queue is combined with the persisted history record.
+	GetExport(ctx context.Context, in *GetExportRequest, opts ...grpc.CallOption) (*ExportRecord, error)
+	// Returns a single export by its history record ID.
+	GetExportByHistory(ctx context.Context, in *GetExportByHistoryRequest, opts ...grpc.CallOption) (*ExportRecord, error)
+	// Streams progress events of an export task until it is done or failed.
+	// The last known event is sent first, so a client may subscribe at any moment.
+	WatchExport(ctx context.Context, in *WatchExportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportProgress], error)
+	// Cancels a queued or running export task.
+	// A queued task is removed from the queue, a running one is aborted by its worker.
+	CancelExport(ctx context.Context, in *CancelExportRequest, opts ...grpc.CallOption) (*ExportRecord, error)
+	// Enqueues a failed or cancelled export again with its original parameters.
+	// The new export is linked to the original one through retry_of.
+	RetryExport(ctx context.Context, in *RetryExportRequest, opts ...grpc.CallOption) (*ExportTask, error)
+	// Checks a document against the SHA-256 recorded when the export was produced.
+	// The first message names the export, the document follows in chunks.
+	VerifyExport(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[VerifyExportRequest, VerifyExportResponse], error)
 	// Deletes a specific export record from the history.
 	DeleteExport(ctx context.Context, in *DeleteExportRequest, opts ...grpc.CallOption) (*DeleteExportResponse, error)
+	// Creates a schedule that runs a batch export of its agents on every occurrence of its cron expression.
+	// Runs are made on behalf of the user who last saved the schedule, with the access that user has then.
+	CreateExportSchedule(ctx context.Context, in *CreateExportScheduleRequest, opts ...grpc.CallOption) (*ExportSchedule, error)
+	// Lists the export schedules of the domain.
+	ListExportSchedules(ctx context.Context, in *ListExportSchedulesRequest, opts ...grpc.CallOption) (*ListExportSchedulesResponse, error)
+	// Returns a single export schedule.
+	GetExportSchedule(ctx context.Context, in *GetExportScheduleRequest, opts ...grpc.CallOption) (*ExportSchedule, error)
+	// Replaces the spec of an export schedule. The next run is computed again from the new spec.
+	UpdateExportSchedule(ctx context.Context, in *UpdateExportScheduleRequest, opts ...grpc.CallOption) (*ExportSchedule, error)
+	// Deletes an export schedule. Exports it already created are kept.
+	DeleteExportSchedule(ctx context.Context, in *DeleteExportScheduleRequest, opts ...grpc.CallOption) (*DeleteExportScheduleResponse, error)
 }
 
 type pdfServiceClient struct {
@@ -95,6 +164,158 @@ func (c *pdfServiceClient) ListCallExports(ctx context.Context, in *ListCallHist
 	return out, nil
 }
 
+func (c *pdfServiceClient) CreateScreenrecordingZipExport(ctx context.Context, in *CreateScreenrecordingRequest, opts ...grpc.CallOption) (*ExportTask, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportTask)
+	err := c.cc.Invoke(ctx, PdfService_CreateScreenrecordingZipExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) CreateCallZipExport(ctx context.Context, in *CreateCallExportRequest, opts ...grpc.CallOption) (*ExportTask, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportTask)
+	err := c.cc.Invoke(ctx, PdfService_CreateCallZipExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) CreateCallTranscriptExport(ctx context.Context, in *CreateCallTranscriptExportRequest, opts ...grpc.CallOption) (*ExportTask, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportTask)
+	err := c.cc.Invoke(ctx, PdfService_CreateCallTranscriptExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) CreateCallDossierExport(ctx context.Context, in *CreateCallDossierExportRequest, opts ...grpc.CallOption) (*ExportTask, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportTask)
+	err := c.cc.Invoke(ctx, PdfService_CreateCallDossierExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) CreateCallAudioExport(ctx context.Context, in *CreateCallAudioExportRequest, opts ...grpc.CallOption) (*ExportTask, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportTask)
+	err := c.cc.Invoke(ctx, PdfService_CreateCallAudioExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) CreateBatchExport(ctx context.Context, in *CreateBatchExportRequest, opts ...grpc.CallOption) (*BatchExportTask, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchExportTask)
+	err := c.cc.Invoke(ctx, PdfService_CreateBatchExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) CreateScreenrecordingVideoExport(ctx context.Context, in *CreateScreenrecordingVideoRequest, opts ...grpc.CallOption) (*ExportTask, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportTask)
+	err := c.cc.Invoke(ctx, PdfService_CreateScreenrecordingVideoExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) CreateCallVideoExport(ctx context.Context, in *CreateCallVideoRequest, opts ...grpc.CallOption) (*ExportTask, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportTask)
+	err := c.cc.Invoke(ctx, PdfService_CreateCallVideoExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) GetExport(ctx context.Context, in *GetExportRequest, opts ...grpc.CallOption) (*ExportRecord, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportRecord)
+	err := c.cc.Invoke(ctx, PdfService_GetExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) GetExportByHistory(ctx context.Context, in *GetExportByHistoryRequest, opts ...grpc.CallOption) (*ExportRecord, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportRecord)
+	err := c.cc.Invoke(ctx, PdfService_GetExportByHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) WatchExport(ctx context.Context, in *WatchExportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PdfService_ServiceDesc.Streams[0], PdfService_WatchExport_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchExportRequest, ExportProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_WatchExportClient = grpc.ServerStreamingClient[ExportProgress]
+
+func (c *pdfServiceClient) CancelExport(ctx context.Context, in *CancelExportRequest, opts ...grpc.CallOption) (*ExportRecord, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportRecord)
+	err := c.cc.Invoke(ctx, PdfService_CancelExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) RetryExport(ctx context.Context, in *RetryExportRequest, opts ...grpc.CallOption) (*ExportTask, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportTask)
+	err := c.cc.Invoke(ctx, PdfService_RetryExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) VerifyExport(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[VerifyExportRequest, VerifyExportResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PdfService_ServiceDesc.Streams[1], PdfService_VerifyExport_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[VerifyExportRequest, VerifyExportResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_VerifyExportClient = grpc.ClientStreamingClient[VerifyExportRequest, VerifyExportResponse]
+
 func (c *pdfServiceClient) DeleteExport(ctx context.Context, in *DeleteExportRequest, opts ...grpc.CallOption) (*DeleteExportResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeleteExportResponse)
@@ -105,6 +326,56 @@ func (c *pdfServiceClient) DeleteExport(ctx context.Context, in *DeleteExportReq
 	return out, nil
 }
 
+func (c *pdfServiceClient) CreateExportSchedule(ctx context.Context, in *CreateExportScheduleRequest, opts ...grpc.CallOption) (*ExportSchedule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportSchedule)
+	err := c.cc.Invoke(ctx, PdfService_CreateExportSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) ListExportSchedules(ctx context.Context, in *ListExportSchedulesRequest, opts ...grpc.CallOption) (*ListExportSchedulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListExportSchedulesResponse)
+	err := c.cc.Invoke(ctx, PdfService_ListExportSchedules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) GetExportSchedule(ctx context.Context, in *GetExportScheduleRequest, opts ...grpc.CallOption) (*ExportSchedule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportSchedule)
+	err := c.cc.Invoke(ctx, PdfService_GetExportSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) UpdateExportSchedule(ctx context.Context, in *UpdateExportScheduleRequest, opts ...grpc.CallOption) (*ExportSchedule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportSchedule)
+	err := c.cc.Invoke(ctx, PdfService_UpdateExportSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pdfServiceClient) DeleteExportSchedule(ctx context.Context, in *DeleteExportScheduleRequest, opts ...grpc.CallOption) (*DeleteExportScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteExportScheduleResponse)
+	err := c.cc.Invoke(ctx, PdfService_DeleteExportSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PdfServiceServer is the server API for PdfService service.
 // All implementations must embed UnimplementedPdfServiceServer
 // for forward compatibility.
@@ -122,8 +393,58 @@ type PdfServiceServer interface {
 	CreateCallExport(context.Context, *CreateCallExportRequest) (*ExportTask, error)
 	// Lists the history of PDF exports for a specific call ID.
 	ListCallExports(context.Context, *ListCallHistoryRequest) (*ListExportsResponse, error)
+	// Creates a new task to bundle the original screenshots of an agent into a ZIP archive.
+	// The archive contains a manifest.json describing every included file.
+	CreateScreenrecordingZipExport(context.Context, *CreateScreenrecordingRequest) (*ExportTask, error)
+	// Creates a new task to bundle the original screenshots of a call into a ZIP archive.
+	CreateCallZipExport(context.Context, *CreateCallExportRequest) (*ExportTask, error)
+	// Creates a task to render the transcripts of the recordings of a call into a PDF:
+	// the phrases of every recording with their speaker channel and start and end offsets.
+	CreateCallTranscriptExport(context.Context, *CreateCallTranscriptExportRequest) (*ExportTask, error)
+	// Creates a task to collect everything about a call into one PDF: the screenshots of the call
+	// and the phrases of its recordings on a single timeline, followed by an inventory of its files.
+	CreateCallDossierExport(context.Context, *CreateCallDossierExportRequest) (*ExportTask, error)
+	// Creates a task to collect the audio recordings of one or more calls, oldest first, into a single
+	// MP3, WAV or Opus file, or into a ZIP archive of the unchanged originals.
+	CreateCallAudioExport(context.Context, *CreateCallAudioExportRequest) (*ExportTask, error)
+	// Creates one export per agent or call of the request over a shared time range, tracked by a parent
+	// batch export. GetExport and WatchExport of the batch report how many of its exports finished;
+	// the batch is done once all of them are, optionally with a ZIP archive of all their files.
+	CreateBatchExport(context.Context, *CreateBatchExportRequest) (*BatchExportTask, error)
+	// Creates a task to encode an agent's screenshots into a time-lapse video (MP4 or WebM).
+	CreateScreenrecordingVideoExport(context.Context, *CreateScreenrecordingVideoRequest) (*ExportTask, error)
+	// Creates a task to encode the screenshots of a call into a time-lapse video (MP4 or WebM).
+	CreateCallVideoExport(context.Context, *CreateCallVideoRequest) (*ExportTask, error)
+	// Returns the current state of a single export task.
+	// The live status of the queue is combined with the persisted history record.
+	GetExport(context.Context, *GetExportRequest) (*ExportRecord, error)
+	// Returns a single export by its history record ID.
+	GetExportByHistory(context.Context, *GetExportByHistoryRequest) (*ExportRecord, error)
+	// Streams progress events of an export task until it is done or failed.
+	// The last known event is sent first, so a client may subscribe at any moment.
+	WatchExport(*WatchExportRequest, grpc.ServerStreamingServer[ExportProgress]) error
+	// Cancels a queued or running export task.
+	// A queued task is removed from the queue, a running one is aborted by its worker.
+	CancelExport(context.Context, *CancelExportRequest) (*ExportRecord, error)
+	// Enqueues a failed or cancelled export again with its original parameters.
+	// The new export is linked to the original one through retry_of.
+	RetryExport(context.Context, *RetryExportRequest) (*ExportTask, error)
+	// Checks a document against the SHA-256 recorded when the export was produced.
+	// The first message names the export, the document follows in chunks.
+	VerifyExport(grpc.ClientStreamingServer[VerifyExportRequest, VerifyExportResponse]) error
 	// Deletes a specific export record from the history.
 	DeleteExport(context.Context, *DeleteExportRequest) (*DeleteExportResponse, error)
+	// Creates a schedule that runs a batch export of its agents on every occurrence of its cron expression.
+	// Runs are made on behalf of the user who last saved the schedule, with the access that user has then.
+	CreateExportSchedule(context.Context, *CreateExportScheduleRequest) (*ExportSchedule, error)
+	// Lists the export schedules of the domain.
+	ListExportSchedules(context.Context, *ListExportSchedulesRequest) (*ListExportSchedulesResponse, error)
+	// Returns a single export schedule.
+	GetExportSchedule(context.Context, *GetExportScheduleRequest) (*ExportSchedule, error)
+	// Replaces the spec of an export schedule. The next run is computed again from the new spec.
+	UpdateExportSchedule(context.Context, *UpdateExportScheduleRequest) (*ExportSchedule, error)
+	// Deletes an export schedule. Exports it already created are kept.
+	DeleteExportSchedule(context.Context, *DeleteExportScheduleRequest) (*DeleteExportScheduleResponse, error)
 	mustEmbedUnimplementedPdfServiceServer()
 }
 
@@ -146,9 +467,66 @@ func (UnimplementedPdfServiceServer) CreateCallExport(context.Context, *CreateCa
 func (UnimplementedPdfServiceServer) ListCallExports(context.Context, *ListCallHistoryRequest) (*ListExportsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListCallExports not implemented")
 }
+func (UnimplementedPdfServiceServer) CreateScreenrecordingZipExport(context.Context, *CreateScreenrecordingRequest) (*ExportTask, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateScreenrecordingZipExport not implemented")
+}
+func (UnimplementedPdfServiceServer) CreateCallZipExport(context.Context, *CreateCallExportRequest) (*ExportTask, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateCallZipExport not implemented")
+}
+func (UnimplementedPdfServiceServer) CreateCallTranscriptExport(context.Context, *CreateCallTranscriptExportRequest) (*ExportTask, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateCallTranscriptExport not implemented")
+}
+func (UnimplementedPdfServiceServer) CreateCallDossierExport(context.Context, *CreateCallDossierExportRequest) (*ExportTask, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateCallDossierExport not implemented")
+}
+func (UnimplementedPdfServiceServer) CreateCallAudioExport(context.Context, *CreateCallAudioExportRequest) (*ExportTask, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateCallAudioExport not implemented")
+}
+func (UnimplementedPdfServiceServer) CreateBatchExport(context.Context, *CreateBatchExportRequest) (*BatchExportTask, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateBatchExport not implemented")
+}
+func (UnimplementedPdfServiceServer) CreateScreenrecordingVideoExport(context.Context, *CreateScreenrecordingVideoRequest) (*ExportTask, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateScreenrecordingVideoExport not implemented")
+}
+func (UnimplementedPdfServiceServer) CreateCallVideoExport(context.Context, *CreateCallVideoRequest) (*ExportTask, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateCallVideoExport not implemented")
+}
+func (UnimplementedPdfServiceServer) GetExport(context.Context, *GetExportRequest) (*ExportRecord, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetExport not implemented")
+}
+func (UnimplementedPdfServiceServer) GetExportByHistory(context.Context, *GetExportByHistoryRequest) (*ExportRecord, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetExportByHistory not implemented")
+}
+func (UnimplementedPdfServiceServer) WatchExport(*WatchExportRequest, grpc.ServerStreamingServer[ExportProgress]) error {
+	return status.Error(codes.Unimplemented, "method WatchExport not implemented")
+}
+func (UnimplementedPdfServiceServer) CancelExport(context.Context, *CancelExportRequest) (*ExportRecord, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelExport not implemented")
+}
+func (UnimplementedPdfServiceServer) RetryExport(context.Context, *RetryExportRequest) (*ExportTask, error) {
+	return nil, status.Error(codes.Unimplemented, "method RetryExport not implemented")
+}
+func (UnimplementedPdfServiceServer) VerifyExport(grpc.ClientStreamingServer[VerifyExportRequest, VerifyExportResponse]) error {
+	return status.Error(codes.Unimplemented, "method VerifyExport not implemented")
+}
 func (UnimplementedPdfServiceServer) DeleteExport(context.Context, *DeleteExportRequest) (*DeleteExportResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteExport not implemented")
 }
+func (UnimplementedPdfServiceServer) CreateExportSchedule(context.Context, *CreateExportScheduleRequest) (*ExportSchedule, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateExportSchedule not implemented")
+}
+func (UnimplementedPdfServiceServer) ListExportSchedules(context.Context, *ListExportSchedulesRequest) (*ListExportSchedulesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListExportSchedules not implemented")
+}
+func (UnimplementedPdfServiceServer) GetExportSchedule(context.Context, *GetExportScheduleRequest) (*ExportSchedule, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetExportSchedule not implemented")
+}
+func (UnimplementedPdfServiceServer) UpdateExportSchedule(context.Context, *UpdateExportScheduleRequest) (*ExportSchedule, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateExportSchedule not implemented")
+}
+func (UnimplementedPdfServiceServer) DeleteExportSchedule(context.Context, *DeleteExportScheduleRequest) (*DeleteExportScheduleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteExportSchedule not implemented")
+}
 func (UnimplementedPdfServiceServer) mustEmbedUnimplementedPdfServiceServer() {}
 func (UnimplementedPdfServiceServer) testEmbeddedByValue()                    {}
 
@@ -242,6 +620,240 @@ func _PdfService_ListCallExports_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PdfService_CreateScreenrecordingZipExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateScreenrecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).CreateScreenrecordingZipExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_CreateScreenrecordingZipExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).CreateScreenrecordingZipExport(ctx, req.(*CreateScreenrecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_CreateCallZipExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCallExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).CreateCallZipExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_CreateCallZipExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).CreateCallZipExport(ctx, req.(*CreateCallExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_CreateCallTranscriptExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCallTranscriptExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).CreateCallTranscriptExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_CreateCallTranscriptExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).CreateCallTranscriptExport(ctx, req.(*CreateCallTranscriptExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_CreateCallDossierExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCallDossierExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).CreateCallDossierExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_CreateCallDossierExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).CreateCallDossierExport(ctx, req.(*CreateCallDossierExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_CreateCallAudioExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCallAudioExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).CreateCallAudioExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_CreateCallAudioExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).CreateCallAudioExport(ctx, req.(*CreateCallAudioExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_CreateBatchExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBatchExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).CreateBatchExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_CreateBatchExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).CreateBatchExport(ctx, req.(*CreateBatchExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_CreateScreenrecordingVideoExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateScreenrecordingVideoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).CreateScreenrecordingVideoExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_CreateScreenrecordingVideoExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).CreateScreenrecordingVideoExport(ctx, req.(*CreateScreenrecordingVideoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_CreateCallVideoExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCallVideoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).CreateCallVideoExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_CreateCallVideoExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).CreateCallVideoExport(ctx, req.(*CreateCallVideoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_GetExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).GetExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_GetExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).GetExport(ctx, req.(*GetExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_GetExportByHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExportByHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).GetExportByHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_GetExportByHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).GetExportByHistory(ctx, req.(*GetExportByHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_WatchExport_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PdfServiceServer).WatchExport(m, &grpc.GenericServerStream[WatchExportRequest, ExportProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_WatchExportServer = grpc.ServerStreamingServer[ExportProgress]
+
+func _PdfService_CancelExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).CancelExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_CancelExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).CancelExport(ctx, req.(*CancelExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_RetryExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetryExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).RetryExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_RetryExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).RetryExport(ctx, req.(*RetryExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_VerifyExport_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PdfServiceServer).VerifyExport(&grpc.GenericServerStream[VerifyExportRequest, VerifyExportResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_VerifyExportServer = grpc.ClientStreamingServer[VerifyExportRequest, VerifyExportResponse]
+
 func _PdfService_DeleteExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteExportRequest)
 	if err := dec(in); err != nil {
@@ -260,6 +872,96 @@ func _PdfService_DeleteExport_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PdfService_CreateExportSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateExportScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).CreateExportSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_CreateExportSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).CreateExportSchedule(ctx, req.(*CreateExportScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_ListExportSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListExportSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).ListExportSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_ListExportSchedules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).ListExportSchedules(ctx, req.(*ListExportSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_GetExportSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExportScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).GetExportSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_GetExportSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).GetExportSchedule(ctx, req.(*GetExportScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_UpdateExportSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateExportScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).UpdateExportSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_UpdateExportSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).UpdateExportSchedule(ctx, req.(*UpdateExportScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PdfService_DeleteExportSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteExportScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PdfServiceServer).DeleteExportSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PdfService_DeleteExportSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PdfServiceServer).DeleteExportSchedule(ctx, req.(*DeleteExportScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PdfService_ServiceDesc is the grpc.ServiceDesc for PdfService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -283,11 +985,90 @@ var PdfService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListCallExports",
 			Handler:    _PdfService_ListCallExports_Handler,
 		},
+		{
+			MethodName: "CreateScreenrecordingZipExport",
+			Handler:    _PdfService_CreateScreenrecordingZipExport_Handler,
+		},
+		{
+			MethodName: "CreateCallZipExport",
+			Handler:    _PdfService_CreateCallZipExport_Handler,
+		},
+		{
+			MethodName: "CreateCallTranscriptExport",
+			Handler:    _PdfService_CreateCallTranscriptExport_Handler,
+		},
+		{
+			MethodName: "CreateCallDossierExport",
+			Handler:    _PdfService_CreateCallDossierExport_Handler,
+		},
+		{
+			MethodName: "CreateCallAudioExport",
+			Handler:    _PdfService_CreateCallAudioExport_Handler,
+		},
+		{
+			MethodName: "CreateBatchExport",
+			Handler:    _PdfService_CreateBatchExport_Handler,
+		},
+		{
+			MethodName: "CreateScreenrecordingVideoExport",
+			Handler:    _PdfService_CreateScreenrecordingVideoExport_Handler,
+		},
+		{
+			MethodName: "CreateCallVideoExport",
+			Handler:    _PdfService_CreateCallVideoExport_Handler,
+		},
+		{
+			MethodName: "GetExport",
+			Handler:    _PdfService_GetExport_Handler,
+		},
+		{
+			MethodName: "GetExportByHistory",
+			Handler:    _PdfService_GetExportByHistory_Handler,
+		},
+		{
+			MethodName: "CancelExport",
+			Handler:    _PdfService_CancelExport_Handler,
+		},
+		{
+			MethodName: "RetryExport",
+			Handler:    _PdfService_RetryExport_Handler,
+		},
 		{
 			MethodName: "DeleteExport",
 			Handler:    _PdfService_DeleteExport_Handler,
 		},
+		{
+			MethodName: "CreateExportSchedule",
+			Handler:    _PdfService_CreateExportSchedule_Handler,
+		},
+		{
+			MethodName: "ListExportSchedules",
+			Handler:    _PdfService_ListExportSchedules_Handler,
+		},
+		{
+			MethodName: "GetExportSchedule",
+			Handler:    _PdfService_GetExportSchedule_Handler,
+		},
+		{
+			MethodName: "UpdateExportSchedule",
+			Handler:    _PdfService_UpdateExportSchedule_Handler,
+		},
+		{
+			MethodName: "DeleteExportSchedule",
+			Handler:    _PdfService_DeleteExportSchedule_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchExport",
+			Handler:       _PdfService_WatchExport_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "VerifyExport",
+			Handler:       _PdfService_VerifyExport_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pdf.proto",
 }
@@ -32,6 +32,7 @@ const (
 type Auther interface {
 	GetRoles() []int64
 	GetUserId() int64
+	GetUserName() string
 	GetUserIp() string
 	GetDomainId() int64
 	GetPermissions() []string
@@ -32,6 +32,17 @@ func (s *UserAuthSession) GetUserId() int64 {
 	return s.User.Id
 }
 
+// GetUserName returns the display name of the user, the username if the name is not set.
+func (s *UserAuthSession) GetUserName() string {
+	if s.User == nil {
+		return ""
+	}
+	if s.User.Name != "" {
+		return s.User.Name
+	}
+	return s.User.Username
+}
+
 func (s *UserAuthSession) GetUserIp() string {
 	if s.UserIp == "" {
 		return "unknown"
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -17,6 +18,8 @@ type AppConfig struct {
 	Redis    *RedisConfig    `json:"redis,omitempty"`
 	Database *DatabaseConfig `json:"database,omitempty"`
 	Export   *ExportConfig   `json:"export,omitempty"`
+	Video    *VideoConfig    `json:"video,omitempty"`
+	Pdf      *PdfConfig      `json:"pdf,omitempty"`
 }
 
 type ConsulConfig struct {
@@ -37,6 +40,46 @@ type DatabaseConfig struct {
 
 type ExportConfig struct {
 	Workers int `json:"workers"`
+	// LeaseTimeout is how long a worker owns a task without renewing its lease.
+	// Tasks with an expired lease are returned to the queue.
+	LeaseTimeout time.Duration `json:"leaseTimeout"`
+	// MaxAttempts is how many times a task is tried before it is moved to the dead-letter list.
+	MaxAttempts int `json:"maxAttempts"`
+	// RetryBaseDelay is the delay before the first retry, doubled on every next one up to RetryMaxDelay.
+	RetryBaseDelay time.Duration `json:"retryBaseDelay"`
+	RetryMaxDelay  time.Duration `json:"retryMaxDelay"`
+	// MaxFiles is the ceiling of files in one export. Larger exports fail instead of being truncated.
+	MaxFiles int `json:"maxFiles"`
+	// TaskDownloads is how many files a single task downloads in parallel,
+	// MaxDownloads is how many files all workers of the process download in parallel.
+	TaskDownloads int `json:"taskDownloads"`
+	MaxDownloads  int `json:"maxDownloads"`
+}
+
+// VideoConfig configures time-lapse video exports.
+type VideoConfig struct {
+	// FFmpegPath is the ffmpeg binary of video exports, also used to join the recordings of audio exports.
+	FFmpegPath string `json:"ffmpegPath"`
+	// FrameDuration is how long every screenshot is shown unless the export asks for another duration.
+	FrameDuration time.Duration `json:"frameDuration"`
+	Width         int           `json:"width"`
+	Height        int           `json:"height"`
+	// FontFile is the font of the timestamp overlay, the default font of ffmpeg when empty.
+	FontFile string `json:"fontFile"`
+}
+
+// PdfConfig configures PDF exports.
+type PdfConfig struct {
+	// FontFile is the TrueType font of captions, headers and footers, the built-in Go font when empty.
+	FontFile string `json:"fontFile"`
+	// PasswordKey is the base64 AES-256 key sealing the passwords of encrypted exports while
+	// they are queued, and the access tokens export schedules run with. It must be the same on every
+	// instance. Encrypted exports and export schedules are disabled when empty.
+	PasswordKey string `json:"passwordKey"`
+	// SigningKey is the PKCS #8 PEM Ed25519 key signing the manifests and digests of PDF exports,
+	// SigningCertificate the optional PEM X.509 certificate of the key. Signing is disabled without a key.
+	SigningKey         string `json:"signingKey"`
+	SigningCertificate string `json:"signingCertificate"`
 }
 
 func LoadConfig() (*AppConfig, error) {
@@ -76,6 +119,24 @@ func bindFlagsAndEnv() {
 	pflag.Int("redis_db", 0, "Redis DB number")
 	// export
 	pflag.Int("workers", 5, "Number of concurrent export workers")
+	pflag.Duration("task_lease", time.Minute, "How long a worker owns an export task without renewing its lease")
+	pflag.Int("task_max_attempts", 5, "How many times an export task is tried before it is dead-lettered")
+	pflag.Duration("task_retry_base_delay", 5*time.Second, "Delay before the first retry of a failed export task")
+	pflag.Duration("task_retry_max_delay", 5*time.Minute, "Upper bound of the delay between export task retries")
+	pflag.Int("export_max_files", 10000, "Maximum number of files in one export")
+	pflag.Int("task_downloads", 8, "Number of files a single export task downloads in parallel")
+	pflag.Int("max_downloads", 32, "Number of files all export workers download in parallel")
+	// video
+	pflag.String("ffmpeg_path", "/usr/bin/ffmpeg", "Path to the ffmpeg binary used for video and audio exports")
+	pflag.Duration("video_frame_duration", time.Second, "Default time every screenshot is shown in a video export")
+	pflag.Int("video_width", 1920, "Width of video exports")
+	pflag.Int("video_height", 1080, "Height of video exports")
+	pflag.String("video_font_file", "", "Font of the timestamp overlay of video exports")
+	// pdf
+	pflag.String("pdf_font_file", "", "TrueType font of the text in PDF exports")
+	pflag.String("pdf_password_key", "", "Base64 AES-256 key sealing the passwords of encrypted PDF exports and the tokens of export schedules")
+	pflag.String("pdf_signing_key", "", "Ed25519 PEM key signing the manifests and digests of PDF exports")
+	pflag.String("pdf_signing_certificate", "", "X.509 PEM certificate of the PDF signing key")
 
 	pflag.Parse()
 
@@ -119,7 +180,29 @@ func buildAppConfig(file string) *AppConfig {
 		File:     file,
 		TempDir:  tempDir,
 		Database: &DatabaseConfig{Url: viper.GetString("data_source")},
-		Export:   &ExportConfig{Workers: viper.GetInt("workers")},
+		Export: &ExportConfig{
+			Workers:        viper.GetInt("workers"),
+			LeaseTimeout:   viper.GetDuration("task_lease"),
+			MaxAttempts:    viper.GetInt("task_max_attempts"),
+			RetryBaseDelay: viper.GetDuration("task_retry_base_delay"),
+			RetryMaxDelay:  viper.GetDuration("task_retry_max_delay"),
+			MaxFiles:       viper.GetInt("export_max_files"),
+			TaskDownloads:  viper.GetInt("task_downloads"),
+			MaxDownloads:   viper.GetInt("max_downloads"),
+		},
+		Video: &VideoConfig{
+			FFmpegPath:    viper.GetString("ffmpeg_path"),
+			FrameDuration: viper.GetDuration("video_frame_duration"),
+			Width:         viper.GetInt("video_width"),
+			Height:        viper.GetInt("video_height"),
+			FontFile:      viper.GetString("video_font_file"),
+		},
+		Pdf: &PdfConfig{
+			FontFile:           viper.GetString("pdf_font_file"),
+			PasswordKey:        viper.GetString("pdf_password_key"),
+			SigningKey:         viper.GetString("pdf_signing_key"),
+			SigningCertificate: viper.GetString("pdf_signing_certificate"),
+		},
 		Consul: &ConsulConfig{
 			Id:            viper.GetString("id"),
 			Address:       viper.GetString("consul"),
@@ -12,8 +12,15 @@ import (
 	cache "github.com/webitel/media-exporter/internal/cache/redis"
 	"github.com/webitel/media-exporter/internal/errors"
 	"github.com/webitel/media-exporter/internal/server"
+	"github.com/webitel/media-exporter/internal/service"
 	"github.com/webitel/media-exporter/internal/store"
 	"github.com/webitel/media-exporter/internal/store/postgres"
+	"github.com/webitel/media-exporter/internal/util/audio"
+	"github.com/webitel/media-exporter/internal/util/pdf"
+	"github.com/webitel/media-exporter/internal/util/seal"
+	"github.com/webitel/media-exporter/internal/util/sign"
+	"github.com/webitel/media-exporter/internal/util/video"
+	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -28,6 +35,19 @@ type App struct {
 	Cache          *cache.RedisCache
 	server         *server.Server
 	StorageClient  storage.FileServiceClient
+	// TranscriptClient reads the transcripts of call recordings.
+	TranscriptClient storage.FileTranscriptServiceClient
+	VideoEncoder     video.Encoder
+	AudioEncoder     audio.Encoder
+	PdfFont          *pdf.Font
+	// PasswordSealer seals the passwords of encrypted exports, nil if encrypted exports are disabled.
+	PasswordSealer *seal.Sealer
+	// Signer signs the manifests and digests of PDF exports, nil if signing is disabled.
+	Signer *sign.Signer
+	// downloadSlots bounds the concurrent storage downloads of all export workers.
+	downloadSlots *semaphore.Weighted
+	// scheduler makes the runs of the export schedules while this instance is the leader.
+	scheduler *service.ExportScheduler
 
 	// gRPC connections
 	storageConn    *grpc.ClientConn
@@ -51,6 +71,17 @@ func New(config *cfg.AppConfig, shutdown func(ctx context.Context) error) (*App,
 	if err := app.initGRPCClients(); err != nil {
 		return nil, err
 	}
+	app.initEncoders()
+	if err := app.initPdfFont(); err != nil {
+		return nil, err
+	}
+	if err := app.initPasswordSealer(); err != nil {
+		return nil, err
+	}
+	if err := app.initSigner(); err != nil {
+		return nil, err
+	}
+	app.downloadSlots = semaphore.NewWeighted(int64(app.globalDownloads()))
 	if err := app.initSessionManager(); err != nil {
 		return nil, err
 	}
@@ -95,6 +126,7 @@ func (app *App) initGRPCClients() error {
 		return errors.New("unable to create storage client", errors.WithCause(err))
 	}
 	app.StorageClient = storage.NewFileServiceClient(app.storageConn)
+	app.TranscriptClient = storage.NewFileTranscriptServiceClient(app.storageConn)
 
 	app.webitelAppConn, err = grpc.NewClient(
 		fmt.Sprintf("consul://%s/go.webitel.app?wait=14s", app.Config.Consul.Address),
@@ -107,6 +139,52 @@ func (app *App) initGRPCClients() error {
 	return nil
 }
 
+func (app *App) initEncoders() {
+	var ffmpegPath string
+	if app.Config.Video != nil {
+		ffmpegPath = app.Config.Video.FFmpegPath
+	}
+	app.VideoEncoder = video.NewFFmpeg(ffmpegPath, app.Config.TempDir)
+	app.AudioEncoder = audio.NewFFmpeg(ffmpegPath, app.Config.TempDir)
+}
+
+func (app *App) initPdfFont() error {
+	var err error
+	if app.Config.Pdf != nil && app.Config.Pdf.FontFile != "" {
+		app.PdfFont, err = pdf.LoadFont(app.Config.Pdf.FontFile)
+	} else {
+		app.PdfFont, err = pdf.DefaultFont()
+	}
+	if err != nil {
+		return errors.New("unable to load PDF font", errors.WithCause(err))
+	}
+	return nil
+}
+
+func (app *App) initPasswordSealer() error {
+	if app.Config.Pdf == nil || app.Config.Pdf.PasswordKey == "" {
+		return nil
+	}
+	sealer, err := seal.New(app.Config.Pdf.PasswordKey)
+	if err != nil {
+		return errors.New("unable to init PDF password sealer", errors.WithCause(err))
+	}
+	app.PasswordSealer = sealer
+	return nil
+}
+
+func (app *App) initSigner() error {
+	if app.Config.Pdf == nil || app.Config.Pdf.SigningKey == "" {
+		return nil
+	}
+	signer, err := sign.Load(app.Config.Pdf.SigningKey, app.Config.Pdf.SigningCertificate)
+	if err != nil {
+		return errors.New("unable to load PDF signing key", errors.WithCause(err))
+	}
+	app.Signer = signer
+	return nil
+}
+
 func (app *App) initSessionManager() error {
 	manager, err := webitel_app.New(app.webitelAppConn)
 	if err != nil {
@@ -133,6 +211,7 @@ func (app *App) Start(ctx context.Context) error {
 
 	go app.server.Start()
 	app.StartExportWorker(ctx)
+	go app.runExportScheduler(ctx)
 
 	return <-app.exitCh
 }
@@ -161,14 +240,6 @@ func (app *App) Stop() error {
 		}
 	}
 
-	if app.Cache != nil {
-		if err := app.Cache.Clear(); err != nil {
-			slog.Error("redis Cache clear error", "err", err)
-		} else {
-			slog.Info("redis Cache cleared")
-		}
-	}
-
 	if app.shutdown != nil {
 		if err := app.shutdown(context.Background()); err != nil {
 			slog.Error("shutdown hook error", "err", err)
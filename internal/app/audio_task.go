@@ -0,0 +1,187 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/webitel/media-exporter/api/storage"
+	"github.com/webitel/media-exporter/internal/domain/model"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/util"
+	"github.com/webitel/media-exporter/internal/util/audio"
+)
+
+// unsafePathChars are replaced in the names of the folders of the calls of a ZIP archive.
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// HandleAudioTask collects the audio recordings of the calls of the task, oldest first, into
+// a single file of the format of the task, or into a ZIP archive of the unchanged originals.
+// Recordings that cannot be downloaded are skipped.
+func (app *App) HandleAudioTask(ctx context.Context, session *model.Session, task domain.ExportTask) error {
+	historyID, err := app.taskHistoryID(task)
+	if err != nil {
+		return err
+	}
+
+	if err := SetTaskStatus(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, "processing", nil); err != nil {
+		return fmt.Errorf("failed to set processing status: %w", err)
+	}
+
+	ctx = util.ContextWithHeaders(ctx, task.Headers)
+
+	app.reportStage(ctx, task.TaskID, domain.StageSearching, 0, 0)
+	recordings, err := app.callRecordings(ctx, task)
+	if err != nil {
+		return fmt.Errorf("search recordings failed: %w", err)
+	}
+	if len(recordings) == 0 {
+		return fmt.Errorf("no recordings found for task %s", task.TaskID)
+	}
+
+	ext, mimeType := ZipExportType, "application/zip"
+	if task.Type != AudioZipExportType {
+		ext, mimeType = task.Type, audio.Format(task.Type).MimeType()
+	}
+	tempFilePath, err := app.exportTempFile(task.Type, ext)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tempFilePath) }()
+
+	progress := newStageCounter(int64(len(recordings)), func(current, total int64) {
+		app.reportStage(ctx, task.TaskID, domain.StageDownloading, current, total)
+	})
+	var included, skipped int
+	if task.Type == AudioZipExportType {
+		manifest, err := writeZipFiles(ctx, app.StorageClient, session, task, recordings, nil, tempFilePath, progress)
+		if err != nil {
+			slog.ErrorContext(ctx, "writeZipFiles failed", "taskID", task.TaskID, "error", err)
+			return fmt.Errorf("ZIP generation failed: %w", err)
+		}
+		included, skipped = len(manifest.Files), len(manifest.Skipped)
+	} else {
+		included, skipped, err = app.joinRecordings(ctx, session, task, recordings, tempFilePath, progress)
+		if err != nil {
+			slog.ErrorContext(ctx, "joinRecordings failed", "taskID", task.TaskID, "error", err)
+			return fmt.Errorf("audio encoding failed: %w", err)
+		}
+	}
+
+	res, err := uploadFileToStorage(ctx, session, app, tempFilePath, task, uploadName(task, ext), mimeType)
+	if err != nil {
+		slog.ErrorContext(ctx, "uploadFileToStorage failed", "taskID", task.TaskID, "error", err)
+		return fmt.Errorf("upload failed: %w", err)
+	}
+
+	if err := SetTaskDone(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, res, nil); err != nil {
+		slog.ErrorContext(ctx, "failed to set done status", "taskID", task.TaskID, "error", err)
+		return fmt.Errorf("failed to set done status: %w", err)
+	}
+
+	_ = app.Cache.ClearExportTask(task.TaskID)
+
+	slog.InfoContext(ctx, "audio task completed successfully",
+		"taskID", task.TaskID,
+		"fileID", res.FileId,
+		"recordings", included,
+		"skipped", skipped,
+	)
+
+	return nil
+}
+
+// callRecordings lists the audio recordings of the calls of the task, oldest first. The recordings
+// of an export of several calls are grouped by call, so that a ZIP archive has a folder per call.
+func (app *App) callRecordings(ctx context.Context, task domain.ExportTask) ([]screenshot, error) {
+	calls := task.CallIDs
+	if len(calls) == 0 {
+		calls = []string{task.CallID}
+	}
+	var recordings []screenshot
+	for _, callID := range calls {
+		files, err := app.searchCallFiles(ctx, task, callID, storage.UploadFileChannel_CallChannel)
+		if err != nil {
+			return nil, fmt.Errorf("call %s: %w", callID, err)
+		}
+		var group screenshotGroup
+		if len(calls) > 1 {
+			group = screenshotGroup{key: "call_" + unsafePathChars.ReplaceAllString(callID, "_"), title: callID}
+		}
+		for _, f := range files {
+			if !strings.HasPrefix(f.GetMimeType(), "audio/") {
+				continue
+			}
+			recordings = append(recordings, screenshot{
+				id:    strconv.FormatInt(f.GetId(), 10),
+				file:  f,
+				time:  recordingStart(f),
+				group: group,
+			})
+		}
+	}
+	sortRecordings(recordings)
+	return recordings, nil
+}
+
+// sortRecordings orders recordings by their start, those of unknown start last.
+func sortRecordings(recordings []screenshot) {
+	sort.SliceStable(recordings, func(i, j int) bool {
+		a, b := recordings[i].time, recordings[j].time
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+}
+
+// joinRecordings downloads the recordings and joins them in order into a single file at outPath
+// in the format of the task. It returns the numbers of joined and skipped recordings.
+func (app *App) joinRecordings(
+	ctx context.Context,
+	session *model.Session,
+	task domain.ExportTask,
+	recordings []screenshot,
+	outPath string,
+	progress *stageCounter,
+) (int, int, error) {
+	workDir, err := os.MkdirTemp(app.Config.TempDir, "recordings-")
+	if err != nil {
+		return 0, 0, fmt.Errorf("create work dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(workDir) }()
+
+	var inputs []string
+	var skipped int
+	for _, rec := range recordings {
+		path := filepath.Join(workDir, rec.id+util.GetFileExt(rec.file.GetMimeType()))
+		if err := downloadToFile(ctx, app.StorageClient, session.DomainID(), rec.file.GetId(), path); err != nil {
+			if ctx.Err() != nil {
+				return 0, 0, ctx.Err()
+			}
+			slog.WarnContext(ctx, "skip recording in audio export", "taskID", task.TaskID, "file_id", rec.file.GetId(), "error", err)
+			skipped++
+		} else {
+			inputs = append(inputs, path)
+		}
+		progress.Inc()
+	}
+	if len(inputs) == 0 {
+		return 0, skipped, fmt.Errorf("no recordings could be downloaded for task %s", task.TaskID)
+	}
+
+	err = app.AudioEncoder.Concat(ctx, inputs, outPath, audio.Format(task.Type), func(done time.Duration) {
+		app.reportStage(ctx, task.TaskID, domain.StageRendering, int64(done.Seconds()), 0)
+	})
+	if err != nil {
+		return 0, skipped, err
+	}
+	return len(inputs), skipped, nil
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSortRecordings(t *testing.T) {
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	recordings := []screenshot{
+		{id: "1", time: start.Add(time.Hour)},
+		{id: "2"},
+		{id: "3", time: start},
+		{id: "4"},
+		{id: "5", time: start.Add(time.Minute)},
+	}
+	sortRecordings(recordings)
+
+	var got []string
+	for _, r := range recordings {
+		got = append(got, r.id)
+	}
+	if want := []string{"3", "5", "1", "2", "4"}; !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
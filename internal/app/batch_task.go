@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/webitel/media-exporter/api/storage"
+	"github.com/webitel/media-exporter/internal/domain/model"
+	"github.com/webitel/media-exporter/internal/domain/model/options"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// batchPollInterval is how often a batch export checks whether its exports finished.
+const batchPollInterval = 5 * time.Second
+
+// HandleBatchTask tracks the exports of a batch export. Until all of them finished, it reports
+// how many did and puts itself back in the delayed queue, so that it never holds a worker its
+// exports need. Once they finished, it packs their files into one ZIP archive if the batch asks for it.
+// A batch succeeds if at least one of its exports did.
+func (app *App) HandleBatchTask(ctx context.Context, session *model.Session, task domain.ExportTask) error {
+	historyID, err := app.taskHistoryID(task)
+	if err != nil {
+		return err
+	}
+	if task.Batch == nil {
+		return fmt.Errorf("batch export %s has no sources", task.TaskID)
+	}
+
+	if status, _ := app.Cache.GetExportStatus(task.TaskID); status != "processing" {
+		if err := SetTaskStatus(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, "processing", nil); err != nil {
+			return fmt.Errorf("failed to set processing status: %w", err)
+		}
+	}
+
+	upd := taskUpdateOptions(ctx, task)
+	children, err := app.Store.Pdf().ListChildExports(&options.SearchOptions{Context: ctx, Time: upd.Time, Auth: upd.Auth}, historyID)
+	if err != nil {
+		// The exports are checked again on the next attempt, a database hiccup must not fail the batch.
+		return status.Errorf(codes.Unavailable, "list batch exports failed: %v", err)
+	}
+	summary := summarizeBatch(children)
+	app.reportStage(ctx, task.TaskID, domain.StageWaiting, int64(summary.finished), int64(len(children)))
+	if summary.finished < len(children) {
+		return app.Cache.Retry(task, batchPollInterval)
+	}
+	if len(summary.done) == 0 {
+		return fmt.Errorf("none of the %d exports of batch %s succeeded", len(children), task.TaskID)
+	}
+
+	if !task.Batch.Combine {
+		if err := SetTaskStatus(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, "done", nil); err != nil {
+			slog.ErrorContext(ctx, "failed to set done status", "taskID", task.TaskID, "error", err)
+			return fmt.Errorf("failed to set done status: %w", err)
+		}
+	} else {
+		tempFilePath, err := app.exportTempFile(BatchExportType, ZipExportType)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = os.Remove(tempFilePath) }()
+
+		files := batchArchiveFiles(summary.done)
+		progress := newStageCounter(int64(len(files)), func(current, total int64) {
+			app.reportStage(ctx, task.TaskID, domain.StageDownloading, current, total)
+		})
+		if _, err := writeZipFiles(ctx, app.StorageClient, session, task, files, summary.skipped, tempFilePath, progress); err != nil {
+			slog.ErrorContext(ctx, "writeZipFiles failed", "taskID", task.TaskID, "error", err)
+			return fmt.Errorf("ZIP generation failed: %w", err)
+		}
+
+		res, err := uploadFileToStorage(ctx, session, app, tempFilePath, task, uploadName(task, ZipExportType), "application/zip")
+		if err != nil {
+			slog.ErrorContext(ctx, "uploadFileToStorage failed", "taskID", task.TaskID, "error", err)
+			return fmt.Errorf("upload failed: %w", err)
+		}
+
+		if err := SetTaskDone(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, res, nil); err != nil {
+			slog.ErrorContext(ctx, "failed to set done status", "taskID", task.TaskID, "error", err)
+			return fmt.Errorf("failed to set done status: %w", err)
+		}
+	}
+
+	_ = app.Cache.ClearExportTask(task.TaskID)
+
+	slog.InfoContext(ctx, "batch task completed successfully",
+		"taskID", task.TaskID,
+		"exports", len(children),
+		"done", len(summary.done),
+		"combined", task.Batch.Combine,
+	)
+
+	return nil
+}
+
+// batchSummary is the state of the exports of a batch export.
+type batchSummary struct {
+	finished int
+	done     []*domain.HistoryRecord
+	// skipped are the finished exports without a file, with the reason.
+	skipped []domain.ManifestSkippedFile
+}
+
+func summarizeBatch(children []*domain.HistoryRecord) batchSummary {
+	var s batchSummary
+	for _, child := range children {
+		if !domain.IsFinalStatus(child.Status) {
+			continue
+		}
+		s.finished++
+		if child.Status == "done" && child.FileID != 0 {
+			s.done = append(s.done, child)
+			continue
+		}
+		reason := "export " + child.Status
+		if child.LastError != "" {
+			reason += ": " + child.LastError
+		}
+		s.skipped = append(s.skipped, domain.ManifestSkippedFile{Name: child.TaskID, Reason: reason})
+	}
+	return s
+}
+
+// batchArchiveFiles lists the files of the exports of a batch for its combined archive,
+// in a folder per agent or call.
+func batchArchiveFiles(done []*domain.HistoryRecord) []screenshot {
+	files := make([]screenshot, 0, len(done))
+	for _, rec := range done {
+		group := screenshotGroup{key: "agent_" + strconv.FormatInt(rec.AgentID, 10), title: strconv.FormatInt(rec.AgentID, 10)}
+		if rec.CallID != "" {
+			group = screenshotGroup{key: "call_" + unsafePathChars.ReplaceAllString(rec.CallID, "_"), title: rec.CallID}
+		}
+		files = append(files, screenshot{
+			id: strconv.FormatInt(rec.FileID, 10),
+			file: &storage.File{
+				Id:         rec.FileID,
+				Name:       rec.Name,
+				MimeType:   rec.MimeType,
+				UploadedAt: rec.UpdatedAt,
+			},
+			group: group,
+		})
+	}
+	return files
+}
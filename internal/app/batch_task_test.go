@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/webitel/media-exporter/internal/domain/model"
+	"github.com/webitel/media-exporter/internal/domain/model/options"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/store"
+)
+
+// failingStore fails to list the exports of a batch.
+type failingStore struct {
+	store.Store
+	store.PdfStore
+}
+
+func (s failingStore) Pdf() store.PdfStore { return s }
+
+func (failingStore) ListChildExports(*options.SearchOptions, int64) ([]*domain.HistoryRecord, error) {
+	return nil, errors.New("connection reset by peer")
+}
+
+// A batch whose exports cannot be listed for a moment is retried, not failed.
+func TestHandleBatchTaskRetriesListFailure(t *testing.T) {
+	app := newCancelApp(t)
+	app.Store = failingStore{}
+	task := domain.ExportTask{TaskID: "batch.zip", HistoryID: 1, Batch: &domain.BatchOptions{}}
+	if err := app.Cache.SetExportStatus(task.TaskID, "processing"); err != nil {
+		t.Fatalf("SetExportStatus() error = %v", err)
+	}
+	session, _ := model.NewSession(7, 1, "token")
+
+	err := app.HandleBatchTask(context.Background(), session, task)
+	if err == nil || !isRetryable(err) {
+		t.Errorf("HandleBatchTask() error = %v, want a retryable error", err)
+	}
+}
+
+func TestSummarizeBatch(t *testing.T) {
+	children := []*domain.HistoryRecord{
+		{ID: 1, TaskID: "a", Name: "a.pdf", Status: "done", FileID: 10, AgentID: 7},
+		{ID: 2, TaskID: "b", Status: "processing"},
+		{ID: 3, TaskID: "c", Status: "failed", LastError: "no screenshots"},
+		{ID: 4, TaskID: "d", Status: "cancelled"},
+		{ID: 5, TaskID: "e", Name: "e.zip", Status: "done", FileID: 11, CallID: "x/y"},
+	}
+	s := summarizeBatch(children)
+
+	if s.finished != 4 {
+		t.Errorf("finished = %d, want 4", s.finished)
+	}
+	if len(s.done) != 2 || s.done[0].ID != 1 || s.done[1].ID != 5 {
+		t.Errorf("done = %v, want records 1 and 5", s.done)
+	}
+	if len(s.skipped) != 2 {
+		t.Fatalf("skipped = %v, want 2 entries", s.skipped)
+	}
+	if want := "export failed: no screenshots"; s.skipped[0].Reason != want {
+		t.Errorf("skipped reason = %q, want %q", s.skipped[0].Reason, want)
+	}
+
+	files := batchArchiveFiles(s.done)
+	for i, want := range []string{"files/agent_7/10_a.pdf", "files/call_x_y/11_e.zip"} {
+		if got := zipEntryName(files[i].file, files[i].group); got != want {
+			t.Errorf("entry %d = %q, want %q", i, got, want)
+		}
+	}
+}
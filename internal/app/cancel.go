@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/errors"
+)
+
+// cancelPollInterval is how often a worker checks whether its running task was cancelled.
+const cancelPollInterval = 2 * time.Second
+
+// errExportCancelled is the cancellation cause of a task context aborted by CancelExport.
+var errExportCancelled = errors.New("export cancelled by user")
+
+// watchCancel cancels the task context once a cancellation of the task is requested,
+// until the returned stop function is called.
+func (app *App) watchCancel(ctx context.Context, taskID string, cancel context.CancelCauseFunc) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(cancelPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if app.cancelRequested(ctx, taskID) {
+					slog.InfoContext(ctx, "aborting cancelled export task", "taskID", taskID)
+					cancel(errExportCancelled)
+					return
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func (app *App) cancelRequested(ctx context.Context, taskID string) bool {
+	requested, err := app.Cache.IsCancelRequested(taskID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to check task cancellation", "taskID", taskID, "error", err)
+		return false
+	}
+	return requested
+}
+
+// finishCancelled marks an aborted task as cancelled and drops its cache entries.
+func (app *App) finishCancelled(ctx context.Context, task domain.ExportTask) {
+	historyID, err := app.taskHistoryID(task)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to resolve export history", "taskID", task.TaskID, "error", err)
+	} else if err := SetTaskStatus(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, "cancelled", nil); err != nil {
+		slog.ErrorContext(ctx, "failed to set cancelled status", "taskID", task.TaskID, "error", err)
+	}
+
+	_ = app.Cache.ClearExportTask(task.TaskID)
+
+	slog.InfoContext(ctx, "export task cancelled", "taskID", task.TaskID)
+}
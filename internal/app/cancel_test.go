@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	cache "github.com/webitel/media-exporter/internal/cache/redis"
+)
+
+func newCancelApp(t *testing.T) *App {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	c, err := cache.NewRedisCache(srv.Addr(), "", 0)
+	if err != nil {
+		t.Fatalf("NewRedisCache() error = %v", err)
+	}
+	return &App{Cache: c}
+}
+
+func TestWatchCancelAbortsRequestedTask(t *testing.T) {
+	app := newCancelApp(t)
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	stop := app.watchCancel(ctx, "task.pdf", cancel)
+	defer stop()
+	if err := app.Cache.RequestCancel("task.pdf"); err != nil {
+		t.Fatalf("RequestCancel() error = %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * cancelPollInterval):
+		t.Fatal("task context was not cancelled after the cancellation request")
+	}
+	if cause := context.Cause(ctx); cause != errExportCancelled {
+		t.Errorf("cancellation cause = %v, want %v", cause, errExportCancelled)
+	}
+}
+
+func TestWatchCancelStopped(t *testing.T) {
+	app := newCancelApp(t)
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	app.watchCancel(ctx, "task.pdf", cancel)()
+	if err := app.Cache.RequestCancel("task.pdf"); err != nil {
+		t.Fatalf("RequestCancel() error = %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("a finished task must not be cancelled")
+	case <-time.After(cancelPollInterval + 500*time.Millisecond):
+	}
+}
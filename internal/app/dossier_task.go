@@ -0,0 +1,264 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/webitel/media-exporter/api/storage"
+	"github.com/webitel/media-exporter/internal/domain/model"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/util"
+	"github.com/webitel/media-exporter/internal/util/pdf"
+)
+
+// Sections of a dossier, in the outline and the table of contents.
+const (
+	timelineSection  = "Timeline"
+	inventorySection = "Files"
+)
+
+// timelineEntry is a screenshot or a phrase of a call at the time it was taken or spoken.
+type timelineEntry struct {
+	at        time.Time // zero if unknown
+	paragraph pdf.Paragraph
+	// shot is the index of the screenshot of the entry, -1 for a phrase.
+	shot int
+}
+
+// HandleDossierTask collects everything about a call into one PDF: the screenshots of the call
+// and the phrases of its recordings on a single timeline, followed by an inventory of its files.
+// Either may be missing, but not both.
+func (app *App) HandleDossierTask(ctx context.Context, session *model.Session, task domain.ExportTask) error {
+	historyID, err := app.taskHistoryID(task)
+	if err != nil {
+		return err
+	}
+
+	if err := SetTaskStatus(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, "processing", nil); err != nil {
+		return fmt.Errorf("failed to set processing status: %w", err)
+	}
+
+	ctx = util.ContextWithHeaders(ctx, task.Headers)
+
+	app.reportStage(ctx, task.TaskID, domain.StageSearching, 0, 0)
+	pages, err := app.screenshotPages(task)
+	if err != nil {
+		return err
+	}
+	screenshots, err := fetchScreenshots(ctx, session, app, task, pages, pdfImageWidth)
+	if err != nil {
+		slog.ErrorContext(ctx, "fetchScreenshots failed", "taskID", task.TaskID, "error", err)
+		return fmt.Errorf("download failed: %w", err)
+	}
+	defer screenshots.Cleanup()
+
+	files, err := app.callFiles(ctx, task)
+	if err != nil {
+		return fmt.Errorf("call files failed: %w", err)
+	}
+	if pages.Count() == 0 && len(files) == 0 {
+		return fmt.Errorf("no screenshots or files found for task %s", task.TaskID)
+	}
+	var recordings []*storage.File
+	for _, f := range files {
+		if isRecording(f) {
+			recordings = append(recordings, f)
+		}
+	}
+	transcripts, failedTranscripts, err := app.readTranscripts(ctx, task, recordings)
+	if err != nil {
+		return fmt.Errorf("transcripts failed: %w", err)
+	}
+
+	layout := app.pdfLayout(task)
+	opts := layout.document(task)
+	if opts.Encryption, err = app.pdfEncryption(task); err != nil {
+		return err
+	}
+	signed := task.Pdf != nil && task.Pdf.SignedManifest
+	if signed && app.Signer == nil {
+		return fmt.Errorf("export %s asks for a signed manifest but no signing key is configured", task.TaskID)
+	}
+
+	shots := layout.order.downloaded(screenshots.Paths, screenshots.Infos)
+	timeline := layout.dossierTimeline(shots, transcripts)
+	skipped := append(append([]domain.ManifestSkippedFile{}, screenshots.Failed...), failedTranscripts...)
+
+	res, digest, err := app.streamPDF(ctx, session, task, opts, func(doc *pdf.Document) error {
+		var rendered []int
+		if len(timeline) > 0 {
+			if err := doc.AddParagraph(pdf.Paragraph{Heading: true, Text: timelineSection, Section: timelineSection}); err != nil {
+				return err
+			}
+		}
+		for i, entry := range timeline {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			err := doc.AddParagraph(entry.paragraph)
+			switch {
+			case err != nil && entry.shot < 0:
+				return err
+			case err != nil:
+				slog.WarnContext(ctx, "skipping screenshot that cannot be rendered", "path", entry.paragraph.Image, "error", err)
+			case entry.shot >= 0:
+				rendered = append(rendered, entry.shot)
+			}
+			app.reportStage(ctx, task.TaskID, domain.StageRendering, int64(i+1), int64(len(timeline)))
+		}
+
+		unrendered := pdfManifest(task, shots, rendered, nil).Skipped
+		for _, p := range layout.inventoryParagraphs(files, append(skipped, unrendered...)) {
+			if err := doc.AddParagraph(p); err != nil {
+				return err
+			}
+		}
+
+		doc.SetCover(layout.dossierCover(task, len(rendered), transcripts, len(files)))
+		if signed {
+			manifest := pdfManifest(task, shots, rendered, skipped)
+			manifest.Files = append(manifest.Files, transcriptManifest(task, transcripts, nil).Files...)
+			return app.attachManifest(doc, manifest)
+		}
+		return nil
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "streamPDF failed", "taskID", task.TaskID, "error", err)
+		return err
+	}
+
+	if err := SetTaskDone(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, res, app.signDigest(task, digest)); err != nil {
+		slog.ErrorContext(ctx, "failed to set done status", "taskID", task.TaskID, "error", err)
+		return fmt.Errorf("failed to set done status: %w", err)
+	}
+
+	_ = app.Cache.ClearExportTask(task.TaskID)
+
+	slog.InfoContext(ctx, "dossier task completed successfully",
+		"taskID", task.TaskID,
+		"fileID", res.FileId,
+		"screenshots", len(shots),
+		"recordings", len(transcripts),
+		"files", len(files),
+	)
+
+	return nil
+}
+
+// dossierTimeline puts the screenshots and the phrases of the recordings in the order they were
+// taken and spoken. Entries of unknown time go last.
+func (l pdfLayout) dossierTimeline(shots []screenshot, transcripts []recordingTranscript) []timelineEntry {
+	var entries []timelineEntry
+	for i, shot := range shots {
+		text := "Screenshot · ID " + shot.id
+		if name := shot.file.GetName(); name != "" {
+			text = "Screenshot " + name + " · ID " + shot.id
+		}
+		entries = append(entries, timelineEntry{
+			at:        shot.time,
+			paragraph: pdf.Paragraph{Label: l.timelineLabel(shot.time), Text: text, Section: timelineSection, Image: shot.path},
+			shot:      i,
+		})
+	}
+	for _, t := range transcripts {
+		start := recordingStart(t.file)
+		for _, p := range t.phrases {
+			var at time.Time
+			if !start.IsZero() {
+				at = start.Add(time.Duration(float64(p.GetStartSec()) * float64(time.Second)))
+			}
+			entries = append(entries, timelineEntry{
+				at:        at,
+				paragraph: pdf.Paragraph{Label: l.timelineLabel(at), Text: fmt.Sprintf("Channel %d: %s", p.GetChannel(), p.GetPhrase()), Section: timelineSection},
+				shot:      -1,
+			})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].at, entries[j].at
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+	return entries
+}
+
+func (l pdfLayout) timelineLabel(at time.Time) string {
+	if at.IsZero() {
+		return "Time unknown"
+	}
+	return at.In(l.loc).Format(time.DateTime)
+}
+
+// recordingStart is when a recording started: its start time if the storage knows it,
+// its upload time otherwise.
+func recordingStart(f *storage.File) time.Time {
+	if start := f.GetProperties().GetStartTime(); start != 0 {
+		return storageTime(start)
+	}
+	return captureTime(f)
+}
+
+// inventoryParagraphs list the files of the call, followed by the files that are missing
+// from the dossier and why.
+func (l pdfLayout) inventoryParagraphs(files []*storage.File, skipped []domain.ManifestSkippedFile) []pdf.Paragraph {
+	paragraphs := []pdf.Paragraph{{Heading: true, Text: inventorySection, Section: inventorySection}}
+	for _, f := range files {
+		details := []string{f.GetName(), f.GetMimeType(), formatSize(f.GetSize()), "ID " + strconv.FormatInt(f.GetId(), 10)}
+		if f.GetSha256Sum() != "" {
+			details = append(details, "SHA-256 "+f.GetSha256Sum())
+		}
+		paragraphs = append(paragraphs, pdf.Paragraph{
+			Label:   l.timelineLabel(captureTime(f)),
+			Text:    strings.Join(details, " · "),
+			Section: inventorySection,
+		})
+	}
+	if len(files) == 0 {
+		paragraphs = append(paragraphs, pdf.Paragraph{Text: "The call has no files.", Section: inventorySection})
+	}
+	for _, s := range skipped {
+		paragraphs = append(paragraphs, pdf.Paragraph{
+			Label:   "Not included",
+			Text:    fmt.Sprintf("%s · ID %d · %s", s.Name, s.ID, s.Reason),
+			Section: inventorySection,
+		})
+	}
+	return paragraphs
+}
+
+// formatSize prints a file size in bytes with a binary unit.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// dossierCover describes a dossier on the cover page.
+func (l pdfLayout) dossierCover(task domain.ExportTask, screenshots int, transcripts []recordingTranscript, files int) *pdf.Cover {
+	phrases := 0
+	for _, t := range transcripts {
+		phrases += len(t.phrases)
+	}
+	fields := append(l.coverFields(task),
+		pdf.CoverField{Label: "Screenshots", Value: strconv.Itoa(screenshots)},
+		pdf.CoverField{Label: "Transcribed recordings", Value: strconv.Itoa(len(transcripts))},
+		pdf.CoverField{Label: "Phrases", Value: strconv.Itoa(phrases)},
+		pdf.CoverField{Label: "Files", Value: strconv.Itoa(files)},
+		pdf.CoverField{Label: "Export ID", Value: task.TaskID},
+	)
+	return &pdf.Cover{Title: l.documentTitle(task), Fields: fields}
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/webitel/media-exporter/api/storage"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+)
+
+func TestDossierTimeline(t *testing.T) {
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	shots := []screenshot{
+		{id: "1", file: &storage.File{Name: "a.jpg"}, path: "a.jpg", time: start.Add(30 * time.Second)},
+		{id: "2", path: "b.jpg"},
+		{id: "3", path: "c.jpg", time: start.Add(5 * time.Second)},
+	}
+	transcripts := []recordingTranscript{{
+		file: &storage.File{Id: 7, Properties: &storage.CustomFileProperties{StartTime: start.UnixMilli()}},
+		phrases: []*storage.TranscriptPhrase{
+			{StartSec: 1, Channel: 0, Phrase: "Hello"},
+			{StartSec: 40, Channel: 1, Phrase: "Bye"},
+		},
+	}}
+	layout := (&App{}).pdfLayout(domain.ExportTask{})
+
+	var got []string
+	var order []int
+	for _, e := range layout.dossierTimeline(shots, transcripts) {
+		got = append(got, e.paragraph.Label+" "+e.paragraph.Text)
+		order = append(order, e.shot)
+	}
+	want := []string{
+		"2026-03-01 10:00:01 Channel 0: Hello",
+		"2026-03-01 10:00:05 Screenshot · ID 3",
+		"2026-03-01 10:00:30 Screenshot a.jpg · ID 1",
+		"2026-03-01 10:00:40 Channel 1: Bye",
+		"Time unknown Screenshot · ID 2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("timeline = %q, want %q", got, want)
+	}
+	if want := []int{-1, 2, 0, -1, 1}; !reflect.DeepEqual(order, want) {
+		t.Errorf("screenshots = %v, want %v", order, want)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	for n, want := range map[int64]string{512: "512 B", 1536: "1.5 KiB", 5 << 20: "5.0 MiB"} {
+		if got := formatSize(n); got != want {
+			t.Errorf("formatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
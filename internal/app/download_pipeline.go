@@ -0,0 +1,367 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/webitel/media-exporter/api/storage"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/util"
+)
+
+const (
+	defaultTaskDownloads   = 8
+	defaultGlobalDownloads = 32
+)
+
+// Stages of the download pipeline a file can fail at.
+const (
+	stageDownload = "download"
+	stageResize   = "resize"
+)
+
+// screenshotSet is the outcome of the download pipeline: the local copies of the screenshots
+// keyed by file ID and the files that could not be fetched.
+type screenshotSet struct {
+	Paths  map[string]string
+	Infos  map[string]*storage.File
+	Failed []domain.ManifestSkippedFile
+	// dir holds the local copies, it belongs to the run that fetched them alone.
+	dir string
+}
+
+// Cleanup removes the local copies of the screenshots.
+func (s *screenshotSet) Cleanup() {
+	_ = os.RemoveAll(s.dir)
+}
+
+// downloadPipeline fetches the files of a search into temporary files in three bounded stages:
+// the search feeds files to a fixed pool of downloaders, which hand them over to a pool of resizers.
+// Stages are connected by small channels, so a slow stage holds back the previous one instead of
+// piling up open streams or temp files.
+type downloadPipeline struct {
+	download func(ctx context.Context, f *storage.File, path string) error
+	resize   func(path string) error
+	// slots bounds the concurrent downloads of all tasks of the process, nil for no bound.
+	slots     limiter
+	tempDir   string
+	downloads int
+	resizers  int
+	onFile    func()
+}
+
+// limiter is the part of semaphore.Weighted the pipeline uses.
+type limiter interface {
+	Acquire(ctx context.Context, n int64) error
+	Release(n int64)
+}
+
+type downloadedFile struct {
+	file *storage.File
+	path string
+}
+
+// Run consumes the search until it is exhausted and returns what was fetched. It fails only on
+// search errors and cancellation; single files that cannot be fetched are reported in Failed.
+// The files are fetched into a directory of their own, so that tasks fetching the same files at
+// the same time do not share them; the caller removes it with Cleanup.
+// On failure no temporary files are left behind.
+func (p *downloadPipeline) Run(ctx context.Context, pages *fileIterator) (*screenshotSet, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	dir, err := os.MkdirTemp(p.tempDir, "screenshots-")
+	if err != nil {
+		return nil, fmt.Errorf("create screenshots dir: %w", err)
+	}
+	set := &screenshotSet{
+		Paths: make(map[string]string),
+		Infos: make(map[string]*storage.File),
+		dir:   dir,
+	}
+	var mu sync.Mutex
+	fail := func(f *storage.File, stage string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		set.Failed = append(set.Failed, domain.ManifestSkippedFile{
+			ID:     f.Id,
+			Name:   f.Name,
+			Reason: fmt.Sprintf("%s: %v", stage, err),
+		})
+	}
+	done := func() {
+		if p.onFile != nil {
+			p.onFile()
+		}
+	}
+
+	jobs := make(chan *storage.File, p.downloads)
+	downloaded := make(chan downloadedFile, p.resizers)
+
+	// Search stage: the next page is only requested once the downloaders took the current one.
+	var searchErr error
+	go func() {
+		defer close(jobs)
+		for pages.Next(ctx) {
+			for _, f := range pages.Page() {
+				select {
+				case jobs <- f:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+		searchErr = pages.Err()
+	}()
+
+	// Download stage.
+	var downloaders sync.WaitGroup
+	for range p.downloads {
+		downloaders.Add(1)
+		go func() {
+			defer downloaders.Done()
+			for f := range jobs {
+				path, err := p.fetch(ctx, dir, f)
+				if err != nil {
+					if ctx.Err() == nil {
+						slog.WarnContext(ctx, "screenshot download failed", "file_id", f.Id, "error", err)
+						fail(f, stageDownload, err)
+					}
+					done()
+					continue
+				}
+				select {
+				case downloaded <- downloadedFile{file: f, path: path}:
+				case <-ctx.Done():
+					_ = os.Remove(path)
+				}
+			}
+		}()
+	}
+	go func() {
+		downloaders.Wait()
+		close(downloaded)
+	}()
+
+	// Resize stage.
+	var resizers sync.WaitGroup
+	for range p.resizers {
+		resizers.Add(1)
+		go func() {
+			defer resizers.Done()
+			for d := range downloaded {
+				if p.resize != nil {
+					if err := p.resize(d.path); err != nil {
+						slog.WarnContext(ctx, "screenshot resize failed", "file_id", d.file.Id, "error", err)
+						_ = os.Remove(d.path)
+						fail(d.file, stageResize, err)
+						done()
+						continue
+					}
+				}
+				mu.Lock()
+				set.Paths[fmt.Sprint(d.file.Id)] = d.path
+				set.Infos[fmt.Sprint(d.file.Id)] = d.file
+				mu.Unlock()
+				done()
+			}
+		}()
+	}
+	resizers.Wait()
+
+	err = searchErr
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		set.Cleanup()
+		return nil, err
+	}
+	return set, nil
+}
+
+// Stream fetches the files of shots and hands each one to consume in the order of shots, as soon as
+// it and the files before it are ready. Downloads run ahead of consume by at most as many files as
+// the pipeline has download and resize slots, which bounds the local copies that exist at a time:
+// a copy is removed once consume returns. Files that cannot be fetched are skipped and returned.
+// Stream fails on cancellation and when consume fails; no temporary files are left behind.
+func (p *downloadPipeline) Stream(ctx context.Context, shots []screenshot, consume func(shot screenshot, path string) error) ([]domain.ManifestSkippedFile, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	dir, err := os.MkdirTemp(p.tempDir, "screenshots-")
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("create screenshots dir: %w", err)
+	}
+
+	// window holds a token for every file that is being fetched or waits for consume.
+	window := make(chan struct{}, p.downloads+p.resizers)
+	resizing := make(chan struct{}, p.resizers)
+	// Every file has a result of its own, so the downloaders never wait for consume.
+	results := make([]chan stagedFile, len(shots))
+	for i := range results {
+		results[i] = make(chan stagedFile, 1)
+	}
+	jobs := make(chan int)
+
+	go func() {
+		defer close(jobs)
+		for i := range shots {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var downloaders sync.WaitGroup
+	for range p.downloads {
+		downloaders.Go(func() {
+			for i := range jobs {
+				results[i] <- p.stage(ctx, dir, shots[i].file, resizing)
+			}
+		})
+	}
+	// Stopped downloaders leave nothing behind but what is in dir.
+	defer func() {
+		cancel()
+		downloaders.Wait()
+		_ = os.RemoveAll(dir)
+	}()
+
+	var failed []domain.ManifestSkippedFile
+	for i, shot := range shots {
+		var f stagedFile
+		select {
+		case f = <-results[i]:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if f.err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			slog.WarnContext(ctx, "screenshot "+f.stage+" failed", "file_id", shot.file.GetId(), "error", f.err)
+			failed = append(failed, domain.ManifestSkippedFile{
+				ID:     shot.file.GetId(),
+				Name:   shot.file.GetName(),
+				Reason: fmt.Sprintf("%s: %v", f.stage, f.err),
+			})
+		} else {
+			err := consume(shot, f.path)
+			_ = os.Remove(f.path)
+			if err != nil {
+				return nil, err
+			}
+		}
+		<-window
+		if p.onFile != nil {
+			p.onFile()
+		}
+	}
+	return failed, nil
+}
+
+// stagedFile is the local copy of a file fetched by Stream, or the stage it failed at.
+type stagedFile struct {
+	path  string
+	stage string
+	err   error
+}
+
+// stage downloads f into dir and resizes it, holding one of resizing meanwhile.
+func (p *downloadPipeline) stage(ctx context.Context, dir string, f *storage.File, resizing chan struct{}) stagedFile {
+	path, err := p.fetch(ctx, dir, f)
+	if err != nil {
+		return stagedFile{stage: stageDownload, err: err}
+	}
+	if p.resize == nil {
+		return stagedFile{path: path}
+	}
+	select {
+	case resizing <- struct{}{}:
+	case <-ctx.Done():
+		_ = os.Remove(path)
+		return stagedFile{stage: stageResize, err: ctx.Err()}
+	}
+	err = p.resize(path)
+	<-resizing
+	if err != nil {
+		_ = os.Remove(path)
+		return stagedFile{stage: stageResize, err: err}
+	}
+	return stagedFile{path: path}
+}
+
+// fetch downloads a single image into a new temporary file in dir, holding a global download slot meanwhile.
+func (p *downloadPipeline) fetch(ctx context.Context, dir string, f *storage.File) (string, error) {
+	if f.Id == 0 || f.Name == "" {
+		return "", fmt.Errorf("invalid file: id=%d, name=%q", f.Id, f.Name)
+	}
+	if !util.IsValidImageMime(f.MimeType) {
+		return "", fmt.Errorf("not an image: %s", f.MimeType)
+	}
+
+	if p.slots != nil {
+		if err := p.slots.Acquire(ctx, 1); err != nil {
+			return "", err
+		}
+		defer p.slots.Release(1)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%d_%s%s", f.Id, filepath.Base(f.Name), util.GetFileExt(f.MimeType)))
+	if err := p.download(ctx, f, path); err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// newDownloadPipeline returns the pipeline fetching the screenshots of a task,
+// resized to width unless it is 0.
+func (app *App) newDownloadPipeline(domainID int64, width int, onFile func()) *downloadPipeline {
+	p := &downloadPipeline{
+		download: func(ctx context.Context, f *storage.File, path string) error {
+			return downloadToFile(ctx, app.StorageClient, domainID, f.Id, path)
+		},
+		tempDir:   cmp.Or(app.Config.TempDir, os.TempDir()),
+		downloads: app.taskDownloads(),
+		resizers:  min(app.taskDownloads(), runtime.NumCPU()),
+		onFile:    onFile,
+	}
+	if app.downloadSlots != nil {
+		p.slots = app.downloadSlots
+	}
+	if width > 0 {
+		p.resize = func(path string) error {
+			return util.ResizeImage(path, width)
+		}
+	}
+	return p
+}
+
+func (app *App) taskDownloads() int {
+	if app.Config.Export == nil || app.Config.Export.TaskDownloads <= 0 {
+		return defaultTaskDownloads
+	}
+	return app.Config.Export.TaskDownloads
+}
+
+func (app *App) globalDownloads() int {
+	if app.Config.Export == nil || app.Config.Export.MaxDownloads <= 0 {
+		return defaultGlobalDownloads
+	}
+	return app.Config.Export.MaxDownloads
+}
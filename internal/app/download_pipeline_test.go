@@ -0,0 +1,379 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/webitel/media-exporter/api/storage"
+	"golang.org/x/sync/semaphore"
+)
+
+// concurrencyProbe records the highest number of calls running at the same time.
+type concurrencyProbe struct {
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (c *concurrencyProbe) enter() {
+	n := c.running.Add(1)
+	for {
+		peak := c.peak.Load()
+		if n <= peak || c.peak.CompareAndSwap(peak, n) {
+			return
+		}
+	}
+}
+
+func (c *concurrencyProbe) leave() { c.running.Add(-1) }
+
+func screenshotSearch(n int) *fileIterator {
+	var requested []int32
+	search := pagedSearch(n, &requested)
+	return newFileIterator(func(ctx context.Context, page, size int32) (*storage.ListFile, error) {
+		resp, err := search(ctx, page, size)
+		for _, f := range resp.GetItems() {
+			f.Name = "shot"
+			f.MimeType = "image/png"
+		}
+		return resp, err
+	}, 10, 0)
+}
+
+func writeFile(_ context.Context, _ *storage.File, path string) error {
+	return os.WriteFile(path, []byte("png"), 0o600)
+}
+
+func TestDownloadPipelineBoundsConcurrency(t *testing.T) {
+	probe := &concurrencyProbe{}
+	var downloaded atomic.Int32
+	p := &downloadPipeline{
+		download: func(ctx context.Context, f *storage.File, path string) error {
+			probe.enter()
+			defer probe.leave()
+			time.Sleep(time.Millisecond)
+			downloaded.Add(1)
+			return writeFile(ctx, f, path)
+		},
+		tempDir:   t.TempDir(),
+		downloads: 4,
+		resizers:  2,
+	}
+
+	set, err := p.Run(context.Background(), screenshotSearch(45))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(set.Paths) != 45 || len(set.Infos) != 45 || downloaded.Load() != 45 {
+		t.Errorf("got %d files, %d infos, %d downloads, want 45", len(set.Paths), len(set.Infos), downloaded.Load())
+	}
+	if peak := probe.peak.Load(); peak > 4 {
+		t.Errorf("%d downloads ran at once, want at most 4", peak)
+	}
+}
+
+func TestDownloadPipelineSharesGlobalSlots(t *testing.T) {
+	probe := &concurrencyProbe{}
+	slots := semaphore.NewWeighted(3)
+	newPipeline := func(dir string) *downloadPipeline {
+		return &downloadPipeline{
+			download: func(ctx context.Context, f *storage.File, path string) error {
+				probe.enter()
+				defer probe.leave()
+				time.Sleep(time.Millisecond)
+				return writeFile(ctx, f, path)
+			},
+			slots:     slots,
+			tempDir:   dir,
+			downloads: 4,
+			resizers:  1,
+		}
+	}
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := newPipeline(t.TempDir()).Run(context.Background(), screenshotSearch(20)); err != nil {
+				t.Errorf("Run() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak := probe.peak.Load(); peak > 3 {
+		t.Errorf("%d downloads ran at once across tasks, want at most 3", peak)
+	}
+}
+
+func TestDownloadPipelineCollectsFailures(t *testing.T) {
+	downloadErr := errors.New("file removed")
+	var files atomic.Int32
+	p := &downloadPipeline{
+		download: func(ctx context.Context, f *storage.File, path string) error {
+			if f.Id%5 == 0 {
+				return downloadErr
+			}
+			return writeFile(ctx, f, path)
+		},
+		resize: func(path string) error {
+			if filepath.Base(path) == "7_shot.png" {
+				return errors.New("corrupt image")
+			}
+			return nil
+		},
+		tempDir:   t.TempDir(),
+		downloads: 3,
+		resizers:  2,
+		onFile:    func() { files.Add(1) },
+	}
+
+	set, err := p.Run(context.Background(), screenshotSearch(20))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(set.Paths) != 15 {
+		t.Errorf("got %d files, want 15", len(set.Paths))
+	}
+	if len(set.Failed) != 5 {
+		t.Fatalf("got %d failures, want 5: %+v", len(set.Failed), set.Failed)
+	}
+	failed := map[int64]string{}
+	for _, f := range set.Failed {
+		failed[f.ID] = f.Reason
+	}
+	if failed[10] != "download: file removed" || failed[7] != "resize: corrupt image" {
+		t.Errorf("unexpected failure reasons: %v", failed)
+	}
+	if files.Load() != 20 {
+		t.Errorf("progress reported %d files, want 20", files.Load())
+	}
+	if _, err := os.Stat(filepath.Join(set.dir, "7_shot.png")); !os.IsNotExist(err) {
+		t.Errorf("file that failed to resize was left behind")
+	}
+}
+
+// Tasks fetching the same files at the same time must not share, overwrite or remove each other's copies.
+func TestDownloadPipelineIsolatesRuns(t *testing.T) {
+	p := &downloadPipeline{download: writeFile, tempDir: t.TempDir(), downloads: 2, resizers: 1}
+
+	first, err := p.Run(context.Background(), screenshotSearch(5))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	second, err := p.Run(context.Background(), screenshotSearch(5))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for id, path := range first.Paths {
+		if path == second.Paths[id] {
+			t.Fatalf("runs share the copy of file %s: %s", id, path)
+		}
+	}
+
+	first.Cleanup()
+	for id, path := range second.Paths {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("copy of file %s of the second run was removed with the first: %v", id, err)
+		}
+	}
+	second.Cleanup()
+	if entries, _ := os.ReadDir(p.tempDir); len(entries) != 0 {
+		t.Errorf("%d temporary entries left behind", len(entries))
+	}
+}
+
+func TestDownloadPipelineCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	dir := t.TempDir()
+	var started atomic.Int32
+	p := &downloadPipeline{
+		download: func(ctx context.Context, f *storage.File, path string) error {
+			if started.Add(1) == 5 {
+				cancel()
+			}
+			return writeFile(ctx, f, path)
+		},
+		tempDir:   dir,
+		downloads: 2,
+		resizers:  1,
+	}
+
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		_, err = p.Run(ctx, screenshotSearch(1000))
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pipeline did not stop after cancellation")
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+	if n := started.Load(); n > 20 {
+		t.Errorf("%d downloads started after cancellation", n)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("%d temporary files left behind", len(entries))
+	}
+}
+
+// listedScreenshots returns the first n screenshots of screenshotSearch in the order of the search.
+func listedScreenshots(t *testing.T, n int) []screenshot {
+	t.Helper()
+	files, err := screenshotSearch(n).All(context.Background())
+	if err != nil {
+		t.Fatalf("All() error = %v", err)
+	}
+	shots := make([]screenshot, len(files))
+	for i, f := range files {
+		shots[i] = screenshot{id: f.Name, file: f}
+	}
+	return shots
+}
+
+// countFiles returns the number of regular files under dir.
+func countFiles(dir string) int {
+	n := 0
+	_ = filepath.WalkDir(dir, func(_ string, d os.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			n++
+		}
+		return nil
+	})
+	return n
+}
+
+func TestDownloadPipelineStreamsInOrder(t *testing.T) {
+	dir := t.TempDir()
+	p := &downloadPipeline{
+		download: func(ctx context.Context, f *storage.File, path string) error {
+			// Later files tend to finish first, so they have to wait for the earlier ones.
+			time.Sleep(time.Duration(5-f.Id%5) * time.Millisecond)
+			return writeFile(ctx, f, path)
+		},
+		tempDir:   dir,
+		downloads: 3,
+		resizers:  1,
+	}
+	shots := listedScreenshots(t, 30)
+
+	var got []int64
+	peak := 0
+	failed, err := p.Stream(context.Background(), shots, func(shot screenshot, path string) error {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("file %d is not on disk: %v", shot.file.Id, err)
+		}
+		got = append(got, shot.file.Id)
+		peak = max(peak, countFiles(dir))
+		// A slow consumer lets the downloads run ahead as far as they may.
+		time.Sleep(2 * time.Millisecond)
+		return nil
+	})
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("Stream() = %v, %v", failed, err)
+	}
+
+	for i, id := range got {
+		if id != shots[i].file.Id {
+			t.Fatalf("page %d is file %d, want %d: %v", i, id, shots[i].file.Id, got)
+		}
+	}
+	if len(got) != len(shots) {
+		t.Errorf("got %d files, want %d", len(got), len(shots))
+	}
+	if peak > 4 {
+		t.Errorf("%d files were on disk at once, want at most the 4 slots", peak)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("%d temporary entries left behind", len(entries))
+	}
+}
+
+func TestDownloadPipelineStreamSkipsFailures(t *testing.T) {
+	var files atomic.Int32
+	p := &downloadPipeline{
+		download: func(ctx context.Context, f *storage.File, path string) error {
+			if f.Id%5 == 0 {
+				return errors.New("file removed")
+			}
+			return writeFile(ctx, f, path)
+		},
+		resize: func(path string) error {
+			if filepath.Base(path) == "7_shot.png" {
+				return errors.New("corrupt image")
+			}
+			return nil
+		},
+		tempDir:   t.TempDir(),
+		downloads: 3,
+		resizers:  2,
+		onFile:    func() { files.Add(1) },
+	}
+
+	consumed := 0
+	failed, err := p.Stream(context.Background(), listedScreenshots(t, 20), func(screenshot, string) error {
+		consumed++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if consumed != 15 || len(failed) != 5 {
+		t.Fatalf("consumed %d files and skipped %d, want 15 and 5: %+v", consumed, len(failed), failed)
+	}
+	reasons := map[int64]string{}
+	for _, f := range failed {
+		reasons[f.ID] = f.Reason
+	}
+	if reasons[10] != "download: file removed" || reasons[7] != "resize: corrupt image" {
+		t.Errorf("unexpected failure reasons: %v", reasons)
+	}
+	if files.Load() != 20 {
+		t.Errorf("progress reported %d files, want 20", files.Load())
+	}
+}
+
+func TestDownloadPipelineStreamStopsOnConsumeError(t *testing.T) {
+	dir := t.TempDir()
+	var started atomic.Int32
+	p := &downloadPipeline{
+		download: func(ctx context.Context, f *storage.File, path string) error {
+			started.Add(1)
+			return writeFile(ctx, f, path)
+		},
+		tempDir:   dir,
+		downloads: 2,
+		resizers:  1,
+	}
+
+	renderErr := errors.New("disk full")
+	consumed := 0
+	_, err := p.Stream(context.Background(), listedScreenshots(t, 100), func(screenshot, string) error {
+		consumed++
+		if consumed == 3 {
+			return renderErr
+		}
+		return nil
+	})
+	if !errors.Is(err, renderErr) {
+		t.Errorf("Stream() error = %v, want %v", err, renderErr)
+	}
+	if n := started.Load(); n > 6 {
+		t.Errorf("%d downloads started, want at most the 3 consumed and the 3 slots ahead", n)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("%d temporary entries left behind", len(entries))
+	}
+}
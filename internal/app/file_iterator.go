@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/webitel/media-exporter/api/storage"
+)
+
+const (
+	// searchPageSize is the number of files requested from the storage search per page.
+	searchPageSize = 1000
+	// defaultMaxExportFiles is the ceiling of files in one export unless configured otherwise.
+	defaultMaxExportFiles = 10000
+)
+
+// fetchPageFunc requests a single page (1-based) of a storage search.
+type fetchPageFunc func(ctx context.Context, page, size int32) (*storage.ListFile, error)
+
+// fileIterator pages through a storage search by following ListFile.Next, so that the files
+// of an export can be processed while the next pages are still unknown.
+//
+//	for it.Next(ctx) {
+//		process(it.Page())
+//	}
+//	if err := it.Err(); err != nil { ... }
+type fileIterator struct {
+	fetch    fetchPageFunc
+	pageSize int32
+	limit    int
+	// onPage, if not nil, is called with every fetched page.
+	onPage func([]*storage.File)
+
+	page    int32
+	current []*storage.File
+	count   int
+	done    bool
+	err     error
+}
+
+// newFileIterator returns an iterator that fails once the search matches more than limit files.
+func newFileIterator(fetch fetchPageFunc, pageSize int32, limit int) *fileIterator {
+	return &fileIterator{fetch: fetch, pageSize: pageSize, limit: limit}
+}
+
+// Next fetches the next non-empty page. It returns false when the search is exhausted or failed.
+func (it *fileIterator) Next(ctx context.Context) bool {
+	for !it.done && it.err == nil {
+		if err := ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page++
+		resp, err := it.fetch(ctx, it.page, it.pageSize)
+		if err != nil {
+			it.err = fmt.Errorf("search page %d: %w", it.page, err)
+			return false
+		}
+
+		items := resp.GetItems()
+		// A page without items ends the search even if it claims there is more.
+		it.done = !resp.GetNext() || len(items) == 0
+		if len(items) == 0 {
+			continue
+		}
+
+		it.count += len(items)
+		if it.limit > 0 && it.count > it.limit {
+			it.err = fmt.Errorf("export matches more than %d files, narrow down the time range or the file list", it.limit)
+			return false
+		}
+
+		it.current = items
+		if it.onPage != nil {
+			it.onPage(items)
+		}
+		return true
+	}
+	return false
+}
+
+// All returns the files of the remaining pages. The listing is bounded by the limit of the
+// iterator, so it can be held in memory to be ordered before anything is downloaded.
+func (it *fileIterator) All(ctx context.Context) ([]*storage.File, error) {
+	var files []*storage.File
+	for it.Next(ctx) {
+		files = append(files, it.Page()...)
+	}
+	return files, it.Err()
+}
+
+// Page returns the files of the current page.
+func (it *fileIterator) Page() []*storage.File {
+	return it.current
+}
+
+// Count returns the number of files returned so far.
+func (it *fileIterator) Count() int {
+	return it.count
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *fileIterator) Err() error {
+	return it.err
+}
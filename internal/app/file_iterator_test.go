@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/webitel/media-exporter/api/storage"
+)
+
+// pagedSearch serves total files in pages of the requested size, like the storage search does.
+func pagedSearch(total int, requested *[]int32) fetchPageFunc {
+	return func(_ context.Context, page, size int32) (*storage.ListFile, error) {
+		*requested = append(*requested, page)
+		from := int(page-1) * int(size)
+		to := min(from+int(size), total)
+		resp := &storage.ListFile{Next: to < total}
+		for id := from; id < to; id++ {
+			resp.Items = append(resp.Items, &storage.File{Id: int64(id + 1)})
+		}
+		return resp, nil
+	}
+}
+
+func TestFileIteratorFollowsNext(t *testing.T) {
+	var requested []int32
+	it := newFileIterator(pagedSearch(2500, &requested), 1000, 0)
+
+	var ids []int64
+	for it.Next(context.Background()) {
+		for _, f := range it.Page() {
+			ids = append(ids, f.Id)
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	if len(ids) != 2500 || it.Count() != 2500 {
+		t.Fatalf("got %d files (count %d), want 2500", len(ids), it.Count())
+	}
+	if ids[0] != 1 || ids[2499] != 2500 {
+		t.Errorf("files out of order: first %d, last %d", ids[0], ids[2499])
+	}
+	if len(requested) != 3 || requested[0] != 1 || requested[2] != 3 {
+		t.Errorf("requested pages %v, want [1 2 3]", requested)
+	}
+}
+
+func TestFileIteratorCeiling(t *testing.T) {
+	var requested []int32
+	it := newFileIterator(pagedSearch(2500, &requested), 1000, 2000)
+
+	pages := 0
+	for it.Next(context.Background()) {
+		pages++
+	}
+
+	if pages != 2 {
+		t.Errorf("got %d pages before the ceiling, want 2", pages)
+	}
+	if err := it.Err(); err == nil || !strings.Contains(err.Error(), "more than 2000 files") {
+		t.Errorf("Err() = %v, want the ceiling error", err)
+	}
+
+	// Exactly the ceiling is fine.
+	it = newFileIterator(pagedSearch(2000, &requested), 1000, 2000)
+	for it.Next(context.Background()) {
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Err() = %v for an export at the ceiling", err)
+	}
+}
+
+func TestFileIteratorStops(t *testing.T) {
+	calls := 0
+	// A broken search claiming more pages without returning anything must not loop forever.
+	it := newFileIterator(func(context.Context, int32, int32) (*storage.ListFile, error) {
+		calls++
+		return &storage.ListFile{Next: true}, nil
+	}, 1000, 0)
+	if it.Next(context.Background()) || it.Err() != nil || calls != 1 {
+		t.Errorf("empty page: Next ran %d requests, err = %v", calls, it.Err())
+	}
+
+	searchErr := errors.New("storage unavailable")
+	it = newFileIterator(func(context.Context, int32, int32) (*storage.ListFile, error) {
+		return nil, searchErr
+	}, 1000, 0)
+	if it.Next(context.Background()) || !errors.Is(it.Err(), searchErr) {
+		t.Errorf("Err() = %v, want the search error", it.Err())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var requested []int32
+	it = newFileIterator(pagedSearch(10, &requested), 1000, 0)
+	if it.Next(ctx) || !errors.Is(it.Err(), context.Canceled) || len(requested) != 0 {
+		t.Errorf("cancelled context: err = %v, requests = %v", it.Err(), requested)
+	}
+}
@@ -0,0 +1,217 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/webitel/media-exporter/api/storage"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+)
+
+// defaultSessionGap is the pause between screenshots that starts a new session when an export
+// is grouped by session and does not choose the gap.
+const defaultSessionGap = 10 * time.Minute
+
+// screenshot is a file of an export with what its place in the export depends on.
+type screenshot struct {
+	id   string
+	file *storage.File // nil if the search did not describe the file
+	// path is the local copy of the file, empty if it is not downloaded.
+	path string
+	// time is the capture time, zero if unknown.
+	time  time.Time
+	group screenshotGroup
+}
+
+// screenshotGroup is a group of screenshots of an export, the zero value if the export is not grouped.
+type screenshotGroup struct {
+	// key is a path-safe name of the group, used for the folders of a ZIP archive.
+	key string
+	// title is a readable name of the group, used for PDF bookmarks.
+	title string
+}
+
+// screenshotOrder orders and groups the screenshots of an export as its task asks,
+// so that every export format lays out the same files in the same way.
+type screenshotOrder struct {
+	sort    string
+	groupBy string
+	gap     time.Duration
+	// loc is the time zone of hours and days.
+	loc *time.Location
+}
+
+// newScreenshotOrder returns the order of the screenshots of the task, sorted by defaultSort
+// if the task does not choose an order and grouped by defaultGroup if it does not choose a grouping.
+func newScreenshotOrder(task domain.ExportTask, defaultSort, defaultGroup string, loc *time.Location) screenshotOrder {
+	o := screenshotOrder{sort: defaultSort, groupBy: defaultGroup, gap: defaultSessionGap, loc: loc}
+	if task.Order == nil {
+		return o
+	}
+	if task.Order.Sort != "" {
+		o.sort = task.Order.Sort
+	}
+	if task.Order.GroupBy != domain.GroupByNone {
+		o.groupBy = task.Order.GroupBy
+	}
+	if task.Order.SessionGapMs > 0 {
+		o.gap = time.Duration(task.Order.SessionGapMs) * time.Millisecond
+	}
+	return o
+}
+
+// downloaded returns the downloaded screenshots in order.
+func (o screenshotOrder) downloaded(paths map[string]string, infos map[string]*storage.File) []screenshot {
+	shots := make([]screenshot, 0, len(paths))
+	for id, path := range paths {
+		if path == "" {
+			continue
+		}
+		info := infos[id]
+		shots = append(shots, screenshot{id: id, file: info, path: path, time: captureTime(info)})
+	}
+	return o.arrange(shots)
+}
+
+// listed returns the files found for an export in order.
+func (o screenshotOrder) listed(files []*storage.File) []screenshot {
+	shots := make([]screenshot, 0, len(files))
+	for _, f := range files {
+		shots = append(shots, screenshot{id: strconv.FormatInt(f.GetId(), 10), file: f, time: captureTime(f)})
+	}
+	return o.arrange(shots)
+}
+
+// arrange sorts the screenshots and assigns their groups.
+func (o screenshotOrder) arrange(shots []screenshot) []screenshot {
+	if o.groupBy == domain.GroupBySession {
+		o.assignSessions(shots)
+	} else if o.groupBy != domain.GroupByNone {
+		for i := range shots {
+			shots[i].group = o.timeGroup(shots[i].time)
+		}
+	}
+
+	// Groups are kept together whatever the sort, in the time order of the sort.
+	starts := groupStarts(shots)
+	sort.SliceStable(shots, func(i, j int) bool {
+		a, b := shots[i], shots[j]
+		if a.group != b.group {
+			return o.groupBefore(a.group, b.group, starts)
+		}
+		if o.sort == domain.SortName {
+			if an, bn := a.file.GetName(), b.file.GetName(); an != bn {
+				return an < bn
+			}
+		}
+		if a.time.IsZero() != b.time.IsZero() {
+			return b.time.IsZero()
+		}
+		if !a.time.Equal(b.time) {
+			if o.sort == domain.SortUploadedDesc {
+				return a.time.After(b.time)
+			}
+			return a.time.Before(b.time)
+		}
+		if a.file.GetId() != b.file.GetId() {
+			return a.file.GetId() < b.file.GetId()
+		}
+		return a.path < b.path
+	})
+	return shots
+}
+
+// groupStarts returns the capture time of the oldest screenshot of every group.
+func groupStarts(shots []screenshot) map[screenshotGroup]time.Time {
+	starts := make(map[screenshotGroup]time.Time)
+	for _, s := range shots {
+		if start, ok := starts[s.group]; !ok || s.time.Before(start) {
+			starts[s.group] = s.time
+		}
+	}
+	return starts
+}
+
+// groupBefore reports whether the group a goes before the group b: oldest first, newest first
+// if the screenshots are sorted newest first, the screenshots of unknown time last.
+func (o screenshotOrder) groupBefore(a, b screenshotGroup, starts map[screenshotGroup]time.Time) bool {
+	if (a == unknownTimeGroup) != (b == unknownTimeGroup) {
+		return b == unknownTimeGroup
+	}
+	if sa, sb := starts[a], starts[b]; !sa.Equal(sb) {
+		if o.sort == domain.SortUploadedDesc {
+			return sa.After(sb)
+		}
+		return sa.Before(sb)
+	}
+	return a.key < b.key
+}
+
+// timeGroup returns the hour or the day a screenshot taken at t belongs to.
+func (o screenshotOrder) timeGroup(t time.Time) screenshotGroup {
+	if t.IsZero() {
+		return unknownTimeGroup
+	}
+	t = t.In(o.loc)
+	if o.groupBy == domain.GroupByHour {
+		return screenshotGroup{
+			key:   t.Format("2006-01-02_15") + "h",
+			title: fmt.Sprintf("%s %02d:00 – %02d:00", t.Format(time.DateOnly), t.Hour(), (t.Hour()+1)%24),
+		}
+	}
+	return screenshotGroup{key: t.Format(time.DateOnly), title: t.Format(time.DateOnly)}
+}
+
+// unknownTimeGroup holds the screenshots without a capture time in time-based groupings.
+var unknownTimeGroup = screenshotGroup{key: "unknown_time", title: "Capture time unknown"}
+
+// assignSessions splits the screenshots into sessions: runs in capture order without a pause
+// longer than the gap. Sessions are numbered from the oldest, whatever the order of the export.
+func (o screenshotOrder) assignSessions(shots []screenshot) {
+	byTime := make([]*screenshot, 0, len(shots))
+	for i := range shots {
+		if shots[i].time.IsZero() {
+			shots[i].group = unknownTimeGroup
+			continue
+		}
+		byTime = append(byTime, &shots[i])
+	}
+	sort.SliceStable(byTime, func(i, j int) bool { return byTime[i].time.Before(byTime[j].time) })
+
+	n := 0
+	for start := 0; start < len(byTime); {
+		end := start + 1
+		for end < len(byTime) && byTime[end].time.Sub(byTime[end-1].time) <= o.gap {
+			end++
+		}
+		n++
+		group := o.session(n, byTime[start].time, byTime[end-1].time)
+		for _, s := range byTime[start:end] {
+			s.group = group
+		}
+		start = end
+	}
+}
+
+// session describes the n-th session, from the first to the last capture time.
+func (o screenshotOrder) session(n int, first, last time.Time) screenshotGroup {
+	first, last = first.In(o.loc), last.In(o.loc)
+	end := last.Format("15:04")
+	if first.YearDay() != last.YearDay() || first.Year() != last.Year() {
+		end = last.Format(rangeTimeFormat)
+	}
+	return screenshotGroup{
+		key:   fmt.Sprintf("session_%03d_%s", n, first.Format("2006-01-02_15-04")),
+		title: fmt.Sprintf("Session %d · %s – %s", n, first.Format(rangeTimeFormat), end),
+	}
+}
+
+// captureTime returns the upload time of the file, zero if it is unknown.
+func captureTime(f *storage.File) time.Time {
+	if f == nil || f.UploadedAt == 0 {
+		return time.Time{}
+	}
+	return fileUploadedAt(f)
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/webitel/media-exporter/api/storage"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+)
+
+func orderFiles() []*storage.File {
+	at := func(hour, minute int) int64 {
+		return time.Date(2026, 3, 2, hour, minute, 0, 0, time.UTC).UnixMilli()
+	}
+	return []*storage.File{
+		{Id: 1, Name: "c.png", UploadedAt: at(9, 0)},
+		{Id: 2, Name: "a.png", UploadedAt: at(9, 5)},
+		{Id: 3, Name: "d.png"},
+		{Id: 4, Name: "b.png", UploadedAt: at(11, 0)},
+		{Id: 5, Name: "e.png", UploadedAt: at(9, 12)},
+	}
+}
+
+func orderedIDs(shots []screenshot) []int64 {
+	ids := make([]int64, len(shots))
+	for i, s := range shots {
+		ids[i] = s.file.GetId()
+	}
+	return ids
+}
+
+func TestScreenshotOrderSort(t *testing.T) {
+	tests := []struct {
+		sort string
+		want []int64
+	}{
+		{domain.SortUploadedDesc, []int64{4, 5, 2, 1, 3}},
+		{domain.SortUploadedAsc, []int64{1, 2, 5, 4, 3}},
+		{domain.SortName, []int64{2, 4, 1, 3, 5}},
+	}
+	for _, tt := range tests {
+		task := domain.ExportTask{Order: &domain.ExportOrder{Sort: tt.sort}}
+		got := orderedIDs(newScreenshotOrder(task, domain.SortUploadedDesc, domain.GroupByNone, time.UTC).listed(orderFiles()))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sort %s = %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+
+	// Without an order of its own the task gets the default of its format.
+	got := orderedIDs(newScreenshotOrder(domain.ExportTask{}, domain.SortUploadedAsc, domain.GroupByNone, time.UTC).listed(orderFiles()))
+	if !reflect.DeepEqual(got, []int64{1, 2, 5, 4, 3}) {
+		t.Errorf("default order = %v", got)
+	}
+}
+
+func TestScreenshotOrderSessions(t *testing.T) {
+	task := domain.ExportTask{Order: &domain.ExportOrder{GroupBy: domain.GroupBySession, SessionGapMs: (6 * time.Minute).Milliseconds()}}
+	shots := newScreenshotOrder(task, domain.SortUploadedDesc, domain.GroupByNone, time.UTC).listed(orderFiles())
+
+	groups := map[int64]screenshotGroup{}
+	for _, s := range shots {
+		groups[s.file.GetId()] = s.group
+	}
+	first := screenshotGroup{key: "session_001_2026-03-02_09-00", title: "Session 1 · 2026-03-02 09:00 – 09:05"}
+	second := screenshotGroup{key: "session_002_2026-03-02_09-12", title: "Session 2 · 2026-03-02 09:12 – 09:12"}
+	third := screenshotGroup{key: "session_003_2026-03-02_11-00", title: "Session 3 · 2026-03-02 11:00 – 11:00"}
+	want := map[int64]screenshotGroup{1: first, 2: first, 5: second, 4: third, 3: unknownTimeGroup}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("sessions = %v, want %v", groups, want)
+	}
+
+	// The default gap keeps the screenshots of the morning together.
+	task.Order.SessionGapMs = 0
+	shots = newScreenshotOrder(task, domain.SortUploadedDesc, domain.GroupByNone, time.UTC).listed(orderFiles())
+	if shots[1].group != shots[2].group || shots[2].group != shots[3].group || shots[0].group == shots[1].group {
+		t.Errorf("sessions with the default gap = %v", shots)
+	}
+}
+
+func TestZipEntryName(t *testing.T) {
+	f := &storage.File{Id: 7, Name: `C:\shots\screen.png`}
+	if got := zipEntryName(f, screenshotGroup{}); got != "files/7_screen.png" {
+		t.Errorf("zipEntryName() = %q", got)
+	}
+	if got := zipEntryName(f, screenshotGroup{key: "2026-03-02", title: "2026-03-02"}); got != "files/2026-03-02/7_screen.png" {
+		t.Errorf("zipEntryName() in a group = %q", got)
+	}
+}
+
+// Sorting by name must not split a group: every group comes once, in time order,
+// with its screenshots sorted by name within it.
+func TestScreenshotOrderSortWithinGroups(t *testing.T) {
+	tests := []struct {
+		sort    string
+		groupBy string
+		gapMs   int64
+		want    []int64
+	}{
+		// Hours: 09 holds 1 (c), 2 (a), 5 (e); 11 holds 4 (b); 3 has no time.
+		{domain.SortName, domain.GroupByHour, 0, []int64{2, 1, 5, 4, 3}},
+		{domain.SortName, domain.GroupByDay, 0, []int64{2, 4, 1, 5, 3}},
+		// Sessions with a 6 minute gap: {1, 2}, {5}, {4}.
+		{domain.SortName, domain.GroupBySession, (6 * time.Minute).Milliseconds(), []int64{2, 1, 5, 4, 3}},
+		{domain.SortUploadedDesc, domain.GroupByHour, 0, []int64{4, 5, 2, 1, 3}},
+		{domain.SortUploadedAsc, domain.GroupBySession, (6 * time.Minute).Milliseconds(), []int64{1, 2, 5, 4, 3}},
+	}
+	for _, tt := range tests {
+		task := domain.ExportTask{Order: &domain.ExportOrder{Sort: tt.sort, GroupBy: tt.groupBy, SessionGapMs: tt.gapMs}}
+		shots := newScreenshotOrder(task, domain.SortUploadedDesc, domain.GroupByNone, time.UTC).listed(orderFiles())
+		if got := orderedIDs(shots); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sort %s by %s = %v, want %v", tt.sort, tt.groupBy, got, tt.want)
+		}
+
+		seen := map[screenshotGroup]bool{}
+		for i, s := range shots {
+			if i > 0 && s.group != shots[i-1].group && seen[s.group] {
+				t.Errorf("sort %s by %s splits group %s", tt.sort, tt.groupBy, s.group.key)
+			}
+			seen[s.group] = true
+		}
+	}
+}
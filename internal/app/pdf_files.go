@@ -6,65 +6,97 @@ import (
 	"io"
 	"log/slog"
 	"os"
-	"path/filepath"
-	"sync"
 
 	"github.com/webitel/media-exporter/api/storage"
 	"github.com/webitel/media-exporter/internal/domain/model"
-	"github.com/webitel/media-exporter/internal/util"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/storage/gen/engine"
 )
 
-func downloadScreenshotsForPDF(ctx context.Context, session *model.Session, app *App, files []*storage.File) (map[string]string, map[string]*storage.File, error) {
-	tmpFiles := make(map[string]string)
-	fileInfos := make(map[string]*storage.File)
-	var mu sync.Mutex
-	var wg sync.WaitGroup
+// screenshotPages returns an iterator over the screenshots matching the task filter,
+// either for a single agent or for a call.
+func (app *App) screenshotPages(task domain.ExportTask) (*fileIterator, error) {
+	channel, err := ParseChannel(task.Channel)
+	if err != nil {
+		return nil, fmt.Errorf("channel missing for task %s: %w", task.TaskID, err)
+	}
+	uploadedAt := &engine.FilterBetween{
+		From: task.From,
+		To:   task.To,
+	}
 
-	errCh := make(chan error, len(files))
-	for _, f := range files {
-		wg.Add(1)
-		go func(f *storage.File) {
-			defer wg.Done()
-			tmpPath, err := downloadAndResize(ctx, app.StorageClient, session.DomainID(), f)
-			if err != nil {
-				// FIXME commented as we receive IDs from SearchScreenRecordings which do not exist / or have been deleted
-				//errCh <- err
-				slog.ErrorContext(ctx, "downloadAndResize failed", "file_id", f.Id, "error", err)
-				return
-			}
-			mu.Lock()
-			tmpFiles[fmt.Sprint(f.Id)] = tmpPath
-			fileInfos[fmt.Sprint(f.Id)] = f
-			mu.Unlock()
-		}(f)
+	var fetch fetchPageFunc
+	if task.AgentID != 0 {
+		fetch = func(ctx context.Context, page, size int32) (*storage.ListFile, error) {
+			return app.StorageClient.SearchScreenRecordingsByAgent(ctx, &storage.SearchScreenRecordingsByAgentRequest{
+				Id:         task.IDs,
+				Type:       storage.ScreenrecordingType_SCREENSHOT,
+				Channel:    channel,
+				AgentId:    task.AgentID,
+				Page:       page,
+				Size:       size,
+				UploadedAt: uploadedAt,
+			})
+		}
+	} else {
+		fetch = func(ctx context.Context, page, size int32) (*storage.ListFile, error) {
+			return app.StorageClient.SearchScreenRecordings(ctx, &storage.SearchScreenRecordingsRequest{
+				Id:         task.IDs,
+				Type:       storage.ScreenrecordingType_SCREENSHOT,
+				Channel:    channel,
+				Page:       page,
+				Size:       size,
+				UploadedAt: uploadedAt,
+			})
+		}
 	}
 
-	wg.Wait()
-	close(errCh)
+	return newFileIterator(fetch, searchPageSize, app.maxExportFiles()), nil
+}
 
-	for err := range errCh {
-		if err != nil {
-			return nil, nil, err
-		}
+func (app *App) maxExportFiles() int {
+	if app.Config.Export == nil || app.Config.Export.MaxFiles <= 0 {
+		return defaultMaxExportFiles
 	}
+	return app.Config.Export.MaxFiles
+}
+
+// pdfImageWidth is the width screenshots are resized to before they are put into a PDF.
+const pdfImageWidth = 400
 
-	return tmpFiles, fileInfos, nil
+// downloadScreenshots downloads the screenshots of every search page as soon as the page arrives
+// to temporary files keyed by file ID, resized to width unless it is 0.
+// Files that cannot be downloaded are skipped and reported in the result.
+// It fails if the search finds no screenshot.
+func downloadScreenshots(ctx context.Context, session *model.Session, app *App, task domain.ExportTask, pages *fileIterator, width int) (*screenshotSet, error) {
+	set, err := fetchScreenshots(ctx, session, app, task, pages, width)
+	if err != nil {
+		return nil, err
+	}
+	if pages.Count() == 0 {
+		return nil, fmt.Errorf("no files found for task %s", task.TaskID)
+	}
+	return set, nil
 }
 
-func downloadAndResize(ctx context.Context, client storage.FileServiceClient, domainID int64, f *storage.File) (string, error) {
-	if f.Id == 0 || f.Name == "" {
-		return "", fmt.Errorf("invalid file: id=%d, name=%q", f.Id, f.Name)
+// fetchScreenshots is downloadScreenshots for exports that may have no screenshots.
+func fetchScreenshots(ctx context.Context, session *model.Session, app *App, task domain.ExportTask, pages *fileIterator, width int) (*screenshotSet, error) {
+	progress := newStageCounter(0, func(current, total int64) {
+		app.reportStage(ctx, task.TaskID, domain.StageDownloading, current, total)
+	})
+	pages.onPage = func(files []*storage.File) {
+		progress.Grow(int64(len(files)))
 	}
-	if !util.IsValidImageMime(f.MimeType) {
-		slog.ErrorContext(ctx, "invalid file: mimeType=%q", f.MimeType)
-		return "", nil
+
+	set, err := app.newDownloadPipeline(session.DomainID(), width, progress.Inc).Run(ctx, pages)
+	if err != nil {
+		return nil, err
 	}
-	tmpPath := filepath.Join(os.TempDir(), fmt.Sprintf("%d_%s%s", f.Id, f.Name, util.GetFileExt(f.MimeType)))
-	if err := downloadToFile(ctx, client, domainID, f.Id, tmpPath); err != nil {
-		return "", err
+	if len(set.Failed) > 0 {
+		slog.WarnContext(ctx, "some screenshots were skipped", "taskID", task.TaskID, "skipped", len(set.Failed), "downloaded", len(set.Paths))
 	}
-	_ = util.ResizeImage(tmpPath, 400)
-	return tmpPath, nil
+
+	return set, nil
 }
 
 func downloadToFile(ctx context.Context, client storage.FileServiceClient, domainID, fileID int64, tmpPath string) error {
@@ -74,6 +106,11 @@ func downloadToFile(ctx context.Context, client storage.FileServiceClient, domai
 	}
 	defer func() { _ = out.Close() }()
 
+	return downloadTo(ctx, client, domainID, fileID, out)
+}
+
+// downloadTo streams the content of a storage file into out.
+func downloadTo(ctx context.Context, client storage.FileServiceClient, domainID, fileID int64, out io.Writer) error {
 	stream, err := client.DownloadFile(ctx, &storage.DownloadFileRequest{
 		Id:       fileID,
 		DomainId: domainID,
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/util/pdf"
+)
+
+// Names of the files attached to a PDF export with a signed manifest.
+const (
+	manifestSignatureName = zipManifestName + ".sig"
+	signerIdentityName    = "signer.pem"
+)
+
+// exportDigest is the SHA-256 of an uploaded export and its signature, recorded in the history
+// so that a copy of the export can be verified later.
+type exportDigest struct {
+	sha256 string
+	// signature is the base64 signature of domain.SignedDigest, empty without a signing key.
+	signature string
+}
+
+// signDigest returns the digest of the export of the task, signed if the service has a signing key.
+func (app *App) signDigest(task domain.ExportTask, digest string) *exportDigest {
+	d := &exportDigest{sha256: digest}
+	if app.Signer == nil {
+		return d
+	}
+	d.signature = base64.StdEncoding.EncodeToString(app.Signer.Sign(domain.SignedDigest(task.TaskID, digest)))
+	return d
+}
+
+// pdfManifest describes the screenshots of a PDF export: those of the rendered pages,
+// and those that were found but failed to download or render.
+func pdfManifest(task domain.ExportTask, shots []screenshot, rendered []int, failed []domain.ManifestSkippedFile) *domain.ExportManifest {
+	manifest := &domain.ExportManifest{
+		TaskID:    task.TaskID,
+		TraceHash: task.TraceHash,
+		AgentID:   task.AgentID,
+		CallID:    task.CallID,
+		Channel:   task.Channel,
+		From:      task.From,
+		To:        task.To,
+		CreatedAt: task.CreatedAt,
+		CreatedBy: task.UserID,
+		Files:     []domain.ManifestFile{},
+		Skipped:   failed,
+	}
+
+	included := make(map[int]bool, len(rendered))
+	for _, i := range rendered {
+		included[i] = true
+	}
+	for i, shot := range shots {
+		// The getters of a file the search did not describe return zero values.
+		f := shot.file
+		id, _ := strconv.ParseInt(shot.id, 10, 64)
+		if !included[i] {
+			manifest.Skipped = append(manifest.Skipped, domain.ManifestSkippedFile{
+				ID:     id,
+				Name:   f.GetName(),
+				Reason: "the image cannot be rendered",
+			})
+			continue
+		}
+		manifest.Files = append(manifest.Files, domain.ManifestFile{
+			ID:         id,
+			Name:       f.GetName(),
+			MimeType:   f.GetMimeType(),
+			Size:       f.GetSize(),
+			Sha256:     f.GetSha256Sum(),
+			UploadedAt: f.GetUploadedAt(),
+			Group:      shot.group.title,
+		})
+	}
+	return manifest
+}
+
+// attachManifest attaches the manifest to the document together with its signature
+// and the certificate or public key that verifies it.
+func (app *App) attachManifest(doc *pdf.Document, manifest *domain.ExportManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	attachments := []pdf.Attachment{
+		{
+			Name:        zipManifestName,
+			MimeType:    "application/json",
+			Description: "Files included into the export",
+			Data:        data,
+		},
+		{
+			Name:        manifestSignatureName,
+			MimeType:    "text/plain",
+			Description: "Base64 Ed25519 signature of " + zipManifestName,
+			Data:        []byte(base64.StdEncoding.EncodeToString(app.Signer.Sign(data))),
+		},
+		{
+			Name:        signerIdentityName,
+			MimeType:    "application/x-pem-file",
+			Description: "Key that verifies the signature of " + zipManifestName,
+			Data:        app.Signer.Identity(),
+		},
+	}
+	for _, a := range attachments {
+		if err := doc.Attach(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/webitel/media-exporter/api/storage"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+)
+
+func TestPdfManifest(t *testing.T) {
+	shots := []screenshot{
+		{id: "1", file: &storage.File{Id: 1, Name: "a.png", Sha256Sum: "aa", UploadedAt: 100}},
+		{id: "2", file: &storage.File{Id: 2, Name: "b.png"}},
+		{id: "3"},
+	}
+	failed := []domain.ManifestSkippedFile{{ID: 4, Name: "d.png", Reason: "not found"}}
+	task := domain.ExportTask{TaskID: "task", TraceHash: "trace", UserID: 7}
+
+	m := pdfManifest(task, shots, []int{0, 2}, failed)
+	if m.TaskID != "task" || m.TraceHash != "trace" || m.CreatedBy != 7 {
+		t.Errorf("manifest describes %+v, want the task", m)
+	}
+	if len(m.Files) != 2 || m.Files[0].ID != 1 || m.Files[0].Sha256 != "aa" || m.Files[0].UploadedAt != 100 || m.Files[1].ID != 3 {
+		t.Errorf("files = %+v, want files 1 and 3", m.Files)
+	}
+	if len(m.Skipped) != 2 || m.Skipped[0].ID != 4 || m.Skipped[1].ID != 2 {
+		t.Errorf("skipped = %+v, want the failed download and file 2", m.Skipped)
+	}
+}
@@ -0,0 +1,290 @@
+package app
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/webitel/media-exporter/api/storage"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/util/pdf"
+)
+
+// Formats of the times printed in PDF exports.
+const (
+	captionTimeFormat = "2006-01-02 15:04:05 MST"
+	rangeTimeFormat   = "2006-01-02 15:04"
+)
+
+// pdfGrids are the columns and rows of the grids of the supported numbers of screenshots per page.
+// Screenshots are wider than high, so a grid has at least as many rows as columns: that leaves
+// them the most room on both portrait and landscape pages.
+var pdfGrids = map[int][2]int{1: {1, 1}, 2: {1, 2}, 4: {2, 2}, 6: {2, 3}, 9: {3, 3}}
+
+// contactSheetGrid is the grid of thumbnails of a contact sheet on a portrait page,
+// turned on landscape pages.
+var contactSheetGrid = [2]int{4, 6}
+
+// pdfLayout decides what is printed on the pages of a PDF export besides the screenshots.
+type pdfLayout struct {
+	font     *pdf.Font
+	loc      *time.Location
+	size     pdf.PageSize
+	columns  int
+	rows     int
+	captions bool
+	// contactSheet labels every screenshot with a single line instead of the caption.
+	contactSheet bool
+	// header prints a running header and "page X of Y" in the footer.
+	header bool
+	title  string
+	// coverPage starts the document with a cover page describing the export.
+	coverPage bool
+	// contents adds a table of contents of the groups of the order.
+	contents bool
+	// watermark draws who requested the export and when across every page.
+	watermark bool
+	// order sorts the screenshots and groups them into the bookmarks and the table of contents.
+	order screenshotOrder
+}
+
+// pdfLayout returns the layout the task asks for. Without options the pages show the bare screenshots.
+func (app *App) pdfLayout(task domain.ExportTask) pdfLayout {
+	layout := pdfLayout{font: app.PdfFont, loc: time.UTC, size: pdf.A4, columns: 1, rows: 1}
+	if task.Pdf == nil {
+		layout.order = newScreenshotOrder(task, domain.SortUploadedDesc, domain.GroupByNone, layout.loc)
+		return layout
+	}
+	if task.Pdf.PageSize == domain.PageSizeLetter {
+		layout.size = pdf.Letter
+	}
+	landscape := task.Pdf.Orientation == domain.OrientationLandscape
+	if landscape {
+		layout.size = layout.size.Landscape()
+	}
+	switch grid, ok := pdfGrids[task.Pdf.ImagesPerPage]; {
+	case task.Pdf.ContactSheet:
+		layout.contactSheet = true
+		layout.columns, layout.rows = contactSheetGrid[0], contactSheetGrid[1]
+		if landscape {
+			layout.columns, layout.rows = layout.rows, layout.columns
+		}
+	case ok:
+		layout.columns, layout.rows = grid[0], grid[1]
+	}
+	layout.captions = task.Pdf.Captions
+	layout.header = task.Pdf.HeaderFooter
+	layout.title = task.Pdf.Title
+	layout.coverPage = task.Pdf.CoverPage
+	layout.watermark = task.Pdf.Watermark
+	layout.contents = task.Pdf.Contents != domain.ContentsNone
+	if task.Pdf.Timezone != "" {
+		// The time zone is validated when the export is created, so this only fails if the
+		// time zone database of the worker differs.
+		if loc, err := time.LoadLocation(task.Pdf.Timezone); err == nil {
+			layout.loc = loc
+		} else {
+			slog.Warn("unknown timezone, printing UTC times", "taskID", task.TaskID, "timezone", task.Pdf.Timezone, "error", err)
+		}
+	}
+	// Without a grouping of the export the table of contents groups by its own unit.
+	group := domain.GroupByNone
+	switch task.Pdf.Contents {
+	case domain.ContentsByHour:
+		group = domain.GroupByHour
+	case domain.ContentsByDay:
+		group = domain.GroupByDay
+	}
+	layout.order = newScreenshotOrder(task, domain.SortUploadedDesc, group, layout.loc)
+	return layout
+}
+
+// document returns the options of the document the task is rendered to.
+func (l pdfLayout) document(task domain.ExportTask) pdf.Options {
+	opts := pdf.Options{
+		Size:     l.size,
+		Columns:  l.columns,
+		Rows:     l.rows,
+		Font:     l.font,
+		Contents: l.contents,
+		Metadata: l.metadata(task),
+	}
+	if l.watermark {
+		opts.Watermark = l.watermarkText(task)
+	}
+	if !l.header {
+		return opts
+	}
+	opts.HeaderLeft = l.documentTitle(task)
+	opts.HeaderRight = l.timeRange(task.From, task.To)
+	opts.Footer = task.TaskID
+	opts.PageNumbers = true
+	return opts
+}
+
+// documentTitle is the title of the document, a description of the source if the task has none.
+func (l pdfLayout) documentTitle(task domain.ExportTask) string {
+	if l.title != "" {
+		return l.title
+	}
+	return exportSource(task)
+}
+
+// page is the page of a screenshot whose local copy is at path, with its caption and section.
+// Pages follow the order of the layout, newest first unless the task chooses another order.
+func (l pdfLayout) page(shot screenshot, path string) pdf.Image {
+	image := pdf.Image{Path: path, Section: shot.group.title}
+	switch {
+	case l.contactSheet:
+		image.Caption = []string{l.label(shot.id, shot.time)}
+	case l.captions && shot.file != nil:
+		image.Caption = l.caption(shot.file, shot.time)
+	}
+	return image
+}
+
+// caption describes a screenshot: when it was taken and by whom, and which storage file it is.
+func (l pdfLayout) caption(f *storage.File, uploadedAt time.Time) []string {
+	when := "Capture time unknown"
+	if !uploadedAt.IsZero() {
+		when = uploadedAt.In(l.loc).Format(captionTimeFormat)
+	}
+	if agent := f.GetUploadedBy(); agent != nil {
+		name := agent.GetName()
+		if name == "" {
+			name = fmt.Sprintf("#%d", agent.GetId())
+		}
+		when += " · Agent: " + name
+	}
+
+	lines := []string{when, fmt.Sprintf("File: %s · ID %d", f.GetName(), f.GetId())}
+	if f.GetSha256Sum() != "" {
+		lines = append(lines, "SHA-256: "+f.GetSha256Sum())
+	}
+	return lines
+}
+
+// label identifies a thumbnail of a contact sheet by its capture time and file ID.
+func (l pdfLayout) label(id string, uploadedAt time.Time) string {
+	if uploadedAt.IsZero() {
+		return "ID " + id
+	}
+	return uploadedAt.In(l.loc).Format(time.DateTime) + " · ID " + id
+}
+
+// cover describes the export on the cover page: its source and range, who requested it and when,
+// and how many of the found screenshots made it into the document.
+func (l pdfLayout) cover(task domain.ExportTask, included, skipped int) *pdf.Cover {
+	fields := append(l.coverFields(task),
+		pdf.CoverField{Label: "Screenshots included", Value: strconv.Itoa(included)},
+		pdf.CoverField{Label: "Screenshots skipped", Value: strconv.Itoa(skipped)},
+		pdf.CoverField{Label: "Export ID", Value: task.TaskID},
+	)
+	return &pdf.Cover{Title: l.documentTitle(task), Fields: fields}
+}
+
+// coverFields are the fields of the cover page every export has: its source and range,
+// and who requested it and when.
+func (l pdfLayout) coverFields(task domain.ExportTask) []pdf.CoverField {
+	fields := []pdf.CoverField{{Label: "Domain", Value: strconv.FormatInt(task.DomainID, 10)}}
+	if task.AgentID != 0 {
+		fields = append(fields, pdf.CoverField{Label: "Agent", Value: strconv.FormatInt(task.AgentID, 10)})
+	} else {
+		fields = append(fields, pdf.CoverField{Label: "Call", Value: task.CallID})
+	}
+
+	period := l.timeRange(task.From, task.To)
+	if period == "" {
+		period = "Not limited"
+	}
+	fields = append(fields, pdf.CoverField{Label: "Time range", Value: period})
+
+	fields = append(fields, pdf.CoverField{Label: "Requested by", Value: requester(task)})
+	if task.CreatedAt != 0 {
+		fields = append(fields, pdf.CoverField{
+			Label: "Requested at",
+			Value: time.UnixMilli(task.CreatedAt).In(l.loc).Format(captionTimeFormat),
+		})
+	}
+	return fields
+}
+
+// watermarkText identifies the copy of the export on every page: who requested it, in which
+// domain, which export it is and when it was requested.
+func (l pdfLayout) watermarkText(task domain.ExportTask) string {
+	parts := []string{requester(task), fmt.Sprintf("Domain %d", task.DomainID), task.TaskID}
+	if task.CreatedAt != 0 {
+		parts = append(parts, time.UnixMilli(task.CreatedAt).In(l.loc).Format(captionTimeFormat))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// metadata describes the export in the document metadata. Besides what a viewer shows, it holds
+// the trace hash of the export, which leads from a copy of the document to its history record.
+func (l pdfLayout) metadata(task domain.ExportTask) *pdf.Metadata {
+	m := &pdf.Metadata{
+		Title:    l.documentTitle(task),
+		Author:   requester(task),
+		Subject:  exportSource(task),
+		Keywords: []string{task.TaskID},
+		Properties: map[string]string{
+			"ExportID":    task.TaskID,
+			"DomainID":    strconv.FormatInt(task.DomainID, 10),
+			"RequestedBy": strconv.FormatInt(task.UserID, 10),
+		},
+	}
+	if task.CreatedAt != 0 {
+		m.Created = time.UnixMilli(task.CreatedAt)
+	}
+	if task.TraceHash != "" {
+		m.Keywords = append(m.Keywords, "trace:"+task.TraceHash)
+		m.Properties["TraceHash"] = task.TraceHash
+		// The hash is random enough to identify the file in the trailer as well.
+		if id, err := hex.DecodeString(task.TraceHash); err == nil && len(id) >= 16 {
+			m.ID = id[:16]
+		}
+	}
+	return m
+}
+
+// requester names the user who requested the export.
+func requester(task domain.ExportTask) string {
+	if task.RequestedBy == "" {
+		return fmt.Sprintf("#%d", task.UserID)
+	}
+	return fmt.Sprintf("%s (#%d)", task.RequestedBy, task.UserID)
+}
+
+// timeRange describes the requested time range of an export, empty if there is none.
+func (l pdfLayout) timeRange(from, to int64) string {
+	format := func(ms int64, layout string) string {
+		return time.UnixMilli(ms).In(l.loc).Format(layout)
+	}
+	switch {
+	case from > 0 && to > 0:
+		return format(from, rangeTimeFormat) + " – " + format(to, rangeTimeFormat+" MST")
+	case from > 0:
+		return "From " + format(from, rangeTimeFormat+" MST")
+	case to > 0:
+		return "Until " + format(to, rangeTimeFormat+" MST")
+	default:
+		return ""
+	}
+}
+
+// exportSource describes whose screenshots, or which call, an export contains.
+func exportSource(task domain.ExportTask) string {
+	switch task.Type {
+	case domain.TranscriptExportType:
+		return fmt.Sprintf("Transcript of call %s", task.CallID)
+	case domain.DossierExportType:
+		return fmt.Sprintf("Dossier of call %s", task.CallID)
+	}
+	if task.AgentID != 0 {
+		return fmt.Sprintf("Screenshots of agent %d", task.AgentID)
+	}
+	return fmt.Sprintf("Screenshots of call %s", task.CallID)
+}
@@ -0,0 +1,247 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/webitel/media-exporter/api/storage"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/util/pdf"
+	"github.com/webitel/storage/gen/engine"
+)
+
+// layoutPages returns the pages of the downloaded screenshots in the order of the layout.
+func layoutPages(l pdfLayout, files map[string]string, infos map[string]*storage.File) []pdf.Image {
+	var pages []pdf.Image
+	for _, shot := range l.order.downloaded(files, infos) {
+		pages = append(pages, l.page(shot, shot.path))
+	}
+	return pages
+}
+
+func TestPdfPagesNewestFirst(t *testing.T) {
+	files := map[string]string{"1": "/tmp/a", "2": "/tmp/b", "3": "/tmp/c", "4": "/tmp/d", "5": ""}
+	infos := map[string]*storage.File{
+		"1": {Id: 1, UploadedAt: 1_700_000_000_000},
+		"2": {Id: 2, UploadedAt: 1_700_000_100},
+		"4": {Id: 4, UploadedAt: 1_700_000_050_000},
+	}
+
+	var got []string
+	images := layoutPages((&App{}).pdfLayout(domain.ExportTask{}), files, infos)
+	for _, img := range images {
+		got = append(got, img.Path)
+		if img.Caption != nil {
+			t.Errorf("%s has a caption without the captions option", img.Path)
+		}
+	}
+	want := []string{"/tmp/b", "/tmp/d", "/tmp/a", "/tmp/c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pages = %v, want %v", got, want)
+	}
+}
+
+func TestPdfCaptions(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Skipf("no time zone database: %v", err)
+	}
+	layout := pdfLayout{loc: kyiv, captions: true}
+	files := map[string]string{"7": "/tmp/a", "8": "/tmp/b"}
+	infos := map[string]*storage.File{
+		"7": {
+			Id:         7,
+			Name:       "screen.png",
+			UploadedAt: time.Date(2026, 7, 1, 9, 30, 0, 0, time.UTC).UnixMilli(),
+			UploadedBy: &engine.Lookup{Id: 12, Name: "Олена Коваль"},
+			Sha256Sum:  "ab12",
+		},
+		"8": {Id: 8, Name: "old.png", UploadedBy: &engine.Lookup{Id: 13}},
+	}
+
+	pages := layoutPages(layout, files, infos)
+	want := [][]string{
+		{"2026-07-01 12:30:00 EEST · Agent: Олена Коваль", "File: screen.png · ID 7", "SHA-256: ab12"},
+		{"Capture time unknown · Agent: #13", "File: old.png · ID 8"},
+	}
+	for i, page := range pages {
+		if !reflect.DeepEqual(page.Caption, want[i]) {
+			t.Errorf("caption of %s = %q, want %q", page.Path, page.Caption, want[i])
+		}
+	}
+}
+
+func TestPdfDocumentOptions(t *testing.T) {
+	task := domain.ExportTask{
+		TaskID: "pdf_call_1.pdf",
+		CallID: "c-1",
+		From:   time.Date(2026, 1, 10, 8, 0, 0, 0, time.UTC).UnixMilli(),
+		To:     time.Date(2026, 1, 10, 18, 0, 0, 0, time.UTC).UnixMilli(),
+	}
+
+	opts := (pdfLayout{loc: time.UTC}).document(task)
+	if opts.HeaderLeft != "" || opts.Footer != "" || opts.PageNumbers {
+		t.Errorf("header or footer without the option: %+v", opts)
+	}
+
+	opts = (pdfLayout{loc: time.UTC, header: true}).document(task)
+	if opts.HeaderLeft != "Screenshots of call c-1" || opts.HeaderRight != "2026-01-10 08:00 – 2026-01-10 18:00 UTC" {
+		t.Errorf("header = %q / %q", opts.HeaderLeft, opts.HeaderRight)
+	}
+	if opts.Footer != task.TaskID || !opts.PageNumbers {
+		t.Errorf("footer = %q, page numbers %v", opts.Footer, opts.PageNumbers)
+	}
+
+	task.From = 0
+	opts = (pdfLayout{loc: time.UTC, header: true, title: "Case 42"}).document(task)
+	if opts.HeaderLeft != "Case 42" || opts.HeaderRight != "Until 2026-01-10 18:00 UTC" {
+		t.Errorf("header = %q / %q", opts.HeaderLeft, opts.HeaderRight)
+	}
+}
+
+func TestPdfSections(t *testing.T) {
+	at := func(hour, minute int) int64 {
+		return time.Date(2026, 3, 2, hour, minute, 0, 0, time.UTC).UnixMilli()
+	}
+	files := map[string]string{"1": "/tmp/a", "2": "/tmp/b", "3": "/tmp/c", "4": "/tmp/d"}
+	infos := map[string]*storage.File{
+		"1": {Id: 1, UploadedAt: at(23, 10)},
+		"2": {Id: 2, UploadedAt: at(23, 50)},
+		"3": {Id: 3, UploadedAt: at(1, 5)},
+	}
+
+	sections := func(contents string) []string {
+		var got []string
+		layout := (&App{}).pdfLayout(domain.ExportTask{Pdf: &domain.PdfOptions{Contents: contents}})
+		images := layoutPages(layout, files, infos)
+		for _, img := range images {
+			got = append(got, img.Section)
+		}
+		return got
+	}
+	if got := sections(domain.ContentsNone); !reflect.DeepEqual(got, []string{"", "", "", ""}) {
+		t.Errorf("sections without contents = %q", got)
+	}
+	want := []string{"2026-03-02 23:00 – 00:00", "2026-03-02 23:00 – 00:00", "2026-03-02 01:00 – 02:00", "Capture time unknown"}
+	if got := sections(domain.ContentsByHour); !reflect.DeepEqual(got, want) {
+		t.Errorf("sections by hour = %q, want %q", got, want)
+	}
+	want = []string{"2026-03-02", "2026-03-02", "2026-03-02", "Capture time unknown"}
+	if got := sections(domain.ContentsByDay); !reflect.DeepEqual(got, want) {
+		t.Errorf("sections by day = %q, want %q", got, want)
+	}
+}
+
+func TestPdfCover(t *testing.T) {
+	task := domain.ExportTask{
+		TaskID:      "pdf_screenrecording_5.pdf",
+		AgentID:     12,
+		UserID:      5,
+		DomainID:    1,
+		From:        time.Date(2026, 1, 10, 8, 0, 0, 0, time.UTC).UnixMilli(),
+		To:          time.Date(2026, 1, 10, 18, 0, 0, 0, time.UTC).UnixMilli(),
+		RequestedBy: "Supervisor",
+		CreatedAt:   time.Date(2026, 1, 11, 9, 0, 0, 0, time.UTC).UnixMilli(),
+	}
+
+	cover := (pdfLayout{loc: time.UTC}).cover(task, 40, 2)
+	if cover.Title != "Screenshots of agent 12" {
+		t.Errorf("title = %q", cover.Title)
+	}
+	got := map[string]string{}
+	for _, f := range cover.Fields {
+		got[f.Label] = f.Value
+	}
+	want := map[string]string{
+		"Domain":               "1",
+		"Agent":                "12",
+		"Time range":           "2026-01-10 08:00 – 2026-01-10 18:00 UTC",
+		"Requested by":         "Supervisor (#5)",
+		"Requested at":         "2026-01-11 09:00:00 UTC",
+		"Screenshots included": "40",
+		"Screenshots skipped":  "2",
+		"Export ID":            task.TaskID,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cover fields = %v, want %v", got, want)
+	}
+}
+
+func TestPdfWatermarkAndMetadata(t *testing.T) {
+	task := domain.ExportTask{
+		TaskID:      "pdf_screenrecording_5.pdf",
+		AgentID:     12,
+		UserID:      5,
+		DomainID:    1,
+		RequestedBy: "Supervisor",
+		CreatedAt:   time.Date(2026, 1, 11, 9, 0, 0, 0, time.UTC).UnixMilli(),
+		TraceHash:   "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff",
+		Pdf:         &domain.PdfOptions{Watermark: true},
+	}
+
+	opts := (&App{}).pdfLayout(task).document(task)
+	if want := "Supervisor (#5) · Domain 1 · pdf_screenrecording_5.pdf · 2026-01-11 09:00:00 UTC"; opts.Watermark != want {
+		t.Errorf("watermark = %q, want %q", opts.Watermark, want)
+	}
+
+	m := opts.Metadata
+	if m == nil {
+		t.Fatal("no metadata")
+	}
+	if m.Author != "Supervisor (#5)" || m.Subject != "Screenshots of agent 12" || !m.Created.Equal(time.UnixMilli(task.CreatedAt)) {
+		t.Errorf("metadata = %+v", m)
+	}
+	if m.Properties["TraceHash"] != task.TraceHash || m.Properties["ExportID"] != task.TaskID {
+		t.Errorf("metadata properties = %v", m.Properties)
+	}
+	if want := []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}; !reflect.DeepEqual(m.ID, want) {
+		t.Errorf("metadata ID = %X", m.ID)
+	}
+
+	// The metadata is always written, the watermark only on request.
+	task.Pdf = nil
+	if opts := (&App{}).pdfLayout(task).document(task); opts.Watermark != "" || opts.Metadata == nil {
+		t.Errorf("without a watermark: %q, %v", opts.Watermark, opts.Metadata)
+	}
+}
+
+func TestPdfLayoutGrid(t *testing.T) {
+	tests := []struct {
+		name    string
+		pdf     *domain.PdfOptions
+		size    pdf.PageSize
+		columns int
+		rows    int
+	}{
+		{"default", nil, pdf.A4, 1, 1},
+		{"letter landscape", &domain.PdfOptions{PageSize: domain.PageSizeLetter, Orientation: domain.OrientationLandscape}, pdf.Letter.Landscape(), 1, 1},
+		{"six per page", &domain.PdfOptions{ImagesPerPage: 6}, pdf.A4, 2, 3},
+		{"contact sheet", &domain.PdfOptions{ContactSheet: true}, pdf.A4, 4, 6},
+		{"landscape contact sheet", &domain.PdfOptions{ContactSheet: true, Orientation: domain.OrientationLandscape}, pdf.A4.Landscape(), 6, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := (&App{}).pdfLayout(domain.ExportTask{Pdf: tt.pdf}).document(domain.ExportTask{})
+			if opts.Size != tt.size || opts.Columns != tt.columns || opts.Rows != tt.rows {
+				t.Errorf("document() = %v %dx%d, want %v %dx%d", opts.Size, opts.Columns, opts.Rows, tt.size, tt.columns, tt.rows)
+			}
+		})
+	}
+}
+
+func TestPdfContactSheetLabels(t *testing.T) {
+	layout := (&App{}).pdfLayout(domain.ExportTask{Pdf: &domain.PdfOptions{ContactSheet: true, Captions: true}})
+	files := map[string]string{"7": "/tmp/a", "8": "/tmp/b"}
+	infos := map[string]*storage.File{
+		"7": {Id: 7, Name: "screen.png", UploadedAt: time.Date(2026, 7, 1, 9, 30, 0, 0, time.UTC).UnixMilli()},
+	}
+
+	pages := layoutPages(layout, files, infos)
+	want := [][]string{{"2026-07-01 09:30:00 · ID 7"}, {"ID 8"}}
+	for i, page := range pages {
+		if !reflect.DeepEqual(page.Caption, want[i]) {
+			t.Errorf("label of %s = %q, want %q", page.Path, page.Caption, want[i])
+		}
+	}
+}
@@ -1,11 +1,13 @@
 package app
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/webitel/media-exporter/api/storage"
@@ -13,7 +15,33 @@ import (
 	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
 )
 
-func uploadPDFToStorage(ctx context.Context, session *model2.Session, app *App, filePath string, task domain.ExportTask) (*storage.UploadFileResponse, error) {
+// uploadName is the name the file of the task is uploaded with. The task ID is the file name
+// of the export, the extension is added only to the IDs of tasks queued without it.
+func uploadName(task domain.ExportTask, ext string) string {
+	if strings.HasSuffix(task.TaskID, "."+ext) {
+		return task.TaskID
+	}
+	return task.TaskID + "." + ext
+}
+
+// exportTempFile creates the local file the output of a task is written to before its upload.
+// Every task gets a file of its own, however many tasks of the same kind run at once;
+// the caller removes it. The file keeps the extension ext, some encoders pick the format by it.
+func (app *App) exportTempFile(exportType, ext string) (string, error) {
+	f, err := os.CreateTemp(cmp.Or(app.Config.TempDir, os.TempDir()), exportType+"-*."+ext)
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	path := f.Name()
+	if err := f.Close(); err != nil {
+		_ = os.Remove(path)
+		return "", fmt.Errorf("close temp file: %w", err)
+	}
+	return path, nil
+}
+
+// uploadFileToStorage streams a local export file to the storage service under the given name and MIME type.
+func uploadFileToStorage(ctx context.Context, session *model2.Session, app *App, filePath string, task domain.ExportTask, name, mimeType string) (*storage.UploadFileResponse, error) {
 	f, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("open file failed: %w", err)
@@ -21,26 +49,48 @@ func uploadPDFToStorage(ctx context.Context, session *model2.Session, app *App,
 	defer func(f *os.File) {
 		err := f.Close()
 		if err != nil {
-			slog.ErrorContext(ctx, "close file failed", slog.String("file", filePath), err)
+			slog.ErrorContext(ctx, "close file failed", slog.String("file", filePath), slog.Any("error", err))
 		}
 	}(f)
 
+	var total int64
+	if info, err := f.Stat(); err == nil {
+		total = info.Size()
+	}
+
+	return uploadStreamToStorage(ctx, session, app, f, total, task, name, mimeType)
+}
+
+// uploadStreamToStorage uploads everything read from r until io.EOF to the storage service.
+// Upload progress is reported only when the total size is known in advance.
+func uploadStreamToStorage(ctx context.Context, session *model2.Session, app *App, r io.Reader, total int64, task domain.ExportTask, name, mimeType string) (*storage.UploadFileResponse, error) {
 	stream, err := app.StorageClient.UploadFile(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("UploadFile init failed: %w", err)
 	}
 
-	if err := sendFileMetadata(stream, session, task); err != nil {
+	if err := sendFileMetadata(stream, session, task, name, mimeType); err != nil {
 		return nil, err
 	}
-	if err := sendFileChunks(stream, f); err != nil {
+	if total > 0 {
+		app.reportStage(ctx, task.TaskID, domain.StageUploading, 0, total)
+		r = &progressReader{
+			r:     r,
+			total: total,
+			last:  time.Now(),
+			report: func(current, total int64) {
+				app.reportStage(ctx, task.TaskID, domain.StageUploading, current, total)
+			},
+		}
+	}
+	if err := sendFileChunks(stream, r); err != nil {
 		return nil, err
 	}
 
 	return stream.CloseAndRecv()
 }
 
-func sendFileMetadata(stream storage.FileService_UploadFileClient, session *model2.Session, task domain.ExportTask) error {
+func sendFileMetadata(stream storage.FileService_UploadFileClient, session *model2.Session, task domain.ExportTask, name, mimeType string) error {
 	channel, err := ParseUploadFileChannel(task.Channel)
 	if err != nil {
 		return err
@@ -48,8 +98,8 @@ func sendFileMetadata(stream storage.FileService_UploadFileClient, session *mode
 	return stream.Send(&storage.UploadFileRequest{
 		Data: &storage.UploadFileRequest_Metadata_{
 			Metadata: &storage.UploadFileRequest_Metadata{
-				Name:           task.TaskID + ".pdf",
-				MimeType:       "application/pdf",
+				Name:           name,
+				MimeType:       mimeType,
 				Uuid:           task.TaskID,
 				StreamResponse: true,
 				Channel:        channel,
@@ -72,7 +122,7 @@ func ParseUploadFileChannel(channel string) (storage.UploadFileChannel, error) {
 	}
 }
 
-func sendFileChunks(stream storage.FileService_UploadFileClient, f *os.File) error {
+func sendFileChunks(stream storage.FileService_UploadFileClient, f io.Reader) error {
 	buf := make([]byte, 32*1024)
 	for {
 		n, err := f.Read(buf)
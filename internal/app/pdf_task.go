@@ -2,128 +2,90 @@ package app
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"log/slog"
-	"path/filepath"
 	"time"
 
 	"github.com/webitel/media-exporter/api/storage"
+	"github.com/webitel/media-exporter/auth/session/user_session"
 	"github.com/webitel/media-exporter/internal/domain/model"
+	"github.com/webitel/media-exporter/internal/domain/model/options"
 	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
 	"github.com/webitel/media-exporter/internal/util"
-	"github.com/webitel/media-exporter/internal/util/pdf/maroto"
-	"github.com/webitel/storage/gen/engine"
+	"github.com/webitel/media-exporter/internal/util/pdf"
 )
 
 func (app *App) HandlePdfTask(ctx context.Context, session *model.Session, task domain.ExportTask) error {
-	historyID, err := app.Cache.GetExportHistoryID(task.TaskID)
+	historyID, err := app.taskHistoryID(task)
 	if err != nil {
-
-		_ = app.Cache.SetExportStatus(task.TaskID, "failed")
-		return fmt.Errorf("historyID missing for task %s: %w", task.TaskID, err)
+		return err
 	}
 
-	if err := SetTaskStatus(app, historyID, task.TaskID, "processing", session.UserID(), nil); err != nil {
+	if err := SetTaskStatus(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, "processing", nil); err != nil {
 		return fmt.Errorf("failed to set processing status: %w", err)
 	}
 
-	ctx = util.ContextWithHeaders(task.Headers)
+	ctx = util.ContextWithHeaders(ctx, task.Headers)
 
-	channel, err := ParseChannel(task.Channel)
+	app.reportStage(ctx, task.TaskID, domain.StageSearching, 0, 0)
+	pages, err := app.screenshotPages(task)
 	if err != nil {
-		_ = app.Cache.SetExportStatus(task.TaskID, "failed")
-		return fmt.Errorf("channel missing for task %s: %w", task.TaskID, err)
-	}
-
-	var filesResp *storage.ListFile
-
-	if task.AgentID != 0 {
-		filesResp, err = app.StorageClient.SearchScreenRecordingsByAgent(ctx, &storage.SearchScreenRecordingsByAgentRequest{
-			Id:      task.IDs,
-			Type:    storage.ScreenrecordingType_SCREENSHOT,
-			Channel: channel,
-			AgentId: task.AgentID,
-			Size:    1000,
-			UploadedAt: &engine.FilterBetween{
-				From: task.From,
-				To:   task.To,
-			},
-		})
-	} else {
-		filesResp, err = app.StorageClient.SearchScreenRecordings(ctx, &storage.SearchScreenRecordingsRequest{
-			Id:      task.IDs,
-			Type:    storage.ScreenrecordingType_SCREENSHOT,
-			Channel: channel,
-			Size:    1000,
-			UploadedAt: &engine.FilterBetween{
-				From: task.From,
-				To:   task.To,
-			},
-		})
+		return err
 	}
 
+	files, err := pages.All(ctx)
 	if err != nil {
-		return fmt.Errorf("SearchScreenRecordings failed: %w", err)
+		return fmt.Errorf("search failed: %w", err)
 	}
-
-	if filesResp == nil || filesResp.Items == nil || len(filesResp.Items) == 0 {
-		_ = SetTaskStatus(app, historyID, task.TaskID, "failed", session.UserID(), nil)
-		return fmt.Errorf("failed to find files: %w", err)
+	if len(files) == 0 {
+		return fmt.Errorf("no files found for task %s", task.TaskID)
 	}
 
-	tmpFiles, fileInfos, err := downloadScreenshotsForPDF(ctx, session, app, filesResp.Items)
-	if err != nil {
-		slog.ErrorContext(ctx, "downloadScreenshotsForPDF failed", "taskID", task.TaskID, "error", err)
-		_ = SetTaskStatus(app, historyID, task.TaskID, "failed", session.UserID(), nil)
-		return fmt.Errorf("download failed: %w", err)
+	layout := app.pdfLayout(task)
+	shots := layout.order.listed(files)
+	opts := layout.document(task)
+	if opts.Encryption, err = app.pdfEncryption(task); err != nil {
+		return err
 	}
-	defer util.CleanupFiles(tmpFiles)
-
-	pdfBytes, err := maroto.GeneratePDF(tmpFiles, fileInfos)
-	if err != nil {
-		slog.ErrorContext(ctx, "GeneratePDF failed", "taskID", task.TaskID, "error", err)
-		_ = SetTaskStatus(app, historyID, task.TaskID, "failed", session.UserID(), nil)
-		return fmt.Errorf("PDF generation failed: %w", err)
-	}
-
-	now := time.Now()
-	var fileName string
-	switch task.Channel {
-	case "call":
-		fileName = fmt.Sprintf("pdf_vc_%d_%04d-%02d-%02d_%02d_%02d_%02d.pdf",
-			session.UserID(),
-			now.Year(), now.Month(), now.Day(),
-			now.Hour(), now.Minute(), now.Second(),
-		)
-	case "screenrecording":
-		fileName = fmt.Sprintf("pdf_ss_%d_%04d-%02d-%02d_%02d_%02d_%02d.pdf",
-			session.UserID(),
-			now.Year(), now.Month(), now.Day(),
-			now.Hour(), now.Minute(), now.Second(),
-		)
-	default:
-		fileName = fmt.Sprintf("pdf_unknown_%d_%04d-%02d-%02d_%02d_%02d_%02d.pdf",
-			session.UserID(),
-			now.Year(), now.Month(), now.Day(),
-			now.Hour(), now.Minute(), now.Second(),
-		)
-	}
-
-	tempFilePath := filepath.Join(app.Config.TempDir, fileName)
-	if err := util.SavePDFToTemp(tempFilePath, pdfBytes); err != nil {
-		slog.ErrorContext(ctx, "SavePDFToFile failed", "taskID", task.TaskID, "error", err)
-		_ = SetTaskStatus(app, historyID, task.TaskID, "failed", session.UserID(), nil)
-		return fmt.Errorf("save PDF failed: %w", err)
+	signed := task.Pdf != nil && task.Pdf.SignedManifest
+	if signed && app.Signer == nil {
+		return fmt.Errorf("export %s asks for a signed manifest but no signing key is configured", task.TaskID)
 	}
 
-	res, err := uploadPDFToStorage(ctx, session, app, tempFilePath, task)
+	// Pages are rendered as their screenshots arrive, so only the few screenshots the downloads
+	// run ahead of the document are on disk at a time.
+	progress := newStageCounter(int64(len(shots)), func(current, total int64) {
+		app.reportStage(ctx, task.TaskID, domain.StageRendering, current, total)
+	})
+	downloads := app.newDownloadPipeline(session.DomainID(), pdfImageWidth, progress.Inc)
+	res, digest, err := app.streamPDF(ctx, session, task, opts, func(doc *pdf.Document) error {
+		added := &pdfPages{r: doc}
+		failed, err := downloads.Stream(ctx, shots, func(shot screenshot, path string) error {
+			return added.add(ctx, shot, layout.page(shot, path))
+		})
+		if err != nil {
+			return err
+		}
+		if len(failed) > 0 {
+			slog.WarnContext(ctx, "some screenshots were skipped", "taskID", task.TaskID, "skipped", len(failed), "downloaded", len(added.shots))
+		}
+		if layout.coverPage {
+			doc.SetCover(layout.cover(task, len(added.rendered), len(shots)-len(added.rendered)))
+		}
+		if signed {
+			return app.attachManifest(doc, pdfManifest(task, added.shots, added.rendered, failed))
+		}
+		return nil
+	})
 	if err != nil {
-		slog.ErrorContext(ctx, "uploadPDFToStorage failed", "taskID", task.TaskID, "error", err)
-		_ = SetTaskStatus(app, historyID, task.TaskID, "failed", session.UserID(), nil)
-		return fmt.Errorf("upload failed: %w", err)
+		slog.ErrorContext(ctx, "streamPDF failed", "taskID", task.TaskID, "error", err)
+		return err
 	}
 
-	if err := SetTaskStatus(app, historyID, task.TaskID, "done", session.UserID(), &res.FileId); err != nil {
+	if err := SetTaskDone(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, res, app.signDigest(task, digest)); err != nil {
 		slog.ErrorContext(ctx, "failed to set done status", "taskID", task.TaskID, "error", err)
 		return fmt.Errorf("failed to set done status: %w", err)
 	}
@@ -135,6 +97,88 @@ func (app *App) HandlePdfTask(ctx context.Context, session *model.Session, task
 	return nil
 }
 
+// pdfEncryption returns the encryption of the document of the task, nil if it is not encrypted.
+// Encrypted exports can be viewed only: printing, copying and changing them is not permitted.
+func (app *App) pdfEncryption(task domain.ExportTask) (*pdf.Encryption, error) {
+	if task.Pdf == nil || task.Pdf.Encryption == nil {
+		return nil, nil
+	}
+	if app.PasswordSealer == nil {
+		return nil, fmt.Errorf("export %s is encrypted but no password key is configured", task.TaskID)
+	}
+	password, err := app.PasswordSealer.Open(task.SealedPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the password of export %s: %w", task.TaskID, err)
+	}
+	return &pdf.Encryption{UserPassword: password}, nil
+}
+
+// streamPDF renders a PDF and uploads it while it is being rendered: the document goes through
+// a pipe straight into the upload stream, so it is never held in memory or on disk as a whole.
+// render adds the content to the document, which streamPDF then closes. The SHA-256 of the
+// document is returned with the uploaded file.
+func (app *App) streamPDF(
+	ctx context.Context,
+	session *model.Session,
+	task domain.ExportTask,
+	opts pdf.Options,
+	render func(doc *pdf.Document) error,
+) (*storage.UploadFileResponse, string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	hash := sha256.New()
+	rendered := make(chan error, 1)
+	go func() {
+		doc := pdf.NewDocument(io.MultiWriter(pw, hash), opts)
+		err := render(doc)
+		if err == nil {
+			err = doc.Close()
+		}
+		// A nil error ends the upload with io.EOF.
+		_ = pw.CloseWithError(err)
+		rendered <- err
+	}()
+
+	res, uploadErr := uploadStreamToStorage(ctx, session, app, pr, 0, task, uploadName(task, PdfExportType), "application/pdf")
+	if uploadErr != nil {
+		// Unblock the renderer and abort the upload stream, so no truncated file is stored.
+		cancel()
+		_ = pr.CloseWithError(uploadErr)
+	}
+	if err := <-rendered; err != nil {
+		return nil, "", fmt.Errorf("PDF generation failed: %w", err)
+	}
+	if uploadErr != nil {
+		return nil, "", fmt.Errorf("upload failed: %w", uploadErr)
+	}
+	return res, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// pdfPages adds the pages of screenshots to r one at a time and remembers which of them were rendered.
+type pdfPages struct {
+	r pdf.Renderer
+	// shots are the screenshots given to add, rendered the indexes of those with a page.
+	shots    []screenshot
+	rendered []int
+}
+
+// add adds the page of shot. Images that cannot be read are skipped.
+// It fails with ctx.Err() once ctx is done.
+func (p *pdfPages) add(ctx context.Context, shot screenshot, page pdf.Image) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := p.r.AddImage(page); err != nil {
+		slog.WarnContext(ctx, "skipping screenshot that cannot be rendered", "path", page.Path, "error", err)
+	} else {
+		p.rendered = append(p.rendered, len(p.shots))
+	}
+	p.shots = append(p.shots, shot)
+	return nil
+}
+
 func ParseChannel(channel string) (storage.ScreenrecordingChannel, error) {
 	switch channel {
 	case "call":
@@ -146,12 +190,76 @@ func ParseChannel(channel string) (storage.ScreenrecordingChannel, error) {
 	}
 }
 
-func SetTaskStatus(app *App, historyID int64, taskID, status string, updatedBy int64, fileID *int64) error {
+func SetTaskStatus(app *App, opts *options.UpdateOptions, historyID int64, taskID, status string, fileID *int64) error {
 	_ = app.Cache.SetExportStatus(taskID, status)
-	return app.Store.Pdf().UpdatePdfExportStatus(&domain.UpdateExportStatus{
+	event := domain.ExportProgress{TaskID: taskID, Status: status}
+	if domain.IsFinalStatus(status) {
+		event.Stage = domain.StageCompleted
+	}
+	app.publishProgress(opts, event)
+	return app.Store.Pdf().UpdatePdfExportStatus(opts, &domain.UpdateExportStatus{
 		ID:        historyID,
 		Status:    status,
-		UpdatedBy: updatedBy,
+		UpdatedBy: opts.Auth.GetUserId(),
 		FileID:    fileID,
 	})
 }
+
+// taskUpdateOptions returns the options for history updates the worker makes on behalf of the task owner,
+// which scopes them to the domain the task was created in.
+func taskUpdateOptions(ctx context.Context, task domain.ExportTask) *options.UpdateOptions {
+	return &options.UpdateOptions{
+		Context: ctx,
+		Time:    time.Now().UTC(),
+		Auth: &user_session.UserAuthSession{
+			User:     &user_session.User{Id: task.UserID},
+			DomainId: task.DomainID,
+		},
+	}
+}
+
+// SetTaskDone marks the task as done and stores the reference to the uploaded file,
+// and the digest of the file if it is not nil.
+func SetTaskDone(app *App, opts *options.UpdateOptions, historyID int64, taskID string, res *storage.UploadFileResponse, digest *exportDigest) error {
+	_ = app.Cache.SetExportStatus(taskID, "done")
+	app.publishProgress(opts, domain.ExportProgress{
+		TaskID: taskID,
+		Status: "done",
+		Stage:  domain.StageCompleted,
+		FileID: res.FileId,
+	})
+	update := &domain.UpdateExportStatus{
+		ID:        historyID,
+		Status:    "done",
+		UpdatedBy: opts.Auth.GetUserId(),
+		FileID:    &res.FileId,
+		Size:      res.Size,
+	}
+	if digest != nil {
+		update.Digest = digest.sha256
+		update.DigestSignature = digest.signature
+	}
+	return app.Store.Pdf().UpdatePdfExportStatus(opts, update)
+}
+
+// SetTaskAttempt records a failed attempt of the task together with the reason of the failure.
+func SetTaskAttempt(app *App, opts *options.UpdateOptions, historyID int64, taskID, status string, attempts int, cause error) error {
+	_ = app.Cache.SetExportStatus(taskID, status)
+	stage := domain.StageQueued
+	if status == "failed" {
+		stage = domain.StageCompleted
+	}
+	app.publishProgress(opts, domain.ExportProgress{
+		TaskID: taskID,
+		Status: status,
+		Stage:  stage,
+		Error:  cause.Error(),
+	})
+	return app.Store.Pdf().UpdatePdfExportStatus(opts, &domain.UpdateExportStatus{
+		ID:        historyID,
+		Status:    status,
+		UpdatedBy: opts.Auth.GetUserId(),
+		Attempts:  attempts,
+		LastError: cause.Error(),
+	})
+}
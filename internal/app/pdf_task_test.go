@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	cfg "github.com/webitel/media-exporter/config"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/util/pdf"
+)
+
+// fakeRenderer records the pages it is given and fails on the broken ones.
+type fakeRenderer struct {
+	pages  []string
+	broken map[string]bool
+	onAdd  func()
+}
+
+func (r *fakeRenderer) AddImage(img pdf.Image) error {
+	if r.onAdd != nil {
+		r.onAdd()
+	}
+	if r.broken[img.Path] {
+		return errors.New("unknown image format")
+	}
+	r.pages = append(r.pages, img.Path)
+	return nil
+}
+
+func (r *fakeRenderer) Close() error { return nil }
+
+func shots(paths ...string) []screenshot {
+	shots := make([]screenshot, len(paths))
+	for i, path := range paths {
+		shots[i] = screenshot{id: path, path: path}
+	}
+	return shots
+}
+
+func TestPdfPagesSkipBrokenImages(t *testing.T) {
+	r := &fakeRenderer{broken: map[string]bool{"/tmp/b": true}}
+	added := &pdfPages{r: r}
+	for _, shot := range shots("/tmp/a", "/tmp/b", "/tmp/c") {
+		if err := added.add(context.Background(), shot, pdf.Image{Path: shot.path}); err != nil {
+			t.Fatalf("add() error = %v", err)
+		}
+	}
+	if !reflect.DeepEqual(added.rendered, []int{0, 2}) || !reflect.DeepEqual(r.pages, []string{"/tmp/a", "/tmp/c"}) {
+		t.Errorf("rendered images %v, pages %v, want /tmp/a and /tmp/c", added.rendered, r.pages)
+	}
+	if len(added.shots) != 3 {
+		t.Errorf("%d screenshots recorded, want every one given", len(added.shots))
+	}
+}
+
+func TestPdfPagesCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &fakeRenderer{onAdd: cancel}
+	added := &pdfPages{r: r}
+	var err error
+	for _, shot := range shots("/tmp/a", "/tmp/b", "/tmp/c") {
+		if err = added.add(ctx, shot, pdf.Image{Path: shot.path}); err != nil {
+			break
+		}
+	}
+	if !errors.Is(err, context.Canceled) || len(added.rendered) != 1 {
+		t.Errorf("add() = %v after %d pages, want context.Canceled after 1", err, len(added.rendered))
+	}
+}
+
+func TestUploadName(t *testing.T) {
+	tests := []struct {
+		taskID string
+		ext    string
+		want   string
+	}{
+		{"pdf_screenrecording_7_2026-03-02_10_20_30.pdf", PdfExportType, "pdf_screenrecording_7_2026-03-02_10_20_30.pdf"},
+		{"zip_call_7_2026-03-02_10_20_30.zip", ZipExportType, "zip_call_7_2026-03-02_10_20_30.zip"},
+		{"mp4_screenrecording_7_2026-03-02_10_20_30_12_1.mp4", "mp4", "mp4_screenrecording_7_2026-03-02_10_20_30_12_1.mp4"},
+		{"pdf_screenrecording_7_2026-03-02_10_20_30", PdfExportType, "pdf_screenrecording_7_2026-03-02_10_20_30.pdf"},
+		{"transcript.pdf.zip", ZipExportType, "transcript.pdf.zip"},
+	}
+	for _, tt := range tests {
+		if got := uploadName(domain.ExportTask{TaskID: tt.taskID}, tt.ext); got != tt.want {
+			t.Errorf("uploadName(%s, %s) = %s, want %s", tt.taskID, tt.ext, got, tt.want)
+		}
+	}
+}
+
+// Tasks of the same kind started at the same time must not share their output file.
+func TestExportTempFile(t *testing.T) {
+	dir := t.TempDir()
+	app := &App{Config: &cfg.AppConfig{TempDir: dir}}
+
+	first, err := app.exportTempFile(BatchExportType, ZipExportType)
+	if err != nil {
+		t.Fatalf("exportTempFile() error = %v", err)
+	}
+	second, err := app.exportTempFile(BatchExportType, ZipExportType)
+	if err != nil {
+		t.Fatalf("exportTempFile() error = %v", err)
+	}
+	if first == second {
+		t.Fatalf("two tasks got the same temp file %s", first)
+	}
+	for _, path := range []string{first, second} {
+		if filepath.Dir(path) != dir || filepath.Ext(path) != ".zip" {
+			t.Errorf("temp file %s, want a .zip file in %s", path, dir)
+		}
+	}
+}
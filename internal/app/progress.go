@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+)
+
+// progressInterval limits how often high-frequency stages (e.g. uploading bytes) publish an event.
+const progressInterval = 500 * time.Millisecond
+
+// publishProgress broadcasts a progress event of the task. Progress is best effort,
+// so a failure is only logged and never fails the task.
+func (app *App) publishProgress(ctx context.Context, event domain.ExportProgress) {
+	event.Timestamp = time.Now().UnixMilli()
+	if err := app.Cache.PublishProgress(event); err != nil {
+		slog.WarnContext(ctx, "failed to publish export progress", "taskID", event.TaskID, "stage", event.Stage, "error", err)
+	}
+}
+
+// reportStage publishes a "processing" event for the given stage of the task.
+func (app *App) reportStage(ctx context.Context, taskID, stage string, current, total int64) {
+	app.publishProgress(ctx, domain.ExportProgress{
+		TaskID:  taskID,
+		Status:  "processing",
+		Stage:   stage,
+		Current: current,
+		Total:   total,
+	})
+}
+
+// stageCounter counts processed units of a stage from concurrent goroutines and reports every step.
+type stageCounter struct {
+	mu      sync.Mutex
+	current int64
+	total   int64
+	report  func(current, total int64)
+}
+
+func newStageCounter(total int64, report func(current, total int64)) *stageCounter {
+	return &stageCounter{total: total, report: report}
+}
+
+// Grow adds n units to the total, for stages whose size is only known as they go.
+func (c *stageCounter) Grow(n int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total += n
+}
+
+func (c *stageCounter) Inc() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current++
+	c.report(c.current, c.total)
+}
+
+// progressReader reports how many bytes were read from r, at most once per progressInterval
+// and always when the end of the input is reached.
+type progressReader struct {
+	r      io.Reader
+	read   int64
+	total  int64
+	last   time.Time
+	report func(current, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+	if err == io.EOF || time.Since(p.last) >= progressInterval {
+		p.last = time.Now()
+		p.report(p.read, p.total)
+	}
+	return n, err
+}
@@ -29,13 +29,22 @@ func RegisterServices(grpcServer *grpc.Server, appInstance *App) {
 			init: func(a *App) (any, error) {
 				pdfService, err := service.NewPdfService(
 					a.Store.Pdf(),
+					a.Store.Schedule(),
 					a.Cache,
+					a.PasswordSealer,
+					a.Signer,
 					log,
 				)
 				if err != nil {
 					return nil, fmt.Errorf("failed to init pdf s: %w", err)
 				}
 
+				// The scheduler creates the exports of the schedules through the same service.
+				a.scheduler, err = service.NewExportScheduler(pdfService, a.sessionManager, log)
+				if err != nil {
+					return nil, fmt.Errorf("failed to init export scheduler: %w", err)
+				}
+
 				pdfHandler, err := grpc2.NewPdfHandler(pdfService)
 				if err != nil {
 					return nil, fmt.Errorf("failed to init pdf handler: %w", err)
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultMaxAttempts    = 5
+	defaultRetryBaseDelay = 5 * time.Second
+	defaultRetryMaxDelay  = 5 * time.Minute
+)
+
+// handleTaskFailure decides what happens to a task whose handler returned an error.
+// Transient failures are re-enqueued with exponential backoff until the attempts are exhausted,
+// after which the task is moved to the dead-letter list. Permanent failures fail the task at once.
+func (app *App) handleTaskFailure(ctx context.Context, task domain.ExportTask, cause error) {
+	attempts := task.Attempts + 1
+	maxAttempts := app.maxAttempts()
+	retryable := isRetryable(cause)
+
+	historyID, err := app.taskHistoryID(task)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to resolve export history", "taskID", task.TaskID, "error", err)
+	}
+
+	if retryable && attempts < maxAttempts {
+		delay := app.retryDelay(attempts)
+		task.Attempts = attempts
+		if historyID != 0 {
+			if err := SetTaskAttempt(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, "pending", attempts, cause); err != nil {
+				slog.ErrorContext(ctx, "failed to record export attempt", "taskID", task.TaskID, "error", err)
+			}
+		}
+		if err := app.Cache.Retry(task, delay); err != nil {
+			slog.ErrorContext(ctx, "failed to schedule export retry", "taskID", task.TaskID, "error", err)
+			return
+		}
+		slog.WarnContext(ctx, "export task failed, retry scheduled",
+			"taskID", task.TaskID,
+			"attempt", attempts,
+			"maxAttempts", maxAttempts,
+			"delay", delay,
+			"error", cause,
+		)
+		return
+	}
+
+	_ = app.Cache.SetExportStatus(task.TaskID, "failed")
+	if historyID != 0 {
+		if err := SetTaskAttempt(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, "failed", attempts, cause); err != nil {
+			slog.ErrorContext(ctx, "failed to set failed status", "taskID", task.TaskID, "error", err)
+		}
+	}
+
+	if retryable {
+		task.Attempts = attempts
+		if err := app.Cache.DeadLetter(task, cause.Error()); err != nil {
+			slog.ErrorContext(ctx, "failed to dead-letter export task", "taskID", task.TaskID, "error", err)
+		}
+		slog.ErrorContext(ctx, "export task exhausted its attempts", "taskID", task.TaskID, "attempts", attempts, "error", cause)
+	} else {
+		slog.ErrorContext(ctx, "export task failed permanently", "taskID", task.TaskID, "type", task.Type, "error", cause)
+	}
+
+	_ = app.Cache.ClearExportTask(task.TaskID)
+}
+
+// isRetryable reports whether the error is transient, i.e. the same task may succeed later.
+// Only storage outages and timeouts are retried; everything else (bad input, missing files,
+// permission errors) fails the same way on every attempt.
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay returns the backoff before the given attempt: base * 2^(attempt-1), capped at the
+// configured maximum, with up to 20% of jitter so that tasks failed together do not retry together.
+func (app *App) retryDelay(attempt int) time.Duration {
+	base, maxDelay := defaultRetryBaseDelay, defaultRetryMaxDelay
+	if app.Config.Export.RetryBaseDelay > 0 {
+		base = app.Config.Export.RetryBaseDelay
+	}
+	if app.Config.Export.RetryMaxDelay > 0 {
+		maxDelay = app.Config.Export.RetryMaxDelay
+	}
+
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return delay - time.Duration(rand.Int64N(int64(delay)/5+1))
+}
+
+func (app *App) maxAttempts() int {
+	if app.Config.Export.MaxAttempts <= 0 {
+		return defaultMaxAttempts
+	}
+	return app.Config.Export.MaxAttempts
+}
+
+// taskHistoryID returns the history record of the task. Tasks queued before the ID was part
+// of the payload are resolved through the cache.
+func (app *App) taskHistoryID(task domain.ExportTask) (int64, error) {
+	if task.HistoryID != 0 {
+		return task.HistoryID, nil
+	}
+	historyID, err := app.Cache.GetExportHistoryID(task.TaskID)
+	if err != nil {
+		return 0, fmt.Errorf("historyID missing for task %s: %w", task.TaskID, err)
+	}
+	if historyID == 0 {
+		return 0, fmt.Errorf("historyID missing for task %s", task.TaskID)
+	}
+	return historyID, nil
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	cfg "github.com/webitel/media-exporter/config"
+	"github.com/webitel/media-exporter/internal/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		export  cfg.ExportConfig
+		attempt int
+		want    time.Duration
+	}{
+		{"first retry waits the default base", cfg.ExportConfig{}, 1, defaultRetryBaseDelay},
+		{"every retry doubles the delay", cfg.ExportConfig{}, 3, 4 * defaultRetryBaseDelay},
+		{"the delay is capped by the default maximum", cfg.ExportConfig{}, 20, defaultRetryMaxDelay},
+		{"configured base", cfg.ExportConfig{RetryBaseDelay: time.Second}, 2, 2 * time.Second},
+		{"configured maximum", cfg.ExportConfig{RetryBaseDelay: time.Second, RetryMaxDelay: 3 * time.Second}, 5, 3 * time.Second},
+		{"a base over the maximum is capped", cfg.ExportConfig{RetryBaseDelay: time.Hour}, 1, defaultRetryMaxDelay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &App{Config: &cfg.AppConfig{Export: &tt.export}}
+			// The jitter takes up to 20% off the delay, never adds to it.
+			for range 50 {
+				got := app.retryDelay(tt.attempt)
+				if got > tt.want || got < tt.want-tt.want/5 {
+					t.Fatalf("retryDelay(%d) = %v, want within [%v, %v]", tt.attempt, got, tt.want-tt.want/5, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"no error", nil, false},
+		{"deadline exceeded", context.DeadlineExceeded, true},
+		{"wrapped deadline exceeded", fmt.Errorf("download: %w", context.DeadlineExceeded), true},
+		{"storage unavailable", status.Error(codes.Unavailable, "connection refused"), true},
+		{"storage timeout", status.Error(codes.DeadlineExceeded, "timeout"), true},
+		{"storage exhausted", status.Error(codes.ResourceExhausted, "too many requests"), true},
+		{"storage aborted", status.Error(codes.Aborted, "conflict"), true},
+		{"missing file", status.Error(codes.NotFound, "file not found"), false},
+		{"permission denied", errors.Forbidden("no access"), false},
+		{"bad input", errors.BadRequest("bad channel"), false},
+		{"canceled", context.Canceled, false},
+		{"plain error", fmt.Errorf("render failed"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
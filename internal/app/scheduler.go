@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"time"
+)
+
+const (
+	schedulerRole     = "export_scheduler"
+	schedulerInterval = 30 * time.Second
+	// schedulerLease is how long the leader keeps the leadership without renewing it,
+	// so that another instance takes over a few intervals after the leader is gone.
+	schedulerLease = 3 * schedulerInterval
+)
+
+// runExportScheduler makes the runs of the export schedules that are due. Only one instance,
+// the holder of the scheduler leadership, runs them at a time.
+func (app *App) runExportScheduler(ctx context.Context) {
+	if app.scheduler == nil {
+		slog.WarnContext(ctx, "export scheduler is not initialized, export schedules will not run")
+		return
+	}
+
+	holder := schedulerHolder()
+	defer func() {
+		if err := app.Cache.ReleaseLeadership(schedulerRole, holder); err != nil {
+			slog.Error("failed to release export scheduler leadership", "error", err)
+		}
+	}()
+
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+	leader := false
+	for {
+		isLeader, err := app.Cache.AcquireLeadership(schedulerRole, holder, schedulerLease)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to acquire export scheduler leadership", "error", err)
+			isLeader = false
+		}
+		if isLeader != leader {
+			leader = isLeader
+			slog.InfoContext(ctx, "export scheduler leadership changed", "holder", holder, "leader", leader)
+		}
+
+		if leader {
+			runs, err := app.scheduler.RunDueSchedules(ctx, time.Now())
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to run due export schedules", "error", err)
+			}
+			if runs > 0 {
+				slog.InfoContext(ctx, "made export schedule runs", "count", runs)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// schedulerHolder identifies this instance as a holder of the scheduler leadership.
+func schedulerHolder() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%08x", host, os.Getpid(), rand.Uint32())
+}
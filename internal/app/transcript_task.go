@@ -0,0 +1,348 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/webitel/media-exporter/api/storage"
+	"github.com/webitel/media-exporter/internal/domain/model"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/util"
+	"github.com/webitel/media-exporter/internal/util/pdf"
+	"github.com/webitel/storage/gen/engine"
+)
+
+// phrasePageSize is the number of transcript phrases requested from the storage per page.
+const phrasePageSize = 500
+
+// recordingTranscript is a recording of a call with the phrases of its transcript.
+type recordingTranscript struct {
+	file    *storage.File
+	phrases []*storage.TranscriptPhrase
+}
+
+// HandleTranscriptTask renders the transcripts of the recordings of a call into a PDF, oldest
+// recording first. Recordings whose transcript cannot be read are skipped and reported.
+func (app *App) HandleTranscriptTask(ctx context.Context, session *model.Session, task domain.ExportTask) error {
+	historyID, err := app.taskHistoryID(task)
+	if err != nil {
+		return err
+	}
+
+	if err := SetTaskStatus(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, "processing", nil); err != nil {
+		return fmt.Errorf("failed to set processing status: %w", err)
+	}
+
+	ctx = util.ContextWithHeaders(ctx, task.Headers)
+
+	app.reportStage(ctx, task.TaskID, domain.StageSearching, 0, 0)
+	transcripts, failed, err := app.callTranscripts(ctx, task)
+	if err != nil {
+		slog.ErrorContext(ctx, "callTranscripts failed", "taskID", task.TaskID, "error", err)
+		return fmt.Errorf("transcripts failed: %w", err)
+	}
+
+	layout := app.pdfLayout(task)
+	opts := layout.document(task)
+	if opts.Encryption, err = app.pdfEncryption(task); err != nil {
+		return err
+	}
+	signed := task.Pdf != nil && task.Pdf.SignedManifest
+	if signed && app.Signer == nil {
+		return fmt.Errorf("export %s asks for a signed manifest but no signing key is configured", task.TaskID)
+	}
+
+	window := windowOf(task)
+	paragraphs := layout.transcriptParagraphs(transcripts, window)
+	res, digest, err := app.streamPDF(ctx, session, task, opts, func(doc *pdf.Document) error {
+		for i, p := range paragraphs {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := doc.AddParagraph(p); err != nil {
+				return err
+			}
+			app.reportStage(ctx, task.TaskID, domain.StageRendering, int64(i+1), int64(len(paragraphs)))
+		}
+		if layout.coverPage {
+			doc.SetCover(layout.transcriptCover(task, transcripts, len(failed), window))
+		}
+		if signed {
+			return app.attachManifest(doc, transcriptManifest(task, transcripts, failed))
+		}
+		return nil
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "streamPDF failed", "taskID", task.TaskID, "error", err)
+		return err
+	}
+
+	if err := SetTaskDone(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, res, app.signDigest(task, digest)); err != nil {
+		slog.ErrorContext(ctx, "failed to set done status", "taskID", task.TaskID, "error", err)
+		return fmt.Errorf("failed to set done status: %w", err)
+	}
+
+	_ = app.Cache.ClearExportTask(task.TaskID)
+
+	slog.InfoContext(ctx, "transcript task completed successfully",
+		"taskID", task.TaskID,
+		"fileID", res.FileId,
+		"recordings", len(transcripts),
+		"skipped", len(failed),
+	)
+
+	return nil
+}
+
+// callTranscripts finds the recordings of the call of the task and reads their transcripts.
+// The recordings are returned oldest first, with those whose transcript failed to be read.
+func (app *App) callTranscripts(ctx context.Context, task domain.ExportTask) ([]recordingTranscript, []domain.ManifestSkippedFile, error) {
+	files, err := app.callFiles(ctx, task)
+	if err != nil {
+		return nil, nil, err
+	}
+	var recordings []*storage.File
+	for _, f := range files {
+		if isRecording(f) {
+			recordings = append(recordings, f)
+		}
+	}
+	if len(recordings) == 0 {
+		return nil, nil, fmt.Errorf("no recordings found for task %s", task.TaskID)
+	}
+
+	transcripts, failed, err := app.readTranscripts(ctx, task, recordings)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(transcripts) == 0 {
+		return nil, nil, fmt.Errorf("no transcript could be read for task %s", task.TaskID)
+	}
+	return transcripts, failed, nil
+}
+
+// callFiles lists the files of the call of the task, oldest first.
+func (app *App) callFiles(ctx context.Context, task domain.ExportTask) ([]*storage.File, error) {
+	return app.searchCallFiles(ctx, task, task.CallID)
+}
+
+// searchCallFiles lists the files of the call in the range and among the files of the task,
+// oldest first. Only files uploaded through the channels are listed, unless none is given.
+func (app *App) searchCallFiles(ctx context.Context, task domain.ExportTask, callID string, channels ...storage.UploadFileChannel) ([]*storage.File, error) {
+	pages := newFileIterator(func(ctx context.Context, page, size int32) (*storage.ListFile, error) {
+		return app.StorageClient.SearchFilesByCall(ctx, &storage.SearchFilesByCallRequest{
+			CallId:     callID,
+			Id:         task.IDs,
+			UploadedAt: &engine.FilterBetween{From: task.From, To: task.To},
+			Channel:    channels,
+			Page:       page,
+			Size:       size,
+		})
+	}, searchPageSize, app.maxExportFiles())
+
+	var files []*storage.File
+	for pages.Next(ctx) {
+		files = append(files, pages.Page()...)
+	}
+	if err := pages.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].GetUploadedAt() < files[j].GetUploadedAt()
+	})
+	return files, nil
+}
+
+// readTranscripts reads the transcripts of the recordings. Recordings whose transcript
+// cannot be read are returned as skipped.
+func (app *App) readTranscripts(ctx context.Context, task domain.ExportTask, recordings []*storage.File) ([]recordingTranscript, []domain.ManifestSkippedFile, error) {
+	progress := newStageCounter(int64(len(recordings)), func(current, total int64) {
+		app.reportStage(ctx, task.TaskID, domain.StageDownloading, current, total)
+	})
+	var transcripts []recordingTranscript
+	var failed []domain.ManifestSkippedFile
+	for _, f := range recordings {
+		phrases, err := app.transcriptPhrases(ctx, f.GetId())
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			slog.WarnContext(ctx, "skip recording without readable transcript", "taskID", task.TaskID, "file_id", f.GetId(), "error", err)
+			failed = append(failed, domain.ManifestSkippedFile{ID: f.GetId(), Name: f.GetName(), Reason: err.Error()})
+		} else {
+			transcripts = append(transcripts, recordingTranscript{file: f, phrases: phrases})
+		}
+		progress.Inc()
+	}
+	return transcripts, failed, nil
+}
+
+// transcriptPhrases reads every page of the transcript of a recording, ordered by start offset.
+func (app *App) transcriptPhrases(ctx context.Context, fileID int64) ([]*storage.TranscriptPhrase, error) {
+	var phrases []*storage.TranscriptPhrase
+	for page := int32(1); ; page++ {
+		resp, err := app.TranscriptClient.GetFileTranscriptPhrases(ctx, &storage.GetFileTranscriptPhrasesRequest{
+			Id:   fileID,
+			Page: page,
+			Size: phrasePageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("transcript page %d: %w", page, err)
+		}
+		phrases = append(phrases, resp.GetItems()...)
+		if !resp.GetNext() {
+			sort.SliceStable(phrases, func(i, j int) bool {
+				return phrases[i].GetStartSec() < phrases[j].GetStartSec()
+			})
+			return phrases, nil
+		}
+	}
+}
+
+// isRecording tells whether a file of a call is a recording of it rather than, e.g., a screenshot
+// or a screen recording of the agent.
+func isRecording(f *storage.File) bool {
+	if f.GetChannel() == storage.UploadFileChannel_ScreenRecordingChannel {
+		return false
+	}
+	mime := f.GetMimeType()
+	return strings.HasPrefix(mime, "audio/") || strings.HasPrefix(mime, "video/")
+}
+
+// phraseWindow is the part of every recording whose phrases are exported, in seconds from its start.
+type phraseWindow struct {
+	from float64
+	// to is 0 if the window is not limited.
+	to float64
+}
+
+func windowOf(task domain.ExportTask) phraseWindow {
+	if task.Transcript == nil {
+		return phraseWindow{}
+	}
+	return phraseWindow{
+		from: float64(task.Transcript.WindowFromMs) / 1000,
+		to:   float64(task.Transcript.WindowToMs) / 1000,
+	}
+}
+
+// contains tells whether the phrase overlaps the window.
+func (w phraseWindow) contains(p *storage.TranscriptPhrase) bool {
+	start, end := float64(p.GetStartSec()), float64(p.GetEndSec())
+	return end >= w.from && (w.to == 0 || start < w.to)
+}
+
+// transcriptParagraphs lays out the transcripts: a heading for every recording, followed by
+// its phrases in the window labeled with their offsets and speaker channel.
+func (l pdfLayout) transcriptParagraphs(transcripts []recordingTranscript, window phraseWindow) []pdf.Paragraph {
+	var paragraphs []pdf.Paragraph
+	for _, t := range transcripts {
+		title := recordingTitle(t.file)
+		paragraphs = append(paragraphs,
+			pdf.Paragraph{Heading: true, Text: title, Section: title},
+			pdf.Paragraph{Text: l.recordingDetails(t.file), Section: title},
+		)
+		n := 0
+		for _, p := range t.phrases {
+			if !window.contains(p) {
+				continue
+			}
+			paragraphs = append(paragraphs, pdf.Paragraph{
+				Label:   fmt.Sprintf("%s – %s · Channel %d", formatOffset(p.GetStartSec()), formatOffset(p.GetEndSec()), p.GetChannel()),
+				Text:    p.GetPhrase(),
+				Section: title,
+			})
+			n++
+		}
+		if n == 0 {
+			paragraphs = append(paragraphs, pdf.Paragraph{Text: "No phrases in the exported window.", Section: title})
+		}
+	}
+	return paragraphs
+}
+
+// recordingTitle names a recording in headings and bookmarks.
+func recordingTitle(f *storage.File) string {
+	if f.GetName() == "" {
+		return fmt.Sprintf("Recording %d", f.GetId())
+	}
+	return "Recording " + f.GetName()
+}
+
+// recordingDetails identifies the storage file of a recording and tells when it was uploaded.
+func (l pdfLayout) recordingDetails(f *storage.File) string {
+	details := "File ID " + strconv.FormatInt(f.GetId(), 10)
+	if t := captureTime(f); !t.IsZero() {
+		details += " · Uploaded " + t.In(l.loc).Format(captionTimeFormat)
+	}
+	if f.GetSha256Sum() != "" {
+		details += " · SHA-256 " + f.GetSha256Sum()
+	}
+	return details
+}
+
+// formatOffset prints an offset into a recording as m:ss, or h:mm:ss from an hour on.
+func formatOffset(sec float32) string {
+	s := int(sec)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// transcriptCover describes a transcript export on the cover page.
+func (l pdfLayout) transcriptCover(task domain.ExportTask, transcripts []recordingTranscript, skipped int, window phraseWindow) *pdf.Cover {
+	phrases := 0
+	for _, t := range transcripts {
+		for _, p := range t.phrases {
+			if window.contains(p) {
+				phrases++
+			}
+		}
+	}
+	fields := append(l.coverFields(task),
+		pdf.CoverField{Label: "Recordings included", Value: strconv.Itoa(len(transcripts))},
+		pdf.CoverField{Label: "Recordings skipped", Value: strconv.Itoa(skipped)},
+		pdf.CoverField{Label: "Phrases", Value: strconv.Itoa(phrases)},
+	)
+	if window != (phraseWindow{}) {
+		end := "end"
+		if window.to != 0 {
+			end = formatOffset(float32(window.to))
+		}
+		fields = append(fields, pdf.CoverField{Label: "Window", Value: formatOffset(float32(window.from)) + " – " + end})
+	}
+	fields = append(fields, pdf.CoverField{Label: "Export ID", Value: task.TaskID})
+	return &pdf.Cover{Title: l.documentTitle(task), Fields: fields}
+}
+
+// transcriptManifest describes the recordings whose transcripts a transcript export contains.
+func transcriptManifest(task domain.ExportTask, transcripts []recordingTranscript, failed []domain.ManifestSkippedFile) *domain.ExportManifest {
+	manifest := &domain.ExportManifest{
+		TaskID:    task.TaskID,
+		TraceHash: task.TraceHash,
+		CallID:    task.CallID,
+		Channel:   task.Channel,
+		From:      task.From,
+		To:        task.To,
+		CreatedAt: task.CreatedAt,
+		CreatedBy: task.UserID,
+		Files:     make([]domain.ManifestFile, 0, len(transcripts)),
+		Skipped:   failed,
+	}
+	for _, t := range transcripts {
+		manifest.Files = append(manifest.Files, domain.ManifestFile{
+			ID:         t.file.GetId(),
+			Name:       t.file.GetName(),
+			MimeType:   t.file.GetMimeType(),
+			Size:       t.file.GetSize(),
+			Sha256:     t.file.GetSha256Sum(),
+			UploadedAt: t.file.GetUploadedAt(),
+		})
+	}
+	return manifest
+}
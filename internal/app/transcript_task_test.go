@@ -0,0 +1,51 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/webitel/media-exporter/api/storage"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+)
+
+func TestTranscriptParagraphs(t *testing.T) {
+	transcripts := []recordingTranscript{
+		{
+			file: &storage.File{Id: 7, Name: "call.mp3"},
+			phrases: []*storage.TranscriptPhrase{
+				{StartSec: 1, EndSec: 4.5, Channel: 0, Phrase: "Hello"},
+				{StartSec: 65, EndSec: 70, Channel: 1, Phrase: "I have a complaint"},
+				{StartSec: 3700, EndSec: 3702, Channel: 0, Phrase: "Goodbye"},
+			},
+		},
+		{file: &storage.File{Id: 8}},
+	}
+	layout := (&App{}).pdfLayout(domain.ExportTask{})
+
+	var got []string
+	for _, p := range layout.transcriptParagraphs(transcripts, phraseWindow{from: 4, to: 3600}) {
+		switch {
+		case p.Heading:
+			got = append(got, "# "+p.Text)
+		case p.Label != "":
+			got = append(got, p.Label+" "+p.Text)
+		}
+	}
+	want := []string{
+		"# Recording call.mp3",
+		"0:01 – 0:04 · Channel 0 Hello",
+		"1:05 – 1:10 · Channel 1 I have a complaint",
+		"# Recording 8",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("paragraphs = %q, want %q", got, want)
+	}
+
+	all := layout.transcriptParagraphs(transcripts, phraseWindow{})
+	if last := all[len(all)-1]; last.Label != "" || last.Section != "Recording 8" {
+		t.Errorf("recording without phrases ends with %+v, want a note in its section", last)
+	}
+	if label := all[4].Label; label != "1:01:40 – 1:01:42 · Channel 0" {
+		t.Errorf("label past an hour = %q", label)
+	}
+}
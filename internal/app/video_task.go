@@ -0,0 +1,144 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/webitel/media-exporter/api/storage"
+	"github.com/webitel/media-exporter/internal/domain/model"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/util"
+	"github.com/webitel/media-exporter/internal/util/video"
+)
+
+const (
+	defaultFrameDuration = time.Second
+	defaultVideoWidth    = 1920
+	defaultVideoHeight   = 1080
+)
+
+// HandleVideoTask encodes the screenshots of the task into a time-lapse video, oldest first
+// unless the task chooses another order.
+func (app *App) HandleVideoTask(ctx context.Context, session *model.Session, task domain.ExportTask) error {
+	historyID, err := app.taskHistoryID(task)
+	if err != nil {
+		return err
+	}
+
+	if err := SetTaskStatus(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, "processing", nil); err != nil {
+		return fmt.Errorf("failed to set processing status: %w", err)
+	}
+
+	ctx = util.ContextWithHeaders(ctx, task.Headers)
+
+	app.reportStage(ctx, task.TaskID, domain.StageSearching, 0, 0)
+	pages, err := app.screenshotPages(task)
+	if err != nil {
+		return err
+	}
+
+	// Frames are scaled by the encoder, so the screenshots are kept in their original size.
+	screenshots, err := downloadScreenshots(ctx, session, app, task, pages, 0)
+	if err != nil {
+		slog.ErrorContext(ctx, "downloadScreenshots failed", "taskID", task.TaskID, "error", err)
+		return fmt.Errorf("download failed: %w", err)
+	}
+	defer screenshots.Cleanup()
+
+	opts := app.videoOptions(task)
+	tempFilePath, err := app.exportTempFile(task.Type, task.Type)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tempFilePath) }()
+
+	order := newScreenshotOrder(task, domain.SortUploadedAsc, domain.GroupByNone, time.UTC)
+	frames, err := encodeTimelapse(ctx, app.VideoEncoder, order, screenshots.Paths, screenshots.Infos, tempFilePath, opts, func(done, total int) {
+		app.reportStage(ctx, task.TaskID, domain.StageRendering, int64(done), int64(total))
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "video encoding failed", "taskID", task.TaskID, "error", err)
+		return fmt.Errorf("video encoding failed: %w", err)
+	}
+
+	res, err := uploadFileToStorage(ctx, session, app, tempFilePath, task, uploadName(task, task.Type), opts.Format.MimeType())
+	if err != nil {
+		slog.ErrorContext(ctx, "uploadFileToStorage failed", "taskID", task.TaskID, "error", err)
+		return fmt.Errorf("upload failed: %w", err)
+	}
+
+	if err := SetTaskDone(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, res, nil); err != nil {
+		slog.ErrorContext(ctx, "failed to set done status", "taskID", task.TaskID, "error", err)
+		return fmt.Errorf("failed to set done status: %w", err)
+	}
+
+	_ = app.Cache.ClearExportTask(task.TaskID)
+
+	slog.InfoContext(ctx, "video task completed successfully", "taskID", task.TaskID, "fileID", res.FileId, "frames", frames)
+
+	return nil
+}
+
+// encodeTimelapse encodes the downloaded screenshots into a video at outPath and returns the number of frames.
+func encodeTimelapse(
+	ctx context.Context,
+	encoder video.Encoder,
+	order screenshotOrder,
+	files map[string]string,
+	fileInfos map[string]*storage.File,
+	outPath string,
+	opts video.Options,
+	onFrame func(done, total int),
+) (int, error) {
+	frames := timelapseFrames(order, files, fileInfos)
+	if len(frames) == 0 {
+		return 0, fmt.Errorf("no valid images found for video")
+	}
+	if err := encoder.Encode(ctx, frames, outPath, opts, onFrame); err != nil {
+		return 0, err
+	}
+	return len(frames), nil
+}
+
+// timelapseFrames returns the downloaded screenshots as frames in order.
+func timelapseFrames(order screenshotOrder, files map[string]string, fileInfos map[string]*storage.File) []video.Frame {
+	shots := order.downloaded(files, fileInfos)
+	frames := make([]video.Frame, len(shots))
+	for i, shot := range shots {
+		frames[i] = video.Frame{Path: shot.path, Time: shot.time}
+	}
+	return frames
+}
+
+// videoOptions combines the options of the task with the configured defaults.
+func (app *App) videoOptions(task domain.ExportTask) video.Options {
+	opts := video.Options{
+		Format:        video.Format(task.Type),
+		FrameDuration: defaultFrameDuration,
+		Width:         defaultVideoWidth,
+		Height:        defaultVideoHeight,
+	}
+
+	if cfg := app.Config.Video; cfg != nil {
+		if cfg.FrameDuration > 0 {
+			opts.FrameDuration = cfg.FrameDuration
+		}
+		if cfg.Width > 0 && cfg.Height > 0 {
+			// yuv420p requires even dimensions.
+			opts.Width, opts.Height = cfg.Width&^1, cfg.Height&^1
+		}
+		opts.FontFile = cfg.FontFile
+	}
+
+	if task.Video != nil {
+		if task.Video.FrameDurationMs > 0 {
+			opts.FrameDuration = time.Duration(task.Video.FrameDurationMs) * time.Millisecond
+		}
+		opts.Timestamps = task.Video.TimestampOverlay
+	}
+
+	return opts
+}
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/webitel/media-exporter/api/storage"
+	cfg "github.com/webitel/media-exporter/config"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/util/video"
+)
+
+// fakeEncoder records what it was asked to encode instead of running ffmpeg.
+type fakeEncoder struct {
+	frames  []video.Frame
+	outPath string
+	opts    video.Options
+	err     error
+}
+
+func (f *fakeEncoder) Encode(_ context.Context, frames []video.Frame, outPath string, opts video.Options, onFrame func(done, total int)) error {
+	f.frames, f.outPath, f.opts = frames, outPath, opts
+	if f.err != nil {
+		return f.err
+	}
+	for i := range frames {
+		onFrame(i+1, len(frames))
+	}
+	return nil
+}
+
+var oldestFirst = newScreenshotOrder(domain.ExportTask{}, domain.SortUploadedAsc, domain.GroupByNone, time.UTC)
+
+func TestEncodeTimelapseOrdersFramesByUploadTime(t *testing.T) {
+	files := map[string]string{
+		"1": "/tmp/1.png",
+		"2": "/tmp/2.png",
+		"3": "/tmp/3.png",
+		"4": "/tmp/4.png",
+		"5": "", // not an image, skipped by the download
+	}
+	infos := map[string]*storage.File{
+		"1": {Id: 1, UploadedAt: 1_700_000_300_000}, // milliseconds
+		"2": {Id: 2, UploadedAt: 1_700_000_100},     // seconds
+		"3": {Id: 3},                                // unknown upload time
+		"4": {Id: 4, UploadedAt: 1_700_000_200_000},
+		"5": {Id: 5, UploadedAt: 1_700_000_000_000},
+	}
+	opts := video.Options{Format: video.WebM, FrameDuration: 500 * time.Millisecond, Timestamps: true}
+
+	encoder := &fakeEncoder{}
+	var progress []int
+	n, err := encodeTimelapse(context.Background(), encoder, oldestFirst, files, infos, "/tmp/out.webm", opts, func(done, total int) {
+		progress = append(progress, done)
+	})
+	if err != nil {
+		t.Fatalf("encodeTimelapse() error = %v", err)
+	}
+
+	wantOrder := []string{"/tmp/2.png", "/tmp/4.png", "/tmp/1.png", "/tmp/3.png"}
+	if n != len(wantOrder) || len(encoder.frames) != len(wantOrder) {
+		t.Fatalf("encoded %d frames (%d reported), want %d", len(encoder.frames), n, len(wantOrder))
+	}
+	for i, want := range wantOrder {
+		if got := encoder.frames[i].Path; got != want {
+			t.Errorf("frame %d = %s, want %s", i, got, want)
+		}
+	}
+	if !encoder.frames[0].Time.Equal(time.Unix(1_700_000_100, 0)) {
+		t.Errorf("frame time = %v, want the upload time of the file", encoder.frames[0].Time)
+	}
+	if !encoder.frames[3].Time.IsZero() {
+		t.Errorf("frame without upload time got time %v", encoder.frames[3].Time)
+	}
+	if encoder.outPath != "/tmp/out.webm" || encoder.opts != opts {
+		t.Errorf("encoder got outPath %q and options %+v", encoder.outPath, encoder.opts)
+	}
+	if len(progress) != len(wantOrder) {
+		t.Errorf("progress reported %d times, want %d", len(progress), len(wantOrder))
+	}
+}
+
+func TestEncodeTimelapseFailures(t *testing.T) {
+	if _, err := encodeTimelapse(context.Background(), &fakeEncoder{}, oldestFirst, map[string]string{"1": ""}, nil, "/tmp/out.mp4", video.Options{}, nil); err == nil {
+		t.Errorf("expected an error when there is nothing to encode")
+	}
+
+	encodeErr := errors.New("ffmpeg exited with 1")
+	_, err := encodeTimelapse(context.Background(), &fakeEncoder{err: encodeErr}, oldestFirst, map[string]string{"1": "/tmp/1.png"}, nil, "/tmp/out.mp4", video.Options{}, nil)
+	if !errors.Is(err, encodeErr) {
+		t.Errorf("error = %v, want the encoder error", err)
+	}
+}
+
+func TestVideoOptions(t *testing.T) {
+	app := &App{Config: &cfg.AppConfig{Video: &cfg.VideoConfig{
+		FrameDuration: 2 * time.Second,
+		Width:         1281,
+		Height:        721,
+		FontFile:      "/usr/share/fonts/DejaVuSans.ttf",
+	}}}
+
+	opts := app.videoOptions(domain.ExportTask{Type: domain.Mp4ExportType})
+	want := video.Options{
+		Format:        video.MP4,
+		FrameDuration: 2 * time.Second,
+		Width:         1280,
+		Height:        720,
+		FontFile:      "/usr/share/fonts/DejaVuSans.ttf",
+	}
+	if opts != want {
+		t.Errorf("videoOptions() = %+v, want %+v", opts, want)
+	}
+
+	opts = app.videoOptions(domain.ExportTask{
+		Type:  domain.WebmExportType,
+		Video: &domain.VideoOptions{FrameDurationMs: 250, TimestampOverlay: true},
+	})
+	if opts.Format != video.WebM || opts.FrameDuration != 250*time.Millisecond || !opts.Timestamps {
+		t.Errorf("videoOptions() ignores the task options: %+v", opts)
+	}
+}
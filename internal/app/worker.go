@@ -6,17 +6,36 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/webitel/media-exporter/internal/cache"
 	"github.com/webitel/media-exporter/internal/domain/model"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/errors"
 )
 
 const (
-	PdfExportType       = "pdf"
-	ZipExportType       = "zip"
-	authorizationHeader = "x-webitel-access"
+	PdfExportType        = "pdf"
+	ZipExportType        = "zip"
+	Mp4ExportType        = "mp4"
+	WebmExportType       = "webm"
+	TranscriptExportType = "transcript"
+	DossierExportType    = "dossier"
+	AudioZipExportType   = "audio_zip"
+	Mp3ExportType        = "mp3"
+	WavExportType        = "wav"
+	OpusExportType       = "opus"
+	BatchExportType      = "batch"
+	authorizationHeader  = "x-webitel-access"
+
+	defaultLeaseTimeout = time.Minute
+	queuePollInterval   = time.Second
+	retryPollInterval   = time.Second
 )
 
 // StartExportWorker launches background workers to process export tasks concurrently.
 // If too many workers are configured, the number is automatically limited based on available CPU cores.
+// Every task is leased while it is processed: the worker renews the lease until the task is done
+// and a reaper returns tasks of crashed workers to the queue once their lease expires.
+// Failed tasks are retried with exponential backoff, see handleTaskFailure.
 func (app *App) StartExportWorker(ctx context.Context) {
 	numWorkers := app.Config.Export.Workers
 	if numWorkers <= 0 {
@@ -28,7 +47,12 @@ func (app *App) StartExportWorker(ctx context.Context) {
 		numWorkers = maxWorkers
 	}
 
-	slog.InfoContext(ctx, "starting export workers", "count", numWorkers)
+	lease := app.leaseTimeout()
+
+	slog.InfoContext(ctx, "starting export workers", "count", numWorkers, "lease", lease)
+
+	go app.runLeaseReaper(ctx, lease)
+	go app.runRetryScheduler(ctx)
 
 	for i := 0; i < numWorkers; i++ {
 		go func(workerID int) {
@@ -37,36 +61,169 @@ func (app *App) StartExportWorker(ctx context.Context) {
 				case <-ctx.Done():
 					return
 				default:
-					task, err := app.Cache.PopExportTask()
-					if err != nil {
-						time.Sleep(time.Second)
+					task, err := app.Cache.PopExportTask(ctx, lease)
+					if errors.Is(err, cache.ErrQueueEmpty) {
 						continue
 					}
-
-					session, err := model.NewSession(task.UserID, task.DomainID, task.Headers[authorizationHeader])
 					if err != nil {
-
-						_ = app.Cache.ClearExportTask(task.TaskID)
+						slog.ErrorContext(ctx, "failed to pop export task", "workerID", workerID, "error", err)
+						time.Sleep(queuePollInterval)
 						continue
 					}
 
-					switch task.Type {
-
-					case PdfExportType:
-						if err := app.HandlePdfTask(ctx, session, task); err != nil {
-							slog.ErrorContext(ctx, "PDF task failed", "taskID", task.TaskID, "error", err)
-							_ = app.Cache.ClearExportTask(task.TaskID)
-						}
-					case ZipExportType:
-						panic("not implemented")
-					default:
-						slog.WarnContext(ctx, "unknown export type",
-							"workerID", workerID,
-							"type", task.Type,
-							"taskID", task.TaskID)
-					}
+					app.processTask(ctx, workerID, task, lease)
 				}
 			}
 		}(i + 1)
 	}
 }
+
+// processTask runs a leased task and acknowledges it once the worker is done with it.
+func (app *App) processTask(ctx context.Context, workerID int, task domain.ExportTask, lease time.Duration) {
+	taskCtx, cancel := context.WithCancelCause(ctx)
+	stopHeartbeat := app.keepLease(ctx, task.TaskID, lease, cancel)
+	stopCancelWatch := app.watchCancel(taskCtx, task.TaskID, cancel)
+	defer func() {
+		stopCancelWatch()
+		stopHeartbeat()
+		lost := errors.Is(context.Cause(taskCtx), errLeaseLost)
+		cancel(nil)
+		if lost {
+			// The task is back in the queue and may be leased to another worker already,
+			// whose lease an ack would remove.
+			return
+		}
+		if err := app.Cache.Ack(task.TaskID); err != nil {
+			slog.ErrorContext(ctx, "failed to ack export task", "taskID", task.TaskID, "error", err)
+		}
+	}()
+
+	session, err := model.NewSession(task.UserID, task.DomainID, task.Headers[authorizationHeader])
+	if err != nil {
+		_ = app.Cache.ClearExportTask(task.TaskID)
+		return
+	}
+
+	if app.cancelRequested(ctx, task.TaskID) {
+		app.finishCancelled(ctx, task)
+		return
+	}
+
+	var handle func(context.Context, *model.Session, domain.ExportTask) error
+	switch task.Type {
+	case PdfExportType:
+		handle = app.HandlePdfTask
+	case ZipExportType:
+		handle = app.HandleZipTask
+	case Mp4ExportType, WebmExportType:
+		handle = app.HandleVideoTask
+	case TranscriptExportType:
+		handle = app.HandleTranscriptTask
+	case DossierExportType:
+		handle = app.HandleDossierTask
+	case AudioZipExportType, Mp3ExportType, WavExportType, OpusExportType:
+		handle = app.HandleAudioTask
+	case BatchExportType:
+		handle = app.HandleBatchTask
+	default:
+		slog.WarnContext(ctx, "unknown export type",
+			"workerID", workerID,
+			"type", task.Type,
+			"taskID", task.TaskID)
+		_ = app.Cache.ClearExportTask(task.TaskID)
+		return
+	}
+
+	err = handle(taskCtx, session, task)
+	switch {
+	case err == nil:
+	case errors.Is(context.Cause(taskCtx), errExportCancelled):
+		app.finishCancelled(ctx, task)
+	case errors.Is(context.Cause(taskCtx), errLeaseLost):
+		// The worker that takes the task over reports its outcome.
+		slog.WarnContext(ctx, "export task aborted after its lease was lost", "workerID", workerID, "taskID", task.TaskID)
+	case ctx.Err() != nil:
+		// The worker is shutting down: give the task back to the queue.
+		if err := app.Cache.Nack(task.TaskID); err != nil {
+			slog.ErrorContext(ctx, "failed to return export task to queue", "taskID", task.TaskID, "error", err)
+		}
+	default:
+		app.handleTaskFailure(ctx, task, err)
+	}
+}
+
+// errLeaseLost is the cancellation cause of a task context whose lease could not be renewed
+// because the task went back to the queue.
+var errLeaseLost = errors.New("export task lease lost")
+
+// keepLease renews the lease of a task in the background until the returned stop function is called.
+// Once the lease turns out to be lost, the task is aborted with cancel, so that it does not run
+// next to the worker that takes it over.
+func (app *App) keepLease(ctx context.Context, taskID string, lease time.Duration, cancel context.CancelCauseFunc) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				err := app.Cache.ExtendLease(taskID, lease)
+				if errors.Is(err, cache.ErrLeaseLost) {
+					slog.WarnContext(ctx, "task lease lost, aborting the task", "taskID", taskID)
+					cancel(errLeaseLost)
+					return
+				}
+				if err != nil {
+					slog.WarnContext(ctx, "failed to extend task lease", "taskID", taskID, "error", err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// runLeaseReaper periodically returns tasks with an expired lease to the queue.
+func (app *App) runLeaseReaper(ctx context.Context, lease time.Duration) {
+	ticker := time.NewTicker(lease / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := app.Cache.RequeueExpired()
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to requeue expired tasks", "error", err)
+				continue
+			}
+			if count > 0 {
+				slog.WarnContext(ctx, "requeued export tasks with expired lease", "count", count)
+			}
+		}
+	}
+}
+
+// runRetryScheduler periodically moves tasks whose retry delay has passed back to the queue.
+func (app *App) runRetryScheduler(ctx context.Context) {
+	ticker := time.NewTicker(retryPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := app.Cache.PromoteDelayed(); err != nil {
+				slog.ErrorContext(ctx, "failed to promote delayed tasks", "error", err)
+			}
+		}
+	}
+}
+
+func (app *App) leaseTimeout() time.Duration {
+	if app.Config.Export.LeaseTimeout <= 0 {
+		return defaultLeaseTimeout
+	}
+	return app.Config.Export.LeaseTimeout
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+)
+
+func TestKeepLease(t *testing.T) {
+	const lease = 60 * time.Millisecond
+
+	t.Run("a leased task keeps running", func(t *testing.T) {
+		app := newCancelApp(t)
+		if err := app.Cache.PushExportTask(domain.ExportTask{TaskID: "task.pdf"}); err != nil {
+			t.Fatalf("PushExportTask() error = %v", err)
+		}
+		if _, err := app.Cache.PopExportTask(context.Background(), lease); err != nil {
+			t.Fatalf("PopExportTask() error = %v", err)
+		}
+		ctx, cancel := context.WithCancelCause(context.Background())
+		defer cancel(nil)
+
+		stop := app.keepLease(ctx, "task.pdf", lease, cancel)
+		defer stop()
+		select {
+		case <-ctx.Done():
+			t.Fatalf("task aborted while its lease is renewed: %v", context.Cause(ctx))
+		case <-time.After(3 * lease):
+		}
+	})
+
+	t.Run("a lost lease aborts the task", func(t *testing.T) {
+		app := newCancelApp(t)
+		ctx, cancel := context.WithCancelCause(context.Background())
+		defer cancel(nil)
+
+		stop := app.keepLease(ctx, "task.pdf", lease, cancel)
+		defer stop()
+		select {
+		case <-ctx.Done():
+		case <-time.After(3 * lease):
+			t.Fatal("task was not aborted after its lease was lost")
+		}
+		if cause := context.Cause(ctx); cause != errLeaseLost {
+			t.Errorf("cancellation cause = %v, want %v", cause, errLeaseLost)
+		}
+	})
+}
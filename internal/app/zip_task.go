@@ -0,0 +1,291 @@
+package app
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/webitel/media-exporter/api/storage"
+	"github.com/webitel/media-exporter/internal/domain/model"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/util"
+)
+
+const zipManifestName = "manifest.json"
+
+func (app *App) HandleZipTask(ctx context.Context, session *model.Session, task domain.ExportTask) error {
+	historyID, err := app.taskHistoryID(task)
+	if err != nil {
+		return err
+	}
+
+	if err := SetTaskStatus(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, "processing", nil); err != nil {
+		return fmt.Errorf("failed to set processing status: %w", err)
+	}
+
+	ctx = util.ContextWithHeaders(ctx, task.Headers)
+
+	app.reportStage(ctx, task.TaskID, domain.StageSearching, 0, 0)
+	pages, err := app.screenshotPages(task)
+	if err != nil {
+		return err
+	}
+
+	tempFilePath, err := app.exportTempFile(ZipExportType, ZipExportType)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tempFilePath) }()
+
+	progress := newStageCounter(0, func(current, total int64) {
+		app.reportStage(ctx, task.TaskID, domain.StageDownloading, current, total)
+	})
+	manifest, err := writeZipArchive(ctx, app.StorageClient, session, task, pages, tempFilePath, progress)
+	if err != nil {
+		slog.ErrorContext(ctx, "writeZipArchive failed", "taskID", task.TaskID, "error", err)
+		return fmt.Errorf("ZIP generation failed: %w", err)
+	}
+
+	res, err := uploadFileToStorage(ctx, session, app, tempFilePath, task, uploadName(task, ZipExportType), "application/zip")
+	if err != nil {
+		slog.ErrorContext(ctx, "uploadFileToStorage failed", "taskID", task.TaskID, "error", err)
+		return fmt.Errorf("upload failed: %w", err)
+	}
+
+	if err := SetTaskDone(app, taskUpdateOptions(ctx, task), historyID, task.TaskID, res, nil); err != nil {
+		slog.ErrorContext(ctx, "failed to set done status", "taskID", task.TaskID, "error", err)
+		return fmt.Errorf("failed to set done status: %w", err)
+	}
+
+	_ = app.Cache.ClearExportTask(task.TaskID)
+
+	slog.InfoContext(ctx, "ZIP task completed successfully",
+		"taskID", task.TaskID,
+		"fileID", res.FileId,
+		"files", len(manifest.Files),
+		"skipped", len(manifest.Skipped),
+	)
+
+	return nil
+}
+
+// writeZipArchive lists the files of the search, orders them as the task asks, newest first by
+// default, and downloads the originals one by one straight into a ZIP archive at zipPath. Grouped
+// exports put every group into a folder of its own. A manifest describing what was included and
+// what was skipped follows the files.
+func writeZipArchive(
+	ctx context.Context,
+	client storage.FileServiceClient,
+	session *model.Session,
+	task domain.ExportTask,
+	pages *fileIterator,
+	zipPath string,
+	progress *stageCounter,
+) (*domain.ExportManifest, error) {
+	pages.onPage = func(files []*storage.File) {
+		progress.Grow(int64(len(files)))
+	}
+	files, err := pages.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if pages.Count() == 0 {
+		return nil, fmt.Errorf("no files found for task %s", task.TaskID)
+	}
+
+	order := newScreenshotOrder(task, domain.SortUploadedDesc, domain.GroupByNone, time.UTC)
+	return writeZipFiles(ctx, client, session, task, order.listed(files), nil, zipPath, progress)
+}
+
+// writeZipFiles downloads the files in order into a ZIP archive at zipPath, followed by the manifest.
+// skipped are files known to be missing before the download, reported in the manifest.
+func writeZipFiles(
+	ctx context.Context,
+	client storage.FileServiceClient,
+	session *model.Session,
+	task domain.ExportTask,
+	files []screenshot,
+	skipped []domain.ManifestSkippedFile,
+	zipPath string,
+	progress *stageCounter,
+) (*domain.ExportManifest, error) {
+	out, err := os.Create(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("create zip file: %w", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	zw := zip.NewWriter(out)
+
+	manifest := &domain.ExportManifest{
+		TaskID:    task.TaskID,
+		AgentID:   task.AgentID,
+		CallID:    task.CallID,
+		Channel:   task.Channel,
+		From:      task.From,
+		To:        task.To,
+		CreatedAt: time.Now().UnixMilli(),
+		CreatedBy: session.UserID(),
+		Files:     []domain.ManifestFile{},
+		Skipped:   skipped,
+	}
+
+	for _, shot := range files {
+		if err := addZipFile(ctx, client, session, task, zw, manifest, shot); err != nil {
+			return nil, err
+		}
+		progress.Inc()
+	}
+
+	if len(manifest.Files) == 0 {
+		return nil, fmt.Errorf("no files could be downloaded for task %s", task.TaskID)
+	}
+
+	w, err := zw.Create(zipManifestName)
+	if err != nil {
+		return nil, fmt.Errorf("create manifest entry: %w", err)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return nil, fmt.Errorf("write manifest: %w", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("finalize zip: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// addZipFile downloads a single file into the archive and records it in the manifest.
+// A file that cannot be opened in storage is recorded as skipped.
+func addZipFile(
+	ctx context.Context,
+	client storage.FileServiceClient,
+	session *model.Session,
+	task domain.ExportTask,
+	zw *zip.Writer,
+	manifest *domain.ExportManifest,
+	shot screenshot,
+) error {
+	f := shot.file
+	if f.Id == 0 {
+		return nil
+	}
+
+	entry := &lazyZipEntry{
+		zw: zw,
+		header: &zip.FileHeader{
+			Name:     zipEntryName(f, shot.group),
+			Method:   zip.Store, // screenshots are already compressed
+			Modified: fileUploadedAt(f),
+		},
+	}
+	hash := sha256.New()
+	counter := &countingWriter{}
+
+	err := downloadTo(ctx, client, session.DomainID(), f.Id, io.MultiWriter(entry, hash, counter))
+	if err != nil {
+		if !entry.created() {
+			// Nothing was written yet, so the archive is still consistent.
+			slog.WarnContext(ctx, "skip file in zip export", "taskID", task.TaskID, "file_id", f.Id, "error", err)
+			manifest.Skipped = append(manifest.Skipped, domain.ManifestSkippedFile{
+				ID:     f.Id,
+				Name:   f.Name,
+				Reason: err.Error(),
+			})
+			return nil
+		}
+		return fmt.Errorf("download file %d: %w", f.Id, err)
+	}
+	if !entry.created() {
+		// Empty file: still add an entry so the manifest path resolves.
+		if _, err := entry.Write(nil); err != nil {
+			return fmt.Errorf("create zip entry for file %d: %w", f.Id, err)
+		}
+	}
+
+	manifest.Files = append(manifest.Files, domain.ManifestFile{
+		ID:         f.Id,
+		Name:       f.Name,
+		Path:       entry.header.Name,
+		MimeType:   f.MimeType,
+		Size:       counter.n,
+		Sha256:     hex.EncodeToString(hash.Sum(nil)),
+		UploadedAt: f.UploadedAt,
+		Group:      shot.group.title,
+	})
+	return nil
+}
+
+// zipEntryName returns a unique, path-safe name for a file inside the archive,
+// in the folder of its group if the export is grouped.
+func zipEntryName(f *storage.File, group screenshotGroup) string {
+	name := path.Base(strings.ReplaceAll(f.Name, "\\", "/"))
+	if name == "." || name == "/" {
+		name = ""
+	}
+	if path.Ext(name) == "" {
+		name += util.GetFileExt(f.MimeType)
+	}
+	if group.key != "" {
+		return fmt.Sprintf("files/%s/%d_%s", group.key, f.Id, name)
+	}
+	return fmt.Sprintf("files/%d_%s", f.Id, name)
+}
+
+// fileUploadedAt converts File.UploadedAt (seconds or milliseconds) to time.Time.
+func fileUploadedAt(f *storage.File) time.Time {
+	if f.UploadedAt == 0 {
+		return time.Now()
+	}
+	return storageTime(f.UploadedAt)
+}
+
+// storageTime converts a timestamp of the storage, in seconds or milliseconds, to time.Time.
+func storageTime(ts int64) time.Time {
+	if ts > 1e12 {
+		return time.UnixMilli(ts)
+	}
+	return time.Unix(ts, 0)
+}
+
+// lazyZipEntry defers creating the archive entry until the first chunk arrives,
+// so a file that cannot be opened in storage leaves no empty entry behind.
+type lazyZipEntry struct {
+	zw     *zip.Writer
+	header *zip.FileHeader
+	w      io.Writer
+}
+
+func (e *lazyZipEntry) Write(p []byte) (int, error) {
+	if e.w == nil {
+		w, err := e.zw.CreateHeader(e.header)
+		if err != nil {
+			return 0, err
+		}
+		e.w = w
+	}
+	return e.w.Write(p)
+}
+
+func (e *lazyZipEntry) created() bool { return e.w != nil }
+
+type countingWriter struct {
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
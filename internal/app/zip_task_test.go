@@ -0,0 +1,201 @@
+package app
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/webitel/media-exporter/api/storage"
+	"github.com/webitel/media-exporter/internal/domain/model"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"google.golang.org/grpc"
+)
+
+// storedFile is a file of fakeFileService: its chunks, then err if it is not nil.
+// openErr fails the download before the stream is opened.
+type storedFile struct {
+	chunks  []string
+	err     error
+	openErr error
+}
+
+// fakeFileService streams the files it holds, the other methods are not expected to be called.
+type fakeFileService struct {
+	storage.FileServiceClient
+	files map[int64]storedFile
+}
+
+func (f *fakeFileService) DownloadFile(_ context.Context, in *storage.DownloadFileRequest, _ ...grpc.CallOption) (storage.FileService_DownloadFileClient, error) {
+	file, ok := f.files[in.Id]
+	if !ok {
+		return nil, errors.New("file not found")
+	}
+	if file.openErr != nil {
+		return nil, file.openErr
+	}
+	return &fakeDownloadStream{file: file}, nil
+}
+
+type fakeDownloadStream struct {
+	grpc.ClientStream
+	file storedFile
+	sent int
+}
+
+func (s *fakeDownloadStream) Recv() (*storage.StreamFile, error) {
+	if s.sent < len(s.file.chunks) {
+		s.sent++
+		return &storage.StreamFile{Data: &storage.StreamFile_Chunk{Chunk: []byte(s.file.chunks[s.sent-1])}}, nil
+	}
+	if s.file.err != nil {
+		return nil, s.file.err
+	}
+	return nil, io.EOF
+}
+
+func zipShots(ids ...int64) []screenshot {
+	shots := make([]screenshot, len(ids))
+	for i, id := range ids {
+		shots[i] = screenshot{file: &storage.File{Id: id, Name: "shot.png", MimeType: "image/png", UploadedAt: 1_700_000_000_000}}
+	}
+	return shots
+}
+
+func TestWriteZipFiles(t *testing.T) {
+	lost := errors.New("connection reset")
+	tests := []struct {
+		name  string
+		files map[int64]storedFile
+		// entries are the IDs of the files in the archive, skipped those recorded as skipped.
+		entries []int64
+		skipped []int64
+		wantErr bool
+	}{
+		{
+			name:    "every file",
+			files:   map[int64]storedFile{1: {chunks: []string{"first ", "file"}}, 2: {chunks: []string{"second"}}},
+			entries: []int64{1, 2},
+		},
+		{
+			name: "files failing before their first chunk are skipped",
+			files: map[int64]storedFile{
+				1: {openErr: errors.New("file removed")},
+				2: {chunks: []string{"kept"}},
+				3: {err: lost},
+			},
+			entries: []int64{2},
+			skipped: []int64{1, 3},
+		},
+		{
+			name:    "a failure after a partial write fails the task",
+			files:   map[int64]storedFile{1: {chunks: []string{"kept"}}, 2: {chunks: []string{"half"}, err: lost}},
+			wantErr: true,
+		},
+		{
+			name:    "an empty file gets an entry",
+			files:   map[int64]storedFile{1: {}, 2: {chunks: []string{"file"}}},
+			entries: []int64{1, 2},
+		},
+		{
+			name:    "no file could be downloaded",
+			files:   map[int64]storedFile{1: {openErr: errors.New("file removed")}},
+			wantErr: true,
+		},
+	}
+
+	session, _ := model.NewSession(7, 1, "token")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []int64
+			for id := int64(1); id <= int64(len(tt.files)); id++ {
+				ids = append(ids, id)
+			}
+			zipPath := filepath.Join(t.TempDir(), "export.zip")
+			progress := newStageCounter(int64(len(ids)), func(int64, int64) {})
+
+			manifest, err := writeZipFiles(context.Background(), &fakeFileService{files: tt.files}, session,
+				domain.ExportTask{TaskID: "zip_ss_1.zip"}, zipShots(ids...), nil, zipPath, progress)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("writeZipFiles() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("writeZipFiles() error = %v", err)
+			}
+
+			var skipped []int64
+			for _, f := range manifest.Skipped {
+				skipped = append(skipped, f.ID)
+			}
+			if !reflect.DeepEqual(skipped, tt.skipped) {
+				t.Errorf("skipped files %v, want %v", skipped, tt.skipped)
+			}
+
+			archive := readZip(t, zipPath)
+			var entries []int64
+			for _, f := range manifest.Files {
+				entries = append(entries, f.ID)
+				content, ok := archive[f.Path]
+				if !ok {
+					t.Errorf("file %d is listed at %s, which is not in the archive", f.ID, f.Path)
+					continue
+				}
+				sum := sha256.Sum256([]byte(content))
+				want := strings.Join(tt.files[f.ID].chunks, "")
+				if content != want || f.Size != int64(len(content)) || f.Sha256 != hex.EncodeToString(sum[:]) {
+					t.Errorf("file %d: archived %q, manifest size %d and sha256 %s, want %q", f.ID, content, f.Size, f.Sha256, want)
+				}
+			}
+			if !reflect.DeepEqual(entries, tt.entries) {
+				t.Errorf("manifest files %v, want %v", entries, tt.entries)
+			}
+			// Every entry besides the manifest is a file of the manifest: skipped files leave nothing behind.
+			if len(archive) != len(tt.entries)+1 {
+				t.Errorf("archive has %d entries, want %d files and the manifest", len(archive), len(tt.entries))
+			}
+
+			var archived domain.ExportManifest
+			if err := json.Unmarshal([]byte(archive[zipManifestName]), &archived); err != nil {
+				t.Fatalf("manifest of the archive: %v", err)
+			}
+			if !reflect.DeepEqual(archived.Files, manifest.Files) {
+				t.Errorf("archived manifest %+v, want %+v", archived.Files, manifest.Files)
+			}
+		})
+	}
+}
+
+// readZip returns the content of every entry of the archive at path by name.
+func readZip(t *testing.T, path string) map[string]string {
+	t.Helper()
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	defer r.Close()
+
+	entries := map[string]string{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name, err)
+		}
+		entries[f.Name] = string(content)
+	}
+	return entries
+}
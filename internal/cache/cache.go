@@ -1,20 +1,49 @@
 package cache
 
 import (
+	"context"
+	"time"
+
 	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/errors"
+)
+
+var (
+	// ErrQueueEmpty is returned by PopExportTask when there is no task waiting in the queue.
+	ErrQueueEmpty = errors.New("export queue is empty")
+	// ErrLeaseLost is returned by ExtendLease when the task is not leased anymore,
+	// e.g. because its lease expired and it went back to the queue.
+	ErrLeaseLost = errors.New("task lease is lost")
 )
 
 type Cache interface {
 	Exists(taskID string) (bool, error)
 	PushExportTask(task domain.ExportTask) error
-	PopExportTask() (domain.ExportTask, error)
+	PopExportTask(ctx context.Context, lease time.Duration) (domain.ExportTask, error)
+	Ack(taskID string) error
+	Nack(taskID string) error
+	ExtendLease(taskID string, lease time.Duration) error
+	RequeueExpired() (int, error)
+	Retry(task domain.ExportTask, delay time.Duration) error
+	PromoteDelayed() (int, error)
+	DeadLetter(task domain.ExportTask, reason string) error
+	RemoveQueuedTask(taskID string) (bool, error)
+	RequestCancel(taskID string) error
+	IsCancelRequested(taskID string) (bool, error)
 	SetExportStatus(taskID, status string) error
 	GetExportStatus(taskID string) (string, error)
 	SetExportURL(taskID, url string) error
 	GetExportURL(taskID string) (string, error)
 	SetExportHistoryID(taskID string, historyID int64) error
 	GetExportHistoryID(taskID string) (int64, error)
+	SetExportPassword(taskID, sealed string) error
+	TakeExportPassword(taskID string) (string, error)
 	ClearExportTask(taskID string) error
+	PublishProgress(event domain.ExportProgress) error
+	GetProgress(taskID string) (*domain.ExportProgress, error)
+	SubscribeProgress(ctx context.Context, taskID string) (<-chan domain.ExportProgress, func(), error)
+	AcquireLeadership(role, holder string, ttl time.Duration) (bool, error)
+	ReleaseLeadership(role, holder string) error
 
 	//FIXME needs to be deleted later
 	// made for development purposes only
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/webitel/media-exporter/internal/cache"
 	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
 	"github.com/webitel/media-exporter/internal/errors"
 )
@@ -18,10 +19,20 @@ type RedisCache struct {
 
 const (
 	exportQueueKey = "export_queue"
+	processingKey  = "export_queue:processing"
+	leasesKey      = "export_queue:leases"
+	delayedKey     = "export_queue:delayed"
+	deadLetterKey  = "export_queue:dead"
+	queuedKey      = "export_queue:queued"
+	claimedKey     = "export_queue:claimed"
 	statusPrefix   = "export_status:"
 	historyPrefix  = "export_history_id:"
 	urlPrefix      = "export_url:"
 	taskPrefix     = "export:task:"
+	progressPrefix = "export_progress:"
+	cancelPrefix   = "export_cancel:"
+	passwordPrefix = "export_password:"
+	leaderPrefix   = "export_leader:"
 )
 
 func NewRedisCache(addr, password string, db int) (*RedisCache, error) {
@@ -29,6 +40,8 @@ func NewRedisCache(addr, password string, db int) (*RedisCache, error) {
 		Addr:     addr,
 		Password: password,
 		DB:       db,
+		// Lets a canceled context interrupt the blocking pop of a stopping worker.
+		ContextTimeoutEnabled: true,
 	})
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -43,48 +56,274 @@ func NewRedisCache(addr, password string, db int) (*RedisCache, error) {
 
 // ----------------------- Task Queue -----------------------
 
+// popTimeout is how long PopExportTask waits for a task before it reports an empty queue.
+const popTimeout = 5 * time.Second
+
+// PushExportTask appends the task to the queue. The payload of every waiting task, queued or
+// delayed, is indexed by task ID, so that it can be removed without scanning the queue.
 func (r *RedisCache) PushExportTask(task domain.ExportTask) error {
 	data, err := json.Marshal(task)
 	if err != nil {
 		return err
 	}
-	if err := r.client.RPush(context.Background(), exportQueueKey, data).Err(); err != nil {
+	ctx := context.Background()
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, queuedKey, task.TaskID, data)
+		pipe.RPush(ctx, exportQueueKey, data)
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("failed to push task to queue: %w", err)
 	}
 	return nil
 }
 
-// cache/redis.go
-
-func (r *RedisCache) PopExportTask() (domain.ExportTask, error) {
-	result, err := r.client.BRPop(context.Background(), 5*time.Second, exportQueueKey).Result()
-
+// leaseScript leases the task ARGV[1] a worker moved from the queue to the claimed list:
+// it stores its payload in the processing hash and registers a lease until ARGV[2].
+// It returns 0 if the task is not claimed anymore, because the reaper returned it to the queue
+// or it was removed from it.
+var leaseScript = redis.NewScript(`
+if redis.call('LREM', KEYS[7], 1, ARGV[1]) == 0 then
+	return 0
+end
+local task = cjson.decode(ARGV[1])
+redis.call('HDEL', KEYS[6], task.task_id)
+redis.call('HSET', KEYS[2], task.task_id, ARGV[1])
+redis.call('ZADD', KEYS[3], ARGV[2], task.task_id)
+return 1
+`)
+
+// nackScript moves a leased task back to the head of the queue.
+var nackScript = redis.NewScript(`
+local raw = redis.call('HGET', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[2], ARGV[1])
+redis.call('ZREM', KEYS[3], ARGV[1])
+if raw then
+	redis.call('HSET', KEYS[6], ARGV[1], raw)
+	redis.call('LPUSH', KEYS[1], raw)
+	return 1
+end
+return 0
+`)
+
+// extendScript moves the lease deadline of a task that is still leased.
+var extendScript = redis.NewScript(`
+if redis.call('ZSCORE', KEYS[3], ARGV[1]) then
+	redis.call('ZADD', KEYS[3], ARGV[2], ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// requeueScript returns every task whose lease expired before ARGV[1] to the head of the queue,
+// and the claimed tasks a worker did not lease, e.g. because it died right after taking them.
+// A worker that is about to lease a task returned this way finds it gone and leaves it to the others.
+var requeueScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[3], '-inf', ARGV[1])
+local count = 0
+for _, id in ipairs(ids) do
+	local raw = redis.call('HGET', KEYS[2], id)
+	redis.call('HDEL', KEYS[2], id)
+	redis.call('ZREM', KEYS[3], id)
+	if raw then
+		redis.call('HSET', KEYS[6], id, raw)
+		redis.call('LPUSH', KEYS[1], raw)
+		count = count + 1
+	end
+end
+local raw = redis.call('RPOP', KEYS[7])
+while raw do
+	redis.call('LPUSH', KEYS[1], raw)
+	count = count + 1
+	raw = redis.call('RPOP', KEYS[7])
+end
+return count
+`)
+
+// retryScript releases a leased task and parks its updated payload in the delayed set until ARGV[3].
+var retryScript = redis.NewScript(`
+redis.call('HDEL', KEYS[2], ARGV[1])
+redis.call('ZREM', KEYS[3], ARGV[1])
+redis.call('HSET', KEYS[6], ARGV[1], ARGV[2])
+redis.call('ZADD', KEYS[4], ARGV[3], ARGV[2])
+return 1
+`)
+
+// deadLetterScript releases a leased task and appends the failure record to the dead-letter list.
+var deadLetterScript = redis.NewScript(`
+redis.call('HDEL', KEYS[2], ARGV[1])
+redis.call('ZREM', KEYS[3], ARGV[1])
+redis.call('LPUSH', KEYS[5], ARGV[2])
+return 1
+`)
+
+// promoteScript moves delayed tasks that are due before ARGV[1] to the tail of the queue.
+var promoteScript = redis.NewScript(`
+local items = redis.call('ZRANGEBYSCORE', KEYS[4], '-inf', ARGV[1])
+for _, raw in ipairs(items) do
+	redis.call('ZREM', KEYS[4], raw)
+	redis.call('RPUSH', KEYS[1], raw)
+end
+return #items
+`)
+
+// removeScript deletes a task that has not been leased by a worker yet from the queue,
+// the delayed retries and the claimed list, finding its payload by its task ID.
+var removeScript = redis.NewScript(`
+local raw = redis.call('HGET', KEYS[6], ARGV[1])
+if not raw then
+	return 0
+end
+redis.call('HDEL', KEYS[6], ARGV[1])
+return redis.call('LREM', KEYS[1], 0, raw) + redis.call('ZREM', KEYS[4], raw) + redis.call('LREM', KEYS[7], 0, raw)
+`)
+
+var queueKeys = []string{exportQueueKey, processingKey, leasesKey, delayedKey, deadLetterKey, queuedKey, claimedKey}
+
+// PopExportTask waits for the oldest pending task and leases it to the caller for the given duration.
+// It reports cache.ErrQueueEmpty if no task arrived in a few seconds or ctx is done.
+// The task stays in the processing set until it is acknowledged with Ack, returned with Nack
+// or its lease expires and RequeueExpired puts it back into the queue.
+func (r *RedisCache) PopExportTask(ctx context.Context, lease time.Duration) (domain.ExportTask, error) {
+	// The task moves to the claimed list in the same command that takes it, so a worker dying
+	// before it leases the task does not lose it: the reaper returns it to the queue.
+	raw, err := r.client.BLMove(ctx, exportQueueKey, claimedKey, "LEFT", "RIGHT", popTimeout).Result()
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return domain.ExportTask{}, fmt.Errorf("queue empty (timeout)")
+		if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+			return domain.ExportTask{}, cache.ErrQueueEmpty
 		}
-		slog.Error("REDIS BRPOP ERROR", "err", err)
-		return domain.ExportTask{}, err
+		return domain.ExportTask{}, fmt.Errorf("failed to pop task: %w", err)
 	}
 
-	if len(result) < 2 {
-		slog.Warn("BRPOP BAD FORMAT", "result", result)
-		return domain.ExportTask{}, fmt.Errorf("unexpected BRPop result format")
+	deadline := time.Now().Add(lease).UnixMilli()
+	leased, err := leaseScript.Run(context.Background(), r.client, queueKeys, raw, deadline).Int()
+	if err != nil {
+		return domain.ExportTask{}, fmt.Errorf("failed to lease task: %w", err)
+	}
+	if leased == 0 {
+		return domain.ExportTask{}, cache.ErrQueueEmpty
 	}
-
-	data := []byte(result[1])
 
 	var task domain.ExportTask
-	if err := json.Unmarshal(data, &task); err != nil {
-		slog.Error("BRPOP UNMARSHAL ERROR", "err", err)
+	if err := json.Unmarshal([]byte(raw), &task); err != nil {
 		return domain.ExportTask{}, fmt.Errorf("failed to unmarshal task: %w", err)
 	}
 
-	slog.Info("POPED TASK FROM REDIS QUEUE", "taskID", task.TaskID)
+	slog.Debug("leased export task", "taskID", task.TaskID, "deadline", deadline)
 
 	return task, nil
 }
 
+// Ack removes a finished task from the processing set and drops its lease.
+func (r *RedisCache) Ack(taskID string) error {
+	ctx := context.Background()
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, processingKey, taskID)
+		pipe.ZRem(ctx, leasesKey, taskID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ack task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// Nack returns a leased task to the head of the queue so another worker can pick it up.
+func (r *RedisCache) Nack(taskID string) error {
+	if err := nackScript.Run(context.Background(), r.client, queueKeys, taskID).Err(); err != nil {
+		return fmt.Errorf("failed to nack task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// ExtendLease pushes the lease deadline of a task forward. It fails
+// with cache.ErrLeaseLost when the task is not leased anymore, e.g. because the reaper already
+// returned it to the queue.
+func (r *RedisCache) ExtendLease(taskID string, lease time.Duration) error {
+	deadline := time.Now().Add(lease).UnixMilli()
+	updated, err := extendScript.Run(context.Background(), r.client, queueKeys, taskID, deadline).Int()
+	if err != nil {
+		return fmt.Errorf("failed to extend lease of task %s: %w", taskID, err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("task %s: %w", taskID, cache.ErrLeaseLost)
+	}
+	return nil
+}
+
+// RequeueExpired returns tasks with an expired lease to the queue and reports how many were moved.
+func (r *RedisCache) RequeueExpired() (int, error) {
+	count, err := requeueScript.Run(context.Background(), r.client, queueKeys, time.Now().UnixMilli()).Int()
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue expired tasks: %w", err)
+	}
+	return count, nil
+}
+
+// Retry releases a leased task and re-enqueues it after the given delay.
+// The task payload is stored as passed, so the caller can bump its attempt counter.
+func (r *RedisCache) Retry(task domain.ExportTask, delay time.Duration) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	readyAt := time.Now().Add(delay).UnixMilli()
+	if err := retryScript.Run(context.Background(), r.client, queueKeys, task.TaskID, data, readyAt).Err(); err != nil {
+		return fmt.Errorf("failed to schedule retry of task %s: %w", task.TaskID, err)
+	}
+	return nil
+}
+
+// PromoteDelayed moves delayed tasks whose retry time has come back to the queue.
+func (r *RedisCache) PromoteDelayed() (int, error) {
+	count, err := promoteScript.Run(context.Background(), r.client, queueKeys, time.Now().UnixMilli()).Int()
+	if err != nil {
+		return 0, fmt.Errorf("failed to promote delayed tasks: %w", err)
+	}
+	return count, nil
+}
+
+// DeadLetter releases a leased task and stores it in the dead-letter list together with the failure reason.
+func (r *RedisCache) DeadLetter(task domain.ExportTask, reason string) error {
+	data, err := json.Marshal(domain.DeadLetter{
+		Task:     task,
+		Reason:   reason,
+		FailedAt: time.Now().UnixMilli(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := deadLetterScript.Run(context.Background(), r.client, queueKeys, task.TaskID, data).Err(); err != nil {
+		return fmt.Errorf("failed to dead-letter task %s: %w", task.TaskID, err)
+	}
+	return nil
+}
+
+// RemoveQueuedTask deletes a task waiting in the queue or for a retry.
+// It reports false if the task is not waiting, e.g. because a worker already took it.
+func (r *RedisCache) RemoveQueuedTask(taskID string) (bool, error) {
+	removed, err := removeScript.Run(context.Background(), r.client, queueKeys, taskID).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to remove task %s from queue: %w", taskID, err)
+	}
+	return removed > 0, nil
+}
+
+// ----------------------- Cancellation -----------------------
+
+// RequestCancel asks the worker running the task to abort it.
+func (r *RedisCache) RequestCancel(taskID string) error {
+	return r.client.Set(context.Background(), cancelPrefix+taskID, 1, 24*time.Hour).Err()
+}
+
+func (r *RedisCache) IsCancelRequested(taskID string) (bool, error) {
+	count, err := r.client.Exists(context.Background(), cancelPrefix+taskID).Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // ----------------------- Status -----------------------
 
 func (r *RedisCache) Exists(taskID string) (bool, error) {
@@ -114,6 +353,113 @@ func (r *RedisCache) GetExportStatus(taskID string) (string, error) {
 	return val, nil
 }
 
+// ----------------------- Progress -----------------------
+
+// PublishProgress keeps the event as the last known progress of the task
+// and broadcasts it to the watchers of the task.
+func (r *RedisCache) PublishProgress(event domain.ExportProgress) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	key := progressPrefix + event.TaskID
+	_, err = r.client.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		pipe.Set(context.Background(), key, data, 24*time.Hour)
+		pipe.Publish(context.Background(), key, data)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish progress of task %s: %w", event.TaskID, err)
+	}
+	return nil
+}
+
+// GetProgress returns the last known progress of the task, or nil if nothing was published yet.
+func (r *RedisCache) GetProgress(taskID string) (*domain.ExportProgress, error) {
+	val, err := r.client.Get(context.Background(), progressPrefix+taskID).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var event domain.ExportProgress
+	if err := json.Unmarshal(val, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// SubscribeProgress delivers progress events of the task until ctx is done or close is called.
+// The subscription is established when the function returns, so events published afterwards are not missed.
+func (r *RedisCache) SubscribeProgress(ctx context.Context, taskID string) (<-chan domain.ExportProgress, func(), error) {
+	sub := r.client.Subscribe(ctx, progressPrefix+taskID)
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return nil, nil, fmt.Errorf("failed to subscribe to progress of task %s: %w", taskID, err)
+	}
+
+	events := make(chan domain.ExportProgress)
+	go func() {
+		defer close(events)
+		for msg := range sub.Channel() {
+			var event domain.ExportProgress
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				slog.Warn("invalid progress event", "taskID", taskID, "error", err)
+				continue
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, func() { _ = sub.Close() }, nil
+}
+
+// ----------------------- Leadership -----------------------
+
+// leaderScript makes ARGV[1] the holder of the lease KEYS[1] for ARGV[2] milliseconds,
+// extending the lease if it already holds it.
+var leaderScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// releaseScript deletes the lease KEYS[1] if ARGV[1] holds it.
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// AcquireLeadership makes the holder the leader of the role for the ttl, or extends its leadership.
+// It reports whether the holder is the leader. A leader that stops extending it loses it after the ttl.
+func (r *RedisCache) AcquireLeadership(role, holder string, ttl time.Duration) (bool, error) {
+	ok, err := leaderScript.Run(context.Background(), r.client, []string{leaderPrefix + role}, holder, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire leadership of %s: %w", role, err)
+	}
+	return ok == 1, nil
+}
+
+// ReleaseLeadership gives up the leadership of the role if the holder has it.
+func (r *RedisCache) ReleaseLeadership(role, holder string) error {
+	if err := releaseScript.Run(context.Background(), r.client, []string{leaderPrefix + role}, holder).Err(); err != nil {
+		return fmt.Errorf("failed to release leadership of %s: %w", role, err)
+	}
+	return nil
+}
+
 // ----------------------- History -----------------------
 
 func (r *RedisCache) SetExportHistoryID(taskID string, historyID int64) error {
@@ -138,6 +484,27 @@ func (r *RedisCache) GetExportHistoryID(taskID string) (int64, error) {
 	return id, nil
 }
 
+// ----------------------- Password -----------------------
+
+// SetExportPassword keeps the sealed generated password of an encrypted export until it is taken.
+// It is not removed with the other keys of the task, the requester may ask for it after the export is done.
+func (r *RedisCache) SetExportPassword(taskID, sealed string) error {
+	return r.client.Set(context.Background(), passwordPrefix+taskID, sealed, 24*time.Hour).Err()
+}
+
+// TakeExportPassword returns the sealed password of the export and deletes it,
+// so it is handed out once. It returns "" if there is none or it is already taken.
+func (r *RedisCache) TakeExportPassword(taskID string) (string, error) {
+	val, err := r.client.GetDel(context.Background(), passwordPrefix+taskID).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", nil
+		}
+		return "", err
+	}
+	return val, nil
+}
+
 // ----------------------- URL -----------------------
 
 func (r *RedisCache) SetExportURL(taskID, url string) error {
@@ -166,6 +533,7 @@ func (r *RedisCache) ClearExportTask(taskID string) error {
 		historyPrefix + taskID,
 		urlPrefix + taskID,
 		taskPrefix + taskID,
+		cancelPrefix + taskID,
 	}
 
 	for _, key := range keys {
@@ -196,6 +564,23 @@ func (r *RedisCache) ListExportQueue() ([]domain.ExportTask, error) {
 	return tasks, nil
 }
 
+// ListDeadLetters returns all tasks in the dead-letter list (debug only)
+func (r *RedisCache) ListDeadLetters() ([]domain.DeadLetter, error) {
+	items, err := r.client.LRange(context.Background(), deadLetterKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	var letters []domain.DeadLetter
+	for _, item := range items {
+		var l domain.DeadLetter
+		if err := json.Unmarshal([]byte(item), &l); err != nil {
+			continue
+		}
+		letters = append(letters, l)
+	}
+	return letters, nil
+}
+
 func (r *RedisCache) Clear() error {
 	ctx := context.Background()
 	if err := r.client.FlushDB(ctx).Err(); err != nil {
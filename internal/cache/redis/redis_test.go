@@ -0,0 +1,261 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/webitel/media-exporter/internal/cache"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+)
+
+func newTestCache(t *testing.T) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr(), ContextTimeoutEnabled: true})
+	t.Cleanup(func() { _ = client.Close() })
+	return &RedisCache{client: client}, srv
+}
+
+func pushTasks(t *testing.T, r *RedisCache, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		if err := r.PushExportTask(domain.ExportTask{TaskID: id}); err != nil {
+			t.Fatalf("PushExportTask(%s) error = %v", id, err)
+		}
+	}
+}
+
+func popTask(t *testing.T, r *RedisCache, lease time.Duration) string {
+	t.Helper()
+	task, err := r.PopExportTask(context.Background(), lease)
+	if err != nil {
+		t.Fatalf("PopExportTask() error = %v", err)
+	}
+	return task.TaskID
+}
+
+func TestPopLeasesOldestTask(t *testing.T) {
+	r, srv := newTestCache(t)
+	pushTasks(t, r, "a", "b")
+
+	if got := popTask(t, r, time.Minute); got != "a" {
+		t.Fatalf("popped %s, want a", got)
+	}
+	if !srv.Exists(processingKey) || srv.HGet(processingKey, "a") == "" {
+		t.Errorf("popped task is not in the processing hash")
+	}
+	if score, err := srv.ZScore(leasesKey, "a"); err != nil || score <= float64(time.Now().UnixMilli()) {
+		t.Errorf("popped task has no lease in the future: %v, %v", score, err)
+	}
+	if srv.HGet(queuedKey, "a") != "" || srv.HGet(queuedKey, "b") == "" {
+		t.Errorf("queued index is not updated, want only b indexed")
+	}
+
+	if err := r.Ack("a"); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	if srv.HGet(processingKey, "a") != "" {
+		t.Errorf("acknowledged task is still processing")
+	}
+	if _, err := srv.ZScore(leasesKey, "a"); err == nil {
+		t.Errorf("acknowledged task is still leased")
+	}
+}
+
+func TestPopWaitsForTask(t *testing.T) {
+	r, _ := newTestCache(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := r.PopExportTask(ctx, time.Minute); !errors.Is(err, cache.ErrQueueEmpty) {
+		t.Fatalf("PopExportTask() of an empty queue error = %v, want ErrQueueEmpty", err)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = r.PushExportTask(domain.ExportTask{TaskID: "late"})
+	}()
+	if got := popTask(t, r, time.Minute); got != "late" {
+		t.Errorf("popped %s, want the task pushed while waiting", got)
+	}
+}
+
+func TestExtendLease(t *testing.T) {
+	r, srv := newTestCache(t)
+	pushTasks(t, r, "a")
+	popTask(t, r, time.Second)
+
+	before, _ := srv.ZScore(leasesKey, "a")
+	if err := r.ExtendLease("a", time.Hour); err != nil {
+		t.Fatalf("ExtendLease() error = %v", err)
+	}
+	if after, _ := srv.ZScore(leasesKey, "a"); after <= before {
+		t.Errorf("lease deadline %v was not moved past %v", after, before)
+	}
+	if err := r.ExtendLease("unknown", time.Hour); !errors.Is(err, cache.ErrLeaseLost) {
+		t.Errorf("ExtendLease() of a task that is not leased error = %v, want ErrLeaseLost", err)
+	}
+}
+
+func TestRequeueExpired(t *testing.T) {
+	r, srv := newTestCache(t)
+	pushTasks(t, r, "expired", "waiting")
+	popTask(t, r, -time.Second)
+
+	count, err := r.RequeueExpired()
+	if err != nil {
+		t.Fatalf("RequeueExpired() error = %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("RequeueExpired() = %d, want 1", count)
+	}
+	if srv.HGet(processingKey, "expired") != "" {
+		t.Errorf("requeued task is still processing")
+	}
+	if err := r.ExtendLease("expired", time.Minute); !errors.Is(err, cache.ErrLeaseLost) {
+		t.Errorf("ExtendLease() of a requeued task error = %v, want ErrLeaseLost", err)
+	}
+	if got := popTask(t, r, time.Minute); got != "expired" {
+		t.Errorf("popped %s, want the requeued task at the head of the queue", got)
+	}
+}
+
+// A worker that dies between taking a task and leasing it must not lose the task,
+// and a worker that leases a task the reaper already returned must leave it to the others.
+func TestRequeueClaimedTask(t *testing.T) {
+	r, srv := newTestCache(t)
+	pushTasks(t, r, "a")
+
+	ctx := context.Background()
+	raw, err := r.client.LMove(ctx, exportQueueKey, claimedKey, "LEFT", "RIGHT").Result()
+	if err != nil {
+		t.Fatalf("LMove() error = %v", err)
+	}
+
+	if count, err := r.RequeueExpired(); err != nil || count != 1 {
+		t.Fatalf("RequeueExpired() = %d, %v, want 1", count, err)
+	}
+	leased, err := leaseScript.Run(ctx, r.client, queueKeys, raw, time.Now().Add(time.Minute).UnixMilli()).Int()
+	if err != nil || leased != 0 {
+		t.Errorf("leasing a requeued task = %d, %v, want 0", leased, err)
+	}
+	if _, err := srv.ZScore(leasesKey, "a"); err == nil {
+		t.Errorf("task returned to the queue is leased")
+	}
+	if got := popTask(t, r, time.Minute); got != "a" {
+		t.Errorf("popped %s, want a", got)
+	}
+}
+
+func TestNack(t *testing.T) {
+	r, _ := newTestCache(t)
+	pushTasks(t, r, "a", "b")
+	popTask(t, r, time.Minute)
+
+	if err := r.Nack("a"); err != nil {
+		t.Fatalf("Nack() error = %v", err)
+	}
+	if got := popTask(t, r, time.Minute); got != "a" {
+		t.Errorf("popped %s, want the returned task at the head of the queue", got)
+	}
+}
+
+func TestRemoveQueuedTask(t *testing.T) {
+	r, srv := newTestCache(t)
+	pushTasks(t, r, "a", "b", "c")
+
+	removed, err := r.RemoveQueuedTask("b")
+	if err != nil || !removed {
+		t.Fatalf("RemoveQueuedTask(b) = %v, %v, want true", removed, err)
+	}
+	if items, _ := srv.List(exportQueueKey); len(items) != 2 {
+		t.Errorf("queue has %d tasks, want 2", len(items))
+	}
+	if removed, _ := r.RemoveQueuedTask("b"); removed {
+		t.Errorf("RemoveQueuedTask() of a removed task = true")
+	}
+
+	popTask(t, r, time.Minute)
+	if removed, _ := r.RemoveQueuedTask("a"); removed {
+		t.Errorf("RemoveQueuedTask() of a leased task = true")
+	}
+
+	if err := r.Retry(domain.ExportTask{TaskID: "a", Attempts: 1}, time.Hour); err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if removed, err := r.RemoveQueuedTask("a"); err != nil || !removed {
+		t.Errorf("RemoveQueuedTask() of a delayed task = %v, %v, want true", removed, err)
+	}
+	if members, _ := srv.ZMembers(delayedKey); len(members) != 0 {
+		t.Errorf("delayed set has %d tasks after removal, want 0", len(members))
+	}
+}
+
+func TestRetryDelaysTask(t *testing.T) {
+	r, srv := newTestCache(t)
+	pushTasks(t, r, "a")
+	popTask(t, r, time.Minute)
+
+	if err := r.Retry(domain.ExportTask{TaskID: "a", Attempts: 1}, time.Hour); err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if srv.HGet(processingKey, "a") != "" {
+		t.Errorf("retried task is still processing")
+	}
+	if _, err := srv.ZScore(leasesKey, "a"); err == nil {
+		t.Errorf("retried task is still leased")
+	}
+	if count, err := r.PromoteDelayed(); err != nil || count != 0 {
+		t.Errorf("PromoteDelayed() before the retry time = %d, %v, want 0", count, err)
+	}
+
+	if err := r.Retry(domain.ExportTask{TaskID: "b", Attempts: 2}, -time.Second); err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if count, err := r.PromoteDelayed(); err != nil || count != 1 {
+		t.Fatalf("PromoteDelayed() = %d, %v, want 1", count, err)
+	}
+	task, err := r.PopExportTask(context.Background(), time.Minute)
+	if err != nil {
+		t.Fatalf("PopExportTask() error = %v", err)
+	}
+	if task.TaskID != "b" || task.Attempts != 2 {
+		t.Errorf("popped %s with %d attempts, want b with 2", task.TaskID, task.Attempts)
+	}
+}
+
+func TestDeadLetter(t *testing.T) {
+	r, srv := newTestCache(t)
+	pushTasks(t, r, "a")
+	popTask(t, r, time.Minute)
+
+	if err := r.DeadLetter(domain.ExportTask{TaskID: "a", Attempts: 5}, "storage unavailable"); err != nil {
+		t.Fatalf("DeadLetter() error = %v", err)
+	}
+	if srv.HGet(processingKey, "a") != "" {
+		t.Errorf("dead-lettered task is still processing")
+	}
+	if _, err := srv.ZScore(leasesKey, "a"); err == nil {
+		t.Errorf("dead-lettered task is still leased")
+	}
+
+	items, err := srv.List(deadLetterKey)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("dead-letter list = %v, %v, want one task", items, err)
+	}
+	var dead domain.DeadLetter
+	if err := json.Unmarshal([]byte(items[0]), &dead); err != nil {
+		t.Fatalf("dead letter is not valid JSON: %v", err)
+	}
+	if dead.Task.TaskID != "a" || dead.Task.Attempts != 5 || dead.Reason != "storage unavailable" || dead.FailedAt == 0 {
+		t.Errorf("dead letter = %+v, want task a with 5 attempts and the failure reason", dead)
+	}
+	if count, err := r.RequeueExpired(); err != nil || count != 0 {
+		t.Errorf("RequeueExpired() after dead-lettering = %d, %v, want 0", count, err)
+	}
+}
@@ -5,4 +5,8 @@ const (
 	NamespaceName  = "webitel"
 )
 
+// ExportObjClass is the object class of the exports, checked by the authorization interceptor
+// for calls of the API and by the scheduler for the runs of export schedules.
+const ExportObjClass = "media_exports"
+
 var CurrentVersion = "dev"
@@ -25,9 +25,161 @@ type UpdateExportStatus struct {
 	Size      int64  `db:"size"`    // Final file size in bytes
 	UpdatedBy int64  `db:"updated_by"`
 	UpdatedAt int64  `db:"updated_at"`
+	Attempts  int    `db:"attempts"`   // Number of failed attempts so far, 0 keeps the stored value
+	LastError string `db:"last_error"` // Reason of the last failed attempt, empty keeps the stored value
+	// Digest is the SHA-256 of the generated document and DigestSignature its signature,
+	// empty keeps the stored values
+	Digest          string `db:"digest"`
+	DigestSignature string `db:"digest_signature"`
 }
 
-const PdfExportType = "pdf"
+const (
+	PdfExportType  = "pdf"
+	ZipExportType  = "zip"
+	Mp4ExportType  = "mp4"
+	WebmExportType = "webm"
+	// TranscriptExportType is a PDF of the transcripts of the recordings of a call.
+	TranscriptExportType = "transcript"
+	// DossierExportType is a PDF of the screenshots, the transcripts and the files of a call.
+	DossierExportType = "dossier"
+	// Audio exports of the recordings of calls: the originals in a ZIP archive,
+	// or the recordings joined into a single file of the format.
+	AudioZipExportType = "audio_zip"
+	Mp3ExportType      = "mp3"
+	WavExportType      = "wav"
+	OpusExportType     = "opus"
+	// BatchExportType tracks the exports of many agents or calls, see BatchOptions.
+	BatchExportType = "batch"
+)
+
+// VideoOptions configure a time-lapse video export of screenshots.
+type VideoOptions struct {
+	FrameDurationMs  int64 `json:"frame_duration_ms,omitempty"` // How long every screenshot is shown, 0 for the configured default
+	TimestampOverlay bool  `json:"timestamp_overlay,omitempty"` // Draw the capture time over every screenshot
+}
+
+// TranscriptOptions configure a transcript export.
+type TranscriptOptions struct {
+	// WindowFromMs and WindowToMs limit the phrases to those overlapping the window,
+	// in milliseconds from the start of every recording. WindowToMs 0 does not limit the end.
+	WindowFromMs int64 `json:"window_from_ms,omitempty"`
+	WindowToMs   int64 `json:"window_to_ms,omitempty"`
+}
+
+// BatchOptions describe the exports of a batch export: one export of the type per agent or call,
+// sharing the range and the options of the batch.
+type BatchOptions struct {
+	Type     string   `json:"type"`
+	AgentIDs []int64  `json:"agent_ids,omitempty"`
+	CallIDs  []string `json:"call_ids,omitempty"`
+	// Combine packs the files of the exports into one ZIP archive once all of them finished.
+	Combine bool `json:"combine,omitempty"`
+}
+
+// ExportOrder orders and groups the screenshots of an export.
+type ExportOrder struct {
+	Sort         string `json:"sort,omitempty"`           // One of the Sort* values, the default of the export format if empty
+	GroupBy      string `json:"group_by,omitempty"`       // One of the GroupBy* values
+	SessionGapMs int64  `json:"session_gap_ms,omitempty"` // Pause that starts a new session with GroupBySession, the default if 0
+}
+
+// Orders of the screenshots of an export. Screenshots without an upload time go last.
+const (
+	SortUploadedDesc = "uploaded_desc"
+	SortUploadedAsc  = "uploaded_asc"
+	SortName         = "name"
+)
+
+// Groupings of the screenshots of an export.
+const (
+	GroupByNone    = ""
+	GroupByHour    = "hour"
+	GroupByDay     = "day"
+	GroupBySession = "session" // Runs of screenshots without a pause longer than the session gap
+)
+
+// PdfOptions configure the pages of a PDF export.
+type PdfOptions struct {
+	Captions     bool   `json:"captions,omitempty"`      // Print capture time, agent, file name/ID and SHA-256 under every screenshot
+	HeaderFooter bool   `json:"header_footer,omitempty"` // Running header with the source and range, footer with page X of Y
+	Timezone     string `json:"timezone,omitempty"`      // IANA time zone of the printed times, UTC if empty
+	Title        string `json:"title,omitempty"`         // Title in the header, a description of the source if empty
+	CoverPage    bool   `json:"cover_page,omitempty"`    // Cover page describing the source, range, requester and counts
+	Contents     string `json:"contents,omitempty"`      // Table of contents grouping the pages by ExportOrder.GroupBy, or by one of the Contents* values if it is not set
+	// Layout of the pages
+	PageSize      string `json:"page_size,omitempty"`       // One of the PageSize* values, A4 if empty
+	Orientation   string `json:"orientation,omitempty"`     // One of the Orientation* values, portrait if empty
+	ImagesPerPage int    `json:"images_per_page,omitempty"` // Screenshots per page in a grid, one if 0
+	ContactSheet  bool   `json:"contact_sheet,omitempty"`   // Thumbnails labeled with the capture time and file ID instead of pages
+	// Watermark draws the requester, domain, export ID and creation time across every page
+	Watermark bool `json:"watermark,omitempty"`
+	// Encryption protects the document with a password, not encrypted if nil
+	Encryption *PdfEncryption `json:"encryption,omitempty"`
+	// SignedManifest embeds a manifest of the included files signed with the key of the service
+	SignedManifest bool `json:"signed_manifest,omitempty"`
+}
+
+// PdfEncryption is the password protection of a PDF export.
+type PdfEncryption struct {
+	// Password is the caller-supplied password. It is never serialized, tasks carry it sealed.
+	Password string `json:"-"`
+	// Generated is set when the service generated the password, which is then returned once by GetExport.
+	Generated bool `json:"generated,omitempty"`
+}
+
+// Page sizes of a PDF export.
+const (
+	PageSizeA4     = "a4"
+	PageSizeLetter = "letter"
+)
+
+// Orientations of the pages of a PDF export.
+const (
+	OrientationPortrait  = "portrait"
+	OrientationLandscape = "landscape"
+)
+
+// Groupings of the pages in the table of contents of a PDF export.
+const (
+	ContentsNone   = ""
+	ContentsByHour = "hour"
+	ContentsByDay  = "day"
+)
+
+// Units of the range of an export schedule.
+const (
+	RangeHour  = "hour"
+	RangeDay   = "day"
+	RangeWeek  = "week"
+	RangeMonth = "month"
+)
+
+// Catch-up policies of an export schedule for the runs it missed while no instance of the service ran.
+const (
+	CatchUpSkip   = "skip"   // Missed runs are dropped
+	CatchUpLatest = "latest" // Only the latest missed run is made
+	CatchUpAll    = "all"    // Every missed run is made
+)
+
+// ScheduleRange is the time range of every run of a schedule, relative to the time of the run.
+type ScheduleRange struct {
+	Unit    string `json:"unit"`              // One of the Range* values
+	Count   int    `json:"count"`             // Number of units, at least 1
+	Rolling bool   `json:"rolling,omitempty"` // End at the run instead of at the start of its unit
+}
+
+// ScheduleParams are the exports of every run of a schedule: one batch export of its agents
+// and of the agents of its teams at the time of the run.
+type ScheduleParams struct {
+	Range    ScheduleRange `json:"range"`
+	AgentIDs []int64       `json:"agent_ids,omitempty"`
+	TeamIDs  []int64       `json:"team_ids,omitempty"`
+	Type     string        `json:"type"` // Type of every export of the batch
+	Video    *VideoOptions `json:"video,omitempty"`
+	Pdf      *PdfOptions   `json:"pdf,omitempty"`
+	Order    *ExportOrder  `json:"order,omitempty"`
+	Combine  bool          `json:"combine,omitempty"`
+}
 
 // --- Request Models ---
 
@@ -37,6 +189,9 @@ type GenerateExportRequest struct {
 	FileIDs []int64
 	From    int64
 	To      int64
+	Video   *VideoOptions // Only for video exports
+	Pdf     *PdfOptions   // Only for PDF exports
+	Order   *ExportOrder
 }
 
 // GenerateCallExportRequest used for Calls
@@ -45,6 +200,58 @@ type GenerateCallExportRequest struct {
 	FileIDs []int64
 	From    int64
 	To      int64
+	Video   *VideoOptions // Only for video exports
+	Pdf     *PdfOptions   // Only for PDF exports
+	Order   *ExportOrder
+}
+
+// GenerateCallTranscriptRequest used for call transcripts
+type GenerateCallTranscriptRequest struct {
+	CallID     string
+	FileIDs    []int64
+	From       int64
+	To         int64
+	Pdf        *PdfOptions
+	Transcript *TranscriptOptions
+}
+
+// GenerateCallAudioRequest used for the audio recordings of calls
+type GenerateCallAudioRequest struct {
+	CallIDs []string
+	FileIDs []int64
+	From    int64
+	To      int64
+}
+
+// GenerateBatchExportRequest used for exports of many agents or calls
+type GenerateBatchExportRequest struct {
+	AgentIDs []int64
+	CallIDs  []string
+	From     int64
+	To       int64
+	Type     string        // Type of every export of the batch
+	Video    *VideoOptions // Only for video exports
+	Pdf      *PdfOptions   // Only for PDF exports
+	Order    *ExportOrder
+	Combine  bool
+}
+
+// GenerateCallDossierRequest used for call dossiers
+type GenerateCallDossierRequest struct {
+	CallID string
+	From   int64
+	To     int64
+	Pdf    *PdfOptions
+}
+
+// ExportScheduleSpec is what an export schedule exports, and when.
+type ExportScheduleSpec struct {
+	Name     string
+	Enabled  bool
+	Cron     string
+	Timezone string // IANA time zone of the cron expression and the ranges, UTC if empty
+	CatchUp  string // One of the CatchUp* values
+	Params   ScheduleParams
 }
 
 type PdfHistoryRequestOptions struct {
@@ -64,17 +271,111 @@ type CallHistoryRequestOptions struct {
 // --- Task & Metadata Models ---
 
 type ExportTask struct {
-	TaskID   string            `json:"task_id"`
-	AgentID  int64             `json:"agent_id,omitempty"`
-	CallID   string            `json:"call_id,omitempty"`
-	UserID   int64             `json:"user_id"`
-	DomainID int64             `json:"domain_id"`
-	Channel  string            `json:"channel"`
-	From     int64             `json:"from"`
-	To       int64             `json:"to"`
-	Headers  map[string]string `json:"headers"`
-	IDs      []int64           `json:"ids"`
-	Type     string            `json:"type"`
+	TaskID    string            `json:"task_id"`
+	HistoryID int64             `json:"history_id,omitempty"`
+	Attempts  int               `json:"attempts,omitempty"` // Number of failed attempts so far
+	AgentID   int64             `json:"agent_id,omitempty"`
+	CallID    string            `json:"call_id,omitempty"`
+	CallIDs   []string          `json:"call_ids,omitempty"` // All calls of an export of several calls, CallID is the first
+	UserID    int64             `json:"user_id"`
+	DomainID  int64             `json:"domain_id"`
+	Channel   string            `json:"channel"`
+	From      int64             `json:"from"`
+	To        int64             `json:"to"`
+	Headers   map[string]string `json:"headers"`
+	IDs       []int64           `json:"ids"`
+	Type      string            `json:"type"`
+	Video     *VideoOptions     `json:"video,omitempty"`
+	Pdf       *PdfOptions       `json:"pdf,omitempty"`
+	Order     *ExportOrder      `json:"order,omitempty"`
+	// Transcript is set for transcript exports only.
+	Transcript *TranscriptOptions `json:"transcript,omitempty"`
+	// Batch is set for batch exports only.
+	Batch *BatchOptions `json:"batch,omitempty"`
+	// RequestedBy and CreatedAt describe who requested the export and when, for the PDF cover page.
+	RequestedBy string `json:"requested_by,omitempty"`
+	CreatedAt   int64  `json:"created_at,omitempty"`
+	// TraceHash identifies the export in its history record and is embedded into the exported document.
+	TraceHash string `json:"trace_hash,omitempty"`
+	// SealedPassword is the password of an encrypted PDF export, sealed with the password key of the service.
+	SealedPassword string `json:"sealed_password,omitempty"`
+}
+
+// ExportManifest describes the content of an export archive.
+// It is written as manifest.json next to the exported files, or attached to a PDF export.
+type ExportManifest struct {
+	TaskID    string                `json:"task_id"`
+	TraceHash string                `json:"trace_hash,omitempty"`
+	AgentID   int64                 `json:"agent_id,omitempty"`
+	CallID    string                `json:"call_id,omitempty"`
+	Channel   string                `json:"channel"`
+	From      int64                 `json:"from,omitempty"`
+	To        int64                 `json:"to,omitempty"`
+	CreatedAt int64                 `json:"created_at"`
+	CreatedBy int64                 `json:"created_by"`
+	Files     []ManifestFile        `json:"files"`
+	Skipped   []ManifestSkippedFile `json:"skipped,omitempty"`
+}
+
+// ManifestFile is a single storage file included into an export.
+type ManifestFile struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Path       string `json:"path,omitempty"` // Path of the file in an archive
+	MimeType   string `json:"mime_type"`
+	Size       int64  `json:"size"`
+	Sha256     string `json:"sha256"`
+	UploadedAt int64  `json:"uploaded_at"`
+	Group      string `json:"group,omitempty"` // Title of the group of the file, if the export is grouped
+}
+
+// ManifestSkippedFile is a storage file that matched the export filter
+// but could not be included, together with the reason.
+type ManifestSkippedFile struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// Stages of the export pipeline reported in ExportProgress.
+const (
+	StageQueued      = "queued"
+	StageSearching   = "searching"
+	StageDownloading = "downloading"
+	StageRendering   = "rendering"
+	StageUploading   = "uploading"
+	StageCompleted   = "completed"
+	// StageWaiting is a batch export waiting for its exports.
+	StageWaiting = "waiting"
+)
+
+// ExportProgress is a progress event published by the worker while it processes a task.
+type ExportProgress struct {
+	TaskID    string `json:"task_id"`
+	Status    string `json:"status"`
+	Stage     string `json:"stage"`
+	Current   int64  `json:"current,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	FileID    int64  `json:"file_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// Final reports whether no further events follow this one.
+func (p *ExportProgress) Final() bool {
+	return IsFinalStatus(p.Status)
+}
+
+// IsFinalStatus reports whether an export with the given status will not change anymore.
+func IsFinalStatus(status string) bool {
+	return status == "done" || status == "failed" || status == "cancelled"
+}
+
+// DeadLetter is a task that exhausted its retry attempts.
+type DeadLetter struct {
+	Task     ExportTask `json:"task"`
+	Reason   string     `json:"reason"`
+	FailedAt int64      `json:"failed_at"`
 }
 
 type PdfExportMetadata struct {
@@ -85,29 +386,88 @@ type PdfExportMetadata struct {
 	Size     int64  `db:"size"`
 }
 
+// BatchExportMetadata describes a batch export and its exports right after they were created.
+type BatchExportMetadata struct {
+	Batch   *PdfExportMetadata
+	Exports []*PdfExportMetadata // In the order of the agents or calls of the request
+}
+
 // --- Persistence Models (Storage/DB) ---
 
+// ExportParams are the parameters of an export persisted with its history record,
+// so that a failed export can be repeated. Credentials are never part of them.
+type ExportParams struct {
+	Type    string        `json:"type"`
+	Channel string        `json:"channel"`
+	AgentID int64         `json:"agent_id,omitempty"`
+	CallID  string        `json:"call_id,omitempty"`
+	CallIDs []string      `json:"call_ids,omitempty"` // All calls of an export of several calls, CallID is the first
+	From    int64         `json:"from"`
+	To      int64         `json:"to"`
+	IDs     []int64       `json:"ids,omitempty"`
+	Video   *VideoOptions `json:"video,omitempty"`
+	Pdf     *PdfOptions   `json:"pdf,omitempty"`
+	Order   *ExportOrder  `json:"order,omitempty"`
+
+	Transcript *TranscriptOptions `json:"transcript,omitempty"`
+	Batch      *BatchOptions      `json:"batch,omitempty"`
+}
+
 type NewExportHistory struct {
-	Name       string `db:"name"`
-	Mime       string `db:"mime"`
-	UploadedAt int64  `db:"uploaded_at"`
-	UploadedBy int64  `db:"uploaded_by"`
-	Status     string `db:"status"`
-	AgentID    int64  `db:"agent_id,omitempty"`
-	CallID     string `db:"call_id,omitempty"`
-	FileID     int64  `db:"file_id"`
+	TaskID     string        `db:"task_id"`
+	Name       string        `db:"name"`
+	Mime       string        `db:"mime"`
+	UploadedAt int64         `db:"uploaded_at"`
+	UploadedBy int64         `db:"uploaded_by"`
+	Status     string        `db:"status"`
+	AgentID    int64         `db:"agent_id,omitempty"`
+	CallID     string        `db:"call_id,omitempty"`
+	FileID     int64         `db:"file_id"`
+	Params     *ExportParams `db:"params"`
+	RetryOf    int64         `db:"retry_of"`   // History ID of the export this one retries
+	TraceHash  string        `db:"trace_hash"` // Hash embedded into the exported document to trace leaked copies
+	ParentID   int64         `db:"parent_id"`  // History ID of the batch export this one is part of
 }
 
 type HistoryRecord struct {
-	ID        int64  `db:"id"`
-	Name      string `db:"name"`
-	FileID    int64  `db:"file_id"`
-	MimeType  string `db:"mime_type"`
-	CreatedAt int64  `db:"created_at"`
-	UpdatedAt int64  `db:"updated_at"`
-	CreatedBy int64  `db:"created_by"`
-	UpdatedBy int64  `db:"updated_by"`
-	Status    string `db:"status"`
+	ID        int64         `db:"id"`
+	Name      string        `db:"name"`
+	FileID    int64         `db:"file_id"`
+	MimeType  string        `db:"mime_type"`
+	CreatedAt int64         `db:"created_at"`
+	UpdatedAt int64         `db:"updated_at"`
+	CreatedBy int64         `db:"created_by"`
+	UpdatedBy int64         `db:"updated_by"`
+	Status    string        `db:"status"`
+	Attempts  int           `db:"attempts"`
+	LastError string        `db:"last_error"`
+	TaskID    string        `db:"task_id"`
+	Size      int64         `db:"size"`
+	Params    *ExportParams `db:"params"`
+	RetryOf   int64         `db:"retry_of"`
+	AgentID   int64         `db:"agent_id"`
+	CallID    string        `db:"call_id"`
+	TraceHash string        `db:"trace_hash"`
+	// Password is the generated password of an encrypted export, set only when it is handed out.
+	Password        string `db:"-"`
+	Digest          string `db:"digest"`
+	DigestSignature string `db:"digest_signature"`
+	ParentID        int64  `db:"parent_id"`
+}
+
+// ExportVerification is the result of checking a document against the digest recorded for its export.
+type ExportVerification struct {
+	Valid          bool   // The document is the one the export produced, unmodified
+	Sha256         string // SHA-256 of the checked document
+	ExpectedSha256 string // SHA-256 recorded when the export was produced
+	Size           int64  // Size of the checked document
+	SignatureValid bool   // The recorded digest carries a valid signature of the service key
+}
+
+// SignedDigest returns what is signed to vouch for the digest of the document of an export.
+// The task ID binds the signature to the export, so it cannot be moved to another record.
+func SignedDigest(taskID, digest string) []byte {
+	return []byte(taskID + "\nsha256:" + digest)
 }
 
 type HistoryResponse struct {
@@ -116,6 +476,42 @@ type HistoryResponse struct {
 	Next  bool             `db:"next"`
 }
 
+// NewExportSchedule is a schedule to insert, or the new state of a schedule to update.
+type NewExportSchedule struct {
+	Spec        ExportScheduleSpec
+	SealedToken string // Access token of the user who saves the schedule, sealed
+	NextRunAt   int64  // Next occurrence (Unix millis), 0 if disabled
+}
+
+// ExportSchedule is a persisted export schedule.
+type ExportSchedule struct {
+	ID        int64
+	DomainID  int64
+	Spec      ExportScheduleSpec
+	CreatedAt int64
+	UpdatedAt int64
+	CreatedBy int64
+	UpdatedBy int64 // Runs are made on behalf of this user
+	NextRunAt int64 // Next occurrence (Unix millis), 0 if disabled
+	LastRunAt int64 // Occurrence of the last run (Unix millis)
+	// LastTaskID is the task of the batch export of the last run, LastError why the run failed.
+	LastTaskID  string
+	LastError   string
+	SealedToken string
+}
+
+// ExportScheduleRun is the outcome of a run of a schedule.
+type ExportScheduleRun struct {
+	At     int64  // Occurrence the run was made for (Unix millis)
+	TaskID string // Task of the batch export, empty if the run failed
+	Error  string
+}
+
+type ExportScheduleList struct {
+	Data []*ExportSchedule
+	Next bool
+}
+
 // --- Utils ---
 
 func ExtractHeadersFromContext(ctx context.Context, keys []string) map[string]string {
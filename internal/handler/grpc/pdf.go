@@ -2,11 +2,13 @@ package grpc
 
 import (
 	"context"
+	"io"
 
 	pdfapi "github.com/webitel/media-exporter/api/pdf"
 	"github.com/webitel/media-exporter/internal/domain/model/options"
 	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
 	"github.com/webitel/media-exporter/internal/service"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -44,18 +46,63 @@ func (h *PdfHandler) CreateScreenrecordingExport(ctx context.Context, req *pdfap
 		FileIDs: req.FileIds,
 		From:    req.From,
 		To:      req.To,
+		Pdf:     convertFromProtoPdfOptions(req.Pdf),
+		Order:   convertFromProtoExportOrder(req.Sort, req.GroupBy, req.SessionGapMs),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &pdfapi.ExportTask{
-		TaskId:   metadata.TaskID,
-		FileName: metadata.FileName,
-		MimeType: metadata.MimeType,
-		Status:   mapDomainStatusToProto(metadata.Status),
-		Size:     metadata.Size,
-	}, nil
+	return convertToProtoExportTask(metadata), nil
+}
+
+func (h *PdfHandler) CreateScreenrecordingZipExport(ctx context.Context, req *pdfapi.CreateScreenrecordingRequest) (*pdfapi.ExportTask, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "agent_id is required")
+	}
+
+	opts, err := options.NewCreateOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := h.service.GenerateZipExport(ctx, opts, &domain.GenerateExportRequest{
+		AgentID: req.AgentId,
+		FileIDs: req.FileIds,
+		From:    req.From,
+		To:      req.To,
+		Order:   convertFromProtoExportOrder(req.Sort, req.GroupBy, req.SessionGapMs),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToProtoExportTask(metadata), nil
+}
+
+func (h *PdfHandler) CreateScreenrecordingVideoExport(ctx context.Context, req *pdfapi.CreateScreenrecordingVideoRequest) (*pdfapi.ExportTask, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "agent_id is required")
+	}
+
+	opts, err := options.NewCreateOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := h.service.GenerateVideoExport(ctx, opts, mapProtoVideoFormat(req.GetVideo().GetFormat()), &domain.GenerateExportRequest{
+		AgentID: req.AgentId,
+		FileIDs: req.FileIds,
+		From:    req.From,
+		To:      req.To,
+		Video:   convertFromProtoVideoOptions(req.Video),
+		Order:   convertFromProtoExportOrder(req.Sort, req.GroupBy, req.SessionGapMs),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToProtoExportTask(metadata), nil
 }
 
 func (h *PdfHandler) ListScreenrecordingExports(ctx context.Context, req *pdfapi.ListScreenrecordingHistoryRequest) (*pdfapi.ListExportsResponse, error) {
@@ -63,7 +110,12 @@ func (h *PdfHandler) ListScreenrecordingExports(ctx context.Context, req *pdfapi
 		return nil, status.Error(codes.InvalidArgument, "agent_id is required")
 	}
 
-	internalResponse, err := h.service.GetHistory(ctx, &domain.PdfHistoryRequestOptions{
+	opts, err := options.NewSearchOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	internalResponse, err := h.service.GetHistory(ctx, opts, &domain.PdfHistoryRequestOptions{
 		AgentID: req.AgentId,
 		Page:    req.Page,
 		Size:    req.Size,
@@ -93,18 +145,166 @@ func (h *PdfHandler) CreateCallExport(ctx context.Context, req *pdfapi.CreateCal
 		FileIDs: req.FileIds,
 		From:    req.From,
 		To:      req.To,
+		Pdf:     convertFromProtoPdfOptions(req.Pdf),
+		Order:   convertFromProtoExportOrder(req.Sort, req.GroupBy, req.SessionGapMs),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &pdfapi.ExportTask{
-		TaskId:   metadata.TaskID,
-		FileName: metadata.FileName,
-		MimeType: metadata.MimeType,
-		Status:   mapDomainStatusToProto(metadata.Status),
-		Size:     metadata.Size,
-	}, nil
+	return convertToProtoExportTask(metadata), nil
+}
+
+func (h *PdfHandler) CreateCallZipExport(ctx context.Context, req *pdfapi.CreateCallExportRequest) (*pdfapi.ExportTask, error) {
+	if req.CallId == "" {
+		return nil, status.Error(codes.InvalidArgument, "call_id is required")
+	}
+
+	opts, err := options.NewCreateOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := h.service.GenerateCallZipExport(ctx, opts, &domain.GenerateCallExportRequest{
+		CallID:  req.CallId,
+		FileIDs: req.FileIds,
+		From:    req.From,
+		To:      req.To,
+		Order:   convertFromProtoExportOrder(req.Sort, req.GroupBy, req.SessionGapMs),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToProtoExportTask(metadata), nil
+}
+
+func (h *PdfHandler) CreateCallVideoExport(ctx context.Context, req *pdfapi.CreateCallVideoRequest) (*pdfapi.ExportTask, error) {
+	if req.CallId == "" {
+		return nil, status.Error(codes.InvalidArgument, "call_id is required")
+	}
+
+	opts, err := options.NewCreateOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := h.service.GenerateCallVideoExport(ctx, opts, mapProtoVideoFormat(req.GetVideo().GetFormat()), &domain.GenerateCallExportRequest{
+		CallID:  req.CallId,
+		FileIDs: req.FileIds,
+		From:    req.From,
+		To:      req.To,
+		Video:   convertFromProtoVideoOptions(req.Video),
+		Order:   convertFromProtoExportOrder(req.Sort, req.GroupBy, req.SessionGapMs),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToProtoExportTask(metadata), nil
+}
+
+func (h *PdfHandler) CreateCallTranscriptExport(ctx context.Context, req *pdfapi.CreateCallTranscriptExportRequest) (*pdfapi.ExportTask, error) {
+	if req.CallId == "" {
+		return nil, status.Error(codes.InvalidArgument, "call_id is required")
+	}
+
+	opts, err := options.NewCreateOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := h.service.GenerateCallTranscriptExport(ctx, opts, &domain.GenerateCallTranscriptRequest{
+		CallID:     req.CallId,
+		FileIDs:    req.FileIds,
+		From:       req.From,
+		To:         req.To,
+		Pdf:        convertFromProtoPdfOptions(req.Pdf),
+		Transcript: convertFromProtoTranscriptOptions(req),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToProtoExportTask(metadata), nil
+}
+
+func (h *PdfHandler) CreateCallDossierExport(ctx context.Context, req *pdfapi.CreateCallDossierExportRequest) (*pdfapi.ExportTask, error) {
+	if req.CallId == "" {
+		return nil, status.Error(codes.InvalidArgument, "call_id is required")
+	}
+
+	opts, err := options.NewCreateOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := h.service.GenerateCallDossierExport(ctx, opts, &domain.GenerateCallDossierRequest{
+		CallID: req.CallId,
+		From:   req.From,
+		To:     req.To,
+		Pdf:    convertFromProtoPdfOptions(req.Pdf),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToProtoExportTask(metadata), nil
+}
+
+func (h *PdfHandler) CreateCallAudioExport(ctx context.Context, req *pdfapi.CreateCallAudioExportRequest) (*pdfapi.ExportTask, error) {
+	if len(req.CallIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "call_ids is required")
+	}
+
+	opts, err := options.NewCreateOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := h.service.GenerateCallAudioExport(ctx, opts, mapProtoAudioFormat(req.Format), &domain.GenerateCallAudioRequest{
+		CallIDs: req.CallIds,
+		FileIDs: req.FileIds,
+		From:    req.From,
+		To:      req.To,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToProtoExportTask(metadata), nil
+}
+
+func (h *PdfHandler) CreateBatchExport(ctx context.Context, req *pdfapi.CreateBatchExportRequest) (*pdfapi.BatchExportTask, error) {
+	if len(req.AgentIds) == 0 && len(req.CallIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "agent_ids or call_ids is required")
+	}
+
+	opts, err := options.NewCreateOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := h.service.GenerateBatchExport(ctx, opts, &domain.GenerateBatchExportRequest{
+		AgentIDs: req.AgentIds,
+		CallIDs:  req.CallIds,
+		From:     req.From,
+		To:       req.To,
+		Type:     mapProtoBatchExportFormat(req.Format),
+		Video:    convertFromProtoVideoOptions(req.Video),
+		Pdf:      convertFromProtoPdfOptions(req.Pdf),
+		Order:    convertFromProtoExportOrder(req.Sort, req.GroupBy, req.SessionGapMs),
+		Combine:  req.Combine,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pdfapi.BatchExportTask{Batch: convertToProtoExportTask(metadata.Batch)}
+	for _, export := range metadata.Exports {
+		res.Exports = append(res.Exports, convertToProtoExportTask(export))
+	}
+	return res, nil
 }
 
 func (h *PdfHandler) ListCallExports(ctx context.Context, req *pdfapi.ListCallHistoryRequest) (*pdfapi.ListExportsResponse, error) {
@@ -112,7 +312,12 @@ func (h *PdfHandler) ListCallExports(ctx context.Context, req *pdfapi.ListCallHi
 		return nil, status.Error(codes.InvalidArgument, "call_id is required")
 	}
 
-	internalResponse, err := h.service.GetCallHistory(ctx, &domain.CallHistoryRequestOptions{
+	opts, err := options.NewSearchOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	internalResponse, err := h.service.GetCallHistory(ctx, opts, &domain.CallHistoryRequestOptions{
 		CallID: req.CallId,
 		Page:   req.Page,
 		Size:   req.Size,
@@ -127,6 +332,142 @@ func (h *PdfHandler) ListCallExports(ctx context.Context, req *pdfapi.ListCallHi
 
 // --- General Operations ---
 
+func (h *PdfHandler) GetExport(ctx context.Context, req *pdfapi.GetExportRequest) (*pdfapi.ExportRecord, error) {
+	if req.TaskId == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+
+	opts, err := options.NewSearchOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := h.service.GetExport(ctx, opts, req.TaskId)
+	if err != nil {
+		return nil, err
+	}
+	return convertToProtoExportRecord(rec), nil
+}
+
+func (h *PdfHandler) GetExportByHistory(ctx context.Context, req *pdfapi.GetExportByHistoryRequest) (*pdfapi.ExportRecord, error) {
+	if req.Id == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	opts, err := options.NewSearchOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := h.service.GetExportByHistoryID(ctx, opts, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return convertToProtoExportRecord(rec), nil
+}
+
+func (h *PdfHandler) WatchExport(req *pdfapi.WatchExportRequest, stream grpc.ServerStreamingServer[pdfapi.ExportProgress]) error {
+	if req.TaskId == "" {
+		return status.Error(codes.InvalidArgument, "task_id is required")
+	}
+
+	opts, err := options.NewSearchOptions(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	return h.service.WatchExport(stream.Context(), opts, req.TaskId, func(event *domain.ExportProgress) error {
+		return stream.Send(convertToProtoExportProgress(event))
+	})
+}
+
+func (h *PdfHandler) CancelExport(ctx context.Context, req *pdfapi.CancelExportRequest) (*pdfapi.ExportRecord, error) {
+	if req.TaskId == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+
+	opts, err := options.NewUpdateOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := h.service.CancelExport(ctx, opts, req.TaskId)
+	if err != nil {
+		return nil, err
+	}
+	return convertToProtoExportRecord(rec), nil
+}
+
+func (h *PdfHandler) RetryExport(ctx context.Context, req *pdfapi.RetryExportRequest) (*pdfapi.ExportTask, error) {
+	if req.Id == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	opts, err := options.NewCreateOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := h.service.RetryExport(ctx, opts, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return convertToProtoExportTask(metadata), nil
+}
+
+func (h *PdfHandler) VerifyExport(stream grpc.ClientStreamingServer[pdfapi.VerifyExportRequest, pdfapi.VerifyExportResponse]) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "id is required")
+	}
+	if err != nil {
+		return err
+	}
+	if first.Id == 0 {
+		return status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	opts, err := options.NewSearchOptions(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	document := &chunkReader{chunk: first.Chunk, recv: func() ([]byte, error) {
+		req, err := stream.Recv()
+		return req.GetChunk(), err
+	}}
+	res, err := h.service.VerifyExport(stream.Context(), opts, first.Id, document)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(&pdfapi.VerifyExportResponse{
+		Valid:          res.Valid,
+		Sha256:         res.Sha256,
+		ExpectedSha256: res.ExpectedSha256,
+		Size:           res.Size,
+		SignatureValid: res.SignatureValid,
+	})
+}
+
+// chunkReader reads the chunks of a client stream as one document, until the client closes the stream.
+type chunkReader struct {
+	chunk []byte
+	recv  func() ([]byte, error)
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		chunk, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		r.chunk = chunk
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
 func (h *PdfHandler) DeleteExport(ctx context.Context, req *pdfapi.DeleteExportRequest) (*pdfapi.DeleteExportResponse, error) {
 	if req.Id == 0 {
 		return nil, status.Error(codes.InvalidArgument, "id is required")
@@ -144,6 +485,96 @@ func (h *PdfHandler) DeleteExport(ctx context.Context, req *pdfapi.DeleteExportR
 	return &pdfapi.DeleteExportResponse{Id: req.Id}, nil
 }
 
+// --- Export Schedules ---
+
+func (h *PdfHandler) CreateExportSchedule(ctx context.Context, req *pdfapi.CreateExportScheduleRequest) (*pdfapi.ExportSchedule, error) {
+	if req.Spec == nil {
+		return nil, status.Error(codes.InvalidArgument, "spec is required")
+	}
+
+	opts, err := options.NewCreateOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sched, err := h.service.CreateExportSchedule(ctx, opts, convertFromProtoExportScheduleSpec(req.Spec))
+	if err != nil {
+		return nil, err
+	}
+	return convertToProtoExportSchedule(sched), nil
+}
+
+func (h *PdfHandler) ListExportSchedules(ctx context.Context, req *pdfapi.ListExportSchedulesRequest) (*pdfapi.ListExportSchedulesResponse, error) {
+	opts, err := options.NewSearchOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := h.service.ListExportSchedules(ctx, opts, req.Page, req.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pdfapi.ListExportSchedulesResponse{Page: max(req.Page, 1), Next: list.Next}
+	for _, sched := range list.Data {
+		res.Items = append(res.Items, convertToProtoExportSchedule(sched))
+	}
+	return res, nil
+}
+
+func (h *PdfHandler) GetExportSchedule(ctx context.Context, req *pdfapi.GetExportScheduleRequest) (*pdfapi.ExportSchedule, error) {
+	if req.Id == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	opts, err := options.NewSearchOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sched, err := h.service.GetExportSchedule(ctx, opts, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return convertToProtoExportSchedule(sched), nil
+}
+
+func (h *PdfHandler) UpdateExportSchedule(ctx context.Context, req *pdfapi.UpdateExportScheduleRequest) (*pdfapi.ExportSchedule, error) {
+	if req.Id == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	if req.Spec == nil {
+		return nil, status.Error(codes.InvalidArgument, "spec is required")
+	}
+
+	opts, err := options.NewUpdateOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sched, err := h.service.UpdateExportSchedule(ctx, opts, req.Id, convertFromProtoExportScheduleSpec(req.Spec))
+	if err != nil {
+		return nil, err
+	}
+	return convertToProtoExportSchedule(sched), nil
+}
+
+func (h *PdfHandler) DeleteExportSchedule(ctx context.Context, req *pdfapi.DeleteExportScheduleRequest) (*pdfapi.DeleteExportScheduleResponse, error) {
+	if req.Id == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	opts, err := options.NewDeleteOptions(ctx, []int64{req.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.service.DeleteExportSchedule(ctx, opts, req.Id); err != nil {
+		return nil, err
+	}
+	return &pdfapi.DeleteExportScheduleResponse{Id: req.Id}, nil
+}
+
 // --- Mappers ---
 
 func mapDomainStatusToProto(status string) pdfapi.ExportStatus {
@@ -156,11 +587,164 @@ func mapDomainStatusToProto(status string) pdfapi.ExportStatus {
 		return pdfapi.ExportStatus_DONE
 	case "failed":
 		return pdfapi.ExportStatus_FAILED
+	case "cancelled":
+		return pdfapi.ExportStatus_CANCELLED
 	default:
 		return pdfapi.ExportStatus_EXPORT_STATUS_UNSPECIFIED
 	}
 }
 
+func mapProtoVideoFormat(format pdfapi.VideoFormat) string {
+	switch format {
+	case pdfapi.VideoFormat_VIDEO_FORMAT_WEBM:
+		return domain.WebmExportType
+	default:
+		return domain.Mp4ExportType
+	}
+}
+
+func mapProtoAudioFormat(format pdfapi.AudioFormat) string {
+	switch format {
+	case pdfapi.AudioFormat_AUDIO_FORMAT_MP3:
+		return domain.Mp3ExportType
+	case pdfapi.AudioFormat_AUDIO_FORMAT_WAV:
+		return domain.WavExportType
+	case pdfapi.AudioFormat_AUDIO_FORMAT_OPUS:
+		return domain.OpusExportType
+	default:
+		return domain.AudioZipExportType
+	}
+}
+
+func mapProtoBatchExportFormat(format pdfapi.BatchExportFormat) string {
+	switch format {
+	case pdfapi.BatchExportFormat_BATCH_EXPORT_FORMAT_ZIP:
+		return domain.ZipExportType
+	case pdfapi.BatchExportFormat_BATCH_EXPORT_FORMAT_MP4:
+		return domain.Mp4ExportType
+	case pdfapi.BatchExportFormat_BATCH_EXPORT_FORMAT_WEBM:
+		return domain.WebmExportType
+	default:
+		return domain.PdfExportType
+	}
+}
+
+func convertFromProtoVideoOptions(video *pdfapi.VideoOptions) *domain.VideoOptions {
+	if video == nil {
+		return nil
+	}
+	return &domain.VideoOptions{
+		FrameDurationMs:  video.FrameDurationMs,
+		TimestampOverlay: video.TimestampOverlay,
+	}
+}
+
+// convertFromProtoExportOrder returns nil if the request keeps the default order.
+func convertFromProtoExportOrder(sort pdfapi.ExportSort, groupBy pdfapi.ExportGrouping, sessionGapMs int64) *domain.ExportOrder {
+	order := &domain.ExportOrder{SessionGapMs: sessionGapMs}
+	switch sort {
+	case pdfapi.ExportSort_SORT_UPLOADED_DESC:
+		order.Sort = domain.SortUploadedDesc
+	case pdfapi.ExportSort_SORT_UPLOADED_ASC:
+		order.Sort = domain.SortUploadedAsc
+	case pdfapi.ExportSort_SORT_NAME:
+		order.Sort = domain.SortName
+	}
+	switch groupBy {
+	case pdfapi.ExportGrouping_GROUP_BY_HOUR:
+		order.GroupBy = domain.GroupByHour
+	case pdfapi.ExportGrouping_GROUP_BY_DAY:
+		order.GroupBy = domain.GroupByDay
+	case pdfapi.ExportGrouping_GROUP_BY_SESSION:
+		order.GroupBy = domain.GroupBySession
+	}
+	if *order == (domain.ExportOrder{}) {
+		return nil
+	}
+	return order
+}
+
+// convertFromProtoTranscriptOptions maps the phrase window of a transcript export, nil if not limited.
+func convertFromProtoTranscriptOptions(req *pdfapi.CreateCallTranscriptExportRequest) *domain.TranscriptOptions {
+	if req.WindowFromMs == 0 && req.WindowToMs == 0 {
+		return nil
+	}
+	return &domain.TranscriptOptions{
+		WindowFromMs: req.WindowFromMs,
+		WindowToMs:   req.WindowToMs,
+	}
+}
+
+func convertFromProtoPdfOptions(pdf *pdfapi.PdfOptions) *domain.PdfOptions {
+	if pdf == nil {
+		return nil
+	}
+	return &domain.PdfOptions{
+		Captions:     pdf.Captions,
+		HeaderFooter: pdf.HeaderFooter,
+		Timezone:     pdf.Timezone,
+		Title:        pdf.Title,
+		CoverPage:    pdf.CoverPage,
+		Contents:     mapProtoPdfContents(pdf.Contents),
+
+		PageSize:      mapProtoPdfPageSize(pdf.PageSize),
+		Orientation:   mapProtoPdfOrientation(pdf.Orientation),
+		ImagesPerPage: int(pdf.ImagesPerPage),
+		ContactSheet:  pdf.ContactSheet,
+		Watermark:     pdf.Watermark,
+		Encryption:    convertFromProtoPdfEncryption(pdf.Encryption),
+
+		SignedManifest: pdf.SignedManifest,
+	}
+}
+
+// convertFromProtoPdfEncryption maps the password protection of a PDF export, nil if not requested.
+func convertFromProtoPdfEncryption(enc *pdfapi.PdfEncryption) *domain.PdfEncryption {
+	if enc == nil {
+		return nil
+	}
+	return &domain.PdfEncryption{Password: enc.Password}
+}
+
+func mapProtoPdfPageSize(size pdfapi.PdfPageSize) string {
+	switch size {
+	case pdfapi.PdfPageSize_PDF_PAGE_SIZE_LETTER:
+		return domain.PageSizeLetter
+	default:
+		return domain.PageSizeA4
+	}
+}
+
+func mapProtoPdfOrientation(orientation pdfapi.PdfOrientation) string {
+	switch orientation {
+	case pdfapi.PdfOrientation_PDF_ORIENTATION_LANDSCAPE:
+		return domain.OrientationLandscape
+	default:
+		return domain.OrientationPortrait
+	}
+}
+
+func mapProtoPdfContents(contents pdfapi.PdfContents) string {
+	switch contents {
+	case pdfapi.PdfContents_PDF_CONTENTS_HOUR:
+		return domain.ContentsByHour
+	case pdfapi.PdfContents_PDF_CONTENTS_DAY:
+		return domain.ContentsByDay
+	default:
+		return domain.ContentsNone
+	}
+}
+
+func convertToProtoExportTask(metadata *domain.PdfExportMetadata) *pdfapi.ExportTask {
+	return &pdfapi.ExportTask{
+		TaskId:   metadata.TaskID,
+		FileName: metadata.FileName,
+		MimeType: metadata.MimeType,
+		Status:   mapDomainStatusToProto(metadata.Status),
+		Size:     metadata.Size,
+	}
+}
+
 func convertToProtoHistoryResponse(internal *domain.HistoryResponse, limit, page int64) *pdfapi.ListExportsResponse {
 	if internal == nil {
 		return &pdfapi.ListExportsResponse{}
@@ -168,17 +752,7 @@ func convertToProtoHistoryResponse(internal *domain.HistoryResponse, limit, page
 
 	protoRecords := make([]*pdfapi.ExportRecord, len(internal.Data))
 	for i, rec := range internal.Data {
-		protoRecords[i] = &pdfapi.ExportRecord{
-			Id:        rec.ID,
-			Name:      rec.Name,
-			FileId:    rec.FileID,
-			MimeType:  rec.MimeType,
-			CreatedAt: rec.CreatedAt,
-			UpdatedAt: rec.UpdatedAt,
-			CreatedBy: rec.CreatedBy,
-			UpdatedBy: rec.UpdatedBy,
-			Status:    mapDomainStatusToProto(rec.Status),
-		}
+		protoRecords[i] = convertToProtoExportRecord(rec)
 	}
 	hasNext := internal.Next
 
@@ -188,3 +762,297 @@ func convertToProtoHistoryResponse(internal *domain.HistoryResponse, limit, page
 		Items: protoRecords,
 	}
 }
+
+func convertToProtoExportRecord(rec *domain.HistoryRecord) *pdfapi.ExportRecord {
+	return &pdfapi.ExportRecord{
+		Id:        rec.ID,
+		Name:      rec.Name,
+		FileId:    rec.FileID,
+		MimeType:  rec.MimeType,
+		CreatedAt: rec.CreatedAt,
+		UpdatedAt: rec.UpdatedAt,
+		CreatedBy: rec.CreatedBy,
+		UpdatedBy: rec.UpdatedBy,
+		Status:    mapDomainStatusToProto(rec.Status),
+		Attempts:  int32(rec.Attempts),
+		LastError: rec.LastError,
+		TaskId:    rec.TaskID,
+		Size:      rec.Size,
+		RetryOf:   rec.RetryOf,
+		TraceHash: rec.TraceHash,
+		Password:  rec.Password,
+		Sha256:    rec.Digest,
+		ParentId:  rec.ParentID,
+	}
+}
+
+func mapDomainStageToProto(stage string) pdfapi.ExportStage {
+	switch stage {
+	case domain.StageQueued:
+		return pdfapi.ExportStage_STAGE_QUEUED
+	case domain.StageSearching:
+		return pdfapi.ExportStage_STAGE_SEARCHING
+	case domain.StageDownloading:
+		return pdfapi.ExportStage_STAGE_DOWNLOADING
+	case domain.StageRendering:
+		return pdfapi.ExportStage_STAGE_RENDERING
+	case domain.StageUploading:
+		return pdfapi.ExportStage_STAGE_UPLOADING
+	case domain.StageCompleted:
+		return pdfapi.ExportStage_STAGE_COMPLETED
+	case domain.StageWaiting:
+		return pdfapi.ExportStage_STAGE_WAITING
+	default:
+		return pdfapi.ExportStage_EXPORT_STAGE_UNSPECIFIED
+	}
+}
+
+func convertToProtoExportProgress(event *domain.ExportProgress) *pdfapi.ExportProgress {
+	return &pdfapi.ExportProgress{
+		TaskId:    event.TaskID,
+		Status:    mapDomainStatusToProto(event.Status),
+		Stage:     mapDomainStageToProto(event.Stage),
+		Current:   event.Current,
+		Total:     event.Total,
+		FileId:    event.FileID,
+		Error:     event.Error,
+		Timestamp: event.Timestamp,
+	}
+}
+
+func convertFromProtoExportScheduleSpec(spec *pdfapi.ExportScheduleSpec) *domain.ExportScheduleSpec {
+	return &domain.ExportScheduleSpec{
+		Name:     spec.Name,
+		Enabled:  spec.Enabled,
+		Cron:     spec.Cron,
+		Timezone: spec.Timezone,
+		CatchUp:  mapProtoScheduleCatchUp(spec.CatchUp),
+		Params: domain.ScheduleParams{
+			Range: domain.ScheduleRange{
+				Unit:    mapProtoScheduleRangeUnit(spec.GetRange().GetUnit()),
+				Count:   int(spec.GetRange().GetCount()),
+				Rolling: spec.GetRange().GetRolling(),
+			},
+			AgentIDs: spec.AgentIds,
+			TeamIDs:  spec.TeamIds,
+			Type:     mapProtoBatchExportFormat(spec.Format),
+			Video:    convertFromProtoVideoOptions(spec.Video),
+			Pdf:      convertFromProtoPdfOptions(spec.Pdf),
+			Order:    convertFromProtoExportOrder(spec.Sort, spec.GroupBy, spec.SessionGapMs),
+			Combine:  spec.Combine,
+		},
+	}
+}
+
+func mapProtoScheduleRangeUnit(unit pdfapi.ScheduleRangeUnit) string {
+	switch unit {
+	case pdfapi.ScheduleRangeUnit_SCHEDULE_RANGE_HOUR:
+		return domain.RangeHour
+	case pdfapi.ScheduleRangeUnit_SCHEDULE_RANGE_WEEK:
+		return domain.RangeWeek
+	case pdfapi.ScheduleRangeUnit_SCHEDULE_RANGE_MONTH:
+		return domain.RangeMonth
+	default:
+		return domain.RangeDay
+	}
+}
+
+func mapProtoScheduleCatchUp(catchUp pdfapi.ScheduleCatchUp) string {
+	switch catchUp {
+	case pdfapi.ScheduleCatchUp_SCHEDULE_CATCH_UP_SKIP:
+		return domain.CatchUpSkip
+	case pdfapi.ScheduleCatchUp_SCHEDULE_CATCH_UP_ALL:
+		return domain.CatchUpAll
+	default:
+		return domain.CatchUpLatest
+	}
+}
+
+// convertToProtoExportSchedule maps a stored schedule. The sealed access token of its owner is never returned.
+func convertToProtoExportSchedule(sched *domain.ExportSchedule) *pdfapi.ExportSchedule {
+	return &pdfapi.ExportSchedule{
+		Id:         sched.ID,
+		Spec:       convertToProtoExportScheduleSpec(&sched.Spec),
+		CreatedAt:  sched.CreatedAt,
+		UpdatedAt:  sched.UpdatedAt,
+		CreatedBy:  sched.CreatedBy,
+		UpdatedBy:  sched.UpdatedBy,
+		NextRunAt:  sched.NextRunAt,
+		LastRunAt:  sched.LastRunAt,
+		LastTaskId: sched.LastTaskID,
+		LastError:  sched.LastError,
+	}
+}
+
+func convertToProtoExportScheduleSpec(spec *domain.ExportScheduleSpec) *pdfapi.ExportScheduleSpec {
+	params := spec.Params
+	res := &pdfapi.ExportScheduleSpec{
+		Name:     spec.Name,
+		Enabled:  spec.Enabled,
+		Cron:     spec.Cron,
+		Timezone: spec.Timezone,
+		Range: &pdfapi.ScheduleRange{
+			Unit:    mapDomainScheduleRangeUnitToProto(params.Range.Unit),
+			Count:   int32(params.Range.Count),
+			Rolling: params.Range.Rolling,
+		},
+		CatchUp:  mapDomainScheduleCatchUpToProto(spec.CatchUp),
+		AgentIds: params.AgentIDs,
+		TeamIds:  params.TeamIDs,
+		Format:   mapDomainBatchExportFormatToProto(params.Type),
+		Pdf:      convertToProtoPdfOptions(params.Pdf),
+		Combine:  params.Combine,
+	}
+	if params.Video != nil {
+		res.Video = &pdfapi.VideoOptions{
+			Format:           mapDomainVideoFormatToProto(params.Type),
+			FrameDurationMs:  params.Video.FrameDurationMs,
+			TimestampOverlay: params.Video.TimestampOverlay,
+		}
+	}
+	if params.Order != nil {
+		res.Sort = mapDomainSortToProto(params.Order.Sort)
+		res.GroupBy = mapDomainGroupByToProto(params.Order.GroupBy)
+		res.SessionGapMs = params.Order.SessionGapMs
+	}
+	return res
+}
+
+func mapDomainScheduleRangeUnitToProto(unit string) pdfapi.ScheduleRangeUnit {
+	switch unit {
+	case domain.RangeHour:
+		return pdfapi.ScheduleRangeUnit_SCHEDULE_RANGE_HOUR
+	case domain.RangeDay:
+		return pdfapi.ScheduleRangeUnit_SCHEDULE_RANGE_DAY
+	case domain.RangeWeek:
+		return pdfapi.ScheduleRangeUnit_SCHEDULE_RANGE_WEEK
+	case domain.RangeMonth:
+		return pdfapi.ScheduleRangeUnit_SCHEDULE_RANGE_MONTH
+	default:
+		return pdfapi.ScheduleRangeUnit_SCHEDULE_RANGE_UNIT_UNSPECIFIED
+	}
+}
+
+func mapDomainScheduleCatchUpToProto(catchUp string) pdfapi.ScheduleCatchUp {
+	switch catchUp {
+	case domain.CatchUpSkip:
+		return pdfapi.ScheduleCatchUp_SCHEDULE_CATCH_UP_SKIP
+	case domain.CatchUpLatest:
+		return pdfapi.ScheduleCatchUp_SCHEDULE_CATCH_UP_LATEST
+	case domain.CatchUpAll:
+		return pdfapi.ScheduleCatchUp_SCHEDULE_CATCH_UP_ALL
+	default:
+		return pdfapi.ScheduleCatchUp_SCHEDULE_CATCH_UP_UNSPECIFIED
+	}
+}
+
+func mapDomainBatchExportFormatToProto(exportType string) pdfapi.BatchExportFormat {
+	switch exportType {
+	case domain.PdfExportType:
+		return pdfapi.BatchExportFormat_BATCH_EXPORT_FORMAT_PDF
+	case domain.ZipExportType:
+		return pdfapi.BatchExportFormat_BATCH_EXPORT_FORMAT_ZIP
+	case domain.Mp4ExportType:
+		return pdfapi.BatchExportFormat_BATCH_EXPORT_FORMAT_MP4
+	case domain.WebmExportType:
+		return pdfapi.BatchExportFormat_BATCH_EXPORT_FORMAT_WEBM
+	default:
+		return pdfapi.BatchExportFormat_BATCH_EXPORT_FORMAT_UNSPECIFIED
+	}
+}
+
+func mapDomainVideoFormatToProto(exportType string) pdfapi.VideoFormat {
+	switch exportType {
+	case domain.Mp4ExportType:
+		return pdfapi.VideoFormat_VIDEO_FORMAT_MP4
+	case domain.WebmExportType:
+		return pdfapi.VideoFormat_VIDEO_FORMAT_WEBM
+	default:
+		return pdfapi.VideoFormat_VIDEO_FORMAT_UNSPECIFIED
+	}
+}
+
+func mapDomainSortToProto(sort string) pdfapi.ExportSort {
+	switch sort {
+	case domain.SortUploadedDesc:
+		return pdfapi.ExportSort_SORT_UPLOADED_DESC
+	case domain.SortUploadedAsc:
+		return pdfapi.ExportSort_SORT_UPLOADED_ASC
+	case domain.SortName:
+		return pdfapi.ExportSort_SORT_NAME
+	default:
+		return pdfapi.ExportSort_EXPORT_SORT_UNSPECIFIED
+	}
+}
+
+func mapDomainGroupByToProto(groupBy string) pdfapi.ExportGrouping {
+	switch groupBy {
+	case domain.GroupByHour:
+		return pdfapi.ExportGrouping_GROUP_BY_HOUR
+	case domain.GroupByDay:
+		return pdfapi.ExportGrouping_GROUP_BY_DAY
+	case domain.GroupBySession:
+		return pdfapi.ExportGrouping_GROUP_BY_SESSION
+	default:
+		return pdfapi.ExportGrouping_GROUP_BY_NONE
+	}
+}
+
+// convertToProtoPdfOptions maps stored PDF options. The password of an encryption is never stored,
+// so an encryption is returned without one.
+func convertToProtoPdfOptions(pdf *domain.PdfOptions) *pdfapi.PdfOptions {
+	if pdf == nil {
+		return nil
+	}
+	res := &pdfapi.PdfOptions{
+		Captions:       pdf.Captions,
+		HeaderFooter:   pdf.HeaderFooter,
+		Timezone:       pdf.Timezone,
+		Title:          pdf.Title,
+		CoverPage:      pdf.CoverPage,
+		Contents:       mapDomainPdfContentsToProto(pdf.Contents),
+		PageSize:       mapDomainPdfPageSizeToProto(pdf.PageSize),
+		Orientation:    mapDomainPdfOrientationToProto(pdf.Orientation),
+		ImagesPerPage:  int32(pdf.ImagesPerPage),
+		ContactSheet:   pdf.ContactSheet,
+		Watermark:      pdf.Watermark,
+		SignedManifest: pdf.SignedManifest,
+	}
+	if pdf.Encryption != nil {
+		res.Encryption = &pdfapi.PdfEncryption{}
+	}
+	return res
+}
+
+func mapDomainPdfContentsToProto(contents string) pdfapi.PdfContents {
+	switch contents {
+	case domain.ContentsByHour:
+		return pdfapi.PdfContents_PDF_CONTENTS_HOUR
+	case domain.ContentsByDay:
+		return pdfapi.PdfContents_PDF_CONTENTS_DAY
+	default:
+		return pdfapi.PdfContents_PDF_CONTENTS_UNSPECIFIED
+	}
+}
+
+func mapDomainPdfPageSizeToProto(size string) pdfapi.PdfPageSize {
+	switch size {
+	case domain.PageSizeLetter:
+		return pdfapi.PdfPageSize_PDF_PAGE_SIZE_LETTER
+	case domain.PageSizeA4:
+		return pdfapi.PdfPageSize_PDF_PAGE_SIZE_A4
+	default:
+		return pdfapi.PdfPageSize_PDF_PAGE_SIZE_UNSPECIFIED
+	}
+}
+
+func mapDomainPdfOrientationToProto(orientation string) pdfapi.PdfOrientation {
+	switch orientation {
+	case domain.OrientationLandscape:
+		return pdfapi.PdfOrientation_PDF_ORIENTATION_LANDSCAPE
+	case domain.OrientationPortrait:
+		return pdfapi.PdfOrientation_PDF_ORIENTATION_PORTRAIT
+	default:
+		return pdfapi.PdfOrientation_PDF_ORIENTATION_UNSPECIFIED
+	}
+}
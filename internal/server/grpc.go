@@ -37,8 +37,15 @@ func BuildServer(config *conf.ConsulConfig, authManager auth.Manager, exitChan c
 		grpc.ChainUnaryInterceptor(
 			interceptor.OuterInterceptor(),
 			interceptor.AuthUnaryServerInterceptor(authManager),
+			interceptor.AuthorizeUnaryServerInterceptor(),
 			interceptor.ValidateUnaryServerInterceptor(val),
 		),
+		grpc.ChainStreamInterceptor(
+			interceptor.OuterStreamInterceptor(),
+			interceptor.AuthStreamServerInterceptor(authManager),
+			interceptor.AuthorizeStreamServerInterceptor(),
+			interceptor.ValidateStreamServerInterceptor(val),
+		),
 	)
 
 	// Open a TCP listener on the configured address
@@ -36,3 +36,33 @@ func AuthUnaryServerInterceptor(authManager auth.Manager) grpc.UnaryServerInterc
 		return resp, nil
 	}
 }
+
+// AuthStreamServerInterceptor authenticates and authorizes streaming RPCs.
+func AuthStreamServerInterceptor(authManager auth.Manager) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		session, err := authManager.AuthorizeFromContext(ss.Context())
+		if err != nil {
+			return errors.New(
+				"unauthorized",
+				errors.WithCause(err),
+				errors.WithCode(codes.Unauthenticated),
+				errors.WithID("auth.interceptor.unauthorized"),
+			)
+		}
+
+		return handler(srv, &wrappedServerStream{
+			ServerStream: ss,
+			ctx:          context.WithValue(ss.Context(), SessionHeader, session),
+		})
+	}
+}
+
+// wrappedServerStream overrides the context of a grpc.ServerStream.
+type wrappedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedServerStream) Context() context.Context {
+	return w.ctx
+}
@@ -0,0 +1,101 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	pdfapi "github.com/webitel/media-exporter/api/pdf"
+	"github.com/webitel/media-exporter/auth"
+	"github.com/webitel/media-exporter/internal/domain/model"
+	"github.com/webitel/media-exporter/internal/errors"
+	"google.golang.org/grpc"
+)
+
+// AuthorizeUnaryServerInterceptor checks the object class access the method requires.
+// It must be chained after the authentication interceptor.
+func AuthorizeUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := authorizeMethod(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthorizeStreamServerInterceptor checks the object class access the method requires.
+// It must be chained after the authentication interceptor.
+func AuthorizeStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorizeMethod(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func authorizeMethod(ctx context.Context, fullMethod string) error {
+	objClass, mode, ok := methodAccess(fullMethod)
+	if !ok {
+		return nil
+	}
+
+	session, ok := ctx.Value(SessionHeader).(auth.Auther)
+	if !ok || session == nil {
+		return errors.Forbidden(
+			"session is missing",
+			errors.WithID("authz.interceptor.session"),
+		)
+	}
+
+	if !session.CheckObacAccess(objClass, mode) {
+		return errors.Forbidden(
+			"access denied to "+objClass,
+			errors.WithID("authz.interceptor.denied"),
+		)
+	}
+
+	return nil
+}
+
+// methodPermissions is the access to model.ExportObjClass each method of the PdfService requires.
+// The generated pdfapi.WebitelAPI carries no permissions, so they are declared here.
+var methodPermissions = map[string]auth.AccessMode{
+	pdfapi.PdfService_CreateScreenrecordingExport_FullMethodName:      auth.Add,
+	pdfapi.PdfService_ListScreenrecordingExports_FullMethodName:       auth.Read,
+	pdfapi.PdfService_CreateCallExport_FullMethodName:                 auth.Add,
+	pdfapi.PdfService_ListCallExports_FullMethodName:                  auth.Read,
+	pdfapi.PdfService_CreateScreenrecordingZipExport_FullMethodName:   auth.Add,
+	pdfapi.PdfService_CreateCallZipExport_FullMethodName:              auth.Add,
+	pdfapi.PdfService_CreateCallTranscriptExport_FullMethodName:       auth.Add,
+	pdfapi.PdfService_CreateCallDossierExport_FullMethodName:          auth.Add,
+	pdfapi.PdfService_CreateCallAudioExport_FullMethodName:            auth.Add,
+	pdfapi.PdfService_CreateBatchExport_FullMethodName:                auth.Add,
+	pdfapi.PdfService_CreateScreenrecordingVideoExport_FullMethodName: auth.Add,
+	pdfapi.PdfService_CreateCallVideoExport_FullMethodName:            auth.Add,
+	pdfapi.PdfService_GetExport_FullMethodName:                        auth.Read,
+	pdfapi.PdfService_GetExportByHistory_FullMethodName:               auth.Read,
+	pdfapi.PdfService_WatchExport_FullMethodName:                      auth.Read,
+	pdfapi.PdfService_CancelExport_FullMethodName:                     auth.Edit,
+	pdfapi.PdfService_RetryExport_FullMethodName:                      auth.Add,
+	pdfapi.PdfService_VerifyExport_FullMethodName:                     auth.Read,
+	pdfapi.PdfService_DeleteExport_FullMethodName:                     auth.Delete,
+	pdfapi.PdfService_CreateExportSchedule_FullMethodName:             auth.Add,
+	pdfapi.PdfService_ListExportSchedules_FullMethodName:              auth.Read,
+	pdfapi.PdfService_GetExportSchedule_FullMethodName:                auth.Read,
+	pdfapi.PdfService_UpdateExportSchedule_FullMethodName:             auth.Edit,
+	pdfapi.PdfService_DeleteExportSchedule_FullMethodName:             auth.Delete,
+}
+
+// methodAccess resolves "/package.Service/Method" to the object class and the access mode the method
+// requires. Methods of other services are not checked, methods of the PdfService missing from
+// methodPermissions require full access.
+func methodAccess(fullMethod string) (string, auth.AccessMode, bool) {
+	if !strings.HasPrefix(fullMethod, "/"+pdfapi.PdfService_ServiceDesc.ServiceName+"/") {
+		return "", auth.NONE, false
+	}
+	mode, ok := methodPermissions[fullMethod]
+	if !ok {
+		return model.ExportObjClass, auth.FULL, true
+	}
+	return model.ExportObjClass, mode, true
+}
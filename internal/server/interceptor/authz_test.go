@@ -0,0 +1,90 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	pdfapi "github.com/webitel/media-exporter/api/pdf"
+	"github.com/webitel/media-exporter/auth"
+	"github.com/webitel/media-exporter/internal/errors"
+	"google.golang.org/grpc/codes"
+)
+
+type fakeSession struct {
+	auth.Auther
+	granted auth.AccessMode
+}
+
+func (f *fakeSession) CheckObacAccess(_ string, mode auth.AccessMode) bool {
+	return f.granted&mode == mode
+}
+
+func TestMethodAccess(t *testing.T) {
+	tests := []struct {
+		method string
+		want   auth.AccessMode
+	}{
+		{"/webitel_media_exporter.PdfService/CreateScreenrecordingExport", auth.Add},
+		{"/webitel_media_exporter.PdfService/CreateCallZipExport", auth.Add},
+		{"/webitel_media_exporter.PdfService/RetryExport", auth.Add},
+		{"/webitel_media_exporter.PdfService/ListCallExports", auth.Read},
+		{"/webitel_media_exporter.PdfService/WatchExport", auth.Read},
+		{"/webitel_media_exporter.PdfService/CancelExport", auth.Edit},
+		{"/webitel_media_exporter.PdfService/DeleteExport", auth.Delete},
+		{"/webitel_media_exporter.PdfService/CreateExportSchedule", auth.Add},
+		{"/webitel_media_exporter.PdfService/ListExportSchedules", auth.Read},
+		{"/webitel_media_exporter.PdfService/UpdateExportSchedule", auth.Edit},
+		{"/webitel_media_exporter.PdfService/DeleteExportSchedule", auth.Delete},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			objClass, mode, ok := methodAccess(tt.method)
+			if !ok {
+				t.Fatalf("method is not mapped")
+			}
+			if objClass != "media_exports" {
+				t.Errorf("objClass = %q, want media_exports", objClass)
+			}
+			if mode != tt.want {
+				t.Errorf("mode = %d, want %d", mode, tt.want)
+			}
+		})
+	}
+
+	if _, _, ok := methodAccess("/grpc.health.v1.Health/Check"); ok {
+		t.Errorf("methods of other services must not be checked")
+	}
+}
+
+// Every method of the PdfService must declare the access it requires.
+func TestEveryMethodHasPermission(t *testing.T) {
+	desc := pdfapi.PdfService_ServiceDesc
+	var methods []string
+	for _, m := range desc.Methods {
+		methods = append(methods, m.MethodName)
+	}
+	for _, s := range desc.Streams {
+		methods = append(methods, s.StreamName)
+	}
+	for _, m := range methods {
+		if _, ok := methodPermissions["/"+desc.ServiceName+"/"+m]; !ok {
+			t.Errorf("method %s has no permission", m)
+		}
+	}
+}
+
+func TestAuthorizeMethod(t *testing.T) {
+	const method = "/webitel_media_exporter.PdfService/CreateCallExport"
+
+	readOnly := context.WithValue(context.Background(), SessionHeader, auth.Auther(&fakeSession{granted: auth.Read}))
+	err := authorizeMethod(readOnly, method)
+	if errors.Code(err) != codes.PermissionDenied {
+		t.Errorf("read-only session: code = %v, want PermissionDenied", errors.Code(err))
+	}
+
+	creator := context.WithValue(context.Background(), SessionHeader, auth.Auther(&fakeSession{granted: auth.Read | auth.Add}))
+	if err := authorizeMethod(creator, method); err != nil {
+		t.Errorf("session with create access: err = %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/webitel/media-exporter/auth"
+	"github.com/webitel/media-exporter/internal/domain/model/options"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/errors"
+)
+
+// Object classes of the sources an export is built from.
+const (
+	agentObjClass = "cc_agent"
+	callObjClass  = "calls"
+)
+
+// authorizeSource checks that the caller may read the recordings an export is built from:
+// the screen recordings of the agent or the files of the call.
+// Access to the exports themselves is checked by the authorization interceptor.
+func (s *PdfServiceImpl) authorizeSource(opts *options.SearchOptions, agentID int64, callID string) error {
+	if agentID != 0 {
+		if err := s.authorizeAgent(opts, agentID); err != nil {
+			return err
+		}
+	}
+	if callID != "" {
+		if err := s.authorizeCall(opts, callID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PdfServiceImpl) authorizeAgent(opts *options.SearchOptions, agentID int64) error {
+	denied := errors.Forbidden(
+		fmt.Sprintf("access denied to agent %d", agentID),
+		errors.WithID("service.pdf.access.agent"),
+	)
+
+	if !opts.Auth.CheckObacAccess(agentObjClass, auth.Read) {
+		return denied
+	}
+	if !opts.Auth.IsRbacCheckRequired(agentObjClass, auth.Read) {
+		return nil
+	}
+
+	allowed, err := s.store.CheckAgentAccess(opts, agentID, auth.Read)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return denied
+	}
+	return nil
+}
+
+func (s *PdfServiceImpl) authorizeCall(opts *options.SearchOptions, callID string) error {
+	denied := errors.Forbidden(
+		fmt.Sprintf("access denied to call %s", callID),
+		errors.WithID("service.pdf.access.call"),
+	)
+
+	if !opts.Auth.CheckObacAccess(callObjClass, auth.Read) {
+		return denied
+	}
+	if !opts.Auth.IsRbacCheckRequired(callObjClass, auth.Read) {
+		return nil
+	}
+
+	allowed, err := s.store.CheckCallAccess(opts, callID, auth.Read)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return denied
+	}
+	return nil
+}
+
+// authorizeRecord checks access to the source of an existing export,
+// to all the agents or calls of a batch export.
+func (s *PdfServiceImpl) authorizeRecord(opts *options.SearchOptions, rec *domain.HistoryRecord) error {
+	if rec.Params != nil && rec.Params.Batch != nil {
+		return s.authorizeBatch(opts, rec.Params.Batch.AgentIDs, rec.Params.Batch.CallIDs)
+	}
+	if rec.Params != nil {
+		return s.authorizeParams(opts, *rec.Params)
+	}
+	return s.authorizeSource(opts, rec.AgentID, rec.CallID)
+}
+
+// authorizeParams checks access to the source of an export: its agent or call,
+// or every call of an export of several calls.
+func (s *PdfServiceImpl) authorizeParams(opts *options.SearchOptions, params domain.ExportParams) error {
+	if len(params.CallIDs) > 0 {
+		return s.authorizeBatch(opts, nil, params.CallIDs)
+	}
+	return s.authorizeSource(opts, params.AgentID, params.CallID)
+}
+
+// authorizeBatch checks access to every agent and call of a batch export.
+func (s *PdfServiceImpl) authorizeBatch(opts *options.SearchOptions, agentIDs []int64, callIDs []string) error {
+	for _, id := range agentIDs {
+		if err := s.authorizeSource(opts, id, ""); err != nil {
+			return err
+		}
+	}
+	for _, id := range callIDs {
+		if err := s.authorizeSource(opts, 0, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
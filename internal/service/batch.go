@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/webitel/media-exporter/internal/domain/model/options"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/errors"
+)
+
+// maxBatchExports bounds the agents or calls of a batch export.
+const maxBatchExports = 100
+
+// GenerateBatchExport records one export per agent or call of the request and a batch export
+// that tracks them. The batch is enqueued after all its exports, so its worker always finds them.
+func (s *PdfServiceImpl) GenerateBatchExport(ctx context.Context, opts *options.CreateOptions, req *domain.GenerateBatchExportRequest) (*domain.BatchExportMetadata, error) {
+	agentIDs, callIDs, err := validateBatchExport(req)
+	if err != nil {
+		return nil, err
+	}
+	// Every source is checked before anything is recorded, so that a forbidden one
+	// does not leave a batch with only some of its exports behind.
+	if err := s.authorizeBatch(lookupOptions(opts, opts.Time, opts.Auth), agentIDs, callIDs); err != nil {
+		return nil, err
+	}
+
+	channel := string(domain.ChannelScreenRecording)
+	if len(callIDs) > 0 {
+		channel = string(domain.ChannelCall)
+	}
+	batch, batchMetadata, err := s.recordExportTask(ctx, opts, domain.ExportParams{
+		Type:    domain.BatchExportType,
+		Channel: channel,
+		From:    req.From,
+		To:      req.To,
+		Batch: &domain.BatchOptions{
+			Type:     req.Type,
+			AgentIDs: agentIDs,
+			CallIDs:  callIDs,
+			Combine:  req.Combine,
+		},
+	}, exportLink{})
+	if err != nil {
+		return nil, err
+	}
+
+	params := domain.ExportParams{
+		Type:    req.Type,
+		Channel: channel,
+		From:    req.From,
+		To:      req.To,
+		Video:   req.Video,
+		Pdf:     req.Pdf,
+		Order:   req.Order,
+	}
+	res := &domain.BatchExportMetadata{Batch: batchMetadata}
+	for i := range len(agentIDs) + len(callIDs) {
+		if i < len(agentIDs) {
+			params.AgentID = agentIDs[i]
+		} else {
+			params.CallID = callIDs[i-len(agentIDs)]
+		}
+		task, metadata, err := s.recordExportTask(ctx, opts, params, exportLink{parentID: batch.HistoryID, seq: i + 1})
+		if err == nil {
+			err = s.enqueueExportTask(ctx, task)
+		}
+		if err != nil {
+			s.failBatch(opts, batch, err)
+			return nil, err
+		}
+		res.Exports = append(res.Exports, metadata)
+	}
+
+	if err := s.enqueueExportTask(ctx, batch); err != nil {
+		s.failBatch(opts, batch, err)
+		return nil, err
+	}
+
+	s.log.InfoContext(ctx, "created batch export", "taskID", batch.TaskID, "exports", len(res.Exports), "type", req.Type)
+	return res, nil
+}
+
+// failBatch records why a batch export could not be created. The exports already enqueued
+// for it run on their own.
+func (s *PdfServiceImpl) failBatch(opts *options.CreateOptions, batch *domain.ExportTask, cause error) {
+	err := s.store.UpdatePdfExportStatus(&options.UpdateOptions{Context: opts, Time: opts.Time, Auth: opts.Auth}, &domain.UpdateExportStatus{
+		ID:        batch.HistoryID,
+		Status:    "failed",
+		UpdatedBy: opts.Auth.GetUserId(),
+		LastError: cause.Error(),
+	})
+	if err != nil {
+		s.log.ErrorContext(opts, "failed to record failed batch export", "taskID", batch.TaskID, "error", err)
+	}
+}
+
+// cancelBatchExports cancels the exports of a batch export that are not finished yet.
+// An export that cannot be cancelled is logged and left running.
+func (s *PdfServiceImpl) cancelBatchExports(ctx context.Context, opts *options.UpdateOptions, rec *domain.HistoryRecord) error {
+	children, err := s.store.ListChildExports(lookupOptions(opts, opts.Time, opts.Auth), rec.ID)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if domain.IsFinalStatus(child.Status) {
+			continue
+		}
+		if _, err := s.CancelExport(ctx, opts, child.TaskID); err != nil {
+			s.log.WarnContext(ctx, "failed to cancel export of batch", "taskID", child.TaskID, "batch", rec.TaskID, "error", err)
+		}
+	}
+	return nil
+}
+
+// validateBatchExport checks the sources, the type and the options of a batch export
+// and returns the agents and calls without repetitions.
+func validateBatchExport(req *domain.GenerateBatchExportRequest) ([]int64, []string, error) {
+	if len(req.AgentIDs) > 0 && len(req.CallIDs) > 0 {
+		return nil, nil, errors.BadRequest("agent_ids and call_ids cannot be combined")
+	}
+
+	var agentIDs []int64
+	for _, id := range req.AgentIDs {
+		if id == 0 {
+			return nil, nil, errors.BadRequest("agent_ids must not contain 0")
+		}
+		if !slices.Contains(agentIDs, id) {
+			agentIDs = append(agentIDs, id)
+		}
+	}
+	var callIDs []string
+	for _, id := range req.CallIDs {
+		if id == "" {
+			return nil, nil, errors.BadRequest("call_ids must not contain empty IDs")
+		}
+		if !slices.Contains(callIDs, id) {
+			callIDs = append(callIDs, id)
+		}
+	}
+	switch n := len(agentIDs) + len(callIDs); {
+	case n == 0:
+		return nil, nil, errors.BadRequest("agent_ids or call_ids is required")
+	case n > maxBatchExports:
+		return nil, nil, errors.BadRequest(fmt.Sprintf("a batch export can have at most %d agents or calls", maxBatchExports))
+	}
+
+	if err := validateBatchType(req.Type, req.Pdf, req.Video, req.Order); err != nil {
+		return nil, nil, err
+	}
+	return agentIDs, callIDs, nil
+}
+
+// validateBatchType checks the type of the exports of a batch export and their options.
+func validateBatchType(exportType string, pdf *domain.PdfOptions, video *domain.VideoOptions, order *domain.ExportOrder) error {
+	switch exportType {
+	case domain.PdfExportType:
+		if err := validatePdfExport(pdf); err != nil {
+			return err
+		}
+	case domain.ZipExportType:
+	case domain.Mp4ExportType, domain.WebmExportType:
+		if err := validateVideoExport(exportType, video); err != nil {
+			return err
+		}
+	default:
+		return errors.BadRequest(fmt.Sprintf("unsupported batch export type: %s", exportType))
+	}
+	return validateExportOrder(order)
+}
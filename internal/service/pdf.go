@@ -2,42 +2,126 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"log/slog"
+	"slices"
 	"time"
+	"unicode/utf8"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/webitel/media-exporter/auth"
 	"github.com/webitel/media-exporter/internal/cache"
 	"github.com/webitel/media-exporter/internal/domain/model/options"
 	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
 	"github.com/webitel/media-exporter/internal/errors"
 	"github.com/webitel/media-exporter/internal/store"
+	"github.com/webitel/media-exporter/internal/util/seal"
+	"github.com/webitel/media-exporter/internal/util/sign"
 )
 
 type PdfService interface {
 	// Screenrecording methods
 	GenerateExport(ctx context.Context, opts *options.CreateOptions, req *domain.GenerateExportRequest) (*domain.PdfExportMetadata, error)
-	GetHistory(ctx context.Context, reqOpts *domain.PdfHistoryRequestOptions) (*domain.HistoryResponse, error)
+	GenerateZipExport(ctx context.Context, opts *options.CreateOptions, req *domain.GenerateExportRequest) (*domain.PdfExportMetadata, error)
+	GenerateVideoExport(ctx context.Context, opts *options.CreateOptions, format string, req *domain.GenerateExportRequest) (*domain.PdfExportMetadata, error)
+	GetHistory(ctx context.Context, opts *options.SearchOptions, reqOpts *domain.PdfHistoryRequestOptions) (*domain.HistoryResponse, error)
 
 	// Call methods
 	GenerateCallExport(ctx context.Context, opts *options.CreateOptions, req *domain.GenerateCallExportRequest) (*domain.PdfExportMetadata, error)
-	GetCallHistory(ctx context.Context, reqOpts *domain.CallHistoryRequestOptions) (*domain.HistoryResponse, error)
+	GenerateCallZipExport(ctx context.Context, opts *options.CreateOptions, req *domain.GenerateCallExportRequest) (*domain.PdfExportMetadata, error)
+	GenerateCallVideoExport(ctx context.Context, opts *options.CreateOptions, format string, req *domain.GenerateCallExportRequest) (*domain.PdfExportMetadata, error)
+	GenerateCallTranscriptExport(ctx context.Context, opts *options.CreateOptions, req *domain.GenerateCallTranscriptRequest) (*domain.PdfExportMetadata, error)
+	GenerateCallDossierExport(ctx context.Context, opts *options.CreateOptions, req *domain.GenerateCallDossierRequest) (*domain.PdfExportMetadata, error)
+	GenerateCallAudioExport(ctx context.Context, opts *options.CreateOptions, format string, req *domain.GenerateCallAudioRequest) (*domain.PdfExportMetadata, error)
+	GetCallHistory(ctx context.Context, opts *options.SearchOptions, reqOpts *domain.CallHistoryRequestOptions) (*domain.HistoryResponse, error)
+
+	// Batch methods
+	GenerateBatchExport(ctx context.Context, opts *options.CreateOptions, req *domain.GenerateBatchExportRequest) (*domain.BatchExportMetadata, error)
+
+	// Schedule methods
+	CreateExportSchedule(ctx context.Context, opts *options.CreateOptions, spec *domain.ExportScheduleSpec) (*domain.ExportSchedule, error)
+	GetExportSchedule(ctx context.Context, opts *options.SearchOptions, id int64) (*domain.ExportSchedule, error)
+	ListExportSchedules(ctx context.Context, opts *options.SearchOptions, page, size int32) (*domain.ExportScheduleList, error)
+	UpdateExportSchedule(ctx context.Context, opts *options.UpdateOptions, id int64, spec *domain.ExportScheduleSpec) (*domain.ExportSchedule, error)
+	DeleteExportSchedule(ctx context.Context, opts *options.DeleteOptions, id int64) error
 
 	// Common
+	GetExport(ctx context.Context, opts *options.SearchOptions, taskID string) (*domain.HistoryRecord, error)
+	GetExportByHistoryID(ctx context.Context, opts *options.SearchOptions, id int64) (*domain.HistoryRecord, error)
+	WatchExport(ctx context.Context, opts *options.SearchOptions, taskID string, send func(*domain.ExportProgress) error) error
+	CancelExport(ctx context.Context, opts *options.UpdateOptions, taskID string) (*domain.HistoryRecord, error)
+	RetryExport(ctx context.Context, opts *options.CreateOptions, id int64) (*domain.PdfExportMetadata, error)
+	VerifyExport(ctx context.Context, opts *options.SearchOptions, id int64, document io.Reader) (*domain.ExportVerification, error)
 	DeleteRecord(ctx context.Context, opts *options.DeleteOptions, recordID int64) error
 }
 
+// exportFormat describes the file produced by an export type.
+type exportFormat struct {
+	ext  string
+	mime string
+}
+
+var exportFormats = map[string]exportFormat{
+	domain.PdfExportType:  {ext: "pdf", mime: "application/pdf"},
+	domain.ZipExportType:  {ext: "zip", mime: "application/zip"},
+	domain.Mp4ExportType:  {ext: "mp4", mime: "video/mp4"},
+	domain.WebmExportType: {ext: "webm", mime: "video/webm"},
+
+	domain.TranscriptExportType: {ext: "pdf", mime: "application/pdf"},
+	domain.DossierExportType:    {ext: "pdf", mime: "application/pdf"},
+
+	domain.AudioZipExportType: {ext: "zip", mime: "application/zip"},
+	domain.Mp3ExportType:      {ext: "mp3", mime: "audio/mpeg"},
+	domain.WavExportType:      {ext: "wav", mime: "audio/wav"},
+	domain.OpusExportType:     {ext: "opus", mime: "audio/ogg"},
+
+	// The file of a batch export is the archive of the files of its exports, if it asks for one.
+	domain.BatchExportType: {ext: "zip", mime: "application/zip"},
+}
+
+// Bounds of the frame duration a video export may ask for.
+const (
+	minFrameDurationMs = 40
+	maxFrameDurationMs = 60_000
+)
+
+// maxPdfTitleLength bounds the title printed in the header of a PDF export.
+const maxPdfTitleLength = 200
+
+// maxPdfPasswordLength bounds the password of an encrypted PDF export, longer ones do not open the document.
+const maxPdfPasswordLength = 127
+
+// maxAudioCalls bounds the calls whose recordings are exported together.
+const maxAudioCalls = 100
+
+// maxSessionGapMs bounds the pause between screenshots of one session of an export, a day.
+const maxSessionGapMs = 24 * 60 * 60 * 1000
+
+// pdfImagesPerPage are the numbers of screenshots a page of a PDF export can be laid out with.
+var pdfImagesPerPage = []int{0, 1, 2, 4, 6, 9}
+
 type PdfServiceImpl struct {
-	store store.PdfStore
-	cache cache.Cache
-	log   *slog.Logger
+	store     store.PdfStore
+	schedules store.ScheduleStore
+	cache     cache.Cache
+	// sealer seals the passwords of encrypted exports and the access tokens of export schedules,
+	// nil if both are disabled.
+	sealer *seal.Sealer
+	// signer signs the manifests and digests of exports, nil if signing is disabled.
+	signer *sign.Signer
+	log    *slog.Logger
 }
 
-func NewPdfService(s store.PdfStore, c cache.Cache, log *slog.Logger) (PdfService, error) {
-	if s == nil || c == nil {
+func NewPdfService(s store.PdfStore, schedules store.ScheduleStore, c cache.Cache, sealer *seal.Sealer, signer *sign.Signer, log *slog.Logger) (PdfService, error) {
+	if s == nil || schedules == nil || c == nil {
 		return nil, errors.Internal("store or cache is nil in PdfService")
 	}
-	return &PdfServiceImpl{store: s, cache: c, log: log}, nil
+	return &PdfServiceImpl{store: s, schedules: schedules, cache: c, sealer: sealer, signer: signer, log: log}, nil
 }
 
 // --- Screenrecording Exports ---
@@ -47,14 +131,72 @@ func (s *PdfServiceImpl) GenerateExport(ctx context.Context, opts *options.Creat
 		return nil, errors.BadRequest("agent_id is required")
 	}
 	// Logic moved to a helper to reuse code between Call and Screenrecording
-	return s.createExportTask(ctx, opts, domain.ChannelScreenRecording, req.AgentID, "", req.FileIDs, req.From, req.To)
+	if err := validatePdfExport(req.Pdf); err != nil {
+		return nil, err
+	}
+	if err := validateExportOrder(req.Order); err != nil {
+		return nil, err
+	}
+	return s.createExportTask(ctx, opts, domain.ExportParams{
+		Type:    domain.PdfExportType,
+		Channel: string(domain.ChannelScreenRecording),
+		AgentID: req.AgentID,
+		From:    req.From,
+		To:      req.To,
+		IDs:     req.FileIDs,
+		Pdf:     req.Pdf,
+		Order:   req.Order,
+	}, 0)
 }
 
-func (s *PdfServiceImpl) GetHistory(ctx context.Context, req *domain.PdfHistoryRequestOptions) (*domain.HistoryResponse, error) {
+func (s *PdfServiceImpl) GenerateZipExport(ctx context.Context, opts *options.CreateOptions, req *domain.GenerateExportRequest) (*domain.PdfExportMetadata, error) {
 	if req.AgentID == 0 {
 		return nil, errors.BadRequest("agent_id is required")
 	}
-	return s.store.GetPdfExportHistory(req)
+	if err := validateExportOrder(req.Order); err != nil {
+		return nil, err
+	}
+	return s.createExportTask(ctx, opts, domain.ExportParams{
+		Type:    domain.ZipExportType,
+		Channel: string(domain.ChannelScreenRecording),
+		AgentID: req.AgentID,
+		From:    req.From,
+		To:      req.To,
+		IDs:     req.FileIDs,
+		Order:   req.Order,
+	}, 0)
+}
+
+func (s *PdfServiceImpl) GenerateVideoExport(ctx context.Context, opts *options.CreateOptions, format string, req *domain.GenerateExportRequest) (*domain.PdfExportMetadata, error) {
+	if req.AgentID == 0 {
+		return nil, errors.BadRequest("agent_id is required")
+	}
+	if err := validateVideoExport(format, req.Video); err != nil {
+		return nil, err
+	}
+	if err := validateExportOrder(req.Order); err != nil {
+		return nil, err
+	}
+	return s.createExportTask(ctx, opts, domain.ExportParams{
+		Type:    format,
+		Channel: string(domain.ChannelScreenRecording),
+		AgentID: req.AgentID,
+		From:    req.From,
+		To:      req.To,
+		IDs:     req.FileIDs,
+		Video:   req.Video,
+		Order:   req.Order,
+	}, 0)
+}
+
+func (s *PdfServiceImpl) GetHistory(ctx context.Context, opts *options.SearchOptions, req *domain.PdfHistoryRequestOptions) (*domain.HistoryResponse, error) {
+	if req.AgentID == 0 {
+		return nil, errors.BadRequest("agent_id is required")
+	}
+	if err := s.authorizeSource(opts, req.AgentID, ""); err != nil {
+		return nil, err
+	}
+	return s.store.GetPdfExportHistory(opts, req)
 }
 
 // --- Call Exports ---
@@ -63,14 +205,128 @@ func (s *PdfServiceImpl) GenerateCallExport(ctx context.Context, opts *options.C
 	if req.CallID == "" {
 		return nil, errors.BadRequest("call_id is required")
 	}
-	return s.createExportTask(ctx, opts, domain.ChannelCall, 0, req.CallID, req.FileIDs, req.From, req.To)
+	if err := validatePdfExport(req.Pdf); err != nil {
+		return nil, err
+	}
+	if err := validateExportOrder(req.Order); err != nil {
+		return nil, err
+	}
+	return s.createExportTask(ctx, opts, domain.ExportParams{
+		Type:    domain.PdfExportType,
+		Channel: string(domain.ChannelCall),
+		CallID:  req.CallID,
+		From:    req.From,
+		To:      req.To,
+		IDs:     req.FileIDs,
+		Pdf:     req.Pdf,
+		Order:   req.Order,
+	}, 0)
+}
+
+func (s *PdfServiceImpl) GenerateCallZipExport(ctx context.Context, opts *options.CreateOptions, req *domain.GenerateCallExportRequest) (*domain.PdfExportMetadata, error) {
+	if req.CallID == "" {
+		return nil, errors.BadRequest("call_id is required")
+	}
+	if err := validateExportOrder(req.Order); err != nil {
+		return nil, err
+	}
+	return s.createExportTask(ctx, opts, domain.ExportParams{
+		Type:    domain.ZipExportType,
+		Channel: string(domain.ChannelCall),
+		CallID:  req.CallID,
+		From:    req.From,
+		To:      req.To,
+		IDs:     req.FileIDs,
+		Order:   req.Order,
+	}, 0)
+}
+
+func (s *PdfServiceImpl) GenerateCallVideoExport(ctx context.Context, opts *options.CreateOptions, format string, req *domain.GenerateCallExportRequest) (*domain.PdfExportMetadata, error) {
+	if req.CallID == "" {
+		return nil, errors.BadRequest("call_id is required")
+	}
+	if err := validateVideoExport(format, req.Video); err != nil {
+		return nil, err
+	}
+	if err := validateExportOrder(req.Order); err != nil {
+		return nil, err
+	}
+	return s.createExportTask(ctx, opts, domain.ExportParams{
+		Type:    format,
+		Channel: string(domain.ChannelCall),
+		CallID:  req.CallID,
+		From:    req.From,
+		To:      req.To,
+		IDs:     req.FileIDs,
+		Video:   req.Video,
+		Order:   req.Order,
+	}, 0)
+}
+
+func (s *PdfServiceImpl) GenerateCallTranscriptExport(ctx context.Context, opts *options.CreateOptions, req *domain.GenerateCallTranscriptRequest) (*domain.PdfExportMetadata, error) {
+	if req.CallID == "" {
+		return nil, errors.BadRequest("call_id is required")
+	}
+	if err := validatePdfExport(req.Pdf); err != nil {
+		return nil, err
+	}
+	if err := validateTranscriptExport(req.Transcript); err != nil {
+		return nil, err
+	}
+	return s.createExportTask(ctx, opts, domain.ExportParams{
+		Type:       domain.TranscriptExportType,
+		Channel:    string(domain.ChannelCall),
+		CallID:     req.CallID,
+		From:       req.From,
+		To:         req.To,
+		IDs:        req.FileIDs,
+		Pdf:        req.Pdf,
+		Transcript: req.Transcript,
+	}, 0)
+}
+
+func (s *PdfServiceImpl) GenerateCallDossierExport(ctx context.Context, opts *options.CreateOptions, req *domain.GenerateCallDossierRequest) (*domain.PdfExportMetadata, error) {
+	if req.CallID == "" {
+		return nil, errors.BadRequest("call_id is required")
+	}
+	if err := validatePdfExport(req.Pdf); err != nil {
+		return nil, err
+	}
+	return s.createExportTask(ctx, opts, domain.ExportParams{
+		Type:    domain.DossierExportType,
+		Channel: string(domain.ChannelCall),
+		CallID:  req.CallID,
+		From:    req.From,
+		To:      req.To,
+		Pdf:     req.Pdf,
+	}, 0)
+}
+
+// GenerateCallAudioExport exports the recordings of the calls, listed in the history of the first of them.
+func (s *PdfServiceImpl) GenerateCallAudioExport(ctx context.Context, opts *options.CreateOptions, format string, req *domain.GenerateCallAudioRequest) (*domain.PdfExportMetadata, error) {
+	callIDs, err := validateAudioExport(format, req.CallIDs)
+	if err != nil {
+		return nil, err
+	}
+	return s.createExportTask(ctx, opts, domain.ExportParams{
+		Type:    format,
+		Channel: string(domain.ChannelCall),
+		CallID:  callIDs[0],
+		CallIDs: callIDs,
+		From:    req.From,
+		To:      req.To,
+		IDs:     req.FileIDs,
+	}, 0)
 }
 
-func (s *PdfServiceImpl) GetCallHistory(ctx context.Context, req *domain.CallHistoryRequestOptions) (*domain.HistoryResponse, error) {
+func (s *PdfServiceImpl) GetCallHistory(ctx context.Context, opts *options.SearchOptions, req *domain.CallHistoryRequestOptions) (*domain.HistoryResponse, error) {
 	if req.CallID == "" {
 		return nil, errors.BadRequest("call_id is required")
 	}
-	return s.store.GetCallPdfExportHistory(req)
+	if err := s.authorizeSource(opts, 0, req.CallID); err != nil {
+		return nil, err
+	}
+	return s.store.GetCallPdfExportHistory(opts, req)
 }
 
 // --- General ---
@@ -79,28 +335,496 @@ func (s *PdfServiceImpl) DeleteRecord(ctx context.Context, opts *options.DeleteO
 	if recordID == 0 {
 		return errors.BadRequest("id is required for delete operation")
 	}
+	lookup := lookupOptions(opts, opts.Time, opts.Auth)
+	rec, err := s.store.GetPdfExportByID(lookup, recordID)
+	if err != nil {
+		return notFoundOr(err, fmt.Sprintf("export %d not found", recordID))
+	}
+	if err := s.authorizeRecord(lookup, rec); err != nil {
+		return err
+	}
 	return s.store.DeletePdfExportRecord(opts, recordID)
 }
 
+// GetExport returns the export of the task. The first response to the requester of an encrypted
+// export with a generated password carries the password, later ones do not.
+func (s *PdfServiceImpl) GetExport(ctx context.Context, opts *options.SearchOptions, taskID string) (*domain.HistoryRecord, error) {
+	rec, err := s.exportByTaskID(opts, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if s.sealer == nil || rec.CreatedBy != opts.Auth.GetUserId() {
+		return rec, nil
+	}
+	sealed, err := s.cache.TakeExportPassword(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export password: %w", err)
+	}
+	if sealed != "" {
+		if rec.Password, err = s.sealer.Open(sealed); err != nil {
+			return nil, fmt.Errorf("failed to open export password: %w", err)
+		}
+	}
+	return rec, nil
+}
+
+// exportByTaskID returns the export of the task without handing out its password.
+func (s *PdfServiceImpl) exportByTaskID(opts *options.SearchOptions, taskID string) (*domain.HistoryRecord, error) {
+	if taskID == "" {
+		return nil, errors.BadRequest("task_id is required")
+	}
+	rec, err := s.store.GetPdfExportByTaskID(opts, taskID)
+	if err != nil {
+		return nil, notFoundOr(err, fmt.Sprintf("export task %s not found", taskID))
+	}
+	if err := s.authorizeRecord(opts, rec); err != nil {
+		return nil, err
+	}
+	return s.withLiveStatus(rec)
+}
+
+func (s *PdfServiceImpl) GetExportByHistoryID(ctx context.Context, opts *options.SearchOptions, id int64) (*domain.HistoryRecord, error) {
+	if id == 0 {
+		return nil, errors.BadRequest("id is required")
+	}
+	rec, err := s.store.GetPdfExportByID(opts, id)
+	if err != nil {
+		return nil, notFoundOr(err, fmt.Sprintf("export %d not found", id))
+	}
+	if err := s.authorizeRecord(opts, rec); err != nil {
+		return nil, err
+	}
+	return s.withLiveStatus(rec)
+}
+
+// WatchExport sends the last known progress of the task and then every new event
+// until the task is done or failed, or the caller goes away.
+func (s *PdfServiceImpl) WatchExport(ctx context.Context, opts *options.SearchOptions, taskID string, send func(*domain.ExportProgress) error) error {
+	// Resolving the record first also checks that the task belongs to the caller's domain
+	// and that the caller may read its source.
+	rec, err := s.exportByTaskID(opts, taskID)
+	if err != nil {
+		return err
+	}
+
+	events, closeSub, err := s.cache.SubscribeProgress(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	defer closeSub()
+
+	last, err := s.cache.GetProgress(taskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task progress: %w", err)
+	}
+	if fromRecord := progressFromRecord(rec); last == nil || fromRecord.Final() {
+		last = fromRecord
+	}
+	if err := send(last); err != nil {
+		return err
+	}
+
+	for !last.Final() {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if event.Timestamp < last.Timestamp {
+				continue
+			}
+			last = &event
+			if err := send(last); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CancelExport removes a queued task right away; a task that a worker already took
+// is flagged and the worker aborts it and marks it cancelled. Cancelling a batch export
+// cancels its unfinished exports too.
+func (s *PdfServiceImpl) CancelExport(ctx context.Context, opts *options.UpdateOptions, taskID string) (*domain.HistoryRecord, error) {
+	rec, err := s.exportByTaskID(lookupOptions(opts, opts.Time, opts.Auth), taskID)
+	if err != nil {
+		return nil, err
+	}
+	if domain.IsFinalStatus(rec.Status) {
+		return nil, errors.BadRequest(fmt.Sprintf("export %s is already %s", taskID, rec.Status))
+	}
+	if rec.Params != nil && rec.Params.Batch != nil {
+		if err := s.cancelBatchExports(ctx, opts, rec); err != nil {
+			return nil, err
+		}
+	}
+
+	// Flag first, so a worker that pops the task right after the removal attempt still sees it.
+	if err := s.cache.RequestCancel(taskID); err != nil {
+		return nil, fmt.Errorf("failed to request cancellation: %w", err)
+	}
+
+	removed, err := s.cache.RemoveQueuedTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		s.log.InfoContext(ctx, "cancellation requested for running export", "taskID", taskID)
+		return rec, nil
+	}
+
+	if err := s.store.UpdatePdfExportStatus(opts, &domain.UpdateExportStatus{
+		ID:        rec.ID,
+		Status:    "cancelled",
+		UpdatedBy: opts.Auth.GetUserId(),
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.cache.PublishProgress(domain.ExportProgress{
+		TaskID:    taskID,
+		Status:    "cancelled",
+		Stage:     domain.StageCompleted,
+		Timestamp: time.Now().UnixMilli(),
+	}); err != nil {
+		s.log.WarnContext(ctx, "failed to publish cancellation", "taskID", taskID, "error", err)
+	}
+	if err := s.cache.ClearExportTask(taskID); err != nil {
+		s.log.WarnContext(ctx, "failed to clear cancelled task", "taskID", taskID, "error", err)
+	}
+
+	s.log.InfoContext(ctx, "queued export cancelled", "taskID", taskID)
+
+	rec.Status = "cancelled"
+	rec.UpdatedBy = opts.Auth.GetUserId()
+	rec.UpdatedAt = time.Now().UnixMilli()
+	return rec, nil
+}
+
+// RetryExport enqueues a failed or cancelled export again with its original parameters.
+// The new export gets its own history record linked to the original one.
+func (s *PdfServiceImpl) RetryExport(ctx context.Context, opts *options.CreateOptions, id int64) (*domain.PdfExportMetadata, error) {
+	if id == 0 {
+		return nil, errors.BadRequest("id is required")
+	}
+
+	rec, err := s.store.GetPdfExportByID(lookupOptions(opts, opts.Time, opts.Auth), id)
+	if err != nil {
+		return nil, notFoundOr(err, fmt.Sprintf("export %d not found", id))
+	}
+	if rec.Status != "failed" && rec.Status != "cancelled" {
+		return nil, errors.BadRequest(fmt.Sprintf("only failed or cancelled exports can be retried, export %d is %s", id, rec.Status))
+	}
+	if rec.Params == nil {
+		return nil, errors.BadRequest(fmt.Sprintf("export %d has no stored parameters and cannot be retried", id))
+	}
+	if rec.Params.Batch != nil {
+		// A batch only waits for its exports, which are retried one by one.
+		return nil, errors.BadRequest(fmt.Sprintf("export %d is a batch export and cannot be retried, retry its failed exports instead", id))
+	}
+	if pdf := rec.Params.Pdf; pdf != nil && pdf.Encryption != nil && !pdf.Encryption.Generated {
+		// The password the caller chose is not stored, so the document cannot be encrypted again.
+		return nil, errors.BadRequest(fmt.Sprintf("export %d is encrypted with a caller-supplied password and cannot be retried", id))
+	}
+
+	s.log.InfoContext(ctx, "retrying export", "id", id, "taskID", rec.TaskID)
+
+	return s.createExportTask(ctx, opts, *rec.Params, rec.ID)
+}
+
+// VerifyExport checks the document against the SHA-256 recorded when the export was produced,
+// and the recorded digest against its signature.
+func (s *PdfServiceImpl) VerifyExport(ctx context.Context, opts *options.SearchOptions, id int64, document io.Reader) (*domain.ExportVerification, error) {
+	if id == 0 {
+		return nil, errors.BadRequest("id is required")
+	}
+	rec, err := s.store.GetPdfExportByID(opts, id)
+	if err != nil {
+		return nil, notFoundOr(err, fmt.Sprintf("export %d not found", id))
+	}
+	if err := s.authorizeRecord(opts, rec); err != nil {
+		return nil, err
+	}
+	if rec.Digest == "" {
+		return nil, errors.BadRequest(fmt.Sprintf("export %d has no recorded digest", id))
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(h, document)
+	if err != nil {
+		return nil, fmt.Errorf("read document: %w", err)
+	}
+
+	res := &domain.ExportVerification{
+		Sha256:         hex.EncodeToString(h.Sum(nil)),
+		ExpectedSha256: rec.Digest,
+		Size:           size,
+	}
+	res.Valid = res.Sha256 == rec.Digest
+	if s.signer != nil && rec.DigestSignature != "" {
+		sig, err := base64.StdEncoding.DecodeString(rec.DigestSignature)
+		res.SignatureValid = err == nil && s.signer.Verify(domain.SignedDigest(rec.TaskID, rec.Digest), sig)
+	}
+
+	s.log.InfoContext(ctx, "verified export", "id", id, "valid", res.Valid, "signatureValid", res.SignatureValid)
+	return res, nil
+}
+
+// validateVideoExport checks the format and the options of a time-lapse video export.
+func validateVideoExport(format string, video *domain.VideoOptions) error {
+	if format != domain.Mp4ExportType && format != domain.WebmExportType {
+		return errors.BadRequest(fmt.Sprintf("unsupported video format: %s", format))
+	}
+	if video == nil || video.FrameDurationMs == 0 {
+		return nil
+	}
+	if video.FrameDurationMs < minFrameDurationMs || video.FrameDurationMs > maxFrameDurationMs {
+		return errors.BadRequest(fmt.Sprintf("frame_duration_ms must be between %d and %d", minFrameDurationMs, maxFrameDurationMs))
+	}
+	return nil
+}
+
+// validateAudioExport checks the format and the calls of an audio export
+// and returns the calls without repetitions.
+func validateAudioExport(format string, callIDs []string) ([]string, error) {
+	switch format {
+	case domain.AudioZipExportType, domain.Mp3ExportType, domain.WavExportType, domain.OpusExportType:
+	default:
+		return nil, errors.BadRequest(fmt.Sprintf("unsupported audio format: %s", format))
+	}
+	var unique []string
+	for _, id := range callIDs {
+		if id == "" {
+			return nil, errors.BadRequest("call_ids must not contain empty IDs")
+		}
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, errors.BadRequest("call_ids is required")
+	}
+	if len(unique) > maxAudioCalls {
+		return nil, errors.BadRequest(fmt.Sprintf("at most %d calls can be exported together", maxAudioCalls))
+	}
+	return unique, nil
+}
+
+// validatePdfExport checks the page options of a PDF export.
+func validatePdfExport(pdf *domain.PdfOptions) error {
+	if pdf == nil {
+		return nil
+	}
+	if pdf.Timezone != "" {
+		if _, err := time.LoadLocation(pdf.Timezone); err != nil {
+			return errors.BadRequest(fmt.Sprintf("unknown timezone: %s", pdf.Timezone))
+		}
+	}
+	if utf8.RuneCountInString(pdf.Title) > maxPdfTitleLength {
+		return errors.BadRequest(fmt.Sprintf("title must be at most %d characters", maxPdfTitleLength))
+	}
+	switch pdf.Contents {
+	case domain.ContentsNone, domain.ContentsByHour, domain.ContentsByDay:
+	default:
+		return errors.BadRequest(fmt.Sprintf("unsupported contents grouping: %s", pdf.Contents))
+	}
+	switch pdf.PageSize {
+	case "", domain.PageSizeA4, domain.PageSizeLetter:
+	default:
+		return errors.BadRequest(fmt.Sprintf("unsupported page size: %s", pdf.PageSize))
+	}
+	switch pdf.Orientation {
+	case "", domain.OrientationPortrait, domain.OrientationLandscape:
+	default:
+		return errors.BadRequest(fmt.Sprintf("unsupported orientation: %s", pdf.Orientation))
+	}
+	if !slices.Contains(pdfImagesPerPage, pdf.ImagesPerPage) {
+		return errors.BadRequest("images_per_page must be one of 1, 2, 4, 6 or 9")
+	}
+	if pdf.ContactSheet && pdf.ImagesPerPage > 1 {
+		return errors.BadRequest("images_per_page cannot be combined with contact_sheet")
+	}
+	if pdf.Encryption != nil && len(pdf.Encryption.Password) > maxPdfPasswordLength {
+		return errors.BadRequest(fmt.Sprintf("password must be at most %d bytes", maxPdfPasswordLength))
+	}
+	return nil
+}
+
+// validateTranscriptExport checks the window of the phrases of a transcript export.
+func validateTranscriptExport(transcript *domain.TranscriptOptions) error {
+	if transcript == nil {
+		return nil
+	}
+	if transcript.WindowFromMs < 0 || transcript.WindowToMs < 0 {
+		return errors.BadRequest("window_from_ms and window_to_ms must not be negative")
+	}
+	if transcript.WindowToMs != 0 && transcript.WindowToMs <= transcript.WindowFromMs {
+		return errors.BadRequest("window_to_ms must be after window_from_ms")
+	}
+	return nil
+}
+
+// validateExportOrder checks the order and grouping of the screenshots of an export.
+func validateExportOrder(order *domain.ExportOrder) error {
+	if order == nil {
+		return nil
+	}
+	switch order.Sort {
+	case "", domain.SortUploadedDesc, domain.SortUploadedAsc, domain.SortName:
+	default:
+		return errors.BadRequest(fmt.Sprintf("unsupported sort: %s", order.Sort))
+	}
+	switch order.GroupBy {
+	case domain.GroupByNone, domain.GroupByHour, domain.GroupByDay, domain.GroupBySession:
+	default:
+		return errors.BadRequest(fmt.Sprintf("unsupported group_by: %s", order.GroupBy))
+	}
+	if order.SessionGapMs < 0 || order.SessionGapMs > maxSessionGapMs {
+		return errors.BadRequest(fmt.Sprintf("session_gap_ms must be between 0 and %d", maxSessionGapMs))
+	}
+	return nil
+}
+
+// newTraceHash returns the hash that identifies the copies of an export: a SHA-256 of who
+// requested which export and when, salted with random bytes so it cannot be guessed from them.
+func newTraceHash(taskID string, userID, domainID int64, at time.Time) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00%d\x00%d\x00", taskID, userID, domainID, at.UnixNano())
+	h.Write(salt)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// sealPassword returns the password of an encrypted export sealed for the queue. Without
+// a password of the caller it generates one and marks the encryption as generated.
+func (s *PdfServiceImpl) sealPassword(enc *domain.PdfEncryption) (string, error) {
+	if s.sealer == nil {
+		return "", errors.BadRequest("encrypted PDF exports are not enabled")
+	}
+	password := enc.Password
+	if password == "" {
+		password = rand.Text()
+		enc.Generated = true
+	}
+	sealed, err := s.sealer.Seal(password)
+	if err != nil {
+		return "", fmt.Errorf("seal password failed: %w", err)
+	}
+	return sealed, nil
+}
+
+// lookupOptions builds the options for the reads a mutation depends on, on behalf of the same caller.
+func lookupOptions(ctx context.Context, t time.Time, a auth.Auther) *options.SearchOptions {
+	return &options.SearchOptions{Context: ctx, Time: t, Auth: a}
+}
+
+// progressFromRecord describes the state of a history record as a progress event.
+func progressFromRecord(rec *domain.HistoryRecord) *domain.ExportProgress {
+	event := &domain.ExportProgress{
+		TaskID:    rec.TaskID,
+		Status:    rec.Status,
+		FileID:    rec.FileID,
+		Error:     rec.LastError,
+		Timestamp: rec.UpdatedAt,
+	}
+	switch rec.Status {
+	case "pending":
+		event.Stage = domain.StageQueued
+	case "done", "failed", "cancelled":
+		event.Stage = domain.StageCompleted
+	}
+	return event
+}
+
+// withLiveStatus overrides the persisted status with the one of the queue while the task is still tracked there.
+func (s *PdfServiceImpl) withLiveStatus(rec *domain.HistoryRecord) (*domain.HistoryRecord, error) {
+	if rec.TaskID == "" {
+		return rec, nil
+	}
+	status, err := s.cache.GetExportStatus(rec.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task status: %w", err)
+	}
+	if status != "" {
+		rec.Status = status
+	}
+	return rec, nil
+}
+
+// notFoundOr converts a store "not found" error to a NotFound API error.
+func notFoundOr(err error, msg string) error {
+	var notFound *errors.DBNotFoundError
+	if errors.As(err, &notFound) {
+		return errors.NotFound(msg)
+	}
+	return err
+}
+
 // --- Internal Helper ---
 
+// exportLink relates a new export to other exports of the history.
+type exportLink struct {
+	// retryOf is the failed export the new one repeats.
+	retryOf int64
+	// parentID is the batch export the new one is part of, and seq its position in the batch,
+	// which keeps the task IDs of the exports of a batch apart.
+	parentID int64
+	seq      int
+}
+
+// createExportTask records a new export in the history and enqueues it.
+// retryOf links the new export to the failed one it repeats, 0 for a new export.
 func (s *PdfServiceImpl) createExportTask(
 	ctx context.Context,
 	opts *options.CreateOptions,
-	channel domain.ExportChannel,
-	agentID int64,
-	callID string,
-	fileIDs []int64,
-	from, to int64,
+	params domain.ExportParams,
+	retryOf int64,
 ) (*domain.PdfExportMetadata, error) {
+	task, metadata, err := s.recordExportTask(ctx, opts, params, exportLink{retryOf: retryOf})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.enqueueExportTask(ctx, task); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// recordExportTask records a new export in the history and returns the task that produces it,
+// ready to be enqueued.
+func (s *PdfServiceImpl) recordExportTask(
+	ctx context.Context,
+	opts *options.CreateOptions,
+	params domain.ExportParams,
+	link exportLink,
+) (*domain.ExportTask, *domain.PdfExportMetadata, error) {
 	now := time.Now()
 
+	if err := s.authorizeParams(lookupOptions(opts, opts.Time, opts.Auth), params); err != nil {
+		return nil, nil, err
+	}
+
+	format, ok := exportFormats[params.Type]
+	if !ok {
+		return nil, nil, errors.BadRequest(fmt.Sprintf("unsupported export type: %s", params.Type))
+	}
+
 	// Generate a meaningful task identifier
 	// Example: pdf_CALL_user123_2023-10-27_10_20_30
-	fileName := fmt.Sprintf("pdf_%s_%d_%s.pdf",
-		channel,
+	stamp := now.Format("2006-01-02_15_04_05")
+	if link.parentID != 0 {
+		stamp += fmt.Sprintf("_%d_%d", link.parentID, link.seq)
+	}
+	fileName := fmt.Sprintf("%s_%s_%d_%s.%s",
+		params.Type,
+		params.Channel,
 		opts.Auth.GetUserId(),
-		now.Format("2006-01-02_15_04_05"),
+		stamp,
+		format.ext,
 	)
 
 	taskID := fileName
@@ -108,67 +832,111 @@ func (s *PdfServiceImpl) createExportTask(
 	// Check if task is already running in cache
 	status, err := s.cache.GetExportStatus(taskID)
 	if err != nil && !errors.Is(err, redis.Nil) {
-		return nil, fmt.Errorf("failed to get task status: %w", err)
+		return nil, nil, fmt.Errorf("failed to get task status: %w", err)
 	}
 	if status == "pending" || status == "processing" {
-		return nil, errors.BadRequest(fmt.Sprintf("task already in progress: %s", taskID))
+		return nil, nil, errors.BadRequest(fmt.Sprintf("task already in progress: %s", taskID))
 	}
 
 	// Prepare history record for DB
 	var fileID int64
-	if len(fileIDs) > 0 {
-		fileID = fileIDs[0]
+	if len(params.IDs) > 0 {
+		fileID = params.IDs[0]
+	}
+
+	traceHash, err := newTraceHash(taskID, opts.Auth.GetUserId(), opts.Auth.GetDomainId(), opts.Time)
+	if err != nil {
+		return nil, nil, fmt.Errorf("trace hash failed: %w", err)
+	}
+
+	if params.Pdf != nil && params.Pdf.SignedManifest && s.signer == nil {
+		return nil, nil, errors.BadRequest("signed manifests are not enabled")
+	}
+
+	var sealedPassword string
+	if params.Pdf != nil && params.Pdf.Encryption != nil {
+		if sealedPassword, err = s.sealPassword(params.Pdf.Encryption); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	history := &domain.NewExportHistory{
+		TaskID:     taskID,
 		Name:       fileName,
-		Mime:       "application/pdf",
+		Mime:       format.mime,
 		UploadedAt: opts.Time.UnixMilli(),
 		UploadedBy: opts.Auth.GetUserId(),
 		Status:     "pending",
-		AgentID:    agentID,
-		CallID:     callID,
+		AgentID:    params.AgentID,
+		CallID:     params.CallID,
 		FileID:     fileID,
+		Params:     &params,
+		RetryOf:    link.retryOf,
+		ParentID:   link.parentID,
+		TraceHash:  traceHash,
 	}
 
 	historyID, err := s.store.InsertPdfExportHistory(opts, history)
 	if err != nil {
-		return nil, fmt.Errorf("insert history failed: %w", err)
+		return nil, nil, fmt.Errorf("insert history failed: %w", err)
 	}
 
 	if err := s.cache.SetExportHistoryID(taskID, historyID); err != nil {
-		return nil, fmt.Errorf("cache set historyID failed: %w", err)
+		return nil, nil, fmt.Errorf("cache set historyID failed: %w", err)
+	}
+	if sealedPassword != "" && params.Pdf.Encryption.Generated {
+		if err := s.cache.SetExportPassword(taskID, sealedPassword); err != nil {
+			return nil, nil, fmt.Errorf("cache set password failed: %w", err)
+		}
 	}
 
 	// Prepare task for Redis Queue
 	task := domain.ExportTask{
-		TaskID:   taskID,
-		AgentID:  agentID,
-		CallID:   callID,
-		UserID:   opts.Auth.GetUserId(),
-		DomainID: opts.Auth.GetDomainId(),
-		Channel:  string(channel),
-		From:     from,
-		To:       to,
-		Headers:  domain.ExtractHeadersFromContext(ctx, []string{"authorization", "x-req-id", "x-webitel-access"}),
-		IDs:      fileIDs,
-		Type:     domain.PdfExportType,
-	}
+		TaskID:    taskID,
+		HistoryID: historyID,
+		AgentID:   params.AgentID,
+		CallID:    params.CallID,
+		CallIDs:   params.CallIDs,
+		UserID:    opts.Auth.GetUserId(),
+		DomainID:  opts.Auth.GetDomainId(),
+		Channel:   params.Channel,
+		From:      params.From,
+		To:        params.To,
+		Headers:   domain.ExtractHeadersFromContext(ctx, []string{"authorization", "x-req-id", "x-webitel-access"}),
+		IDs:       params.IDs,
+		Type:      params.Type,
+		Video:     params.Video,
+		Pdf:       params.Pdf,
+		Order:     params.Order,
 
-	if err := s.cache.PushExportTask(task); err != nil {
-		return nil, fmt.Errorf("push task failed: %w", err)
-	}
+		Transcript: params.Transcript,
+		Batch:      params.Batch,
 
-	s.log.InfoContext(ctx, "PUSHED TASK TO REDIS QUEUE", "taskID", taskID, "channel", channel)
+		RequestedBy: opts.Auth.GetUserName(),
+		CreatedAt:   opts.Time.UnixMilli(),
+		TraceHash:   traceHash,
 
-	if err := s.cache.SetExportStatus(taskID, "pending"); err != nil {
-		return nil, fmt.Errorf("cache set status failed: %w", err)
+		SealedPassword: sealedPassword,
 	}
 
-	return &domain.PdfExportMetadata{
+	return &task, &domain.PdfExportMetadata{
 		TaskID:   taskID,
 		FileName: history.Name,
 		MimeType: history.Mime,
 		Status:   "pending",
 	}, nil
 }
+
+// enqueueExportTask puts a recorded task into the queue of the workers.
+func (s *PdfServiceImpl) enqueueExportTask(ctx context.Context, task *domain.ExportTask) error {
+	if err := s.cache.PushExportTask(*task); err != nil {
+		return fmt.Errorf("push task failed: %w", err)
+	}
+
+	s.log.DebugContext(ctx, "queued export task", "taskID", task.TaskID, "channel", task.Channel)
+
+	if err := s.cache.SetExportStatus(task.TaskID, "pending"); err != nil {
+		return fmt.Errorf("cache set status failed: %w", err)
+	}
+	return nil
+}
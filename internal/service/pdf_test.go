@@ -0,0 +1,417 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/webitel/media-exporter/auth"
+	rediscache "github.com/webitel/media-exporter/internal/cache/redis"
+	"github.com/webitel/media-exporter/internal/domain/model/options"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/errors"
+	"github.com/webitel/media-exporter/internal/store"
+	"github.com/webitel/media-exporter/internal/util/seal"
+	"github.com/webitel/media-exporter/internal/util/sign"
+	"google.golang.org/grpc/codes"
+)
+
+// fakePdfStore serves the history records it holds and grants access to the calls it lists,
+// the other methods are not expected to be called.
+type fakePdfStore struct {
+	store.PdfStore
+	records map[int64]*domain.HistoryRecord
+	calls   map[string]bool
+	// domainID is the domain the records belong to, that of every caller if 0.
+	domainID int64
+}
+
+func (f *fakePdfStore) GetPdfExportByID(opts *options.SearchOptions, id int64) (*domain.HistoryRecord, error) {
+	rec, ok := f.records[id]
+	if !ok || f.domainID != 0 && f.domainID != opts.Auth.GetDomainId() {
+		return nil, errors.NewDBNotFoundError("fake.get", "not found")
+	}
+	// Like the database, every read returns a record of its own.
+	read := *rec
+	return &read, nil
+}
+
+func (f *fakePdfStore) GetPdfExportByTaskID(opts *options.SearchOptions, taskID string) (*domain.HistoryRecord, error) {
+	if f.domainID != 0 && f.domainID != opts.Auth.GetDomainId() {
+		return nil, errors.NewDBNotFoundError("fake.get", "not found")
+	}
+	for _, rec := range f.records {
+		if rec.TaskID == taskID {
+			read := *rec
+			return &read, nil
+		}
+	}
+	return nil, errors.NewDBNotFoundError("fake.get", "not found")
+}
+
+func (f *fakePdfStore) UpdatePdfExportStatus(_ *options.UpdateOptions, input *domain.UpdateExportStatus) error {
+	rec, ok := f.records[input.ID]
+	if !ok {
+		return errors.NewDBNotFoundError("fake.update", "not found")
+	}
+	rec.Status = input.Status
+	return nil
+}
+
+func (f *fakePdfStore) CheckCallAccess(_ *options.SearchOptions, callID string, _ auth.AccessMode) (bool, error) {
+	return f.calls[callID], nil
+}
+
+// fakeSession has class-level access to every object class, record-level access is checked by the store.
+type fakeSession struct {
+	auth.Auther
+}
+
+func (fakeSession) CheckObacAccess(string, auth.AccessMode) bool     { return true }
+func (fakeSession) IsRbacCheckRequired(string, auth.AccessMode) bool { return true }
+func (fakeSession) GetDomainId() int64                               { return 1 }
+func (fakeSession) GetUserId() int64                                 { return 7 }
+
+func TestRetryExportRejectsBatch(t *testing.T) {
+	s := &PdfServiceImpl{
+		store: &fakePdfStore{records: map[int64]*domain.HistoryRecord{
+			1: {
+				ID:     1,
+				Status: "failed",
+				Params: &domain.ExportParams{
+					Type:  domain.BatchExportType,
+					Batch: &domain.BatchOptions{Type: domain.PdfExportType, AgentIDs: []int64{5, 6}},
+				},
+			},
+		}},
+		log: slog.Default(),
+	}
+	opts := &options.CreateOptions{Context: context.Background(), Time: time.Now()}
+
+	_, err := s.RetryExport(context.Background(), opts, 1)
+	if errors.Code(err) != codes.InvalidArgument {
+		t.Fatalf("RetryExport() of a batch = %v, want InvalidArgument", err)
+	}
+}
+
+// An export of several calls must not reveal a call the caller may not read,
+// whichever position it has in the request.
+func TestCallAudioExportAuthorizesEveryCall(t *testing.T) {
+	s := &PdfServiceImpl{
+		store: &fakePdfStore{calls: map[string]bool{"granted": true}},
+		log:   slog.Default(),
+	}
+	opts := &options.CreateOptions{Context: context.Background(), Time: time.Now(), Auth: fakeSession{}}
+
+	for _, calls := range [][]string{{"granted", "foreign"}, {"foreign", "granted"}} {
+		_, err := s.GenerateCallAudioExport(context.Background(), opts, domain.AudioZipExportType, &domain.GenerateCallAudioRequest{CallIDs: calls})
+		if errors.Code(err) != codes.PermissionDenied {
+			t.Errorf("GenerateCallAudioExport(%v) = %v, want PermissionDenied", calls, err)
+		}
+	}
+}
+
+func TestCancelExport(t *testing.T) {
+	tests := []struct {
+		name string
+		// popped is whether a worker already took the task from the queue.
+		popped     bool
+		status     string
+		wantCode   codes.Code
+		wantStatus string
+		// wantStored is the status of the history record after the call.
+		wantStored string
+		// wantFlag is whether the worker running the task is asked to abort it.
+		wantFlag bool
+	}{
+		{name: "queued export is cancelled at once", status: "pending", wantStatus: "cancelled", wantStored: "cancelled"},
+		{name: "running export is left to its worker", popped: true, status: "processing", wantStatus: "processing", wantStored: "processing", wantFlag: true},
+		{name: "finished export", status: "done", wantCode: codes.InvalidArgument, wantStored: "done"},
+		{name: "failed export", status: "failed", wantCode: codes.InvalidArgument, wantStored: "failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := miniredis.RunT(t)
+			c, err := rediscache.NewRedisCache(srv.Addr(), "", 0)
+			if err != nil {
+				t.Fatalf("NewRedisCache() error = %v", err)
+			}
+			if err := c.PushExportTask(domain.ExportTask{TaskID: "task.pdf", HistoryID: 1}); err != nil {
+				t.Fatalf("PushExportTask() error = %v", err)
+			}
+			if tt.popped {
+				if _, err := c.PopExportTask(context.Background(), time.Minute); err != nil {
+					t.Fatalf("PopExportTask() error = %v", err)
+				}
+			}
+			st := &fakePdfStore{
+				records: map[int64]*domain.HistoryRecord{1: {ID: 1, TaskID: "task.pdf", CallID: "granted", Status: tt.status}},
+				calls:   map[string]bool{"granted": true},
+			}
+			s := &PdfServiceImpl{store: st, cache: c, log: slog.Default()}
+			opts := &options.UpdateOptions{Context: context.Background(), Time: time.Now(), Auth: fakeSession{}}
+
+			rec, err := s.CancelExport(context.Background(), opts, "task.pdf")
+			if errors.Code(err) != tt.wantCode {
+				t.Fatalf("CancelExport() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && rec.Status != tt.wantStatus {
+				t.Errorf("CancelExport() status = %s, want %s", rec.Status, tt.wantStatus)
+			}
+			if got := st.records[1].Status; got != tt.wantStored {
+				t.Errorf("stored status = %s, want %s", got, tt.wantStored)
+			}
+
+			if requested, _ := c.IsCancelRequested("task.pdf"); requested != tt.wantFlag {
+				t.Errorf("cancellation requested = %v, want %v", requested, tt.wantFlag)
+			}
+			queued, _ := srv.List("export_queue")
+			if wantQueued := tt.wantCode != codes.OK && !tt.popped; (len(queued) == 1) != wantQueued {
+				t.Errorf("queue holds %d tasks, want the task queued: %v", len(queued), wantQueued)
+			}
+		})
+	}
+}
+
+// newTestCache returns a cache backed by an in-memory Redis.
+func newTestCache(t *testing.T) *rediscache.RedisCache {
+	t.Helper()
+	c, err := rediscache.NewRedisCache(miniredis.RunT(t).Addr(), "", 0)
+	if err != nil {
+		t.Fatalf("NewRedisCache() error = %v", err)
+	}
+	return c
+}
+
+// userSession is fakeSession for another user of the domain.
+type userSession struct {
+	fakeSession
+	user int64
+}
+
+func (s userSession) GetUserId() int64 { return s.user }
+
+func TestGetExport(t *testing.T) {
+	sealer, err := seal.New(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", seal.KeySize))))
+	if err != nil {
+		t.Fatalf("seal.New() error = %v", err)
+	}
+	newService := func(t *testing.T) (*PdfServiceImpl, *rediscache.RedisCache) {
+		c := newTestCache(t)
+		st := &fakePdfStore{
+			records: map[int64]*domain.HistoryRecord{1: {ID: 1, TaskID: "task.pdf", CallID: "granted", Status: "pending", CreatedBy: 7}},
+			calls:   map[string]bool{"granted": true},
+		}
+		return &PdfServiceImpl{store: st, cache: c, sealer: sealer, log: slog.Default()}, c
+	}
+	search := func(a auth.Auther) *options.SearchOptions {
+		return &options.SearchOptions{Context: context.Background(), Time: time.Now(), Auth: a}
+	}
+
+	t.Run("live status comes from the cache", func(t *testing.T) {
+		s, c := newService(t)
+		rec, err := s.GetExport(context.Background(), search(fakeSession{}), "task.pdf")
+		if err != nil || rec.Status != "pending" {
+			t.Fatalf("GetExport() = %+v, %v, want the stored status before a worker takes the task", rec, err)
+		}
+		if err := c.SetExportStatus("task.pdf", "processing"); err != nil {
+			t.Fatalf("SetExportStatus() error = %v", err)
+		}
+		if rec, err := s.GetExport(context.Background(), search(fakeSession{}), "task.pdf"); err != nil || rec.Status != "processing" {
+			t.Errorf("GetExport() = %+v, %v, want the status of the cache", rec, err)
+		}
+	})
+
+	t.Run("the generated password is returned once to the requester", func(t *testing.T) {
+		s, c := newService(t)
+		sealed, err := sealer.Seal("generated-pass")
+		if err != nil {
+			t.Fatalf("Seal() error = %v", err)
+		}
+		if err := c.SetExportPassword("task.pdf", sealed); err != nil {
+			t.Fatalf("SetExportPassword() error = %v", err)
+		}
+
+		if rec, err := s.GetExport(context.Background(), search(userSession{user: 9}), "task.pdf"); err != nil || rec.Password != "" {
+			t.Fatalf("GetExport() by another user = %+v, %v, want no password", rec, err)
+		}
+		if rec, err := s.GetExport(context.Background(), search(fakeSession{}), "task.pdf"); err != nil || rec.Password != "generated-pass" {
+			t.Fatalf("GetExport() by the requester = %+v, %v, want the password", rec, err)
+		}
+		if rec, err := s.GetExport(context.Background(), search(fakeSession{}), "task.pdf"); err != nil || rec.Password != "" {
+			t.Errorf("GetExport() by the requester again = %+v, %v, want no password", rec, err)
+		}
+	})
+}
+
+func TestWatchExport(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		// events are published once the first event is sent, cancel ends the watch from the client side after them.
+		events []domain.ExportProgress
+		cancel bool
+		want   []string
+	}{
+		{
+			name:   "ends on a terminal status",
+			status: "processing",
+			events: []domain.ExportProgress{
+				{Status: "processing", Stage: domain.StageDownloading, Current: 1, Total: 2, Timestamp: 2},
+				{Status: "processing", Stage: domain.StageDownloading, Current: 0, Total: 2, Timestamp: 1},
+				{Status: "processing", Stage: domain.StageDownloading, Current: 2, Total: 2, Timestamp: 3},
+				{Status: "done", Stage: domain.StageCompleted, Timestamp: 4},
+			},
+			want: []string{"processing/", "processing/downloading 1", "processing/downloading 2", "done/completed 0"},
+		},
+		{
+			name:   "ends when the client goes away",
+			status: "processing",
+			events: []domain.ExportProgress{{Status: "processing", Stage: domain.StageRendering, Current: 1, Total: 5, Timestamp: 2}},
+			cancel: true,
+			want:   []string{"processing/", "processing/rendering 1"},
+		},
+		{
+			name:   "a finished export sends its status only",
+			status: "done",
+			want:   []string{"done/completed 0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCache(t)
+			st := &fakePdfStore{
+				records: map[int64]*domain.HistoryRecord{1: {ID: 1, TaskID: "task.pdf", CallID: "granted", Status: tt.status}},
+				calls:   map[string]bool{"granted": true},
+			}
+			s := &PdfServiceImpl{store: st, cache: c, log: slog.Default()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sent := make(chan domain.ExportProgress)
+			done := make(chan error, 1)
+			go func() {
+				done <- s.WatchExport(ctx, &options.SearchOptions{Context: ctx, Time: time.Now(), Auth: fakeSession{}}, "task.pdf", func(p *domain.ExportProgress) error {
+					sent <- *p
+					return nil
+				})
+			}()
+
+			var got []string
+			receive := func() bool {
+				select {
+				case p := <-sent:
+					desc := p.Status + "/" + p.Stage
+					if p.Stage != "" {
+						desc += fmt.Sprintf(" %d", p.Current)
+					}
+					got = append(got, desc)
+					return true
+				case err := <-done:
+					if err != nil {
+						t.Errorf("WatchExport() error = %v", err)
+					}
+					done <- err
+					return false
+				case <-time.After(5 * time.Second):
+					t.Fatal("WatchExport() neither sent an event nor ended")
+					return false
+				}
+			}
+
+			receive()
+			for _, event := range tt.events {
+				event.TaskID = "task.pdf"
+				if err := c.PublishProgress(event); err != nil {
+					t.Fatalf("PublishProgress() error = %v", err)
+				}
+			}
+			for len(got) < len(tt.want) && receive() {
+			}
+			if tt.cancel {
+				cancel()
+			}
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Errorf("WatchExport() error = %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("WatchExport() did not end")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sent %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyExport(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := sign.New(key, nil)
+	if err != nil {
+		t.Fatalf("sign.New() error = %v", err)
+	}
+	document := []byte("%PDF-1.7 export")
+	sum := sha256.Sum256(document)
+	digest := hex.EncodeToString(sum[:])
+	signature := func(taskID string) string {
+		return base64.StdEncoding.EncodeToString(signer.Sign(domain.SignedDigest(taskID, digest)))
+	}
+
+	tests := []struct {
+		name string
+		// domainID is the domain of the export, that of the caller if 0.
+		domainID           int64
+		document           []byte
+		signature          string
+		wantCode           codes.Code
+		wantValid          bool
+		wantSignatureValid bool
+	}{
+		{name: "unchanged document", document: document, signature: signature("task.pdf"), wantValid: true, wantSignatureValid: true},
+		{name: "tampered document", document: []byte("%PDF-1.7 exporT"), signature: signature("task.pdf"), wantSignatureValid: true},
+		{name: "digest signed for another export", document: document, signature: signature("other.pdf"), wantValid: true},
+		{name: "export of another domain", domainID: 2, document: document, signature: signature("task.pdf"), wantCode: codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := &fakePdfStore{
+				records: map[int64]*domain.HistoryRecord{1: {
+					ID: 1, TaskID: "task.pdf", CallID: "granted", Status: "done", Digest: digest, DigestSignature: tt.signature,
+				}},
+				calls:    map[string]bool{"granted": true},
+				domainID: tt.domainID,
+			}
+			s := &PdfServiceImpl{store: st, signer: signer, log: slog.Default()}
+			opts := &options.SearchOptions{Context: context.Background(), Time: time.Now(), Auth: fakeSession{}}
+
+			res, err := s.VerifyExport(context.Background(), opts, 1, bytes.NewReader(tt.document))
+			if errors.Code(err) != tt.wantCode {
+				t.Fatalf("VerifyExport() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if res.Valid != tt.wantValid || res.SignatureValid != tt.wantSignatureValid {
+				t.Errorf("VerifyExport() = valid %v, signature valid %v, want %v and %v", res.Valid, res.SignatureValid, tt.wantValid, tt.wantSignatureValid)
+			}
+			if res.ExpectedSha256 != digest || res.Size != int64(len(tt.document)) {
+				t.Errorf("VerifyExport() = %+v, want the recorded digest and the size of the document", res)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/webitel/media-exporter/auth"
+	"github.com/webitel/media-exporter/internal/domain/model/options"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/errors"
+	"github.com/webitel/media-exporter/internal/util/schedule"
+)
+
+// accessTokenHeader carries the access token of the caller, which an export schedule runs with.
+const accessTokenHeader = "x-webitel-access"
+
+// Bounds of an export schedule.
+const (
+	maxScheduleNameLength = 200
+	maxScheduleTeams      = 100
+	maxScheduleRangeCount = 366
+)
+
+// CreateExportSchedule stores a schedule that runs on behalf of the caller.
+func (s *PdfServiceImpl) CreateExportSchedule(ctx context.Context, opts *options.CreateOptions, spec *domain.ExportScheduleSpec) (*domain.ExportSchedule, error) {
+	input, err := s.newExportSchedule(ctx, lookupOptions(opts, opts.Time, opts.Auth), spec)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.schedules.InsertExportSchedule(opts, input)
+	if err != nil {
+		return nil, err
+	}
+	s.log.InfoContext(ctx, "created export schedule", "id", id, "cron", spec.Cron, "next_run_at", input.NextRunAt)
+	return s.schedules.GetExportSchedule(lookupOptions(opts, opts.Time, opts.Auth), id)
+}
+
+func (s *PdfServiceImpl) GetExportSchedule(ctx context.Context, opts *options.SearchOptions, id int64) (*domain.ExportSchedule, error) {
+	if id == 0 {
+		return nil, errors.BadRequest("id is required")
+	}
+	return s.ownSchedule(opts, id, auth.SuperSelectPermission)
+}
+
+// ListExportSchedules lists the schedules of the caller, or of the whole domain if the caller may read them all.
+func (s *PdfServiceImpl) ListExportSchedules(ctx context.Context, opts *options.SearchOptions, page, size int32) (*domain.ExportScheduleList, error) {
+	ownerID := opts.Auth.GetUserId()
+	if opts.Auth.HasSuperPermission(auth.SuperSelectPermission) {
+		ownerID = 0
+	}
+	return s.schedules.ListExportSchedules(opts, ownerID, int64(page), int64(size))
+}
+
+// UpdateExportSchedule replaces the spec of a schedule, which then runs on behalf of the caller.
+// Its next run is computed again, so an update never makes up for the runs missed before it.
+func (s *PdfServiceImpl) UpdateExportSchedule(ctx context.Context, opts *options.UpdateOptions, id int64, spec *domain.ExportScheduleSpec) (*domain.ExportSchedule, error) {
+	if id == 0 {
+		return nil, errors.BadRequest("id is required")
+	}
+	if _, err := s.ownSchedule(lookupOptions(opts, opts.Time, opts.Auth), id, auth.SuperEditPermission); err != nil {
+		return nil, err
+	}
+	input, err := s.newExportSchedule(ctx, lookupOptions(opts, opts.Time, opts.Auth), spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.schedules.UpdateExportSchedule(opts, id, input); err != nil {
+		return nil, err
+	}
+	s.log.InfoContext(ctx, "updated export schedule", "id", id, "cron", spec.Cron, "next_run_at", input.NextRunAt)
+	return s.schedules.GetExportSchedule(lookupOptions(opts, opts.Time, opts.Auth), id)
+}
+
+func (s *PdfServiceImpl) DeleteExportSchedule(ctx context.Context, opts *options.DeleteOptions, id int64) error {
+	if id == 0 {
+		return errors.BadRequest("id is required")
+	}
+	if _, err := s.ownSchedule(lookupOptions(opts, opts.Time, opts.Auth), id, auth.SuperDeletePermission); err != nil {
+		return err
+	}
+	return s.schedules.DeleteExportSchedule(opts, id)
+}
+
+// ownSchedule returns the schedule if the caller may manage it: the caller created it or it runs on
+// their behalf, or the caller holds the permission for the schedules of every user of the domain.
+func (s *PdfServiceImpl) ownSchedule(opts *options.SearchOptions, id int64, permission auth.SuperPermission) (*domain.ExportSchedule, error) {
+	sched, err := s.schedules.GetExportSchedule(opts, id)
+	if err != nil {
+		return nil, err
+	}
+	user := opts.Auth.GetUserId()
+	if sched.CreatedBy != user && sched.UpdatedBy != user && !opts.Auth.HasSuperPermission(permission) {
+		return nil, errors.Forbidden(
+			fmt.Sprintf("access denied to export schedule %d", id),
+			errors.WithID("service.pdf.access.schedule"),
+		)
+	}
+	return sched, nil
+}
+
+// newExportSchedule validates the spec of a schedule saved by the caller, checks that the caller
+// may export its agents and seals the access token of the caller for its runs.
+func (s *PdfServiceImpl) newExportSchedule(ctx context.Context, opts *options.SearchOptions, spec *domain.ExportScheduleSpec) (*domain.NewExportSchedule, error) {
+	if s.sealer == nil {
+		return nil, errors.BadRequest("export schedules are not enabled")
+	}
+	cron, err := validateExportSchedule(spec)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Params.Pdf != nil && spec.Params.Pdf.SignedManifest && s.signer == nil {
+		return nil, errors.BadRequest("signed manifests are not enabled")
+	}
+	// The agents of the teams are only known at the time of a run, and checked then.
+	if err := s.authorizeBatch(opts, spec.Params.AgentIDs, nil); err != nil {
+		return nil, err
+	}
+
+	token := domain.ExtractHeadersFromContext(ctx, []string{accessTokenHeader})[accessTokenHeader]
+	if token == "" {
+		return nil, errors.BadRequest("an access token is required to save an export schedule")
+	}
+	sealed, err := s.sealer.Seal(token)
+	if err != nil {
+		return nil, fmt.Errorf("seal access token failed: %w", err)
+	}
+
+	input := &domain.NewExportSchedule{Spec: *spec, SealedToken: sealed}
+	if spec.Enabled {
+		input.NextRunAt = nextRunAt(cron, opts.Time)
+	}
+	return input, nil
+}
+
+// nextRunAt returns the first occurrence of the schedule after t in Unix millis, 0 if there is none.
+func nextRunAt(cron *schedule.Schedule, t time.Time) int64 {
+	next := cron.Next(t)
+	if next.IsZero() {
+		return 0
+	}
+	return next.UnixMilli()
+}
+
+// validateExportSchedule checks the spec of a schedule and fills in its defaults.
+// It returns the parsed cron expression.
+func validateExportSchedule(spec *domain.ExportScheduleSpec) (*schedule.Schedule, error) {
+	switch n := utf8.RuneCountInString(spec.Name); {
+	case n == 0:
+		return nil, errors.BadRequest("name is required")
+	case n > maxScheduleNameLength:
+		return nil, errors.BadRequest(fmt.Sprintf("name must be at most %d characters", maxScheduleNameLength))
+	}
+
+	cron, err := schedule.Parse(spec.Cron, spec.Timezone)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+	if cron.Next(time.Now()).IsZero() {
+		return nil, errors.BadRequest(fmt.Sprintf("cron expression %q never occurs", spec.Cron))
+	}
+
+	switch spec.CatchUp {
+	case "":
+		spec.CatchUp = domain.CatchUpLatest
+	case domain.CatchUpSkip, domain.CatchUpLatest, domain.CatchUpAll:
+	default:
+		return nil, errors.BadRequest(fmt.Sprintf("unsupported catch-up policy: %s", spec.CatchUp))
+	}
+
+	params := &spec.Params
+	if params.Range.Unit == "" {
+		params.Range.Unit = domain.RangeDay
+	}
+	if !schedule.Unit(params.Range.Unit).Valid() {
+		return nil, errors.BadRequest(fmt.Sprintf("unsupported range unit: %s", params.Range.Unit))
+	}
+	switch c := params.Range.Count; {
+	case c == 0:
+		params.Range.Count = 1
+	case c < 0 || c > maxScheduleRangeCount:
+		return nil, errors.BadRequest(fmt.Sprintf("range count must be between 1 and %d", maxScheduleRangeCount))
+	}
+
+	if params.AgentIDs, err = uniqueIDs("agent_ids", params.AgentIDs, maxBatchExports); err != nil {
+		return nil, err
+	}
+	if params.TeamIDs, err = uniqueIDs("team_ids", params.TeamIDs, maxScheduleTeams); err != nil {
+		return nil, err
+	}
+	if len(params.AgentIDs) == 0 && len(params.TeamIDs) == 0 {
+		return nil, errors.BadRequest("agent_ids or team_ids is required")
+	}
+
+	if params.Type == domain.PdfExportType && params.Pdf != nil && params.Pdf.Encryption != nil && params.Pdf.Encryption.Password != "" {
+		return nil, errors.BadRequest("a scheduled export cannot have a password, leave it empty to generate one per export")
+	}
+	if err := validateBatchType(params.Type, params.Pdf, params.Video, params.Order); err != nil {
+		return nil, err
+	}
+	return cron, nil
+}
+
+// uniqueIDs returns the IDs without repetitions, rejecting zero IDs and more than limit of them.
+func uniqueIDs(field string, ids []int64, limit int) ([]int64, error) {
+	var res []int64
+	for _, id := range ids {
+		if id == 0 {
+			return nil, errors.BadRequest(field + " must not contain 0")
+		}
+		if !slices.Contains(res, id) {
+			res = append(res, id)
+		}
+	}
+	if len(res) > limit {
+		return nil, errors.BadRequest(fmt.Sprintf("%s can have at most %d IDs", field, limit))
+	}
+	return res, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/webitel/media-exporter/auth"
+	"github.com/webitel/media-exporter/internal/domain/model/options"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/errors"
+	"github.com/webitel/media-exporter/internal/store"
+	"google.golang.org/grpc/codes"
+)
+
+// fakeScheduleStore holds the schedules of a domain and records what was changed.
+type fakeScheduleStore struct {
+	store.ScheduleStore
+	schedules map[int64]*domain.ExportSchedule
+	updated   []int64
+	deleted   []int64
+	ownerID   int64
+}
+
+func (f *fakeScheduleStore) GetExportSchedule(_ *options.SearchOptions, id int64) (*domain.ExportSchedule, error) {
+	sched, ok := f.schedules[id]
+	if !ok {
+		return nil, errors.NewDBNotFoundError("fake.get_schedule", "not found")
+	}
+	return sched, nil
+}
+
+func (f *fakeScheduleStore) ListExportSchedules(_ *options.SearchOptions, ownerID, _, _ int64) (*domain.ExportScheduleList, error) {
+	f.ownerID = ownerID
+	return &domain.ExportScheduleList{}, nil
+}
+
+func (f *fakeScheduleStore) UpdateExportSchedule(_ *options.UpdateOptions, id int64, _ *domain.NewExportSchedule) error {
+	f.updated = append(f.updated, id)
+	return nil
+}
+
+func (f *fakeScheduleStore) DeleteExportSchedule(_ *options.DeleteOptions, id int64) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+// scheduleSession is the user 7, holding the given permissions for the schedules of every user.
+type scheduleSession struct {
+	fakeSession
+	super map[auth.SuperPermission]bool
+}
+
+func (s scheduleSession) HasSuperPermission(p auth.SuperPermission) bool { return s.super[p] }
+
+func TestExportSchedulesOfOtherUsers(t *testing.T) {
+	newService := func() (*PdfServiceImpl, *fakeScheduleStore) {
+		schedules := &fakeScheduleStore{schedules: map[int64]*domain.ExportSchedule{
+			1: {ID: 1, CreatedBy: 7, UpdatedBy: 7},
+			2: {ID: 2, CreatedBy: 9, UpdatedBy: 7},
+			3: {ID: 3, CreatedBy: 9, UpdatedBy: 9},
+		}}
+		return &PdfServiceImpl{schedules: schedules, log: slog.Default()}, schedules
+	}
+	session := func(perms ...auth.SuperPermission) scheduleSession {
+		s := scheduleSession{super: map[auth.SuperPermission]bool{}}
+		for _, p := range perms {
+			s.super[p] = true
+		}
+		return s
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		id      int64
+		session scheduleSession
+		want    codes.Code
+	}{
+		{"created by the caller", 1, session(), codes.OK},
+		{"saved last by the caller", 2, session(), codes.OK},
+		{"of another user", 3, session(), codes.PermissionDenied},
+		{"of another user with the permission", 3, session(auth.SuperSelectPermission, auth.SuperEditPermission, auth.SuperDeletePermission), codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, schedules := newService()
+
+			_, err := s.GetExportSchedule(ctx, &options.SearchOptions{Context: ctx, Time: time.Now(), Auth: tt.session}, tt.id)
+			if errors.Code(err) != tt.want {
+				t.Errorf("GetExportSchedule() error = %v, want %v", err, tt.want)
+			}
+
+			// The spec is rejected after the ownership check, so only a refusal tells that it was made.
+			_, err = s.UpdateExportSchedule(ctx, &options.UpdateOptions{Context: ctx, Time: time.Now(), Auth: tt.session}, tt.id, &domain.ExportScheduleSpec{})
+			if got := errors.Code(err) == codes.PermissionDenied; got != (tt.want == codes.PermissionDenied) {
+				t.Errorf("UpdateExportSchedule() error = %v, want refused: %v", err, tt.want == codes.PermissionDenied)
+			}
+			if len(schedules.updated) != 0 {
+				t.Errorf("schedule updated with an invalid spec")
+			}
+
+			err = s.DeleteExportSchedule(ctx, &options.DeleteOptions{Context: ctx, Time: time.Now(), Auth: tt.session}, tt.id)
+			if errors.Code(err) != tt.want {
+				t.Errorf("DeleteExportSchedule() error = %v, want %v", err, tt.want)
+			}
+			if deleted := len(schedules.deleted) == 1; deleted != (tt.want == codes.OK) {
+				t.Errorf("schedule deleted: %v, want %v", deleted, tt.want == codes.OK)
+			}
+		})
+	}
+
+	t.Run("permissions are checked one by one", func(t *testing.T) {
+		s, schedules := newService()
+		read := session(auth.SuperSelectPermission)
+		if _, err := s.GetExportSchedule(ctx, &options.SearchOptions{Context: ctx, Time: time.Now(), Auth: read}, 3); err != nil {
+			t.Errorf("GetExportSchedule() error = %v", err)
+		}
+		err := s.DeleteExportSchedule(ctx, &options.DeleteOptions{Context: ctx, Time: time.Now(), Auth: read}, 3)
+		if errors.Code(err) != codes.PermissionDenied || len(schedules.deleted) != 0 {
+			t.Errorf("DeleteExportSchedule() with the read permission only = %v, want PermissionDenied", err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		s, schedules := newService()
+		if _, err := s.ListExportSchedules(ctx, &options.SearchOptions{Context: ctx, Time: time.Now(), Auth: session()}, 1, 20); err != nil || schedules.ownerID != 7 {
+			t.Errorf("ListExportSchedules() listed the schedules of %d, %v, want those of the caller", schedules.ownerID, err)
+		}
+		if _, err := s.ListExportSchedules(ctx, &options.SearchOptions{Context: ctx, Time: time.Now(), Auth: session(auth.SuperSelectPermission)}, 1, 20); err != nil || schedules.ownerID != 0 {
+			t.Errorf("ListExportSchedules() with the permission listed the schedules of %d, %v, want every schedule", schedules.ownerID, err)
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/webitel/media-exporter/auth"
+	"github.com/webitel/media-exporter/internal/domain/model"
+	"github.com/webitel/media-exporter/internal/domain/model/options"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/errors"
+	"github.com/webitel/media-exporter/internal/util/schedule"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// dueSchedulesLimit bounds the schedules run by one call of RunDueSchedules, the others wait for the next one.
+	dueSchedulesLimit = 100
+	// maxCatchUpRuns bounds the missed runs of a schedule made up for at once, the oldest ones are dropped.
+	maxCatchUpRuns = 100
+	// onTimeWindow is how late a run may be made and still not count as missed.
+	onTimeWindow = 5 * time.Minute
+)
+
+// ExportScheduler makes the runs of the export schedules of all domains when they are due.
+// Every run is a batch export created on behalf of the owner of the schedule.
+type ExportScheduler struct {
+	exports  *PdfServiceImpl
+	sessions auth.Manager
+	log      *slog.Logger
+}
+
+func NewExportScheduler(exports PdfService, sessions auth.Manager, log *slog.Logger) (*ExportScheduler, error) {
+	impl, ok := exports.(*PdfServiceImpl)
+	if !ok || sessions == nil {
+		return nil, errors.Internal("export service or session manager is nil in ExportScheduler")
+	}
+	return &ExportScheduler{exports: impl, sessions: sessions, log: log}, nil
+}
+
+// RunDueSchedules makes the runs of the schedules that are due at now and returns how many it made.
+// Every due occurrence is claimed before its runs are made, so that it is never run twice,
+// even by several schedulers: an occurrence whose runs fail is not retried.
+func (s *ExportScheduler) RunDueSchedules(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.exports.schedules.ListDueExportSchedules(ctx, now.UnixMilli(), dueSchedulesLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	runs := 0
+	for _, sched := range due {
+		if err := ctx.Err(); err != nil {
+			return runs, err
+		}
+		runs += s.runSchedule(ctx, sched, now)
+	}
+	return runs, nil
+}
+
+// runSchedule claims the due occurrences of the schedule and makes the runs its catch-up policy
+// asks for. It returns the number of runs made.
+func (s *ExportScheduler) runSchedule(ctx context.Context, sched *domain.ExportSchedule, now time.Time) int {
+	log := s.log.With("schedule", sched.ID, "domain", sched.DomainID)
+
+	var runs []time.Time
+	var next int64
+	cron, parseErr := schedule.Parse(sched.Spec.Cron, sched.Spec.Timezone)
+	if parseErr == nil {
+		runs = dueRuns(cron, sched.Spec.CatchUp, time.UnixMilli(sched.NextRunAt), now)
+		next = nextRunAt(cron, now)
+	}
+
+	claimed, err := s.exports.schedules.ClaimExportScheduleRun(ctx, sched.ID, sched.NextRunAt, next)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to claim export schedule run", "error", err)
+		return 0
+	}
+	if !claimed {
+		return 0
+	}
+	if parseErr != nil {
+		s.recordRun(ctx, sched, &domain.ExportScheduleRun{At: sched.NextRunAt, Error: parseErr.Error()})
+		return 0
+	}
+	if len(runs) == 0 {
+		log.InfoContext(ctx, "skipped missed runs of export schedule", "missed_since", sched.NextRunAt, "next_run_at", next)
+		return 0
+	}
+
+	for _, at := range runs {
+		run := &domain.ExportScheduleRun{At: at.UnixMilli()}
+		res, err := s.runOnce(ctx, sched, cron, at)
+		if err != nil {
+			log.WarnContext(ctx, "export schedule run failed", "at", at, "error", err)
+			run.Error = err.Error()
+		} else {
+			log.InfoContext(ctx, "export schedule run created batch export", "at", at, "taskID", res.Batch.TaskID, "exports", len(res.Exports))
+			run.TaskID = res.Batch.TaskID
+		}
+		s.recordRun(ctx, sched, run)
+	}
+	return len(runs)
+}
+
+// dueRuns returns the occurrences of a schedule to run at now, from its next run on,
+// following its catch-up policy.
+func dueRuns(cron *schedule.Schedule, catchUp string, nextRun, now time.Time) []time.Time {
+	due := cron.Occurrences(nextRun, now, maxCatchUpRuns)
+	if len(due) == 0 {
+		return nil
+	}
+	latest := due[len(due)-1:]
+	switch catchUp {
+	case domain.CatchUpAll:
+		return due
+	case domain.CatchUpSkip:
+		if now.Sub(latest[0]) > onTimeWindow {
+			return nil
+		}
+		return latest
+	default:
+		return latest
+	}
+}
+
+// runOnce creates the batch export of the run of the schedule at the occurrence at.
+func (s *ExportScheduler) runOnce(ctx context.Context, sched *domain.ExportSchedule, cron *schedule.Schedule, at time.Time) (*domain.BatchExportMetadata, error) {
+	opts, err := s.ownerOptions(ctx, sched)
+	if err != nil {
+		return nil, err
+	}
+
+	params := sched.Spec.Params
+	agentIDs := slices.Clone(params.AgentIDs)
+	if len(params.TeamIDs) > 0 {
+		teamAgents, err := s.exports.schedules.ListTeamAgents(lookupOptions(opts, opts.Time, opts.Auth), params.TeamIDs)
+		if err != nil {
+			return nil, fmt.Errorf("list team agents failed: %w", err)
+		}
+		for _, id := range teamAgents {
+			if !slices.Contains(agentIDs, id) {
+				agentIDs = append(agentIDs, id)
+			}
+		}
+	}
+	if len(agentIDs) == 0 {
+		return nil, errors.BadRequest("the teams of the schedule have no agents")
+	}
+
+	from, to := cron.Range(at, schedule.Unit(params.Range.Unit), params.Range.Count, params.Range.Rolling)
+	return s.exports.GenerateBatchExport(opts, opts, &domain.GenerateBatchExportRequest{
+		AgentIDs: agentIDs,
+		From:     from.UnixMilli(),
+		To:       to.UnixMilli() - 1, // The range of an export includes its end
+		Type:     params.Type,
+		Video:    params.Video,
+		Pdf:      params.Pdf,
+		Order:    params.Order,
+		Combine:  params.Combine,
+	})
+}
+
+// ownerOptions authenticates the owner of the schedule with the access token sealed in it, so that
+// every run has the access the owner has at the time of the run, and is refused if the owner lost it.
+// The token is also what the workers download the files of the run with, the storage service does not
+// accept anything else. It expires like any other token: the runs then fail with the reason recorded
+// in the schedule until the owner saves it again.
+func (s *ExportScheduler) ownerOptions(ctx context.Context, sched *domain.ExportSchedule) (*options.CreateOptions, error) {
+	if s.exports.sealer == nil {
+		return nil, errors.BadRequest("export schedules are not enabled")
+	}
+	token, err := s.exports.sealer.Open(sched.SealedToken)
+	if err != nil {
+		return nil, fmt.Errorf("open access token of the schedule: %w", err)
+	}
+
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(accessTokenHeader, token))
+	owner, err := s.sessions.AuthorizeFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("authenticate the owner of the schedule, save it again to renew its access token: %w", err)
+	}
+	if owner.GetDomainId() != sched.DomainID {
+		return nil, errors.Forbidden("the owner of the schedule is not in its domain")
+	}
+	if !owner.CheckObacAccess(model.ExportObjClass, auth.Add) {
+		return nil, errors.Forbidden("the owner of the schedule may not create exports")
+	}
+	return &options.CreateOptions{Context: ctx, Time: time.Now().UTC(), Auth: owner}, nil
+}
+
+func (s *ExportScheduler) recordRun(ctx context.Context, sched *domain.ExportSchedule, run *domain.ExportScheduleRun) {
+	if err := s.exports.schedules.RecordExportScheduleRun(ctx, sched.ID, run); err != nil {
+		s.log.ErrorContext(ctx, "failed to record export schedule run", "schedule", sched.ID, "error", err)
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	"github.com/webitel/media-exporter/internal/util/schedule"
+)
+
+func TestDueRuns(t *testing.T) {
+	cron, err := schedule.Parse("0 * * * *", "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 3, 2, hour, minute, 0, 0, time.UTC)
+	}
+	hours := func(from, to int) []time.Time {
+		var res []time.Time
+		for h := from; h <= to; h++ {
+			res = append(res, at(h, 0))
+		}
+		return res
+	}
+
+	tests := []struct {
+		name    string
+		catchUp string
+		nextRun time.Time
+		now     time.Time
+		want    []time.Time
+	}{
+		{"not due yet", domain.CatchUpAll, at(10, 0), at(9, 59), nil},
+		{"skip runs on time", domain.CatchUpSkip, at(10, 0), at(10, 2), hours(10, 10)},
+		{"skip runs the latest missed run still on time", domain.CatchUpSkip, at(10, 0), at(12, 3), hours(12, 12)},
+		{"skip drops late runs", domain.CatchUpSkip, at(10, 0), at(12, 30), nil},
+		{"latest runs the latest missed run", domain.CatchUpLatest, at(10, 0), at(12, 30), hours(12, 12)},
+		{"latest is the default", "", at(10, 0), at(12, 30), hours(12, 12)},
+		{"all runs every missed run", domain.CatchUpAll, at(10, 0), at(12, 30), hours(10, 12)},
+		{"all runs a run on time", domain.CatchUpAll, at(10, 0), at(10, 0), hours(10, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dueRuns(cron, tt.catchUp, tt.nextRun, tt.now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("dueRuns() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("dueRuns()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	t.Run("all keeps the latest runs over the limit", func(t *testing.T) {
+		now := at(12, 30)
+		got := dueRuns(cron, domain.CatchUpAll, now.Add(-200*time.Hour), now)
+		if len(got) != maxCatchUpRuns {
+			t.Fatalf("dueRuns() made %d runs, want %d", len(got), maxCatchUpRuns)
+		}
+		if first, last := got[0], got[len(got)-1]; !last.Equal(at(12, 0)) || !first.Equal(at(12, 0).Add(-(maxCatchUpRuns-1)*time.Hour)) {
+			t.Errorf("dueRuns() = %v ... %v, want the %d runs up to %v", first, last, maxCatchUpRuns, at(12, 0))
+		}
+	})
+}
@@ -0,0 +1,87 @@
+alter table media_exporter.pdf_export_history
+  add attempts integer default 0 not null;
+
+alter table media_exporter.pdf_export_history
+  add last_error text;
+
+alter table media_exporter.pdf_export_history
+  add task_id varchar;
+
+alter table media_exporter.pdf_export_history
+  add size bigint;
+
+create index pdf_export_history_task_id_index
+  on media_exporter.pdf_export_history (task_id);
+
+alter type media_exporter.export_status add value 'cancelled';
+
+alter table media_exporter.pdf_export_history
+  add params jsonb;
+
+alter table media_exporter.pdf_export_history
+  add retry_of bigint;
+
+alter table media_exporter.pdf_export_history
+  add constraint pdf_export_history_retry_of_fk
+    foreign key (retry_of) references media_exporter.pdf_export_history (id)
+      on delete set null;
+
+alter table media_exporter.pdf_export_history
+  add trace_hash varchar;
+
+create unique index pdf_export_history_trace_hash_uindex
+  on media_exporter.pdf_export_history (trace_hash);
+
+alter table media_exporter.pdf_export_history
+  add digest varchar,
+  add digest_signature varchar;
+
+alter table media_exporter.pdf_export_history
+  add parent_id bigint;
+
+alter table media_exporter.pdf_export_history
+  add constraint pdf_export_history_parent_id_fk
+    foreign key (parent_id) references media_exporter.pdf_export_history (id)
+      on delete set null;
+
+create index pdf_export_history_parent_id_index
+  on media_exporter.pdf_export_history (parent_id);
+
+create table media_exporter.export_schedule
+(
+  id           bigserial
+    constraint export_schedule_pk
+      primary key,
+  dc           bigint                    not null
+    constraint export_schedule_wbt_domain_dc_fk
+      references directory.wbt_domain
+        on delete cascade,
+  name         varchar                   not null,
+  enabled      boolean default true      not null,
+  cron         varchar                   not null,
+  timezone     varchar default 'UTC'     not null,
+  catch_up     varchar default 'latest'  not null,
+  params       jsonb                     not null,
+  sealed_token varchar                   not null,
+  created_at   bigint                    not null,
+  updated_at   bigint                    not null,
+  created_by   bigint
+    constraint export_schedule_wbt_user_id_fk
+      references directory.wbt_user
+        on delete set null,
+  updated_by   bigint
+    constraint export_schedule_wbt_user_id_fk_2
+      references directory.wbt_user
+        on delete set null,
+  next_run_at  bigint,
+  last_run_at  bigint,
+  last_task_id varchar,
+  last_error   text
+);
+
+create index export_schedule_dc_index
+  on media_exporter.export_schedule (dc);
+
+create index export_schedule_next_run_at_index
+  on media_exporter.export_schedule (next_run_at)
+  where enabled;
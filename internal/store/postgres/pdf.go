@@ -1,15 +1,16 @@
 package postgres
 
 import (
-	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
-	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v5"
+	"github.com/webitel/media-exporter/auth"
 	"github.com/webitel/media-exporter/internal/domain/model/options"
 
 	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
@@ -23,32 +24,151 @@ type Pdf struct {
 
 // --- Screenrecording History ---
 
-func (m *Pdf) GetPdfExportHistory(req *domain.PdfHistoryRequestOptions) (*domain.HistoryResponse, error) {
-	filter := sq.And{
-		sq.Eq{"h.agent_id": req.AgentID},
-		sq.Or{
-			sq.Eq{"h.file_id": nil},
-			sq.Expr("EXISTS (SELECT 1 FROM storage.files f WHERE f.id = h.file_id AND f.removed IS NULL)"),
-		},
-	}
-	return m.listHistory(filter, int64(req.Page), int64(req.Size), req.Sort)
+func (m *Pdf) GetPdfExportHistory(opts *options.SearchOptions, req *domain.PdfHistoryRequestOptions) (*domain.HistoryResponse, error) {
+	return m.listHistory(opts, sq.Eq{"h.agent_id": req.AgentID}, int64(req.Page), int64(req.Size), req.Sort)
 }
 
 // --- Call History ---
 
-func (m *Pdf) GetCallPdfExportHistory(req *domain.CallHistoryRequestOptions) (*domain.HistoryResponse, error) {
-	filter := sq.And{
-		sq.Eq{"h.call_id": req.CallID},
-		sq.Or{
-			sq.Eq{"h.file_id": nil},
-			sq.Expr("EXISTS (SELECT 1 FROM storage.files f WHERE f.id = h.file_id AND f.removed IS NULL)"),
-		},
+func (m *Pdf) GetCallPdfExportHistory(opts *options.SearchOptions, req *domain.CallHistoryRequestOptions) (*domain.HistoryResponse, error) {
+	return m.listHistory(opts, callHistoryFilter(req.CallID), int64(req.Page), int64(req.Size), req.Sort)
+}
+
+// callHistoryFilter matches the exports of the call, including the exports of several calls
+// that record it among their calls.
+func callHistoryFilter(callID string) sq.Sqlizer {
+	return sq.Or{
+		sq.Eq{"h.call_id": callID},
+		sq.Expr("h.params @> jsonb_build_object('call_ids', jsonb_build_array(?::text))", callID),
+	}
+}
+
+// --- Batch Exports ---
+
+func (m *Pdf) ListChildExports(opts *options.SearchOptions, parentID int64) ([]*domain.HistoryRecord, error) {
+	db, err := m.storage.Database()
+	if err != nil {
+		return nil, dberr.NewDBInternalError("list_child_exports", err)
+	}
+
+	sqlStr, args, err := buildListChildrenQuery(opts.Auth.GetDomainId(), parentID).ToSql()
+	if err != nil {
+		return nil, dberr.NewDBInternalError("list_child_exports", err)
+	}
+
+	rows, err := db.Query(opts, sqlStr, args...)
+	if err != nil {
+		return nil, dberr.NewDBInternalError("list_child_exports", err)
+	}
+	defer rows.Close()
+
+	var records []*domain.HistoryRecord
+	for rows.Next() {
+		rec, err := scanHistoryRecord(rows)
+		if err != nil {
+			return nil, dberr.NewDBInternalError("list_child_exports", err)
+		}
+		records = append(records, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberr.NewDBInternalError("list_child_exports", err)
+	}
+
+	return records, nil
+}
+
+// --- Single Export ---
+
+func (m *Pdf) GetPdfExportByTaskID(opts *options.SearchOptions, taskID string) (*domain.HistoryRecord, error) {
+	return m.getHistoryRecord(opts, "get_pdf_export_by_task_id", sq.Eq{"h.task_id": taskID})
+}
+
+func (m *Pdf) GetPdfExportByID(opts *options.SearchOptions, id int64) (*domain.HistoryRecord, error) {
+	return m.getHistoryRecord(opts, "get_pdf_export_by_id", sq.Eq{"h.id": id})
+}
+
+// Internal helper for fetching a single record of the caller's domain
+func (m *Pdf) getHistoryRecord(opts *options.SearchOptions, op string, filter sq.Sqlizer) (*domain.HistoryRecord, error) {
+	db, err := m.storage.Database()
+	if err != nil {
+		return nil, dberr.NewDBInternalError(op, err)
+	}
+
+	sqlStr, args, err := buildGetHistoryQuery(opts.Auth.GetDomainId(), filter).ToSql()
+	if err != nil {
+		return nil, dberr.NewDBInternalError(op, err)
 	}
-	return m.listHistory(filter, int64(req.Page), int64(req.Size), req.Sort)
+
+	rec, err := scanHistoryRecord(db.QueryRow(opts, sqlStr, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, dberr.NewDBNotFoundError(op, "export not found")
+		}
+		return nil, dberr.NewDBInternalError(op, err)
+	}
+
+	return rec, nil
+}
+
+var historyColumns = []string{
+	"h.id", "h.name", "h.file_id", "h.mime",
+	"h.uploaded_at", "h.updated_at", "h.uploaded_by", "h.updated_by", "h.status",
+	"h.attempts", "h.last_error", "h.task_id", "h.size",
+	"h.params", "h.retry_of", "h.agent_id", "h.call_id", "h.trace_hash",
+	"h.digest", "h.digest_signature", "h.parent_id",
+}
+
+// encodeParams encodes the parameters of an export for the params column, nil if there are none.
+// Secrets such as a caller-supplied password are left out by their JSON tags.
+func encodeParams(params *domain.ExportParams) ([]byte, error) {
+	if params == nil {
+		return nil, nil
+	}
+	return json.Marshal(params)
+}
+
+// scanHistoryRecord reads a row selected with historyColumns.
+func scanHistoryRecord(row pgx.Row) (*domain.HistoryRecord, error) {
+	var rec domain.HistoryRecord
+	var fileID, size, retryOf, agentID, parentID sql.NullInt64
+	var lastError, taskID, callID, traceHash, digest, digestSignature sql.NullString
+	var params []byte
+
+	err := row.Scan(
+		&rec.ID, &rec.Name, &fileID, &rec.MimeType,
+		&rec.CreatedAt, &rec.UpdatedAt, &rec.CreatedBy, &rec.UpdatedBy, &rec.Status,
+		&rec.Attempts, &lastError, &taskID, &size,
+		&params, &retryOf, &agentID, &callID, &traceHash,
+		&digest, &digestSignature, &parentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(params) > 0 {
+		rec.Params = &domain.ExportParams{}
+		if err := json.Unmarshal(params, rec.Params); err != nil {
+			return nil, fmt.Errorf("decode params of export %d: %w", rec.ID, err)
+		}
+	}
+
+	rec.FileID = fileID.Int64
+	rec.LastError = lastError.String
+	rec.TaskID = taskID.String
+	rec.Size = size.Int64
+	rec.RetryOf = retryOf.Int64
+	rec.AgentID = agentID.Int64
+	rec.CallID = callID.String
+	rec.TraceHash = traceHash.String
+	rec.Digest = digest.String
+	rec.DigestSignature = digestSignature.String
+	rec.ParentID = parentID.Int64
+
+	return &rec, nil
 }
 
 // Internal helper for paginated history fetching
-func (m *Pdf) listHistory(filter sq.Sqlizer, page, size int64, sort string) (*domain.HistoryResponse, error) {
+func (m *Pdf) listHistory(opts *options.SearchOptions, filter sq.Sqlizer, page, size int64, sort string) (*domain.HistoryResponse, error) {
 	db, err := m.storage.Database()
 	if err != nil {
 		return nil, dberr.NewDBInternalError("list_history", err)
@@ -62,26 +182,13 @@ func (m *Pdf) listHistory(filter sq.Sqlizer, page, size int64, sort string) (*do
 	}
 
 	offset := (page - 1) * size
-	limit := size + 1
-
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	query := psql.
-		Select(
-			"h.id", "h.name", "h.file_id", "h.mime",
-			"h.uploaded_at", "h.updated_at", "h.uploaded_by", "h.updated_by", "h.status",
-		).
-		From("media_exporter.pdf_export_history h").
-		Where(filter).
-		OrderBy(parseSort(sort)).
-		Offset(uint64(offset)).
-		Limit(uint64(limit))
 
-	sqlStr, args, err := query.ToSql()
+	sqlStr, args, err := buildListHistoryQuery(opts.Auth.GetDomainId(), filter, page, size, sort).ToSql()
 	if err != nil {
 		return nil, dberr.NewDBInternalError("list_history", err)
 	}
 
-	rows, err := db.Query(context.Background(), sqlStr, args...)
+	rows, err := db.Query(opts, sqlStr, args...)
 	if err != nil {
 		return nil, dberr.NewDBInternalError("list_history", err)
 	}
@@ -89,23 +196,11 @@ func (m *Pdf) listHistory(filter sq.Sqlizer, page, size int64, sort string) (*do
 
 	var records []*domain.HistoryRecord
 	for rows.Next() {
-		var rec domain.HistoryRecord
-		var fileID sql.NullInt64
-		var status string
-
-		err := rows.Scan(
-			&rec.ID, &rec.Name, &fileID, &rec.MimeType,
-			&rec.CreatedAt, &rec.UpdatedAt, &rec.CreatedBy, &rec.UpdatedBy, &status,
-		)
+		rec, err := scanHistoryRecord(rows)
 		if err != nil {
 			return nil, dberr.NewDBInternalError("list_history", err)
 		}
-
-		if fileID.Valid {
-			rec.FileID = fileID.Int64
-		}
-		rec.Status = status
-		records = append(records, &rec)
+		records = append(records, rec)
 	}
 
 	hasNext := false
@@ -121,82 +216,89 @@ func (m *Pdf) listHistory(filter sq.Sqlizer, page, size int64, sort string) (*do
 	}, nil
 }
 
-// --- Mutations ---
+// --- Access ---
 
-func (m *Pdf) InsertPdfExportHistory(opts *options.CreateOptions, input *domain.NewExportHistory) (int64, error) {
+func (m *Pdf) CheckAgentAccess(opts *options.SearchOptions, agentID int64, access auth.AccessMode) (bool, error) {
 	db, err := m.storage.Database()
 	if err != nil {
-		return 0, dberr.NewDBInternalError("insert_pdf_export_history", err)
+		return false, dberr.NewDBInternalError("check_agent_access", err)
 	}
 
-	query := `
-       INSERT INTO media_exporter.pdf_export_history
-          (name, file_id, mime, uploaded_at, updated_at, uploaded_by, status, agent_id, call_id, dc)
-       VALUES ($1, $2, $3, $4, $4, $5, $6, $7, $8, $9)
-       RETURNING id
-    `
+	sqlStr, args, err := buildAgentAccessQuery(opts.Auth.GetDomainId(), agentID, opts.Auth.GetRoles(), access).ToSql()
+	if err != nil {
+		return false, dberr.NewDBInternalError("check_agent_access", err)
+	}
 
-	var id int64
+	var allowed bool
+	if err := db.QueryRow(opts, sqlStr, args...).Scan(&allowed); err != nil {
+		return false, dberr.NewDBInternalError("check_agent_access", err)
+	}
 
-	// Перевірка на 0 для file_id
-	var fileID sql.NullInt64
-	if input.FileID != 0 {
-		fileID = sql.NullInt64{Int64: input.FileID, Valid: true}
+	return allowed, nil
+}
+
+func (m *Pdf) CheckCallAccess(opts *options.SearchOptions, callID string, access auth.AccessMode) (bool, error) {
+	db, err := m.storage.Database()
+	if err != nil {
+		return false, dberr.NewDBInternalError("check_call_access", err)
 	}
 
-	var agentID sql.NullInt64
-	if input.AgentID != 0 {
-		agentID = sql.NullInt64{Int64: input.AgentID, Valid: true}
+	sqlStr, args, err := buildCallAccessQuery(opts.Auth.GetDomainId(), callID, opts.Auth.GetUserId(), opts.Auth.GetRoles(), access).ToSql()
+	if err != nil {
+		return false, dberr.NewDBInternalError("check_call_access", err)
 	}
 
-	var callID sql.NullString
-	if input.CallID != "" {
-		callID = sql.NullString{String: input.CallID, Valid: true}
+	var allowed bool
+	if err := db.QueryRow(opts, sqlStr, args...).Scan(&allowed); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "22P02" {
+			// Not a call ID at all, so no call of the domain.
+			return false, nil
+		}
+		return false, dberr.NewDBInternalError("check_call_access", err)
 	}
 
-	err = db.QueryRow(
-		context.Background(),
-		query,
-		input.Name,
-		fileID,
-		input.Mime,
-		input.UploadedAt,
-		input.UploadedBy,
-		input.Status,
-		agentID,
-		callID,
-		opts.Auth.GetDomainId(),
-	).Scan(&id)
+	return allowed, nil
+}
+
+// --- Mutations ---
+
+func (m *Pdf) InsertPdfExportHistory(opts *options.CreateOptions, input *domain.NewExportHistory) (int64, error) {
+	db, err := m.storage.Database()
 	if err != nil {
+		return 0, dberr.NewDBInternalError("insert_pdf_export_history", err)
+	}
+
+	params, err := encodeParams(input.Params)
+	if err != nil {
+		return 0, dberr.NewDBInternalError("insert_pdf_export_history", err)
+	}
+
+	query, args, err := buildInsertHistoryQuery(opts.Auth.GetDomainId(), input, params).ToSql()
+	if err != nil {
+		return 0, dberr.NewDBInternalError("insert_pdf_export_history", err)
+	}
+
+	var id int64
+	if err := db.QueryRow(opts, query, args...).Scan(&id); err != nil {
 		return 0, m.handlePgError("insert_export_history", err)
 	}
 
 	return id, nil
 }
 
-func (m *Pdf) UpdatePdfExportStatus(input *domain.UpdateExportStatus) error {
+func (m *Pdf) UpdatePdfExportStatus(opts *options.UpdateOptions, input *domain.UpdateExportStatus) error {
 	db, err := m.storage.Database()
 	if err != nil {
 		return dberr.NewDBInternalError("update_pdf_export_status", err)
 	}
 
-	query := `
-    UPDATE media_exporter.pdf_export_history
-    SET status = $1,
-        updated_at = $2,
-        updated_by = $3,
-        file_id = COALESCE(NULLIF($4, 0), file_id)
-    WHERE id = $5
-`
-	cmd, err := db.Exec(
-		context.Background(),
-		query,
-		input.Status,
-		time.Now().UnixMilli(),
-		input.UpdatedBy,
-		input.FileID,
-		input.ID,
-	)
+	sqlStr, args, err := buildUpdateStatusQuery(opts.Auth.GetDomainId(), input, opts.Time.UnixMilli()).ToSql()
+	if err != nil {
+		return dberr.NewDBInternalError("update_export_status", err)
+	}
+
+	cmd, err := db.Exec(opts, sqlStr, args...)
 	if err != nil {
 		return dberr.NewDBInternalError("update_export_status", err)
 	}
@@ -214,9 +316,12 @@ func (m *Pdf) DeletePdfExportRecord(opts *options.DeleteOptions, recordID int64)
 		return dberr.NewDBInternalError("delete_pdf_export_record", err)
 	}
 
-	query := `DELETE FROM media_exporter.pdf_export_history WHERE id = $1 AND dc = $2`
+	sqlStr, args, err := buildDeleteQuery(opts.Auth.GetDomainId(), recordID).ToSql()
+	if err != nil {
+		return dberr.NewDBInternalError("delete_pdf_export_record", err)
+	}
 
-	cmd, err := db.Exec(opts.Context, query, recordID, opts.Auth.GetDomainId())
+	cmd, err := db.Exec(opts.Context, sqlStr, args...)
 	if err != nil {
 		return dberr.NewDBInternalError("delete_pdf_export_record", err)
 	}
@@ -226,6 +331,138 @@ func (m *Pdf) DeletePdfExportRecord(opts *options.DeleteOptions, recordID int64)
 	return nil
 }
 
+// --- Query Builders ---
+// Every query is scoped by the domain (dc) of the caller, so records of
+// other domains can be neither read nor modified even if their IDs are known.
+
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+// visibleFileFilter hides records whose generated file was removed from storage.
+var visibleFileFilter = sq.Or{
+	sq.Eq{"h.file_id": nil},
+	sq.Expr("EXISTS (SELECT 1 FROM storage.files f WHERE f.id = h.file_id AND f.removed IS NULL)"),
+}
+
+func buildListHistoryQuery(domainID int64, filter sq.Sqlizer, page, size int64, sort string) sq.SelectBuilder {
+	return psql.
+		Select(historyColumns...).
+		From("media_exporter.pdf_export_history h").
+		Where(sq.And{sq.Eq{"h.dc": domainID}, filter, visibleFileFilter}).
+		OrderBy(parseSort(sort)).
+		Offset(uint64((page - 1) * size)).
+		Limit(uint64(size + 1))
+}
+
+func buildGetHistoryQuery(domainID int64, filter sq.Sqlizer) sq.SelectBuilder {
+	return psql.
+		Select(historyColumns...).
+		From("media_exporter.pdf_export_history h").
+		Where(sq.And{sq.Eq{"h.dc": domainID}, filter}).
+		Limit(1)
+}
+
+// buildListChildrenQuery lists the exports of a batch export in the order they were created.
+func buildListChildrenQuery(domainID, parentID int64) sq.SelectBuilder {
+	return psql.
+		Select(historyColumns...).
+		From("media_exporter.pdf_export_history h").
+		Where(sq.Eq{"h.dc": domainID, "h.parent_id": parentID}).
+		OrderBy("h.id ASC")
+}
+
+// buildInsertHistoryQuery inserts a record of the export into the domain, IDs of 0 are stored as NULL.
+func buildInsertHistoryQuery(domainID int64, input *domain.NewExportHistory, params []byte) sq.InsertBuilder {
+	return psql.
+		Insert("media_exporter.pdf_export_history").
+		Columns("name", "file_id", "mime", "uploaded_at", "updated_at", "uploaded_by", "status",
+			"agent_id", "call_id", "dc", "task_id", "params", "retry_of", "trace_hash", "parent_id").
+		Values(
+			input.Name,
+			nullInt64(input.FileID),
+			input.Mime,
+			input.UploadedAt,
+			input.UploadedAt,
+			input.UploadedBy,
+			input.Status,
+			nullInt64(input.AgentID),
+			sql.NullString{String: input.CallID, Valid: input.CallID != ""},
+			domainID,
+			input.TaskID,
+			params,
+			nullInt64(input.RetryOf),
+			sq.Expr("NULLIF(?, '')", input.TraceHash),
+			nullInt64(input.ParentID),
+		).
+		Suffix("RETURNING id")
+}
+
+// nullInt64 is NULL for 0.
+func nullInt64(v int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: v, Valid: v != 0}
+}
+
+func buildUpdateStatusQuery(domainID int64, input *domain.UpdateExportStatus, now int64) sq.UpdateBuilder {
+	return psql.
+		Update("media_exporter.pdf_export_history").
+		Set("status", input.Status).
+		Set("updated_at", now).
+		Set("updated_by", input.UpdatedBy).
+		Set("file_id", sq.Expr("COALESCE(NULLIF(?::bigint, 0), file_id)", input.FileID)).
+		Set("attempts", sq.Expr("GREATEST(attempts, ?::int)", input.Attempts)).
+		Set("last_error", sq.Expr("COALESCE(NULLIF(?::text, ''), last_error)", input.LastError)).
+		Set("size", sq.Expr("COALESCE(NULLIF(?::bigint, 0), size)", input.Size)).
+		Set("digest", sq.Expr("COALESCE(NULLIF(?::text, ''), digest)", input.Digest)).
+		Set("digest_signature", sq.Expr("COALESCE(NULLIF(?::text, ''), digest_signature)", input.DigestSignature)).
+		Where(sq.Eq{"id": input.ID, "dc": domainID})
+}
+
+func buildDeleteQuery(domainID, recordID int64) sq.DeleteBuilder {
+	return psql.
+		Delete("media_exporter.pdf_export_history").
+		Where(sq.Eq{"id": recordID, "dc": domainID})
+}
+
+// buildAgentAccessQuery checks the agent's access control list for a grant of the access mode
+// to any of the roles of the caller.
+func buildAgentAccessQuery(domainID, agentID int64, roles []int64, access auth.AccessMode) sq.SelectBuilder {
+	return psql.
+		Select().
+		Column(sq.Expr("EXISTS (?)", psql.
+			Select("1").
+			From("call_center.cc_agent_acl acl").
+			Where(sq.Eq{"acl.dc": domainID, "acl.object": agentID}).
+			Where("acl.subject = ANY(?::int8[])", roles).
+			Where("acl.access & ?::int = ?::int", int(access), int(access)),
+		))
+}
+
+// buildCallAccessQuery checks that the call is in the domain and was taken by the user,
+// or that its agent or its queue grants the access to one of the roles.
+func buildCallAccessQuery(domainID int64, callID string, userID int64, roles []int64, access auth.AccessMode) sq.SelectBuilder {
+	granted := func(aclTable, objectColumn string) sq.Sqlizer {
+		return sq.Expr("EXISTS (?)", psql.
+			Select("1").
+			From(aclTable+" acl").
+			Where("acl.dc = c.domain_id AND acl.object = "+objectColumn).
+			Where("acl.subject = ANY(?::int8[])", roles).
+			Where("acl.access & ?::int = ?::int", int(access), int(access)),
+		)
+	}
+	return psql.
+		Select().
+		Column(sq.Expr("EXISTS (?)", psql.
+			Select("1").
+			From("call_center.cc_calls_history c").
+			Where(sq.Eq{"c.domain_id": domainID}).
+			Where("c.id = ?::uuid", callID).
+			Where(sq.Or{
+				sq.Eq{"c.user_id": userID},
+				granted("call_center.cc_agent_acl", "c.agent_id"),
+				granted("call_center.cc_queue_acl", "c.queue_id"),
+			}),
+		))
+}
+
 func (m *Pdf) handlePgError(op string, err error) error {
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) {
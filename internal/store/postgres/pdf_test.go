@@ -0,0 +1,280 @@
+package postgres
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/webitel/media-exporter/auth"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+)
+
+var domainPredicate = regexp.MustCompile(`\bdc = \$(\d+)`)
+
+// boundDomain returns the domain ID bound to the dc predicate of the query,
+// failing the test if the query is not scoped by domain.
+func boundDomain(t *testing.T, query sq.Sqlizer) int64 {
+	t.Helper()
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+
+	match := domainPredicate.FindStringSubmatch(sqlStr)
+	if match == nil {
+		t.Fatalf("query is not scoped by domain: %s", sqlStr)
+	}
+	idx, _ := strconv.Atoi(match[1])
+	if idx < 1 || idx > len(args) {
+		t.Fatalf("placeholder $%d out of range, args = %v", idx, args)
+	}
+
+	domainID, ok := args[idx-1].(int64)
+	if !ok {
+		t.Fatalf("dc is bound to %T, want int64", args[idx-1])
+	}
+	return domainID
+}
+
+func TestQueriesAreScopedByDomain(t *testing.T) {
+	const callerDomain int64 = 2
+
+	tests := []struct {
+		name  string
+		query sq.Sqlizer
+	}{
+		{
+			name:  "agent history",
+			query: buildListHistoryQuery(callerDomain, sq.Eq{"h.agent_id": int64(10)}, 1, 20, ""),
+		},
+		{
+			name:  "call history",
+			query: buildListHistoryQuery(callerDomain, callHistoryFilter("call-1"), 3, 50, "-name"),
+		},
+		{
+			name:  "export by id",
+			query: buildGetHistoryQuery(callerDomain, sq.Eq{"h.id": int64(1)}),
+		},
+		{
+			name:  "export by task id",
+			query: buildGetHistoryQuery(callerDomain, sq.Eq{"h.task_id": "pdf_ss_1.pdf"}),
+		},
+		{
+			name: "status update",
+			query: buildUpdateStatusQuery(callerDomain, &domain.UpdateExportStatus{
+				ID:        1,
+				Status:    "done",
+				UpdatedBy: 7,
+			}, 1700000000000),
+		},
+		{
+			name:  "delete",
+			query: buildDeleteQuery(callerDomain, 1),
+		},
+		{
+			name:  "batch children",
+			query: buildListChildrenQuery(callerDomain, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := boundDomain(t, tt.query); got != callerDomain {
+				t.Errorf("query bound to domain %d, want caller domain %d", got, callerDomain)
+			}
+		})
+	}
+}
+
+// A record created in one domain must not be reachable by ID from another one:
+// the ID and the domain of the caller are both part of the same predicate.
+func TestCrossDomainAccessIsRefused(t *testing.T) {
+	const (
+		ownerDomain    int64 = 1
+		attackerDomain int64 = 2
+		recordID       int64 = 100
+	)
+
+	tests := []struct {
+		name  string
+		query sq.Sqlizer
+	}{
+		{
+			name:  "read",
+			query: buildGetHistoryQuery(attackerDomain, sq.Eq{"h.id": recordID}),
+		},
+		{
+			name:  "update",
+			query: buildUpdateStatusQuery(attackerDomain, &domain.UpdateExportStatus{ID: recordID, Status: "failed"}, 0),
+		},
+		{
+			name:  "delete",
+			query: buildDeleteQuery(attackerDomain, recordID),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlStr, args, err := tt.query.ToSql()
+			if err != nil {
+				t.Fatalf("ToSql() error = %v", err)
+			}
+			if got := boundDomain(t, tt.query); got == ownerDomain {
+				t.Errorf("query for domain %d matches records of domain %d: %s %v", attackerDomain, ownerDomain, sqlStr, args)
+			}
+			where := sqlStr[strings.Index(sqlStr, " WHERE ")+len(" WHERE "):]
+			if !strings.Contains(where, "id = $") || strings.Contains(where, " OR ") {
+				t.Errorf("id and domain are not required together: %s", sqlStr)
+			}
+		})
+	}
+}
+
+// A call is only found in the domain of the caller, and only through the caller or the ACLs of their roles.
+func TestCallAccessQueryIsScopedByDomain(t *testing.T) {
+	sqlStr, args, err := buildCallAccessQuery(2, "0b7c3a8e-5a0e-4d39-9d39-1f7bfb0d2f6a", 7, []int64{7, 3}, auth.Read).ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+	if !strings.Contains(sqlStr, "c.domain_id = $1") || args[0] != int64(2) {
+		t.Errorf("call access is not scoped by domain: %s %v", sqlStr, args)
+	}
+	for _, acl := range []string{"call_center.cc_agent_acl", "call_center.cc_queue_acl"} {
+		if !strings.Contains(sqlStr, acl) {
+			t.Errorf("call access does not check %s: %s", acl, sqlStr)
+		}
+	}
+}
+
+// paramsRow is a history row holding only an ID and the encoded params.
+type paramsRow struct {
+	id     int64
+	params []byte
+}
+
+func (r paramsRow) Scan(dest ...any) error {
+	*dest[0].(*int64) = r.id
+	*dest[13].(*[]byte) = r.params
+	return nil
+}
+
+// The parameters stored with an export are read back unchanged, so a retry repeats the same export,
+// but the password the caller chose is never stored.
+func TestExportParamsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *domain.ExportParams
+	}{
+		{
+			name: "pdf of an agent",
+			params: &domain.ExportParams{
+				Type:    domain.PdfExportType,
+				Channel: "screenrecording",
+				AgentID: 10,
+				From:    1700000000000,
+				To:      1700003600000,
+				IDs:     []int64{3, 1, 2},
+				Order:   &domain.ExportOrder{Sort: domain.SortUploadedDesc, GroupBy: domain.GroupBySession, SessionGapMs: 60000},
+				Pdf: &domain.PdfOptions{
+					Captions:      true,
+					Timezone:      "Europe/Kyiv",
+					PageSize:      domain.PageSizeLetter,
+					ImagesPerPage: 4,
+					Encryption:    &domain.PdfEncryption{Generated: true},
+				},
+			},
+		},
+		{
+			name: "audio of several calls",
+			params: &domain.ExportParams{
+				Type:       domain.AudioZipExportType,
+				CallID:     "call-1",
+				CallIDs:    []string{"call-1", "call-2"},
+				Transcript: &domain.TranscriptOptions{WindowFromMs: 1000, WindowToMs: 5000},
+			},
+		},
+		{
+			name: "batch",
+			params: &domain.ExportParams{
+				Type:  domain.BatchExportType,
+				Batch: &domain.BatchOptions{Type: domain.PdfExportType, AgentIDs: []int64{5, 6}, Combine: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := encodeParams(tt.params)
+			if err != nil {
+				t.Fatalf("encodeParams() error = %v", err)
+			}
+			rec, err := scanHistoryRecord(paramsRow{id: 1, params: encoded})
+			if err != nil {
+				t.Fatalf("scanHistoryRecord() error = %v", err)
+			}
+			if !reflect.DeepEqual(rec.Params, tt.params) {
+				t.Errorf("params = %+v, want %+v", rec.Params, tt.params)
+			}
+		})
+	}
+
+	t.Run("caller password", func(t *testing.T) {
+		params := &domain.ExportParams{
+			Type: domain.PdfExportType,
+			Pdf:  &domain.PdfOptions{Encryption: &domain.PdfEncryption{Password: "s3cret-pass"}},
+		}
+		encoded, err := encodeParams(params)
+		if err != nil {
+			t.Fatalf("encodeParams() error = %v", err)
+		}
+		if strings.Contains(string(encoded), "s3cret-pass") {
+			t.Fatalf("stored params contain the password: %s", encoded)
+		}
+		rec, err := scanHistoryRecord(paramsRow{id: 1, params: encoded})
+		if err != nil {
+			t.Fatalf("scanHistoryRecord() error = %v", err)
+		}
+		if enc := rec.Params.Pdf.Encryption; enc == nil || enc.Password != "" || enc.Generated {
+			t.Errorf("encryption = %+v, want a caller-supplied password that is not stored", enc)
+		}
+	})
+
+	t.Run("no params", func(t *testing.T) {
+		encoded, err := encodeParams(nil)
+		if err != nil || encoded != nil {
+			t.Fatalf("encodeParams(nil) = %s, %v, want nil", encoded, err)
+		}
+		rec, err := scanHistoryRecord(paramsRow{id: 1})
+		if err != nil {
+			t.Fatalf("scanHistoryRecord() error = %v", err)
+		}
+		if rec.Params != nil {
+			t.Errorf("params = %+v, want nil", rec.Params)
+		}
+	})
+}
+
+// A new record belongs to the domain of the caller.
+func TestInsertIsScopedByDomain(t *testing.T) {
+	input := &domain.NewExportHistory{TaskID: "pdf_ss_1.pdf", Name: "pdf_ss_1.pdf", Status: "pending", AgentID: 10}
+	sqlStr, args, err := buildInsertHistoryQuery(3, input, nil).ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+
+	columns := sqlStr[strings.Index(sqlStr, "(")+1 : strings.Index(sqlStr, ")")]
+	for i, col := range strings.Split(columns, ",") {
+		if strings.TrimSpace(col) != "dc" {
+			continue
+		}
+		if args[i] != int64(3) {
+			t.Errorf("record inserted into domain %v, want 3: %s %v", args[i], sqlStr, args)
+		}
+		return
+	}
+	t.Errorf("insert does not set the domain: %s", sqlStr)
+}
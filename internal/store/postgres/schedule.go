@@ -0,0 +1,380 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/webitel/media-exporter/internal/domain/model/options"
+
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+	dberr "github.com/webitel/media-exporter/internal/errors"
+	"github.com/webitel/media-exporter/internal/store"
+)
+
+type Schedule struct {
+	storage *Store
+}
+
+// --- Schedules ---
+
+func (m *Schedule) InsertExportSchedule(opts *options.CreateOptions, input *domain.NewExportSchedule) (int64, error) {
+	db, err := m.storage.Database()
+	if err != nil {
+		return 0, dberr.NewDBInternalError("insert_export_schedule", err)
+	}
+
+	query, err := buildInsertScheduleQuery(opts.Auth.GetDomainId(), opts.Auth.GetUserId(), opts.Time.UnixMilli(), input)
+	if err != nil {
+		return 0, dberr.NewDBInternalError("insert_export_schedule", err)
+	}
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return 0, dberr.NewDBInternalError("insert_export_schedule", err)
+	}
+
+	var id int64
+	if err := db.QueryRow(opts, sqlStr, args...).Scan(&id); err != nil {
+		return 0, dberr.NewDBInternalError("insert_export_schedule", err)
+	}
+	return id, nil
+}
+
+func (m *Schedule) GetExportSchedule(opts *options.SearchOptions, id int64) (*domain.ExportSchedule, error) {
+	db, err := m.storage.Database()
+	if err != nil {
+		return nil, dberr.NewDBInternalError("get_export_schedule", err)
+	}
+
+	sqlStr, args, err := buildGetScheduleQuery(opts.Auth.GetDomainId(), id).ToSql()
+	if err != nil {
+		return nil, dberr.NewDBInternalError("get_export_schedule", err)
+	}
+
+	sched, err := scanSchedule(db.QueryRow(opts, sqlStr, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, dberr.NewDBNotFoundError("get_export_schedule", "export schedule not found")
+		}
+		return nil, dberr.NewDBInternalError("get_export_schedule", err)
+	}
+	return sched, nil
+}
+
+func (m *Schedule) ListExportSchedules(opts *options.SearchOptions, ownerID, page, size int64) (*domain.ExportScheduleList, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+
+	schedules, err := m.querySchedules(opts, "list_export_schedules", buildListSchedulesQuery(opts.Auth.GetDomainId(), ownerID, page, size))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &domain.ExportScheduleList{Data: schedules}
+	if int64(len(schedules)) > size {
+		res.Next = true
+		res.Data = schedules[:size]
+	}
+	return res, nil
+}
+
+func (m *Schedule) UpdateExportSchedule(opts *options.UpdateOptions, id int64, input *domain.NewExportSchedule) error {
+	db, err := m.storage.Database()
+	if err != nil {
+		return dberr.NewDBInternalError("update_export_schedule", err)
+	}
+
+	query, err := buildUpdateScheduleQuery(opts.Auth.GetDomainId(), opts.Auth.GetUserId(), opts.Time.UnixMilli(), id, input)
+	if err != nil {
+		return dberr.NewDBInternalError("update_export_schedule", err)
+	}
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return dberr.NewDBInternalError("update_export_schedule", err)
+	}
+
+	cmd, err := db.Exec(opts, sqlStr, args...)
+	if err != nil {
+		return dberr.NewDBInternalError("update_export_schedule", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return dberr.NewDBNotFoundError("update_export_schedule", fmt.Sprintf("id=%d", id))
+	}
+	return nil
+}
+
+func (m *Schedule) DeleteExportSchedule(opts *options.DeleteOptions, id int64) error {
+	db, err := m.storage.Database()
+	if err != nil {
+		return dberr.NewDBInternalError("delete_export_schedule", err)
+	}
+
+	sqlStr, args, err := buildDeleteScheduleQuery(opts.Auth.GetDomainId(), id).ToSql()
+	if err != nil {
+		return dberr.NewDBInternalError("delete_export_schedule", err)
+	}
+
+	cmd, err := db.Exec(opts, sqlStr, args...)
+	if err != nil {
+		return dberr.NewDBInternalError("delete_export_schedule", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return dberr.NewDBNotFoundError("delete_export_schedule", fmt.Sprintf("id=%d", id))
+	}
+	return nil
+}
+
+// --- Teams ---
+
+func (m *Schedule) ListTeamAgents(opts *options.SearchOptions, teamIDs []int64) ([]int64, error) {
+	db, err := m.storage.Database()
+	if err != nil {
+		return nil, dberr.NewDBInternalError("list_team_agents", err)
+	}
+
+	sqlStr, args, err := buildTeamAgentsQuery(opts.Auth.GetDomainId(), teamIDs).ToSql()
+	if err != nil {
+		return nil, dberr.NewDBInternalError("list_team_agents", err)
+	}
+
+	rows, err := db.Query(opts, sqlStr, args...)
+	if err != nil {
+		return nil, dberr.NewDBInternalError("list_team_agents", err)
+	}
+	agentIDs, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, dberr.NewDBInternalError("list_team_agents", err)
+	}
+	return agentIDs, nil
+}
+
+// --- Scheduler ---
+
+func (m *Schedule) ListDueExportSchedules(ctx context.Context, now int64, limit int) ([]*domain.ExportSchedule, error) {
+	return m.querySchedules(ctx, "list_due_export_schedules", buildDueSchedulesQuery(now, limit))
+}
+
+func (m *Schedule) ClaimExportScheduleRun(ctx context.Context, id, nextRunAt, newNextRunAt int64) (bool, error) {
+	db, err := m.storage.Database()
+	if err != nil {
+		return false, dberr.NewDBInternalError("claim_export_schedule_run", err)
+	}
+
+	sqlStr, args, err := buildClaimScheduleRunQuery(id, nextRunAt, newNextRunAt).ToSql()
+	if err != nil {
+		return false, dberr.NewDBInternalError("claim_export_schedule_run", err)
+	}
+
+	cmd, err := db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return false, dberr.NewDBInternalError("claim_export_schedule_run", err)
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+func (m *Schedule) RecordExportScheduleRun(ctx context.Context, id int64, run *domain.ExportScheduleRun) error {
+	db, err := m.storage.Database()
+	if err != nil {
+		return dberr.NewDBInternalError("record_export_schedule_run", err)
+	}
+
+	sqlStr, args, err := buildRecordScheduleRunQuery(id, run).ToSql()
+	if err != nil {
+		return dberr.NewDBInternalError("record_export_schedule_run", err)
+	}
+
+	if _, err := db.Exec(ctx, sqlStr, args...); err != nil {
+		return dberr.NewDBInternalError("record_export_schedule_run", err)
+	}
+	return nil
+}
+
+// Internal helper for reading the schedules selected by a query
+func (m *Schedule) querySchedules(ctx context.Context, op string, query sq.SelectBuilder) ([]*domain.ExportSchedule, error) {
+	db, err := m.storage.Database()
+	if err != nil {
+		return nil, dberr.NewDBInternalError(op, err)
+	}
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, dberr.NewDBInternalError(op, err)
+	}
+
+	rows, err := db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, dberr.NewDBInternalError(op, err)
+	}
+	defer rows.Close()
+
+	var schedules []*domain.ExportSchedule
+	for rows.Next() {
+		sched, err := scanSchedule(rows)
+		if err != nil {
+			return nil, dberr.NewDBInternalError(op, err)
+		}
+		schedules = append(schedules, sched)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberr.NewDBInternalError(op, err)
+	}
+	return schedules, nil
+}
+
+var scheduleColumns = []string{
+	"s.id", "s.dc", "s.name", "s.enabled", "s.cron", "s.timezone", "s.catch_up",
+	"s.params", "s.sealed_token", "s.created_at", "s.updated_at", "s.created_by", "s.updated_by",
+	"s.next_run_at", "s.last_run_at", "s.last_task_id", "s.last_error",
+}
+
+// scanSchedule reads a row selected with scheduleColumns.
+func scanSchedule(row pgx.Row) (*domain.ExportSchedule, error) {
+	var sched domain.ExportSchedule
+	var createdBy, updatedBy, nextRunAt, lastRunAt sql.NullInt64
+	var lastTaskID, lastError sql.NullString
+	var params []byte
+
+	err := row.Scan(
+		&sched.ID, &sched.DomainID, &sched.Spec.Name, &sched.Spec.Enabled, &sched.Spec.Cron, &sched.Spec.Timezone, &sched.Spec.CatchUp,
+		&params, &sched.SealedToken, &sched.CreatedAt, &sched.UpdatedAt, &createdBy, &updatedBy,
+		&nextRunAt, &lastRunAt, &lastTaskID, &lastError,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(params, &sched.Spec.Params); err != nil {
+		return nil, fmt.Errorf("decode params of export schedule %d: %w", sched.ID, err)
+	}
+
+	sched.CreatedBy = createdBy.Int64
+	sched.UpdatedBy = updatedBy.Int64
+	sched.NextRunAt = nextRunAt.Int64
+	sched.LastRunAt = lastRunAt.Int64
+	sched.LastTaskID = lastTaskID.String
+	sched.LastError = lastError.String
+
+	return &sched, nil
+}
+
+// --- Query Builders ---
+// Queries of the API are scoped by the domain (dc) of the caller like those of the history.
+// Queries of the scheduler serve all domains and address schedules by the ID it read.
+
+func buildInsertScheduleQuery(domainID, userID, now int64, input *domain.NewExportSchedule) (sq.InsertBuilder, error) {
+	params, err := json.Marshal(input.Spec.Params)
+	if err != nil {
+		return sq.InsertBuilder{}, err
+	}
+	spec := input.Spec
+	return psql.
+		Insert("media_exporter.export_schedule").
+		Columns(
+			"dc", "name", "enabled", "cron", "timezone", "catch_up", "params", "sealed_token",
+			"created_at", "updated_at", "created_by", "updated_by", "next_run_at",
+		).
+		Values(
+			domainID, spec.Name, spec.Enabled, spec.Cron, spec.Timezone, spec.CatchUp, params, input.SealedToken,
+			now, now, userID, userID, sq.Expr("NULLIF(?::bigint, 0)", input.NextRunAt),
+		).
+		Suffix("RETURNING id"), nil
+}
+
+func buildGetScheduleQuery(domainID, id int64) sq.SelectBuilder {
+	return psql.
+		Select(scheduleColumns...).
+		From("media_exporter.export_schedule s").
+		Where(sq.Eq{"s.dc": domainID, "s.id": id}).
+		Limit(1)
+}
+
+// buildListSchedulesQuery lists the schedules of the domain, only those of the user ownerID unless it is 0.
+func buildListSchedulesQuery(domainID, ownerID, page, size int64) sq.SelectBuilder {
+	query := psql.
+		Select(scheduleColumns...).
+		From("media_exporter.export_schedule s").
+		Where(sq.Eq{"s.dc": domainID}).
+		OrderBy("s.id DESC").
+		Offset(uint64((page - 1) * size)).
+		Limit(uint64(size + 1))
+	if ownerID != 0 {
+		query = query.Where(sq.Or{sq.Eq{"s.created_by": ownerID}, sq.Eq{"s.updated_by": ownerID}})
+	}
+	return query
+}
+
+func buildUpdateScheduleQuery(domainID, userID, now, id int64, input *domain.NewExportSchedule) (sq.UpdateBuilder, error) {
+	params, err := json.Marshal(input.Spec.Params)
+	if err != nil {
+		return sq.UpdateBuilder{}, err
+	}
+	spec := input.Spec
+	return psql.
+		Update("media_exporter.export_schedule").
+		Set("name", spec.Name).
+		Set("enabled", spec.Enabled).
+		Set("cron", spec.Cron).
+		Set("timezone", spec.Timezone).
+		Set("catch_up", spec.CatchUp).
+		Set("params", params).
+		Set("sealed_token", input.SealedToken).
+		Set("updated_at", now).
+		Set("updated_by", userID).
+		Set("next_run_at", sq.Expr("NULLIF(?::bigint, 0)", input.NextRunAt)).
+		Where(sq.Eq{"id": id, "dc": domainID}), nil
+}
+
+func buildDeleteScheduleQuery(domainID, id int64) sq.DeleteBuilder {
+	return psql.
+		Delete("media_exporter.export_schedule").
+		Where(sq.Eq{"id": id, "dc": domainID})
+}
+
+func buildTeamAgentsQuery(domainID int64, teamIDs []int64) sq.SelectBuilder {
+	return psql.
+		Select("a.id").
+		From("call_center.cc_agent a").
+		Where(sq.Eq{"a.domain_id": domainID}).
+		Where("a.team_id = ANY(?::int8[])", teamIDs).
+		OrderBy("a.id")
+}
+
+func buildDueSchedulesQuery(now int64, limit int) sq.SelectBuilder {
+	return psql.
+		Select(scheduleColumns...).
+		From("media_exporter.export_schedule s").
+		Where(sq.And{sq.Eq{"s.enabled": true}, sq.LtOrEq{"s.next_run_at": now}}).
+		OrderBy("s.next_run_at ASC").
+		Limit(uint64(limit))
+}
+
+func buildClaimScheduleRunQuery(id, nextRunAt, newNextRunAt int64) sq.UpdateBuilder {
+	return psql.
+		Update("media_exporter.export_schedule").
+		Set("next_run_at", sq.Expr("NULLIF(?::bigint, 0)", newNextRunAt)).
+		Where(sq.Eq{"id": id, "next_run_at": nextRunAt, "enabled": true})
+}
+
+func buildRecordScheduleRunQuery(id int64, run *domain.ExportScheduleRun) sq.UpdateBuilder {
+	return psql.
+		Update("media_exporter.export_schedule").
+		Set("last_run_at", run.At).
+		Set("last_task_id", sq.Expr("NULLIF(?::text, '')", run.TaskID)).
+		Set("last_error", sq.Expr("NULLIF(?::text, '')", run.Error)).
+		Where(sq.Eq{"id": id})
+}
+
+func NewScheduleStore(store *Store) (store.ScheduleStore, error) {
+	if store == nil {
+		return nil, dberr.NewDBInternalError("new_store", errors.New("store is nil"))
+	}
+	return &Schedule{storage: store}, nil
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
+)
+
+func TestScheduleQueriesAreScopedByDomain(t *testing.T) {
+	const callerDomain int64 = 2
+
+	update, err := buildUpdateScheduleQuery(callerDomain, 7, 1700000000000, 1, &domain.NewExportSchedule{
+		Spec: domain.ExportScheduleSpec{Name: "weekly", Cron: "0 6 * * MON", CatchUp: domain.CatchUpLatest},
+	})
+	if err != nil {
+		t.Fatalf("buildUpdateScheduleQuery() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query sq.Sqlizer
+	}{
+		{name: "schedule by id", query: buildGetScheduleQuery(callerDomain, 1)},
+		{name: "schedules", query: buildListSchedulesQuery(callerDomain, 0, 2, 20)},
+		{name: "schedule update", query: update},
+		{name: "schedule delete", query: buildDeleteScheduleQuery(callerDomain, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := boundDomain(t, tt.query); got != callerDomain {
+				t.Errorf("query bound to domain %d, want caller domain %d", got, callerDomain)
+			}
+		})
+	}
+}
+
+func TestScheduleListIsScopedByOwner(t *testing.T) {
+	sqlStr, args, err := buildListSchedulesQuery(2, 7, 1, 20).ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+	if !strings.Contains(sqlStr, "(s.created_by = $2 OR s.updated_by = $3)") || args[1] != int64(7) || args[2] != int64(7) {
+		t.Errorf("schedules are not scoped by owner: %s %v", sqlStr, args)
+	}
+
+	sqlStr, _, _ = buildListSchedulesQuery(2, 0, 1, 20).ToSql()
+	if strings.Contains(sqlStr, "created_by =") {
+		t.Errorf("schedules of every owner are scoped by owner: %s", sqlStr)
+	}
+}
+
+func TestTeamAgentsQueryIsScopedByDomain(t *testing.T) {
+	sqlStr, args, err := buildTeamAgentsQuery(2, []int64{5, 6}).ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+	if !strings.Contains(sqlStr, "a.domain_id = $1") || len(args) == 0 || args[0] != int64(2) {
+		t.Errorf("team agents are not scoped by domain: %s %v", sqlStr, args)
+	}
+}
+
+// A run must only be claimed while the schedule still expects it, so that two schedulers,
+// or a scheduler and an update of the schedule, never both act on the same occurrence.
+func TestClaimScheduleRunRequiresExpectedRun(t *testing.T) {
+	sqlStr, args, err := buildClaimScheduleRunQuery(1, 1700000000000, 1700000600000).ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+	where := sqlStr[strings.Index(sqlStr, " WHERE ")+len(" WHERE "):]
+	for _, column := range []string{"id = $", "next_run_at = $", "enabled = $"} {
+		if !strings.Contains(where, column) {
+			t.Errorf("claim does not require %q: %s %v", column, sqlStr, args)
+		}
+	}
+}
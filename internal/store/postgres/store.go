@@ -13,9 +13,10 @@ import (
 
 // Store is the struct implementing the Store interface.
 type Store struct {
-	pdfStore store.PdfStore
-	config   *conf.DatabaseConfig
-	conn     *pgxpool.Pool
+	pdfStore      store.PdfStore
+	scheduleStore store.ScheduleStore
+	config        *conf.DatabaseConfig
+	conn          *pgxpool.Pool
 }
 
 // New creates a new Store instance.
@@ -34,6 +35,17 @@ func (s *Store) Pdf() store.PdfStore {
 	return s.pdfStore
 }
 
+func (s *Store) Schedule() store.ScheduleStore {
+	if s.scheduleStore == nil {
+		ss, err := NewScheduleStore(s)
+		if err != nil {
+			return nil
+		}
+		s.scheduleStore = ss
+	}
+	return s.scheduleStore
+}
+
 // Database returns the database connection or a custom error if it is not opened.
 func (s *Store) Database() (*pgxpool.Pool, error) { // Return custom DB error
 	if s.conn == nil {
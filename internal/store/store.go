@@ -1,32 +1,90 @@
 package store
 
 import (
+	"context"
+
+	"github.com/webitel/media-exporter/auth"
 	"github.com/webitel/media-exporter/internal/domain/model/options"
 	domain "github.com/webitel/media-exporter/internal/domain/model/pdf"
 )
 
 type Store interface {
 	Pdf() PdfStore
+	Schedule() ScheduleStore
 
 	// ------------ Database Management ------------ //
 	Open() error
 	Close() error
 }
 
+// PdfStore persists the export history. Every method is scoped by the domain
+// of the caller taken from the options.
 type PdfStore interface {
 	// InsertPdfExportHistory adds a new record to the export history table.
 	// Used by both screenrecordings and calls.
 	InsertPdfExportHistory(opts *options.CreateOptions, input *domain.NewExportHistory) (int64, error)
 
 	// UpdatePdfExportStatus updates the processing status and final file reference.
-	UpdatePdfExportStatus(input *domain.UpdateExportStatus) error
+	UpdatePdfExportStatus(opts *options.UpdateOptions, input *domain.UpdateExportStatus) error
 
 	// GetPdfExportHistory retrieves paginated history for screenrecordings by AgentID.
-	GetPdfExportHistory(req *domain.PdfHistoryRequestOptions) (*domain.HistoryResponse, error)
+	GetPdfExportHistory(opts *options.SearchOptions, req *domain.PdfHistoryRequestOptions) (*domain.HistoryResponse, error)
 
 	// GetCallPdfExportHistory retrieves paginated history for calls by CallID.
-	GetCallPdfExportHistory(req *domain.CallHistoryRequestOptions) (*domain.HistoryResponse, error)
+	GetCallPdfExportHistory(opts *options.SearchOptions, req *domain.CallHistoryRequestOptions) (*domain.HistoryResponse, error)
+
+	// GetPdfExportByTaskID retrieves a single history record by the ID of the task that produced it.
+	GetPdfExportByTaskID(opts *options.SearchOptions, taskID string) (*domain.HistoryRecord, error)
+
+	// GetPdfExportByID retrieves a single history record by its ID.
+	GetPdfExportByID(opts *options.SearchOptions, id int64) (*domain.HistoryRecord, error)
+
+	// ListChildExports retrieves the exports of a batch export, oldest first.
+	ListChildExports(opts *options.SearchOptions, parentID int64) ([]*domain.HistoryRecord, error)
 
 	// DeletePdfExportRecord removes a specific record from the history.
 	DeletePdfExportRecord(opts *options.DeleteOptions, recordID int64) error
+
+	// CheckAgentAccess reports whether the agent's access control list grants the access mode
+	// to any of the roles of the caller.
+	CheckAgentAccess(opts *options.SearchOptions, agentID int64, access auth.AccessMode) (bool, error)
+
+	// CheckCallAccess reports whether the caller took part in the call, or the access control list
+	// of its agent or of its queue grants the access mode to any of the roles of the caller.
+	CheckCallAccess(opts *options.SearchOptions, callID string, access auth.AccessMode) (bool, error)
+}
+
+// ScheduleStore persists the export schedules. Every method is scoped by the domain
+// of the caller taken from the options, except those of the scheduler, which serves all domains.
+type ScheduleStore interface {
+	// InsertExportSchedule adds a new schedule and returns its ID.
+	InsertExportSchedule(opts *options.CreateOptions, input *domain.NewExportSchedule) (int64, error)
+
+	// GetExportSchedule retrieves a single schedule by its ID.
+	GetExportSchedule(opts *options.SearchOptions, id int64) (*domain.ExportSchedule, error)
+
+	// ListExportSchedules retrieves a page of the schedules, newest first: those created or last saved
+	// by the user ownerID, or all of them if it is 0.
+	ListExportSchedules(opts *options.SearchOptions, ownerID, page, size int64) (*domain.ExportScheduleList, error)
+
+	// UpdateExportSchedule replaces the spec, the owner and the next run of a schedule.
+	UpdateExportSchedule(opts *options.UpdateOptions, id int64, input *domain.NewExportSchedule) error
+
+	// DeleteExportSchedule removes a schedule.
+	DeleteExportSchedule(opts *options.DeleteOptions, id int64) error
+
+	// ListTeamAgents retrieves the agents of the teams.
+	ListTeamAgents(opts *options.SearchOptions, teamIDs []int64) ([]int64, error)
+
+	// ListDueExportSchedules retrieves the enabled schedules of all domains whose next run is not after now,
+	// the longest overdue first. Used by the scheduler only.
+	ListDueExportSchedules(ctx context.Context, now int64, limit int) ([]*domain.ExportSchedule, error)
+
+	// ClaimExportScheduleRun moves the next run of a schedule from nextRunAt to newNextRunAt, 0 for none.
+	// It reports false if the next run is no longer nextRunAt, e.g. because another scheduler claimed it
+	// or the schedule was updated. Used by the scheduler only.
+	ClaimExportScheduleRun(ctx context.Context, id, nextRunAt, newNextRunAt int64) (bool, error)
+
+	// RecordExportScheduleRun stores the outcome of a run of a schedule. Used by the scheduler only.
+	RecordExportScheduleRun(ctx context.Context, id int64, run *domain.ExportScheduleRun) error
 }
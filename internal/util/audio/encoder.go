@@ -0,0 +1,36 @@
+package audio
+
+import (
+	"context"
+	"time"
+)
+
+// Format is the codec and container of an encoded audio file.
+type Format string
+
+const (
+	MP3  Format = "mp3"
+	WAV  Format = "wav"
+	Opus Format = "opus"
+)
+
+// MimeType returns the MIME type of files in the format.
+func (f Format) MimeType() string {
+	switch f {
+	case WAV:
+		return "audio/wav"
+	case Opus:
+		return "audio/ogg"
+	default:
+		return "audio/mpeg"
+	}
+}
+
+// Encoder joins audio recordings into a single file.
+type Encoder interface {
+	// Concat writes the inputs, one after another in the given order, to a single file at outPath.
+	// Inputs may differ in codec, sample rate and number of channels.
+	// onProgress, if not nil, is called with the duration encoded so far.
+	// Encoding stops with ctx.Err() once ctx is done.
+	Concat(ctx context.Context, inputs []string, outPath string, format Format, onProgress func(done time.Duration)) error
+}
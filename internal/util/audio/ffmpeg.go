@@ -0,0 +1,157 @@
+package audio
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultFFmpegPath = "/usr/bin/ffmpeg"
+
+	// sampleRate and channelLayout are what every input is converted to before joining.
+	// Opus only supports a few rates, 48 kHz is one of them.
+	sampleRate    = 48000
+	channelLayout = "stereo"
+	// stderrLimit is how much of the ffmpeg diagnostics is kept for the error of a failed run.
+	stderrLimit = 4 * 1024
+)
+
+// FFmpeg joins recordings by running the ffmpeg binary. Inputs are resampled to a common
+// format and joined by the concat filter, so they do not need to share a codec.
+type FFmpeg struct {
+	path    string
+	tempDir string
+}
+
+// NewFFmpeg returns an encoder running the ffmpeg binary at path.
+// Its working files are created in tempDir.
+func NewFFmpeg(path, tempDir string) *FFmpeg {
+	if path == "" {
+		path = DefaultFFmpegPath
+	}
+	return &FFmpeg{path: path, tempDir: tempDir}
+}
+
+func (e *FFmpeg) Concat(ctx context.Context, inputs []string, outPath string, format Format, onProgress func(done time.Duration)) error {
+	if len(inputs) == 0 {
+		return fmt.Errorf("no recordings to join")
+	}
+
+	workDir, err := os.MkdirTemp(e.tempDir, "audio-")
+	if err != nil {
+		return fmt.Errorf("create work dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(workDir) }()
+
+	filterPath := filepath.Join(workDir, "filter.txt")
+	if err := os.WriteFile(filterPath, []byte(filterGraph(len(inputs))), 0o600); err != nil {
+		return fmt.Errorf("write filter graph: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, e.path, ffmpegArgs(inputs, filterPath, outPath, format)...)
+	stderr := &tailBuffer{limit: stderrLimit}
+	cmd.Stderr = stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("ffmpeg stdout: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start ffmpeg: %w", err)
+	}
+	readProgress(stdout, onProgress)
+
+	if err := cmd.Wait(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// ffmpegArgs returns the command line joining the inputs with the filter graph into the format.
+func ffmpegArgs(inputs []string, filterPath, outPath string, format Format) []string {
+	args := []string{
+		"-hide_banner", "-nostdin", "-nostats", "-y",
+		"-loglevel", "error",
+		"-progress", "pipe:1",
+	}
+	for _, in := range inputs {
+		args = append(args, "-i", in)
+	}
+	args = append(args,
+		"-filter_complex_script", filterPath,
+		"-map", "[out]",
+		"-vn",
+	)
+
+	switch format {
+	case WAV:
+		args = append(args, "-c:a", "pcm_s16le", "-f", "wav")
+	case Opus:
+		args = append(args, "-c:a", "libopus", "-b:a", "64k", "-f", "ogg")
+	default:
+		args = append(args, "-c:a", "libmp3lame", "-q:a", "2", "-f", "mp3")
+	}
+
+	return append(args, outPath)
+}
+
+// filterGraph converts every one of n inputs to the common sample rate and channel layout
+// and joins them into the [out] stream.
+func filterGraph(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "[%d:a:0]aresample=%d,aformat=sample_fmts=fltp:channel_layouts=%s[a%d];\n", i, sampleRate, channelLayout, i)
+	}
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "[a%d]", i)
+	}
+	fmt.Fprintf(&b, "concat=n=%d:v=0:a=1[out]", n)
+	return b.String()
+}
+
+// readProgress reports the output time of ffmpeg's -progress output until it is closed.
+func readProgress(r io.Reader, onProgress func(done time.Duration)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "out_time_us=")
+		if !ok || onProgress == nil {
+			continue
+		}
+		us, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || us < 0 {
+			continue
+		}
+		onProgress(time.Duration(us) * time.Microsecond)
+	}
+	_, _ = io.Copy(io.Discard, r)
+}
+
+// tailBuffer keeps the last limit bytes written to it.
+type tailBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf.Write(p)
+	if over := t.buf.Len() - t.limit; over > 0 {
+		t.buf.Next(over)
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	return t.buf.String()
+}
@@ -0,0 +1,55 @@
+package audio
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilterGraph(t *testing.T) {
+	got := filterGraph(2)
+	want := "[0:a:0]aresample=48000,aformat=sample_fmts=fltp:channel_layouts=stereo[a0];\n" +
+		"[1:a:0]aresample=48000,aformat=sample_fmts=fltp:channel_layouts=stereo[a1];\n" +
+		"[a0][a1]concat=n=2:v=0:a=1[out]"
+	if got != want {
+		t.Errorf("filterGraph() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFFmpegArgs(t *testing.T) {
+	mp3 := ffmpegArgs([]string{"/w/a.wav", "/w/b.mp3"}, "/w/filter.txt", "/out/a.mp3", MP3)
+	if mp3[len(mp3)-1] != "/out/a.mp3" {
+		t.Errorf("output path must be the last argument: %v", mp3)
+	}
+	if i := slices.Index(mp3, "/w/a.wav"); i < 1 || mp3[i-1] != "-i" || mp3[i+1] != "-i" || mp3[i+2] != "/w/b.mp3" {
+		t.Errorf("inputs must be passed in order: %v", mp3)
+	}
+	for _, want := range []string{"libmp3lame", "/w/filter.txt", "[out]"} {
+		if !slices.Contains(mp3, want) {
+			t.Errorf("mp3 args miss %q: %v", want, mp3)
+		}
+	}
+
+	opus := ffmpegArgs([]string{"/w/a.wav"}, "/w/filter.txt", "/out/a.opus", Opus)
+	if !slices.Contains(opus, "libopus") || !slices.Contains(opus, "ogg") {
+		t.Errorf("opus args use the wrong codec: %v", opus)
+	}
+	wav := ffmpegArgs([]string{"/w/a.wav"}, "/w/filter.txt", "/out/a.wav", WAV)
+	if !slices.Contains(wav, "pcm_s16le") {
+		t.Errorf("wav args use the wrong codec: %v", wav)
+	}
+}
+
+func TestReadProgress(t *testing.T) {
+	out := "out_time_us=1500000\nprogress=continue\nout_time_us=N/A\nout_time_us=3000000\nprogress=end\n"
+
+	var got []time.Duration
+	readProgress(strings.NewReader(out), func(done time.Duration) {
+		got = append(got, done)
+	})
+
+	if want := []time.Duration{1500 * time.Millisecond, 3 * time.Second}; !slices.Equal(got, want) {
+		t.Errorf("progress = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package pdf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Attachment is a file embedded into a document, which viewers list next to the pages.
+type Attachment struct {
+	Name        string
+	MimeType    string
+	Description string
+	Data        []byte
+}
+
+// attachment is an embedded file written to the document, listed in the catalog on Close.
+type attachment struct {
+	name string
+	obj  int
+}
+
+// Attach embeds the file into the document. Attachments are independent of the pages,
+// so it may be called at any time before Close.
+func (d *Document) Attach(a Attachment) error {
+	dict := "/Type /EmbeddedFile"
+	if a.MimeType != "" {
+		dict += " /Subtype /" + escapeName(a.MimeType)
+	}
+	fileObj := d.w.Reserve()
+	if err := d.w.WriteStream(fileObj, fmt.Sprintf("%s /Params << /Size %d >>", dict, len(a.Data)), a.Data); err != nil {
+		return err
+	}
+
+	spec := fmt.Sprintf("/Type /Filespec /F %s /UF %s /EF << /F %s /UF %[3]s >>", Text(a.Name), TextString(a.Name), Ref(fileObj))
+	if a.Description != "" {
+		spec += " /Desc " + TextString(a.Description)
+	}
+	specObj := d.w.Reserve()
+	if err := d.w.WriteObject(specObj, "<< "+spec+" >>"); err != nil {
+		return err
+	}
+	d.attachments = append(d.attachments, attachment{name: a.Name, obj: specObj})
+	return nil
+}
+
+// embeddedFiles returns the name tree of the attachments, keyed by their names in sorted order.
+func (d *Document) embeddedFiles() string {
+	sort.SliceStable(d.attachments, func(i, j int) bool { return d.attachments[i].name < d.attachments[j].name })
+	names := make([]string, len(d.attachments))
+	for i, a := range d.attachments {
+		names[i] = Text(a.name) + " " + Ref(a.obj)
+	}
+	return "<< /Names [" + strings.Join(names, " ") + "] >>"
+}
+
+// escapeName returns s as the characters of a PDF name, with the delimiters written as #xx.
+func escapeName(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c > '~' || strings.IndexByte("()<>[]{}/%#", c) >= 0 {
+			fmt.Fprintf(&sb, "#%02X", c)
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
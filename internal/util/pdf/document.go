@@ -0,0 +1,506 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// PageSize is the size of a page in points (1/72 inch).
+type PageSize struct {
+	Width  float64
+	Height float64
+}
+
+// Sizes of portrait pages.
+var (
+	A4     = PageSize{Width: 595.28, Height: 841.89}
+	Letter = PageSize{Width: 612, Height: 792}
+)
+
+// Landscape returns the size turned to landscape orientation.
+func (s PageSize) Landscape() PageSize {
+	if s.Width < s.Height {
+		return PageSize{Width: s.Height, Height: s.Width}
+	}
+	return s
+}
+
+const (
+	// pageMargin is the blank border around the content of a page, 10mm.
+	pageMargin = 28.35
+
+	// textSize is the size of the header, footer and caption text.
+	textSize = 8.0
+	// lineHeight is the distance between the baselines of caption lines.
+	lineHeight = 11.0
+	// bandHeight is the height the header or the footer takes from the content area.
+	bandHeight = 18.0
+	// cellGap is the space between the cells of a grid of images.
+	cellGap = 8.0
+	// textGray is the gray level of the text, a dark gray that keeps the images in focus.
+	textGray = 0.25
+)
+
+// ErrNoPages is returned by Close when the document has no pages.
+var ErrNoPages = errors.New("pdf document has no pages")
+
+// Options configure the pages of a document.
+type Options struct {
+	Size PageSize
+	// Columns and Rows of the grid of images on every page, one image per page if not set.
+	Columns int
+	Rows    int
+	// Font of the text, the default font if nil.
+	Font *Font
+	// HeaderLeft and HeaderRight are printed at the top of every page.
+	HeaderLeft  string
+	HeaderRight string
+	// Footer is printed at the bottom right of every page.
+	Footer string
+	// PageNumbers prints "Page X of Y" at the bottom left of every page.
+	PageNumbers bool
+	// Contents adds a table of contents listing the sections of the images after the cover.
+	Contents bool
+	// Watermark is drawn across every page, over the content.
+	Watermark string
+	// Metadata describes the document to viewers, none if nil.
+	Metadata *Metadata
+	// Encryption protects the document with a password, none if nil.
+	Encryption *Encryption
+}
+
+func (o Options) grid() (columns, rows int) {
+	return max(o.Columns, 1), max(o.Rows, 1)
+}
+
+func (o Options) hasHeader() bool {
+	return o.HeaderLeft != "" || o.HeaderRight != ""
+}
+
+func (o Options) hasFooter() bool {
+	return o.Footer != "" || o.PageNumbers
+}
+
+// Document is a Renderer writing a PDF with a grid of images on every page, one image per page
+// by default. Images are written as they are added, a page once its grid is full. Paragraphs
+// of text flow over pages of their own, between the image pages they are added between.
+//
+// The cover and the table of contents depend on the whole document, so they are written when
+// the document is closed and put in front of the image pages in the page tree. Page numbers
+// count the image pages only, the front pages are labeled separately.
+type Document struct {
+	w     *Writer
+	opts  Options
+	root  int
+	pages []int
+	// cells are the images of the page being filled.
+	cells  []cell
+	images int
+	// flow is the content of the text page being filled, nil if there is none,
+	// and flowY the baseline of its last line.
+	flow       *bytes.Buffer
+	flowY      float64
+	flowImages []int
+	paragraphs int
+
+	// font and fontObj are set once the first text is printed.
+	font    *fontUse
+	fontObj int
+	// totalObj is the form showing the number of pages. It is written when the document
+	// is closed and the number is known, every page refers to it before.
+	totalObj int
+	// gsObj is the graphics state of the watermark.
+	gsObj int
+
+	cover       *Cover
+	sections    []section
+	attachments []attachment
+}
+
+// cell is an image written to the document and waiting for its page.
+type cell struct {
+	obj     int
+	width   int
+	height  int
+	caption []string
+}
+
+// section is a run of pages whose images share Image.Section.
+type section struct {
+	title string
+	// page is the index of the first page of the section in Document.pages.
+	page int
+}
+
+var _ Renderer = (*Document)(nil)
+
+// NewDocument starts a document on w.
+func NewDocument(w io.Writer, opts Options) *Document {
+	pw := NewWriter(w)
+	d := &Document{
+		w:    pw,
+		opts: opts,
+		// The page tree is written last, but every page refers to it.
+		root: pw.Reserve(),
+	}
+	if opts.Encryption != nil {
+		// A failure is kept by the writer and returned by every later write.
+		_ = pw.Encrypt(opts.Encryption)
+	}
+	if opts.PageNumbers {
+		d.totalObj = pw.Reserve()
+	}
+	if opts.Watermark != "" {
+		d.gsObj = pw.Reserve()
+		_ = pw.WriteObject(d.gsObj, fmt.Sprintf("<< /Type /ExtGState /ca %.2f /CA %.2f >>", watermarkOpacity, watermarkOpacity))
+	}
+	if opts.Metadata != nil {
+		pw.SetID(opts.Metadata.ID)
+	}
+	return d
+}
+
+// AddImage adds the image to the next cell of the grid of the page, scaled to fit the cell
+// keeping its aspect ratio, at the top of the cell and centered horizontally. The caption
+// follows the image.
+func (d *Document) AddImage(img Image) error {
+	pic, err := loadImage(img.Path)
+	if err != nil {
+		return err
+	}
+	if err := d.endFlow(); err != nil {
+		return err
+	}
+	if len(img.Caption) > 0 || d.opts.hasHeader() || d.opts.hasFooter() || d.opts.Watermark != "" {
+		if err := d.useFont(); err != nil {
+			return err
+		}
+	}
+
+	imageObj, err := d.writeImage(pic)
+	if err != nil {
+		return err
+	}
+
+	d.startSection(img.Section)
+	d.cells = append(d.cells, cell{obj: imageObj, width: pic.width, height: pic.height, caption: img.Caption})
+	d.images++
+
+	if columns, rows := d.opts.grid(); len(d.cells) == columns*rows {
+		return d.writePage()
+	}
+	return nil
+}
+
+// writeImage writes the image XObject and returns its object.
+func (d *Document) writeImage(pic *jpegImage) (int, error) {
+	obj := d.w.Reserve()
+	return obj, d.w.WriteStream(obj, fmt.Sprintf(
+		"/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8 /Filter /DCTDecode",
+		pic.width, pic.height, pic.colorSpace,
+	), pic.data)
+}
+
+// startSection starts a section on the page being filled, which is the next one in d.pages,
+// unless the title is empty or the section is already started.
+func (d *Document) startSection(title string) {
+	if title != "" && (len(d.sections) == 0 || d.sections[len(d.sections)-1].title != title) {
+		d.sections = append(d.sections, section{title: title, page: len(d.pages)})
+	}
+}
+
+// contentArea returns the bounds of the content of a page, inside the margins, the header and the footer.
+func (d *Document) contentArea() (left, right, top, bottom float64) {
+	size := d.opts.Size
+	left, right = pageMargin, size.Width-pageMargin
+	top, bottom = size.Height-pageMargin, pageMargin
+	if d.opts.hasHeader() {
+		top -= bandHeight
+	}
+	if d.opts.hasFooter() {
+		bottom += bandHeight
+	}
+	return left, right, top, bottom
+}
+
+// writePage writes the page showing the pending cells, row by row from the top left.
+func (d *Document) writePage() error {
+	left, right, top, bottom := d.contentArea()
+	columns, rows := d.opts.grid()
+	cellW := (right - left - cellGap*float64(columns-1)) / float64(columns)
+	cellH := (top - bottom - cellGap*float64(rows-1)) / float64(rows)
+
+	var content bytes.Buffer
+	images := make([]int, len(d.cells))
+	for i, c := range d.cells {
+		images[i] = c.obj
+		cellX := left + float64(i%columns)*(cellW+cellGap)
+		cellY := top - float64(i/columns)*(cellH+cellGap)
+
+		// Captions never take more than half of the cell.
+		boxH := max(cellH-float64(len(c.caption))*lineHeight, cellH/2)
+		scale := min(cellW/float64(c.width), boxH/float64(c.height))
+		w, h := float64(c.width)*scale, float64(c.height)*scale
+		x, y := cellX+(cellW-w)/2, cellY-h
+
+		fmt.Fprintf(&content, "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", w, h, x, y, i)
+		for j, line := range c.caption {
+			if y-float64(j+1)*lineHeight < cellY-cellH {
+				break
+			}
+			d.text(&content, x, y-float64(j+1)*lineHeight+2, textSize, d.fit(line, textSize, cellX+cellW-x))
+		}
+	}
+	d.cells = d.cells[:0]
+	return d.writeContentPage(&content, images)
+}
+
+// writeContentPage adds the header, the footer and the watermark to the content
+// and writes it as the next page, showing the images.
+func (d *Document) writeContentPage(content *bytes.Buffer, images []int) error {
+	left, right, _, _ := d.contentArea()
+	d.header(content, left, right)
+	d.footer(content, left, right, len(d.pages)+1)
+	d.watermark(content)
+
+	contentObj, pageObj := d.w.Reserve(), d.w.Reserve()
+	if err := d.w.WriteStream(contentObj, "", content.Bytes()); err != nil {
+		return err
+	}
+	if err := d.w.WriteObject(pageObj, fmt.Sprintf(
+		"<< /Type /Page /Parent %s /MediaBox [0 0 %.2f %.2f] /Resources %s /Contents %s >>",
+		Ref(d.root), d.opts.Size.Width, d.opts.Size.Height, d.resources(images), Ref(contentObj),
+	)); err != nil {
+		return err
+	}
+	d.pages = append(d.pages, pageObj)
+	return nil
+}
+
+// SetCover sets the cover page of the document. It may be called at any time before Close,
+// so the cover can tell what the document turned out to contain.
+func (d *Document) SetCover(cover *Cover) {
+	d.cover = cover
+}
+
+// Images returns the number of images added so far.
+func (d *Document) Images() int {
+	return d.images
+}
+
+// Close writes the objects that depend on the whole document: the number of pages, the cover,
+// the table of contents, the font, the outline, the page tree and the catalog, followed by the
+// cross-reference table. It does not close the underlying writer.
+func (d *Document) Close() error {
+	if d.images == 0 && d.paragraphs == 0 {
+		return ErrNoPages
+	}
+	if len(d.cells) > 0 {
+		if err := d.writePage(); err != nil {
+			return err
+		}
+	}
+	if err := d.endFlow(); err != nil {
+		return err
+	}
+	if d.totalObj != 0 {
+		var content bytes.Buffer
+		d.text(&content, 0, 0, textSize, strconv.Itoa(len(d.pages)))
+		if err := d.w.WriteStream(d.totalObj, fmt.Sprintf(
+			"/Type /XObject /Subtype /Form /BBox [0 -%[1]d %[2]d %[1]d] /Resources << /Font << /F1 %[3]s >> >>",
+			int(textSize), int(textSize*8), Ref(d.fontObj),
+		), content.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	var front []int
+	var labels []string
+	if d.cover != nil {
+		page, err := d.writeCover()
+		if err != nil {
+			return err
+		}
+		front = append(front, page)
+		labels = append(labels, fmt.Sprintf("0 << /P %s >>", TextString("Cover")))
+	}
+	if d.opts.Contents && len(d.sections) > 0 {
+		pages, err := d.writeContents()
+		if err != nil {
+			return err
+		}
+		labels = append(labels, fmt.Sprintf("%d << /S /r >>", len(front)))
+		front = append(front, pages...)
+	}
+
+	if d.font != nil {
+		if err := d.font.write(d.w, d.fontObj); err != nil {
+			return err
+		}
+	}
+	outline, err := d.writeOutline()
+	if err != nil {
+		return err
+	}
+
+	kids := append(front, d.pages...)
+	if err := d.w.WriteObject(d.root, fmt.Sprintf(
+		"<< /Type /Pages /Kids %s /Count %d >>", RefArray(kids), len(kids),
+	)); err != nil {
+		return err
+	}
+
+	var info int
+	catalog := d.w.Reserve()
+	dict := "/Type /Catalog /Pages " + Ref(d.root)
+	if d.opts.Metadata != nil {
+		if info, err = d.writeInfo(); err != nil {
+			return err
+		}
+		xmp, err := d.writeXMP()
+		if err != nil {
+			return err
+		}
+		dict += " /Metadata " + Ref(xmp)
+	}
+	if d.w.crypt != nil {
+		// AES-256 encryption is an extension of PDF 1.7 readers announce support for.
+		dict += " /Extensions << /ADBE << /BaseVersion /1.7 /ExtensionLevel 8 >> >>"
+	}
+	if outline != 0 {
+		dict += " /Outlines " + Ref(outline) + " /PageMode /UseOutlines"
+	}
+	if len(d.attachments) > 0 {
+		dict += " /Names << /EmbeddedFiles " + d.embeddedFiles() + " >>"
+	}
+	if len(front) > 0 {
+		labels = append(labels, fmt.Sprintf("%d << /S /D >>", len(front)))
+		dict += " /PageLabels << /Nums [" + strings.Join(labels, " ") + "] >>"
+	}
+	if err := d.w.WriteObject(catalog, "<< "+dict+" >>"); err != nil {
+		return err
+	}
+	return d.w.Close(catalog, info)
+}
+
+func (d *Document) useFont() error {
+	if d.font != nil {
+		return nil
+	}
+	f := d.opts.Font
+	if f == nil {
+		var err error
+		if f, err = DefaultFont(); err != nil {
+			return err
+		}
+	}
+	d.font = newFontUse(f)
+	d.fontObj = d.w.Reserve()
+	return nil
+}
+
+// resources returns the resource dictionary of a page showing the images.
+func (d *Document) resources(images []int) string {
+	var xobjects []string
+	for i, obj := range images {
+		xobjects = append(xobjects, fmt.Sprintf("/Im%d %s", i, Ref(obj)))
+	}
+	if d.totalObj != 0 {
+		xobjects = append(xobjects, "/Total "+Ref(d.totalObj))
+	}
+	var res []string
+	if len(xobjects) > 0 {
+		res = append(res, "/XObject << "+strings.Join(xobjects, " ")+" >>")
+	}
+	if d.font != nil {
+		res = append(res, "/Font << /F1 "+Ref(d.fontObj)+" >>")
+	}
+	if d.gsObj != 0 {
+		res = append(res, "/ExtGState << /GS0 "+Ref(d.gsObj)+" >>")
+	}
+	return "<< " + strings.Join(res, " ") + " >>"
+}
+
+// writeTextPage writes a page that shows only text, with optional annotations, and returns its object.
+func (d *Document) writeTextPage(content []byte, annots []string) (int, error) {
+	c := bytes.NewBuffer(content)
+	d.watermark(c)
+	contentObj, pageObj := d.w.Reserve(), d.w.Reserve()
+	if err := d.w.WriteStream(contentObj, "", c.Bytes()); err != nil {
+		return 0, err
+	}
+	resources := "/Font << /F1 " + Ref(d.fontObj) + " >>"
+	if d.gsObj != 0 {
+		resources += " /ExtGState << /GS0 " + Ref(d.gsObj) + " >>"
+	}
+	dict := fmt.Sprintf(
+		"/Type /Page /Parent %s /MediaBox [0 0 %.2f %.2f] /Resources << %s >> /Contents %s",
+		Ref(d.root), d.opts.Size.Width, d.opts.Size.Height, resources, Ref(contentObj),
+	)
+	if len(annots) > 0 {
+		dict += " /Annots [" + strings.Join(annots, " ") + "]"
+	}
+	return pageObj, d.w.WriteObject(pageObj, "<< "+dict+" >>")
+}
+
+func (d *Document) header(c *bytes.Buffer, left, right float64) {
+	if !d.opts.hasHeader() {
+		return
+	}
+	y := d.opts.Size.Height - pageMargin - textSize
+	rightW := d.width(d.opts.HeaderRight, textSize)
+	if d.opts.HeaderRight != "" {
+		d.text(c, right-rightW, y, textSize, d.opts.HeaderRight)
+	}
+	d.text(c, left, y, textSize, d.fit(d.opts.HeaderLeft, textSize, right-left-rightW-textSize))
+}
+
+func (d *Document) footer(c *bytes.Buffer, left, right float64, page int) {
+	if !d.opts.hasFooter() {
+		return
+	}
+	y := pageMargin
+	leftW := 0.0
+	if d.opts.PageNumbers {
+		prefix := fmt.Sprintf("Page %d of ", page)
+		d.text(c, left, y, textSize, prefix)
+		leftW = d.width(prefix, textSize)
+		fmt.Fprintf(c, "q 1 0 0 1 %.2f %.2f cm /Total Do Q\n", left+leftW, y)
+		leftW += textSize * 3
+	}
+	if d.opts.Footer != "" {
+		footer := d.fit(d.opts.Footer, textSize, right-left-leftW-textSize)
+		d.text(c, right-d.width(footer, textSize), y, textSize, footer)
+	}
+}
+
+// text prints s at the given size with its baseline starting at x, y.
+func (d *Document) text(c *bytes.Buffer, x, y, size float64, s string) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(c, "q %.2f g BT /F1 %.1f Tf %.2f %.2f Td %s Tj ET Q\n", textGray, size, x, y, d.font.encode(s))
+}
+
+func (d *Document) width(s string, size float64) float64 {
+	return d.font.font.Width(s, size)
+}
+
+// fit shortens s with an ellipsis until it is at most maxWidth wide at the given size.
+func (d *Document) fit(s string, size, maxWidth float64) string {
+	if d.width(s, size) <= maxWidth {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && d.width(string(runes)+"…", size) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	if len(runes) == 0 {
+		return ""
+	}
+	return string(runes) + "…"
+}
@@ -0,0 +1,521 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+func writeImage(t *testing.T, name string, img image.Image) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if strings.HasSuffix(name, ".jpg") {
+		err = jpeg.Encode(f, img, nil)
+	} else {
+		err = png.Encode(f, img)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDocument(t *testing.T) {
+	rgba := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	rgba.Set(1, 1, color.NRGBA{R: 255, A: 128})
+	gray := image.NewGray(image.Rect(0, 0, 10, 30))
+	paths := []string{
+		writeImage(t, "transparent.png", rgba),
+		writeImage(t, "photo.jpg", image.NewRGBA(image.Rect(0, 0, 30, 30))),
+		writeImage(t, "gray.png", gray),
+	}
+	broken := filepath.Join(t.TempDir(), "broken.png")
+	if err := os.WriteFile(broken, []byte("not an image"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	doc := NewDocument(&buf, Options{Size: A4})
+	for _, path := range paths {
+		if err := doc.AddImage(Image{Path: path}); err != nil {
+			t.Fatalf("AddImage(%s) error = %v", path, err)
+		}
+	}
+	written := buf.Len()
+	if err := doc.AddImage(Image{Path: broken}); err == nil {
+		t.Fatal("AddImage() of a broken image succeeded")
+	}
+	if buf.Len() != written || doc.Images() != 3 {
+		t.Fatalf("a broken image changed the document: %d images", doc.Images())
+	}
+	if err := doc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	out := buf.Bytes()
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatalf("missing header or trailer")
+	}
+	if !bytes.Contains(out, []byte("/Type /Pages /Kids [4 0 R 7 0 R 10 0 R] /Count 3")) {
+		t.Errorf("page tree not found")
+	}
+	if !bytes.Contains(out, []byte("/ColorSpace /DeviceGray")) {
+		t.Errorf("gray image is not embedded as gray")
+	}
+
+	if size := checkXref(t, out); size != 12 {
+		t.Errorf("got %d objects, want 12", size)
+	}
+}
+
+// checkXref checks that every entry of the cross-reference table points at the start of its object.
+// It returns the number of objects.
+func checkXref(t *testing.T, out []byte) int {
+	t.Helper()
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if m == nil {
+		t.Fatal("startxref not found")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	table := string(out[xref:])
+	var size int
+	if _, err := fmt.Sscanf(table, "xref\n0 %d\n", &size); err != nil {
+		t.Fatalf("startxref does not point at an xref table: %.20q", table)
+	}
+	entries := strings.Split(table, "\n")[3 : size+2]
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(entry[:10])
+		if err != nil {
+			t.Fatalf("entry %d: %q", i+1, entry)
+		}
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(out[offset:], []byte(want)) {
+			t.Errorf("object %d: offset %d points at %.10q", i+1, offset, out[offset:])
+		}
+	}
+	return size
+}
+
+func TestDocumentText(t *testing.T) {
+	font, err := DefaultFont()
+	if err != nil {
+		t.Fatalf("DefaultFont() error = %v", err)
+	}
+	path := writeImage(t, "shot.png", image.NewRGBA(image.Rect(0, 0, 80, 40)))
+
+	var buf bytes.Buffer
+	doc := NewDocument(&buf, Options{
+		Size:        A4,
+		Font:        font,
+		HeaderLeft:  "Агент 12",
+		HeaderRight: "2026-10-01 – 2026-10-02",
+		Footer:      "pdf_screenrecording_1.pdf",
+		PageNumbers: true,
+	})
+	for range 3 {
+		if err := doc.AddImage(Image{Path: path, Caption: []string{"2026-10-01 10:00:00 EEST", strings.Repeat("long caption ", 40)}}); err != nil {
+			t.Fatalf("AddImage() error = %v", err)
+		}
+	}
+	if err := doc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	out := buf.Bytes()
+	// root, total pages, font, 3 pages of 3 objects, 4 font parts, catalog
+	if size := checkXref(t, out); size != 18 {
+		t.Errorf("got %d objects, want 18", size)
+	}
+	for _, want := range []string{
+		"/Subtype /Type0 /BaseFont /GoRegular /Encoding /Identity-H",
+		"/Subtype /CIDFontType2",
+		"/FontFile2 ",
+		"/Type /XObject /Subtype /Form",
+		"/Total Do",
+		"/Font << /F1 3 0 R >>",
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("document does not contain %q", want)
+		}
+	}
+
+	// Printed runes map back to Unicode, so the text can be searched, the Cyrillic header included.
+	glyph := func(r rune) string {
+		var b sfnt.Buffer
+		gid, _ := font.glyph(&b, r)
+		return fmt.Sprintf("%04X", uint16(gid))
+	}
+	if !bytes.Contains(out, []byte("<"+glyph('А')+"> <0410>")) {
+		t.Errorf("ToUnicode does not map the glyph of А")
+	}
+	// The total number of pages is printed once, in the form every page refers to.
+	if n := bytes.Count(out, []byte("<"+glyph('3')+"> Tj")); n != 1 {
+		t.Errorf("total page count printed %d times, want once", n)
+	}
+	if n := bytes.Count(out, []byte(glyph('…')+"> Tj")); n != 3 {
+		t.Errorf("%d long captions were shortened, want 3", n)
+	}
+}
+
+func TestDocumentFrontMatter(t *testing.T) {
+	path := writeImage(t, "shot.png", image.NewRGBA(image.Rect(0, 0, 80, 40)))
+	broken := filepath.Join(t.TempDir(), "broken.png")
+	if err := os.WriteFile(broken, []byte("not an image"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	doc := NewDocument(&buf, Options{Size: A4, Contents: true, PageNumbers: true})
+	for _, img := range []Image{
+		{Path: path, Section: "2026-10-16"},
+		{Path: path, Section: "2026-10-16"},
+		{Path: broken, Section: "2026-10-15"},
+		{Path: path, Section: "2026-10-15"},
+		{Path: path, Section: "Без часу"},
+	} {
+		_ = doc.AddImage(img)
+	}
+	doc.SetCover(&Cover{Title: "Screenshots", Fields: []CoverField{{Label: "Included", Value: "4"}}})
+	if err := doc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	out := buf.Bytes()
+	checkXref(t, out)
+
+	kids := regexp.MustCompile(`/Type /Pages /Kids \[([^\]]*)\] /Count (\d+)`).FindSubmatch(out)
+	if kids == nil || string(kids[2]) != "6" {
+		t.Fatalf("page tree of cover, contents and 4 images not found")
+	}
+	refs := strings.Fields(strings.ReplaceAll(string(kids[1]), " 0 R", ""))
+	page := func(ref string) []byte {
+		return regexp.MustCompile(`(?m)^` + ref + ` 0 obj\n.*$`).Find(out)
+	}
+	if bytes.Contains(page(refs[0]), []byte("/XObject")) || bytes.Contains(page(refs[1]), []byte("/XObject")) {
+		t.Errorf("the first two pages are not the cover and the contents")
+	}
+	// The section that starts with a broken image begins at the next image, the third image page.
+	if !regexp.MustCompile(`/Title \(2026-10-15\) /Parent \d+ 0 R /Dest \[` + refs[4] + ` 0 R /Fit\]`).Match(out) {
+		t.Errorf("bookmark of the second section does not point at page object %s", refs[4])
+	}
+	for _, want := range []string{
+		"/Type /Outlines",
+		"/Count 3 >>",
+		"/Title <FEFF04110435043700200447043004410443>",
+		"/PageMode /UseOutlines",
+		"/PageLabels << /Nums [0 << /P (Cover) >> 1 << /S /r >> 2 << /S /D >>] >>",
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("document does not contain %q", want)
+		}
+	}
+	if n := bytes.Count(out, []byte("/Subtype /Link")); n != 3 {
+		t.Errorf("contents have %d links, want 3", n)
+	}
+}
+
+func TestDocumentGrid(t *testing.T) {
+	wide := writeImage(t, "wide.png", image.NewRGBA(image.Rect(0, 0, 160, 90)))
+	tall := writeImage(t, "tall.png", image.NewRGBA(image.Rect(0, 0, 90, 160)))
+
+	var buf bytes.Buffer
+	doc := NewDocument(&buf, Options{Size: Letter.Landscape(), Columns: 2, Rows: 2, Contents: true})
+	for i := range 5 {
+		img := Image{Path: wide, Section: "first"}
+		if i == 4 {
+			img = Image{Path: tall, Section: "second"}
+		}
+		if err := doc.AddImage(img); err != nil {
+			t.Fatalf("AddImage() error = %v", err)
+		}
+	}
+	if err := doc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	out := buf.Bytes()
+	checkXref(t, out)
+	pages := regexp.MustCompile(`<< /Type /Page .*/MediaBox \[0 0 792\.00 612\.00\] /Resources << /XObject << ([^>]*) >>`).FindAllSubmatch(out, -1)
+	if len(pages) != 2 {
+		t.Fatalf("got %d image pages, want 2", len(pages))
+	}
+	if n := bytes.Count(pages[0][1], []byte("/Im")); n != 4 {
+		t.Errorf("first page shows %d images, want 4", n)
+	}
+	if n := bytes.Count(pages[1][1], []byte("/Im")); n != 1 {
+		t.Errorf("last page shows %d images, want 1", n)
+	}
+
+	// Every image keeps its aspect ratio and fits its cell: wide images fill the width of a cell,
+	// the tall one its height.
+	cellW := (792 - 2*pageMargin - cellGap) / 2
+	cellH := (612 - 2*pageMargin - cellGap) / 2
+	draws := regexp.MustCompile(`q ([\d.]+) 0 0 ([\d.]+) [\d.]+ [\d.]+ cm /Im`).FindAllSubmatch(out, -1)
+	if len(draws) != 5 {
+		t.Fatalf("got %d images drawn, want 5", len(draws))
+	}
+	for i, m := range draws {
+		w, _ := strconv.ParseFloat(string(m[1]), 64)
+		h, _ := strconv.ParseFloat(string(m[2]), 64)
+		if w > cellW+0.01 || h > cellH+0.01 {
+			t.Errorf("image %d of %.2fx%.2f does not fit a cell of %.2fx%.2f", i, w, h, cellW, cellH)
+		}
+		if i < 4 && (w < cellW-0.01 || w/h < 1.77 || w/h > 1.78) {
+			t.Errorf("wide image %d drawn at %.2fx%.2f", i, w, h)
+		}
+		if i == 4 && h < cellH-0.01 {
+			t.Errorf("tall image drawn at %.2fx%.2f", w, h)
+		}
+	}
+	if n := bytes.Count(out, []byte("/Subtype /Link")); n != 2 {
+		t.Errorf("contents have %d links, want 2", n)
+	}
+}
+
+func TestDocumentWatermarkAndMetadata(t *testing.T) {
+	path := writeImage(t, "shot.png", image.NewRGBA(image.Rect(0, 0, 80, 40)))
+
+	var buf bytes.Buffer
+	doc := NewDocument(&buf, Options{
+		Size:      A4,
+		Watermark: "Олена (#5) · domain 1 · pdf_1.pdf · 2026-10-16 10:00 UTC",
+		Metadata: &Metadata{
+			Title:      "Agent <12>",
+			Author:     "Олена",
+			Keywords:   []string{"pdf_1.pdf", "trace:abc"},
+			Created:    time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC),
+			ID:         []byte{0xab, 0xcd},
+			Properties: map[string]string{"TraceHash": "abc", "ExportID": "pdf_1.pdf"},
+		},
+	})
+	if err := doc.AddImage(Image{Path: path}); err != nil {
+		t.Fatalf("AddImage() error = %v", err)
+	}
+	doc.SetCover(&Cover{Title: "Screenshots"})
+	if err := doc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	out := buf.Bytes()
+	checkXref(t, out)
+	for _, want := range []string{
+		"<< /Type /ExtGState /ca 0.18 /CA 0.18 >>",
+		"/GS0 gs",
+		"/Metadata ",
+		"/CreationDate (D:20261016100000Z)",
+		"/ExportID (pdf_1.pdf) /TraceHash (abc)",
+		"/Keywords (pdf_1.pdf, trace:abc)",
+		"<dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">Agent &lt;12&gt;</rdf:li></rdf:Alt></dc:title>",
+		"<xmpMM:DocumentID>xmp.did:abcd</xmpMM:DocumentID>",
+		"<mx:TraceHash>abc</mx:TraceHash>",
+		"/ID [<ABCD> <ABCD>]",
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("document does not contain %q", want)
+		}
+	}
+	if !regexp.MustCompile(`trailer\n<< [^>]*/Info \d+ 0 R`).Match(out) {
+		t.Errorf("trailer does not refer to the information dictionary")
+	}
+	// Three lines on the cover and on the image page, both pages use the graphics state.
+	if n := bytes.Count(out, []byte(" Tm ")); n != 6 {
+		t.Errorf("watermark drawn %d times, want 6", n)
+	}
+	if n := bytes.Count(out, []byte("/ExtGState << /GS0")); n != 2 {
+		t.Errorf("%d pages refer to the watermark graphics state, want 2", n)
+	}
+}
+
+func TestDocumentEncryption(t *testing.T) {
+	path := writeImage(t, "shot.png", image.NewRGBA(image.Rect(0, 0, 80, 40)))
+
+	var buf bytes.Buffer
+	doc := NewDocument(&buf, Options{
+		Size:       A4,
+		Metadata:   &Metadata{Title: "Agent (12)", Properties: map[string]string{"TraceHash": "abc"}},
+		Encryption: &Encryption{UserPassword: "s3cret", Permissions: PermitAccessibility},
+	})
+	if err := doc.AddImage(Image{Path: path, Caption: []string{"Caption"}}); err != nil {
+		t.Fatalf("AddImage() error = %v", err)
+	}
+	if err := doc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	out := buf.Bytes()
+	checkXref(t, out)
+	for _, leak := range []string{"Agent", "TraceHash (abc)", "<mx:TraceHash>", "/Registry (Adobe)"} {
+		if bytes.Contains(out, []byte(leak)) {
+			t.Errorf("encrypted document contains %q", leak)
+		}
+	}
+	if !regexp.MustCompile(`trailer\n<< [^>]*/Encrypt \d+ 0 R /ID \[<[0-9A-F]{32}>`).Match(out) {
+		t.Errorf("trailer does not refer to the encryption dictionary and an identifier")
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-1.7\n")) {
+		t.Errorf("encrypted document header = %q, want PDF 1.7", out[:bytes.IndexByte(out, '\n')])
+	}
+	if !bytes.Contains(out, []byte("/Extensions << /ADBE << /BaseVersion /1.7 /ExtensionLevel 8 >> >>")) {
+		t.Errorf("catalog does not declare the encryption extension")
+	}
+
+	entry := func(name string) []byte {
+		m := regexp.MustCompile(`/` + name + ` <([0-9A-F]+)>`).FindSubmatch(out)
+		if m == nil {
+			t.Fatalf("encryption dictionary has no /%s", name)
+		}
+		b, _ := hex.DecodeString(string(m[1]))
+		return b
+	}
+	u, ue, perms := entry("U"), entry("UE"), entry("Perms")
+
+	// The password is checked and the file key recovered as a viewer does it.
+	if bytes.Equal(passwordHash([]byte("wrong"), u[32:40], nil), u[:32]) {
+		t.Errorf("a wrong password opens the document")
+	}
+	if !bytes.Equal(passwordHash([]byte("s3cret"), u[32:40], nil), u[:32]) {
+		t.Fatalf("the user password does not open the document")
+	}
+	block, _ := aes.NewCipher(passwordHash([]byte("s3cret"), u[40:48], nil))
+	key := make([]byte, 32)
+	cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(key, ue)
+
+	bits := uint32(PermitAccessibility) | reservedPermissions
+	block, _ = aes.NewCipher(key)
+	block.Decrypt(perms, perms)
+	if p := binary.LittleEndian.Uint32(perms); p != bits || string(perms[9:12]) != "adb" {
+		t.Errorf("permissions = %b %q", p, perms[8:12])
+	}
+	if !bytes.Contains(out, []byte(fmt.Sprintf("/P %d ", int32(bits)))) {
+		t.Errorf("encryption dictionary does not hold the permissions")
+	}
+
+	decrypt := func(data []byte) string {
+		plain := make([]byte, len(data)-aes.BlockSize)
+		cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(plain, data[aes.BlockSize:])
+		return string(plain[:len(plain)-int(plain[len(plain)-1])])
+	}
+	if title := decrypt(entry("Title")); title != "Agent (12)" {
+		t.Errorf("title = %q", title)
+	}
+}
+
+func TestDocumentAttachments(t *testing.T) {
+	path := writeImage(t, "shot.png", image.NewRGBA(image.Rect(0, 0, 80, 40)))
+
+	var buf bytes.Buffer
+	doc := NewDocument(&buf, Options{Size: A4})
+	if err := doc.AddImage(Image{Path: path}); err != nil {
+		t.Fatalf("AddImage() error = %v", err)
+	}
+	for _, a := range []Attachment{
+		{Name: "manifest.json", MimeType: "application/json", Description: "Files", Data: []byte(`{"files":[]}`)},
+		{Name: "manifest.json.sig", Data: []byte("c2lnbmF0dXJl")},
+	} {
+		if err := doc.Attach(a); err != nil {
+			t.Fatalf("Attach() error = %v", err)
+		}
+	}
+	if err := doc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	out := buf.Bytes()
+	checkXref(t, out)
+	for _, want := range []string{
+		"/Type /EmbeddedFile /Subtype /application#2Fjson /Params << /Size 12 >> /Length 12 >>\nstream\n{\"files\":[]}",
+		"/Type /Filespec /F (manifest.json) /UF (manifest.json) /EF << /F ",
+		"/Desc (Files)",
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("document does not contain %q", want)
+		}
+	}
+	if !regexp.MustCompile(`/Names << /EmbeddedFiles << /Names \[\(manifest\.json\) \d+ 0 R \(manifest\.json\.sig\) \d+ 0 R\] >> >>`).Match(out) {
+		t.Errorf("catalog does not list the attachments by name")
+	}
+}
+
+func TestDocumentParagraphs(t *testing.T) {
+	path := writeImage(t, "shot.png", image.NewRGBA(image.Rect(0, 0, 80, 40)))
+
+	var buf bytes.Buffer
+	doc := NewDocument(&buf, Options{Size: A4, Contents: true})
+	if err := doc.AddImage(Image{Path: path}); err != nil {
+		t.Fatalf("AddImage() error = %v", err)
+	}
+	paragraphs := []Paragraph{{Heading: true, Text: "Recording 1", Section: "Recording 1"}}
+	for i := range 80 {
+		paragraphs = append(paragraphs, Paragraph{
+			Label:   fmt.Sprintf("00:%02d · Channel 1", i),
+			Text:    strings.Repeat("word ", 40) + strings.Repeat("x", 300),
+			Section: "Recording 1",
+		})
+	}
+	paragraphs = append(paragraphs, Paragraph{Label: "01:30 · Screenshot", Text: "shot.png", Image: path})
+	for _, p := range paragraphs {
+		if err := doc.AddParagraph(p); err != nil {
+			t.Fatalf("AddParagraph() error = %v", err)
+		}
+	}
+	if err := doc.AddParagraph(Paragraph{Text: "broken", Image: filepath.Join(t.TempDir(), "missing.png")}); err == nil {
+		t.Error("AddParagraph() with a missing picture succeeded")
+	}
+	if err := doc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	out := buf.Bytes()
+	checkXref(t, out)
+	kids := regexp.MustCompile(`/Type /Pages /Kids \[[^\]]*\] /Count (\d+)`).FindSubmatch(out)
+	if kids == nil {
+		t.Fatal("page tree not found")
+	}
+	// The image page, the text pages and the table of contents.
+	if n, _ := strconv.Atoi(string(kids[1])); n < 4 {
+		t.Errorf("document has %d pages, want the paragraphs to flow over several pages", n)
+	}
+	// Text pages refer to no image.
+	if !bytes.Contains(out, []byte("/Resources << /Font")) {
+		t.Error("text pages do not use the font")
+	}
+	// The picture of the last paragraph is drawn on a text page.
+	if n := bytes.Count(out, []byte("/Subtype /Image")); n != 2 {
+		t.Errorf("document has %d images, want the page image and the paragraph picture", n)
+	}
+	if n := len(regexp.MustCompile(`/Resources << /XObject << /Im0 \d+ 0 R >>`).FindAll(out, -1)); n != 2 {
+		t.Errorf("%d pages show an image, want the image page and the text page with the picture", n)
+	}
+	// The section starts on the first text page, after the image page.
+	if !regexp.MustCompile(`/Title \(Recording 1\) /Parent \d+ 0 R /Dest \[\d+ 0 R /Fit\]`).Match(out) {
+		t.Error("paragraph section has no bookmark")
+	}
+}
+
+func TestDocumentWithoutPages(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewDocument(&buf, Options{Size: A4}).Close(); !errors.Is(err, ErrNoPages) {
+		t.Errorf("Close() error = %v, want ErrNoPages", err)
+	}
+}
@@ -0,0 +1,298 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Permission is an operation a viewer allows on an encrypted document opened with the user password.
+type Permission uint32
+
+// Permissions of an encrypted document, the bits of the /P entry of the encryption dictionary.
+const (
+	PermitPrint         Permission = 1<<2 | 1<<11 // printing, in full quality
+	PermitModify        Permission = 1 << 3
+	PermitCopy          Permission = 1 << 4 // copying text and images
+	PermitAnnotate      Permission = 1 << 5
+	PermitFillForms     Permission = 1 << 8
+	PermitAccessibility Permission = 1 << 9 // extracting text for accessibility tools
+	PermitAssemble      Permission = 1 << 10
+)
+
+// reservedPermissions are the bits of /P that must be set whatever is permitted.
+const reservedPermissions = 0xFFFFF0C0
+
+// maxPasswordLength is the number of bytes of a password that count, longer passwords are truncated.
+const maxPasswordLength = 127
+
+// Encryption protects a document with AES-256, the standard security handler of revision 6.
+type Encryption struct {
+	// UserPassword opens the document.
+	UserPassword string
+	// Permissions are what a viewer allows once the document is open, nothing but viewing if 0.
+	// Nobody knows the owner password, so they cannot be lifted.
+	Permissions Permission
+}
+
+// encryption holds the file key of a document being written and encrypts its strings and streams.
+type encryption struct {
+	key []byte
+}
+
+// Encrypt encrypts every object written from now on and writes the encryption dictionary.
+// It must be called before any other object is written.
+func (pw *Writer) Encrypt(e *Encryption) error {
+	if pw.err != nil {
+		return pw.err
+	}
+	if err := pw.encrypt(e); err != nil {
+		pw.err = err
+	}
+	return pw.err
+}
+
+func (pw *Writer) encrypt(e *Encryption) error {
+	for _, offset := range pw.offsets {
+		if offset != 0 {
+			return fmt.Errorf("pdf encryption must be set before the first object is written")
+		}
+	}
+
+	fileKey, err := random(32)
+	if err != nil {
+		return err
+	}
+	ownerPassword, err := random(32)
+	if err != nil {
+		return err
+	}
+	user, userKey, err := passwordEntries([]byte(e.UserPassword), fileKey, nil)
+	if err != nil {
+		return err
+	}
+	owner, ownerKey, err := passwordEntries(ownerPassword, fileKey, user)
+	if err != nil {
+		return err
+	}
+	p := int32(uint32(e.Permissions) | reservedPermissions)
+	perms, err := permsEntry(fileKey, p)
+	if err != nil {
+		return err
+	}
+
+	// AES-256 is defined by the extension level 8 of PDF 1.7, declared by the catalog.
+	pw.version = "1.7"
+	// The dictionary itself is not encrypted, so it is written before the key is set.
+	pw.encryptObj = pw.Reserve()
+	if err := pw.WriteObject(pw.encryptObj, fmt.Sprintf(
+		"<< /Filter /Standard /V 5 /R 6 /Length 256"+
+			" /CF << /StdCF << /Type /CryptFilter /CFM /AESV3 /AuthEvent /DocOpen /Length 32 >> >>"+
+			" /StmF /StdCF /StrF /StdCF /O <%X> /U <%X> /OE <%X> /UE <%X> /P %d /Perms <%X> >>",
+		owner, user, ownerKey, userKey, p, perms,
+	)); err != nil {
+		return err
+	}
+	pw.crypt = &encryption{key: fileKey}
+	return nil
+}
+
+// passwordEntries returns the hash of the password (the /U or /O entry) and the file key encrypted
+// with a key derived from the password (the /UE or /OE entry). user is the /U entry when the password
+// is the owner password, nil when it is the user password.
+func passwordEntries(password, fileKey, user []byte) (entry, encryptedKey []byte, err error) {
+	if len(password) > maxPasswordLength {
+		password = password[:maxPasswordLength]
+	}
+	salts, err := random(16)
+	if err != nil {
+		return nil, nil, err
+	}
+	validationSalt, keySalt := salts[:8], salts[8:]
+
+	entry = append(passwordHash(password, validationSalt, user), salts...)
+
+	block, err := aes.NewCipher(passwordHash(password, keySalt, user))
+	if err != nil {
+		return nil, nil, err
+	}
+	encryptedKey = make([]byte, len(fileKey))
+	cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(encryptedKey, fileKey)
+	return entry, encryptedKey, nil
+}
+
+// passwordHash is the hash of a password of revision 6 (ISO 32000-2, algorithm 2.B).
+func passwordHash(password, salt, user []byte) []byte {
+	sum := sha256.Sum256(bytes.Join([][]byte{password, salt, user}, nil))
+	k := sum[:]
+	for round := 1; ; round++ {
+		k1 := bytes.Repeat(bytes.Join([][]byte{password, k, user}, nil), 64)
+		block, _ := aes.NewCipher(k[:16])
+		e := make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(e, k1)
+
+		// The first 16 bytes of e as a number modulo 3, which is the sum of the bytes modulo 3.
+		var n int
+		for _, b := range e[:16] {
+			n += int(b)
+		}
+		switch n % 3 {
+		case 0:
+			s := sha256.Sum256(e)
+			k = s[:]
+		case 1:
+			s := sha512.Sum384(e)
+			k = s[:]
+		default:
+			s := sha512.Sum512(e)
+			k = s[:]
+		}
+		if round >= 64 && int(e[len(e)-1]) <= round-32 {
+			return k[:32]
+		}
+	}
+}
+
+// permsEntry returns the /Perms entry: the permissions encrypted with the file key,
+// so that a viewer can tell they were not changed.
+func permsEntry(fileKey []byte, p int32) ([]byte, error) {
+	perms := make([]byte, aes.BlockSize)
+	binary.LittleEndian.PutUint32(perms, uint32(p))
+	copy(perms[4:], []byte{0xFF, 0xFF, 0xFF, 0xFF, 'T', 'a', 'd', 'b'})
+	if _, err := rand.Read(perms[12:]); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	block.Encrypt(perms, perms)
+	return perms, nil
+}
+
+// encrypt encrypts data with AES-256 in CBC mode, prefixed with the random initialization vector.
+func (e *encryption) encrypt(data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(e.key)
+	if err != nil {
+		return nil, err
+	}
+	pad := aes.BlockSize - len(data)%aes.BlockSize
+	out := make([]byte, aes.BlockSize+len(data)+pad)
+	if _, err := rand.Read(out[:aes.BlockSize]); err != nil {
+		return nil, err
+	}
+	copy(out[aes.BlockSize:], data)
+	for i := len(out) - pad; i < len(out); i++ {
+		out[i] = byte(pad)
+	}
+	cipher.NewCBCEncrypter(block, out[:aes.BlockSize]).CryptBlocks(out[aes.BlockSize:], out[aes.BlockSize:])
+	return out, nil
+}
+
+// encryptStrings replaces every string of an object body with its encrypted hexadecimal form.
+// Bodies are written by this package, so a string is either a literal string in parentheses
+// or a hexadecimal string in angle brackets that are not the delimiters of a dictionary.
+func (e *encryption) encryptStrings(body string) (string, error) {
+	var out strings.Builder
+	for i := 0; i < len(body); {
+		var s []byte
+		switch c := body[i]; {
+		case strings.HasPrefix(body[i:], "<<") || strings.HasPrefix(body[i:], ">>"):
+			out.WriteString(body[i : i+2])
+			i += 2
+			continue
+		case c == '(':
+			s, i = literalString(body, i)
+		case c == '<':
+			end := strings.IndexByte(body[i:], '>')
+			if end < 0 {
+				return "", fmt.Errorf("pdf string is not terminated")
+			}
+			digits := strings.Join(strings.Fields(body[i+1:i+end]), "")
+			if len(digits)%2 == 1 {
+				digits += "0"
+			}
+			var err error
+			if s, err = hex.DecodeString(digits); err != nil {
+				return "", fmt.Errorf("invalid pdf hexadecimal string: %w", err)
+			}
+			i += end + 1
+		default:
+			out.WriteByte(c)
+			i++
+			continue
+		}
+		encrypted, err := e.encrypt(s)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&out, "<%X>", encrypted)
+	}
+	return out.String(), nil
+}
+
+// literalString decodes the literal string starting at body[start] and returns it
+// with the position after its closing parenthesis.
+func literalString(body string, start int) ([]byte, int) {
+	var s []byte
+	depth := 0
+	i := start + 1
+	for ; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\\' && i+1 < len(body):
+			i++
+			switch c = body[i]; c {
+			case 'n':
+				s = append(s, '\n')
+			case 'r':
+				s = append(s, '\r')
+			case 't':
+				s = append(s, '\t')
+			case 'b':
+				s = append(s, '\b')
+			case 'f':
+				s = append(s, '\f')
+			case '\n':
+				// A line continuation.
+			default:
+				if c >= '0' && c <= '7' {
+					n := 0
+					for j := 0; j < 3 && i < len(body) && body[i] >= '0' && body[i] <= '7'; j++ {
+						n = n*8 + int(body[i]-'0')
+						i++
+					}
+					i--
+					s = append(s, byte(n))
+				} else {
+					s = append(s, c)
+				}
+			}
+			continue
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 {
+				return s, i + 1
+			}
+			depth--
+		}
+		s = append(s, c)
+	}
+	return s, i
+}
+
+func random(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,244 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Font is a TrueType font for the text of a document. The whole font file is embedded,
+// so any script the font covers can be printed, e.g. Cyrillic names.
+// A Font is safe for concurrent use by several documents.
+type Font struct {
+	data     []byte
+	sfnt     *sfnt.Font
+	name     string
+	upem     int
+	ascent   int
+	descent  int
+	capH     int
+	bbox     [4]int
+	compress sync.Once
+	deflated []byte
+}
+
+var (
+	defaultFont     *Font
+	defaultFontErr  error
+	defaultFontOnce sync.Once
+)
+
+// DefaultFont returns the Go Regular font, which covers Latin, Greek and Cyrillic.
+func DefaultFont() (*Font, error) {
+	defaultFontOnce.Do(func() {
+		defaultFont, defaultFontErr = ParseFont(goregular.TTF)
+	})
+	return defaultFont, defaultFontErr
+}
+
+// LoadFont reads a TrueType font file.
+func LoadFont(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read font: %w", err)
+	}
+	return ParseFont(data)
+}
+
+// ParseFont parses TrueType font data. OpenType fonts with CFF outlines are not supported.
+func ParseFont(data []byte) (*Font, error) {
+	if bytes.HasPrefix(data, []byte("OTTO")) {
+		return nil, errors.New("parse font: CFF based OpenType fonts are not supported, use a TrueType font")
+	}
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse font: %w", err)
+	}
+
+	var b sfnt.Buffer
+	upem := int(f.UnitsPerEm())
+	ppem := fixed.I(upem)
+	metrics, err := f.Metrics(&b, ppem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("parse font metrics: %w", err)
+	}
+	bounds, err := f.Bounds(&b, ppem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("parse font bounds: %w", err)
+	}
+	name, err := f.Name(&b, sfnt.NameIDPostScript)
+	if err != nil || name == "" {
+		name = "EmbeddedFont"
+	}
+
+	// Everything is kept in PDF glyph space, 1000 units per em.
+	scale := func(v fixed.Int26_6) int { return v.Round() * 1000 / upem }
+	return &Font{
+		data:    data,
+		sfnt:    f,
+		name:    pdfName(name),
+		upem:    upem,
+		ascent:  scale(metrics.Ascent),
+		descent: -scale(metrics.Descent),
+		capH:    scale(metrics.CapHeight),
+		// Bounds are y-down, the PDF bounding box is y-up.
+		bbox: [4]int{scale(bounds.Min.X), -scale(bounds.Max.Y), scale(bounds.Max.X), -scale(bounds.Min.Y)},
+	}, nil
+}
+
+// glyph returns the glyph of r and its advance in PDF glyph space.
+// Runes missing from the font map to glyph 0, which renders as a box.
+func (f *Font) glyph(b *sfnt.Buffer, r rune) (sfnt.GlyphIndex, int) {
+	gid, err := f.sfnt.GlyphIndex(b, r)
+	if err != nil {
+		gid = 0
+	}
+	adv, err := f.sfnt.GlyphAdvance(b, gid, fixed.I(f.upem), font.HintingNone)
+	if err != nil {
+		return gid, 0
+	}
+	return gid, adv.Round() * 1000 / f.upem
+}
+
+// Width returns the width of s set at size points.
+func (f *Font) Width(s string, size float64) float64 {
+	var b sfnt.Buffer
+	total := 0
+	for _, r := range s {
+		_, w := f.glyph(&b, r)
+		total += w
+	}
+	return float64(total) * size / 1000
+}
+
+// fontFile returns the compressed font file.
+func (f *Font) fontFile() []byte {
+	f.compress.Do(func() {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		_, _ = zw.Write(f.data)
+		_ = zw.Close()
+		f.deflated = buf.Bytes()
+	})
+	return f.deflated
+}
+
+// usedGlyph is a glyph a document printed, with what it stands for.
+type usedGlyph struct {
+	r     rune
+	width int
+}
+
+// fontUse tracks the glyphs of a font one document uses, for the widths and the text mapping
+// written with the font when the document is closed.
+type fontUse struct {
+	font   *Font
+	buf    sfnt.Buffer
+	glyphs map[sfnt.GlyphIndex]usedGlyph
+}
+
+func newFontUse(f *Font) *fontUse {
+	return &fontUse{font: f, glyphs: make(map[sfnt.GlyphIndex]usedGlyph)}
+}
+
+// encode returns s as a hex string of glyph IDs for the Identity-H encoding.
+func (u *fontUse) encode(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('<')
+	for _, r := range s {
+		gid, w := u.font.glyph(&u.buf, r)
+		if _, ok := u.glyphs[gid]; !ok {
+			u.glyphs[gid] = usedGlyph{r: r, width: w}
+		}
+		fmt.Fprintf(&sb, "%04X", uint16(gid))
+	}
+	sb.WriteByte('>')
+	return sb.String()
+}
+
+// write writes the composite font as object num, with its descendant font, descriptor,
+// font file and the mapping back to Unicode, so that the text can be searched and copied.
+func (u *fontUse) write(w *Writer, num int) error {
+	f := u.font
+	cidFont, descriptor, file, toUnicode := w.Reserve(), w.Reserve(), w.Reserve(), w.Reserve()
+
+	gids := make([]sfnt.GlyphIndex, 0, len(u.glyphs))
+	for gid := range u.glyphs {
+		gids = append(gids, gid)
+	}
+	slices.Sort(gids)
+
+	var widths strings.Builder
+	for _, gid := range gids {
+		fmt.Fprintf(&widths, "%d [%d] ", gid, u.glyphs[gid].width)
+	}
+
+	if err := w.WriteObject(num, fmt.Sprintf(
+		"<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%s] /ToUnicode %s >>",
+		f.name, Ref(cidFont), Ref(toUnicode),
+	)); err != nil {
+		return err
+	}
+	if err := w.WriteObject(cidFont, fmt.Sprintf(
+		"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+			"/FontDescriptor %s /CIDToGIDMap /Identity /W [%s] >>",
+		f.name, Ref(descriptor), strings.TrimSpace(widths.String()),
+	)); err != nil {
+		return err
+	}
+	if err := w.WriteObject(descriptor, fmt.Sprintf(
+		"<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 "+
+			"/Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %s >>",
+		f.name, f.bbox[0], f.bbox[1], f.bbox[2], f.bbox[3], f.ascent, f.descent, f.capH, Ref(file),
+	)); err != nil {
+		return err
+	}
+	if err := w.WriteStream(file, fmt.Sprintf("/Length1 %d /Filter /FlateDecode", len(f.data)), f.fontFile()); err != nil {
+		return err
+	}
+	return w.WriteStream(toUnicode, "", toUnicodeCMap(gids, u.glyphs))
+}
+
+// toUnicodeCMap maps the glyph IDs back to the runes they were printed for.
+func toUnicodeCMap(gids []sfnt.GlyphIndex, glyphs map[sfnt.GlyphIndex]usedGlyph) []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// A bfchar section holds at most 100 entries.
+	for chunk := range slices.Chunk(gids, 100) {
+		fmt.Fprintf(&b, "%d beginbfchar\n", len(chunk))
+		for _, gid := range chunk {
+			fmt.Fprintf(&b, "<%04X> <", uint16(gid))
+			for _, u := range utf16.Encode([]rune{glyphs[gid].r}) {
+				fmt.Fprintf(&b, "%04X", u)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
+
+// pdfName strips the characters that are not allowed in a PDF name.
+func pdfName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || strings.ContainsRune("()<>[]{}/%#", r) {
+			return -1
+		}
+		return r
+	}, s)
+}
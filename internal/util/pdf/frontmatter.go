@@ -0,0 +1,121 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// Layout of the cover and the table of contents.
+const (
+	titleSize    = 18.0
+	headingSize  = 14.0
+	entrySize    = 10.0
+	entryHeight  = 16.0
+	coverLabelW  = 150.0
+	coverTitleY  = 120.0
+	contentsTopY = 60.0
+)
+
+// Cover is the first page of a document, describing what the document contains.
+type Cover struct {
+	Title  string
+	Fields []CoverField
+}
+
+// CoverField is a labeled value printed on the cover.
+type CoverField struct {
+	Label string
+	Value string
+}
+
+// writeCover writes the cover page and returns its object.
+func (d *Document) writeCover() (int, error) {
+	if err := d.useFont(); err != nil {
+		return 0, err
+	}
+	left, right := pageMargin*2, d.opts.Size.Width-pageMargin*2
+
+	var c bytes.Buffer
+	y := d.opts.Size.Height - coverTitleY
+	d.text(&c, left, y, titleSize, d.fit(d.cover.Title, titleSize, right-left))
+	y -= titleSize * 2.5
+	for _, field := range d.cover.Fields {
+		d.text(&c, left, y, entrySize, d.fit(field.Label, entrySize, coverLabelW-entrySize))
+		d.text(&c, left+coverLabelW, y, entrySize, d.fit(field.Value, entrySize, right-left-coverLabelW))
+		y -= entryHeight
+	}
+	return d.writeTextPage(c.Bytes(), nil)
+}
+
+// writeContents writes the table of contents, as many pages as the sections need,
+// and returns the page objects. Every entry links to the first page of its section.
+func (d *Document) writeContents() ([]int, error) {
+	if err := d.useFont(); err != nil {
+		return nil, err
+	}
+	left, right := pageMargin*2, d.opts.Size.Width-pageMargin*2
+	top := d.opts.Size.Height - contentsTopY
+	perPage := max(1, int((top-headingSize*2-pageMargin*2)/entryHeight))
+
+	var pages []int
+	for start := 0; start < len(d.sections); start += perPage {
+		var c bytes.Buffer
+		var annots []string
+		y := top
+		if start == 0 {
+			d.text(&c, left, y, headingSize, "Contents")
+		}
+		y -= headingSize * 2
+
+		for _, s := range d.sections[start:min(start+perPage, len(d.sections))] {
+			number := strconv.Itoa(s.page + 1)
+			numberW := d.width(number, entrySize)
+			d.text(&c, left, y, entrySize, d.fit(s.title, entrySize, right-left-numberW-entrySize*2))
+			d.text(&c, right-numberW, y, entrySize, number)
+			annots = append(annots, fmt.Sprintf(
+				"<< /Type /Annot /Subtype /Link /Rect [%.2f %.2f %.2f %.2f] /Border [0 0 0] /Dest [%s /Fit] >>",
+				left, y-entrySize/2, right, y+entrySize, Ref(d.pages[s.page]),
+			))
+			y -= entryHeight
+		}
+
+		page, err := d.writeTextPage(c.Bytes(), annots)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+// writeOutline writes the bookmarks of the sections and returns the outline root, 0 if there are no sections.
+func (d *Document) writeOutline() (int, error) {
+	if len(d.sections) == 0 {
+		return 0, nil
+	}
+	root := d.w.Reserve()
+	items := make([]int, len(d.sections))
+	for i := range items {
+		items[i] = d.w.Reserve()
+	}
+
+	if err := d.w.WriteObject(root, fmt.Sprintf(
+		"<< /Type /Outlines /First %s /Last %s /Count %d >>", Ref(items[0]), Ref(items[len(items)-1]), len(items),
+	)); err != nil {
+		return 0, err
+	}
+	for i, s := range d.sections {
+		dict := fmt.Sprintf("/Title %s /Parent %s /Dest [%s /Fit]", TextString(s.title), Ref(root), Ref(d.pages[s.page]))
+		if i > 0 {
+			dict += " /Prev " + Ref(items[i-1])
+		}
+		if i < len(items)-1 {
+			dict += " /Next " + Ref(items[i+1])
+		}
+		if err := d.w.WriteObject(items[i], "<< "+dict+" >>"); err != nil {
+			return 0, err
+		}
+	}
+	return root, nil
+}
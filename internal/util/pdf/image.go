@@ -0,0 +1,70 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"os"
+
+	"github.com/disintegration/imaging"
+)
+
+// jpegQuality is used for images that are not JPEG files already.
+const jpegQuality = 90
+
+// jpegImage is an image ready to be embedded with the DCTDecode filter.
+type jpegImage struct {
+	data       []byte
+	width      int
+	height     int
+	colorSpace string
+}
+
+// loadImage reads the image at path. JPEG files are embedded as they are,
+// other formats are converted to JPEG with transparent areas on white.
+func loadImage(path string) (*jpegImage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read image: %w", err)
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image %s: %w", path, err)
+	}
+	if cfg.Width == 0 || cfg.Height == 0 {
+		return nil, fmt.Errorf("decode image %s: empty image", path)
+	}
+	if format == "jpeg" {
+		switch cfg.ColorModel {
+		case color.YCbCrModel:
+			return &jpegImage{data: data, width: cfg.Width, height: cfg.Height, colorSpace: "/DeviceRGB"}, nil
+		case color.GrayModel:
+			return &jpegImage{data: data, width: cfg.Width, height: cfg.Height, colorSpace: "/DeviceGray"}, nil
+		}
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image %s: %w", path, err)
+	}
+	colorSpace := "/DeviceRGB"
+	if _, ok := img.(*image.Gray); ok {
+		colorSpace = "/DeviceGray"
+	} else if o, ok := img.(interface{ Opaque() bool }); !ok || !o.Opaque() {
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.White, image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+		img = flat
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, fmt.Errorf("encode image %s: %w", path, err)
+	}
+	b := img.Bounds()
+	return &jpegImage{data: buf.Bytes(), width: b.Dx(), height: b.Dy(), colorSpace: colorSpace}, nil
+}
@@ -0,0 +1,147 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// producer names the software that writes the documents.
+const producer = "Webitel media-exporter"
+
+// propertiesNamespace is the XMP namespace of Metadata.Properties.
+const propertiesNamespace = "http://webitel.com/ns/media-exporter/1.0/"
+
+// Metadata describes a document. It is written both to the document information dictionary and
+// to the XMP metadata stream of the catalog, which is what viewers and indexers read.
+type Metadata struct {
+	Title    string
+	Author   string
+	Subject  string
+	Keywords []string
+	Created  time.Time
+	// ID identifies the document, as the file identifier in the trailer and the XMP document ID.
+	ID []byte
+	// Properties are custom entries. Their names must be valid XML names without a prefix.
+	Properties map[string]string
+}
+
+const (
+	// watermarkOpacity keeps the content under the watermark readable.
+	watermarkOpacity = 0.18
+	// watermarkGray is the gray level of the watermark, a middle gray seen on light and dark images.
+	watermarkGray = 0.5
+	// watermarkMaxSize bounds the size of short watermark texts.
+	watermarkMaxSize = 28.0
+)
+
+// writeInfo writes the document information dictionary and returns its object.
+func (d *Document) writeInfo() (int, error) {
+	m := d.opts.Metadata
+	dict := "/Producer " + TextString(producer) + " /Creator " + TextString(producer)
+	if m.Title != "" {
+		dict += " /Title " + TextString(m.Title)
+	}
+	if m.Author != "" {
+		dict += " /Author " + TextString(m.Author)
+	}
+	if m.Subject != "" {
+		dict += " /Subject " + TextString(m.Subject)
+	}
+	if len(m.Keywords) > 0 {
+		dict += " /Keywords " + TextString(strings.Join(m.Keywords, ", "))
+	}
+	if !m.Created.IsZero() {
+		dict += " /CreationDate " + Text(m.Created.UTC().Format("D:20060102150405Z"))
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.Properties)) {
+		dict += " /" + pdfName(name) + " " + TextString(m.Properties[name])
+	}
+
+	info := d.w.Reserve()
+	return info, d.w.WriteObject(info, "<< "+dict+" >>")
+}
+
+// writeXMP writes the XMP metadata stream and returns its object.
+func (d *Document) writeXMP() (int, error) {
+	m := d.opts.Metadata
+	var b bytes.Buffer
+	esc := func(s string) string {
+		var e bytes.Buffer
+		_ = xml.EscapeText(&e, []byte(s))
+		return e.String()
+	}
+
+	b.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	b.WriteString("<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n")
+	b.WriteString("<rdf:Description rdf:about=\"\"")
+	b.WriteString(" xmlns:dc=\"http://purl.org/dc/elements/1.1/\"")
+	b.WriteString(" xmlns:xmp=\"http://ns.adobe.com/xap/1.0/\"")
+	b.WriteString(" xmlns:xmpMM=\"http://ns.adobe.com/xap/1.0/mm/\"")
+	b.WriteString(" xmlns:pdf=\"http://ns.adobe.com/pdf/1.3/\"")
+	fmt.Fprintf(&b, " xmlns:mx=\"%s\">\n", propertiesNamespace)
+
+	b.WriteString("<dc:format>application/pdf</dc:format>\n")
+	if m.Title != "" {
+		fmt.Fprintf(&b, "<dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:title>\n", esc(m.Title))
+	}
+	if m.Author != "" {
+		fmt.Fprintf(&b, "<dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", esc(m.Author))
+	}
+	if m.Subject != "" {
+		fmt.Fprintf(&b, "<dc:description><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:description>\n", esc(m.Subject))
+	}
+	if !m.Created.IsZero() {
+		fmt.Fprintf(&b, "<xmp:CreateDate>%s</xmp:CreateDate>\n", m.Created.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "<xmp:CreatorTool>%s</xmp:CreatorTool>\n", esc(producer))
+	fmt.Fprintf(&b, "<pdf:Producer>%s</pdf:Producer>\n", esc(producer))
+	if len(m.Keywords) > 0 {
+		fmt.Fprintf(&b, "<pdf:Keywords>%s</pdf:Keywords>\n", esc(strings.Join(m.Keywords, ", ")))
+	}
+	if len(m.ID) > 0 {
+		fmt.Fprintf(&b, "<xmpMM:DocumentID>xmp.did:%s</xmpMM:DocumentID>\n", hex.EncodeToString(m.ID))
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.Properties)) {
+		fmt.Fprintf(&b, "<mx:%[1]s>%[2]s</mx:%[1]s>\n", name, esc(m.Properties[name]))
+	}
+
+	b.WriteString("</rdf:Description>\n</rdf:RDF>\n</x:xmpmeta>\n<?xpacket end=\"w\"?>")
+
+	obj := d.w.Reserve()
+	return obj, d.w.WriteStream(obj, "/Type /Metadata /Subtype /XML", b.Bytes())
+}
+
+// watermark draws the watermark text along the diagonal of the page, on three parallel lines,
+// so that a cropped part of the page still shows it.
+func (d *Document) watermark(c *bytes.Buffer) {
+	text := d.opts.Watermark
+	if text == "" {
+		return
+	}
+	size := d.opts.Size
+	diagonal := math.Hypot(size.Width, size.Height)
+	cos, sin := size.Width/diagonal, size.Height/diagonal
+
+	// The side lines cross the page on half the diagonal, the text fits into that.
+	fontSize := min(diagonal*0.45/max(d.width(text, 1), 1), watermarkMaxSize)
+	w := d.width(text, fontSize)
+	offset := size.Width * size.Height / diagonal / 2
+
+	fmt.Fprintf(c, "q /GS0 gs %.2f g\n", watermarkGray)
+	for _, shift := range []float64{-offset, 0, offset} {
+		// Start of the line centered on the diagonal moved by shift across it.
+		x := size.Width/2 - shift*sin - w/2*cos
+		y := size.Height/2 + shift*cos - w/2*sin
+		fmt.Fprintf(c, "BT /F1 %.1f Tf %.4f %.4f %.4f %.4f %.2f %.2f Tm %s Tj ET\n",
+			fontSize, cos, sin, -sin, cos, x, y, d.font.encode(text))
+	}
+	c.WriteString("Q\n")
+}
@@ -0,0 +1,20 @@
+package pdf
+
+// Image is a picture to put into a document.
+type Image struct {
+	Path string
+	// Caption lines are printed under the image.
+	Caption []string
+	// Section groups consecutive images in the outline and the table of contents, none if empty.
+	Section string
+}
+
+// Renderer renders a PDF document page by page. Every image and every page is written to the output
+// as soon as it is complete, so the size of the document does not affect the memory used to render it.
+type Renderer interface {
+	// AddImage adds the image on the next free place of a page. An image that cannot be read
+	// leaves the document unchanged, so the caller may skip it and go on.
+	AddImage(img Image) error
+	// Close finishes the document. It fails if no page was added.
+	Close() error
+}
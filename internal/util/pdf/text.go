@@ -0,0 +1,169 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Layout of paragraphs.
+const (
+	paragraphSize    = 9.0
+	paragraphLeading = 12.0
+	// paragraphGap is the space between paragraphs.
+	paragraphGap = 4.0
+	// labelColumnW is the width of the column of the labels of paragraphs.
+	labelColumnW = 150.0
+	// maxFigureShare is the part of the height of the content of a page a picture of a paragraph may take.
+	maxFigureShare = 1.0 / 3
+)
+
+// Paragraph is a block of text to put into a document.
+type Paragraph struct {
+	// Heading prints the text larger, as the title of the paragraphs that follow it.
+	Heading bool
+	// Label is printed in a column left of the first line of the text, e.g. the time of a phrase.
+	Label string
+	// Text is wrapped to the width of the page, line breaks start new lines.
+	Text string
+	// Section groups consecutive paragraphs and images in the outline and the table of contents, none if empty.
+	Section string
+	// Image is the path of a picture shown under the text, scaled to the width of the text
+	// and to at most a third of the height of the page. None if empty.
+	Image string
+}
+
+// AddParagraph adds the paragraph under the previous one, on a new page after images.
+// A paragraph longer than the rest of the page goes on over the next page, its picture
+// is never split. A picture that cannot be read leaves the document unchanged.
+func (d *Document) AddParagraph(p Paragraph) error {
+	var pic *jpegImage
+	if p.Image != "" {
+		var err error
+		if pic, err = loadImage(p.Image); err != nil {
+			return err
+		}
+	}
+	if err := d.useFont(); err != nil {
+		return err
+	}
+	if len(d.cells) > 0 {
+		if err := d.writePage(); err != nil {
+			return err
+		}
+	}
+
+	left, right, top, bottom := d.contentArea()
+	size, leading, indent := paragraphSize, paragraphLeading, 0.0
+	if p.Heading {
+		size, leading = headingSize, headingSize*1.5
+	} else if p.Label != "" {
+		indent = labelColumnW
+	}
+	lines := d.wrap(p.Text, size, right-left-indent)
+
+	// A heading is kept together with the first lines after it.
+	need := leading
+	if p.Heading {
+		need += paragraphLeading * 2
+	}
+	if d.flow != nil && d.flowY-need < bottom {
+		if err := d.endFlow(); err != nil {
+			return err
+		}
+	}
+	for i, line := range lines {
+		if d.flow != nil && d.flowY-leading < bottom {
+			if err := d.endFlow(); err != nil {
+				return err
+			}
+		}
+		if d.flow == nil {
+			d.flow, d.flowY = &bytes.Buffer{}, top
+		}
+		if i == 0 {
+			d.startSection(p.Section)
+		}
+		d.flowY -= leading
+		if i == 0 && indent > 0 {
+			d.text(d.flow, left, d.flowY, size, d.fit(p.Label, size, indent-size))
+		}
+		d.text(d.flow, left+indent, d.flowY, size, line)
+	}
+	if pic != nil {
+		if err := d.addFigure(pic, left+indent, right-left-indent, (top-bottom)*maxFigureShare); err != nil {
+			return err
+		}
+	}
+	d.flowY -= paragraphGap
+	d.paragraphs++
+	return nil
+}
+
+// addFigure draws the picture under the last line of the text page, at x and scaled
+// to fit maxW and maxH, on the next page if the rest of the page is too short.
+func (d *Document) addFigure(pic *jpegImage, x, maxW, maxH float64) error {
+	obj, err := d.writeImage(pic)
+	if err != nil {
+		return err
+	}
+	scale := min(maxW/float64(pic.width), maxH/float64(pic.height))
+	w, h := float64(pic.width)*scale, float64(pic.height)*scale
+
+	_, _, top, bottom := d.contentArea()
+	if d.flowY-paragraphGap-h < bottom {
+		if err := d.endFlow(); err != nil {
+			return err
+		}
+		d.flow, d.flowY = &bytes.Buffer{}, top
+	}
+	d.flowY -= paragraphGap + h
+	fmt.Fprintf(d.flow, "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", w, h, x, d.flowY, len(d.flowImages))
+	d.flowImages = append(d.flowImages, obj)
+	return nil
+}
+
+// endFlow writes the text page being filled, if there is one.
+func (d *Document) endFlow() error {
+	if d.flow == nil {
+		return nil
+	}
+	content, images := d.flow, d.flowImages
+	d.flow, d.flowImages = nil, nil
+	return d.writeContentPage(content, images)
+}
+
+// wrap breaks the text into lines at most maxWidth wide at the given size, breaking at spaces
+// and within words that do not fit on a line. It returns a single empty line for an empty text.
+func (d *Document) wrap(text string, size, maxWidth float64) []string {
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		var line string
+		for _, word := range strings.Fields(para) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if d.width(candidate, size) <= maxWidth {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			// A word wider than the line is broken where it reaches the width.
+			for d.width(word, size) > maxWidth {
+				runes := []rune(word)
+				n := len(runes) - 1
+				for n > 1 && d.width(string(runes[:n]), size) > maxWidth {
+					n--
+				}
+				lines = append(lines, string(runes[:n]))
+				word = string(runes[n:])
+			}
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,204 @@
+// Package pdf writes PDF documents incrementally: every object goes to the underlying writer
+// as soon as it is complete, only the offsets of the objects are kept until the end.
+package pdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Writer writes the objects of a PDF file and the cross-reference table that indexes them.
+type Writer struct {
+	w       *bufio.Writer
+	offset  int64
+	offsets []int64 // offsets[n] is the position of object n, 0 while it is not written
+	id      []byte
+	err     error
+
+	// crypt encrypts the strings and streams of the objects, nil if the file is not encrypted.
+	crypt      *encryption
+	encryptObj int
+
+	// version is the PDF version in the header, written with the first object
+	// so that Encrypt can still raise it.
+	version string
+}
+
+// NewWriter returns a writer for the objects of a PDF file written to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w), offsets: []int64{0}, version: "1.4"}
+}
+
+// Reserve allocates the number of an object that is written later,
+// so that other objects can refer to it before it exists.
+func (pw *Writer) Reserve() int {
+	pw.offsets = append(pw.offsets, 0)
+	return len(pw.offsets) - 1
+}
+
+// WriteObject writes the object num with the given body, e.g. a dictionary.
+func (pw *Writer) WriteObject(num int, body string) error {
+	if pw.crypt != nil && pw.err == nil {
+		body, pw.err = pw.crypt.encryptStrings(body)
+	}
+	pw.begin(num)
+	pw.printf("%s\nendobj\n", body)
+	return pw.err
+}
+
+// WriteStream writes the stream object num. dict holds the entries of the stream dictionary
+// except /Length, which is added from data.
+func (pw *Writer) WriteStream(num int, dict string, data []byte) error {
+	if pw.crypt != nil && pw.err == nil {
+		if dict, pw.err = pw.crypt.encryptStrings(dict); pw.err == nil {
+			data, pw.err = pw.crypt.encrypt(data)
+		}
+	}
+	pw.begin(num)
+	pw.printf("<< %s /Length %d >>\nstream\n", dict, len(data))
+	pw.write(data)
+	pw.printf("\nendstream\nendobj\n")
+	return pw.err
+}
+
+// SetID sets the identifier of the file, written to the trailer.
+func (pw *Writer) SetID(id []byte) {
+	pw.id = id
+}
+
+// Close writes the cross-reference table and the trailer pointing at the catalog object root,
+// then flushes the output. Every reserved object must be written by then.
+func (pw *Writer) Close(root int, info int) error {
+	if pw.err != nil {
+		return pw.err
+	}
+	for num := 1; num < len(pw.offsets); num++ {
+		if pw.offsets[num] == 0 {
+			return fmt.Errorf("pdf object %d was reserved but never written", num)
+		}
+	}
+
+	pw.header()
+	xref := pw.offset
+	pw.printf("xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets))
+	for _, offset := range pw.offsets[1:] {
+		pw.printf("%010d 00000 n \n", offset)
+	}
+	trailer := fmt.Sprintf("/Size %d /Root %d 0 R", len(pw.offsets), root)
+	if info != 0 {
+		trailer += fmt.Sprintf(" /Info %d 0 R", info)
+	}
+	if pw.crypt != nil {
+		trailer += fmt.Sprintf(" /Encrypt %d 0 R", pw.encryptObj)
+		// Encrypted files must have an identifier.
+		if len(pw.id) == 0 {
+			if pw.id, pw.err = random(16); pw.err != nil {
+				return pw.err
+			}
+		}
+	}
+	if len(pw.id) > 0 {
+		// The identifier does not change with revisions of the file, so both parts are the same.
+		trailer += fmt.Sprintf(" /ID [<%X> <%X>]", pw.id, pw.id)
+	}
+	pw.printf("trailer\n<< %s >>\nstartxref\n%d\n%%%%EOF\n", trailer, xref)
+
+	if pw.err == nil {
+		pw.err = pw.w.Flush()
+	}
+	return pw.err
+}
+
+// Err returns the first error that occurred while writing.
+func (pw *Writer) Err() error {
+	return pw.err
+}
+
+func (pw *Writer) begin(num int) {
+	if pw.err != nil {
+		return
+	}
+	if num <= 0 || num >= len(pw.offsets) {
+		pw.err = fmt.Errorf("pdf object %d was not reserved", num)
+		return
+	}
+	if pw.offsets[num] != 0 {
+		pw.err = fmt.Errorf("pdf object %d is already written", num)
+		return
+	}
+	pw.header()
+	pw.offsets[num] = pw.offset
+	pw.printf("%d 0 obj\n", num)
+}
+
+// header writes the PDF header once, before anything else.
+func (pw *Writer) header() {
+	if pw.offset != 0 {
+		return
+	}
+	// The binary comment marks the file as binary for transfer tools.
+	pw.printf("%%PDF-%s\n%%\xe2\xe3\xcf\xd3\n", pw.version)
+}
+
+func (pw *Writer) printf(format string, args ...any) {
+	if pw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(pw.w, format, args...)
+	pw.offset += int64(n)
+	pw.err = err
+}
+
+func (pw *Writer) write(p []byte) {
+	if pw.err != nil {
+		return
+	}
+	n, err := pw.w.Write(p)
+	pw.offset += int64(n)
+	pw.err = err
+}
+
+// Ref returns a reference to the object num.
+func Ref(num int) string {
+	return fmt.Sprintf("%d 0 R", num)
+}
+
+// RefArray returns an array of references to the objects.
+func RefArray(nums []int) string {
+	refs := make([]string, len(nums))
+	for i, num := range nums {
+		refs[i] = Ref(num)
+	}
+	return "[" + strings.Join(refs, " ") + "]"
+}
+
+// Text returns s as a PDF literal string.
+func Text(s string) string {
+	return "(" + strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", `\r`, "\n", `\n`).Replace(s) + ")"
+}
+
+// TextString returns s as a PDF text string for titles and labels the viewer shows:
+// a literal string if s is ASCII, UTF-16BE with a byte order mark otherwise.
+func TextString(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return Text(s)
+	}
+	var sb strings.Builder
+	sb.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&sb, "%04X", u)
+	}
+	sb.WriteByte('>')
+	return sb.String()
+}
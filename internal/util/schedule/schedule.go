@@ -0,0 +1,124 @@
+// Package schedule computes the occurrences of the cron expressions of export schedules
+// and the time ranges of their runs.
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Unit is the unit of the time range of a run.
+type Unit string
+
+const (
+	Hour  Unit = "hour"
+	Day   Unit = "day"
+	Week  Unit = "week" // Weeks start on Monday
+	Month Unit = "month"
+)
+
+// Valid reports whether u is one of the units.
+func (u Unit) Valid() bool {
+	switch u {
+	case Hour, Day, Week, Month:
+		return true
+	}
+	return false
+}
+
+// parser accepts standard five-field expressions and descriptors such as "@daily".
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Schedule is a cron expression in a time zone.
+type Schedule struct {
+	spec cron.Schedule
+	loc  *time.Location
+}
+
+// Parse parses a cron expression whose times are in the time zone, UTC if empty.
+func Parse(expr, timezone string) (*Schedule, error) {
+	loc := time.UTC
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", timezone, err)
+		}
+	}
+	// The time zone is a setting of its own, so an expression must not override it.
+	if trimmed := strings.TrimSpace(expr); strings.HasPrefix(trimmed, "TZ=") || strings.HasPrefix(trimmed, "CRON_TZ=") {
+		return nil, fmt.Errorf("invalid cron expression %q: the time zone cannot be part of it", expr)
+	}
+	spec, err := parser.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	return &Schedule{spec: spec, loc: loc}, nil
+}
+
+// Location is the time zone of the schedule.
+func (s *Schedule) Location() *time.Location {
+	return s.loc
+}
+
+// Next returns the first occurrence after t, the zero time if there is none.
+func (s *Schedule) Next(t time.Time) time.Time {
+	return s.spec.Next(t.In(s.loc))
+}
+
+// Occurrences returns the occurrences from an occurrence from up to to, both inclusive, oldest first.
+// Only the last limit of them are returned.
+func (s *Schedule) Occurrences(from, to time.Time, limit int) []time.Time {
+	var res []time.Time
+	// Occurrences fall on whole minutes, so from is the next occurrence after a second before it.
+	for t := s.Next(from.Add(-time.Second)); !t.IsZero() && !t.After(to); t = s.Next(t) {
+		res = append(res, t)
+		if len(res) > limit {
+			res = res[1:]
+		}
+	}
+	return res
+}
+
+// Range returns the time range of a run at the time at, as a start and an exclusive end.
+// A rolling range is made of count units ending at the run. Otherwise it is made of count whole units
+// ending at the start of the unit of the run: for a run on a Monday, one Week is the previous week.
+// Units are in the time zone of the schedule.
+func (s *Schedule) Range(at time.Time, unit Unit, count int, rolling bool) (time.Time, time.Time) {
+	to := at.In(s.loc)
+	if !rolling {
+		to = startOf(to, unit)
+	}
+	return shift(to, unit, -count), to
+}
+
+func startOf(t time.Time, unit Unit) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	switch unit {
+	case Hour:
+		return time.Date(y, m, d, t.Hour(), 0, 0, 0, t.Location())
+	case Week:
+		return day.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	case Month:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// shift moves t by n units. Days, weeks and months follow the calendar, across changes of the offset of the time zone.
+func shift(t time.Time, unit Unit, n int) time.Time {
+	switch unit {
+	case Hour:
+		return t.Add(time.Duration(n) * time.Hour)
+	case Week:
+		return t.AddDate(0, 0, 7*n)
+	case Month:
+		return t.AddDate(0, n, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
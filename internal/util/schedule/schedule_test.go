@@ -0,0 +1,89 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	for _, expr := range []string{"0 6 * * MON", "@daily", "*/15 * * * *"} {
+		if _, err := Parse(expr, "Europe/Kyiv"); err != nil {
+			t.Errorf("Parse(%q) error = %v", expr, err)
+		}
+	}
+	for _, expr := range []string{"", "0 6 * *", "0 0 6 * * MON", "CRON_TZ=UTC 0 6 * * *", "61 * * * *"} {
+		if _, err := Parse(expr, ""); err == nil {
+			t.Errorf("Parse(%q) must fail", expr)
+		}
+	}
+	if _, err := Parse("@daily", "Mars/Olympus"); err == nil {
+		t.Errorf("Parse with an unknown time zone must fail")
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	s, err := Parse("0 6 * * MON", "Europe/Kyiv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kyiv := s.Location()
+
+	first := time.Date(2026, 3, 2, 6, 0, 0, 0, kyiv)
+	if next := s.Next(first.Add(-time.Hour)); !next.Equal(first) {
+		t.Errorf("Next() = %v, want %v", next, first)
+	}
+
+	now := time.Date(2026, 3, 30, 12, 0, 0, 0, kyiv)
+	got := s.Occurrences(first.UTC(), now, 10)
+	if len(got) != 5 {
+		t.Fatalf("Occurrences() = %v, want 5 Mondays", got)
+	}
+	// The last Sunday of March moves Kyiv from +02:00 to +03:00, the runs stay at 06:00 local time.
+	for i, at := range got {
+		if at.In(kyiv).Hour() != 6 || at.Weekday() != time.Monday {
+			t.Errorf("occurrence %d = %v, want Monday 06:00", i, at.In(kyiv))
+		}
+	}
+
+	latest := s.Occurrences(first, now, 2)
+	if len(latest) != 2 || !latest[1].Equal(got[4]) || !latest[0].Equal(got[3]) {
+		t.Errorf("limited Occurrences() = %v, want the last two of %v", latest, got)
+	}
+	if got := s.Occurrences(first, first.Add(-time.Minute), 10); len(got) != 0 {
+		t.Errorf("Occurrences() of an empty window = %v", got)
+	}
+}
+
+func TestRange(t *testing.T) {
+	s, err := Parse("0 6 * * MON", "Europe/Kyiv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kyiv := s.Location()
+	at := time.Date(2026, 4, 6, 6, 0, 0, 0, kyiv) // Monday
+	date := func(m time.Month, d, h int) time.Time { return time.Date(2026, m, d, h, 0, 0, 0, kyiv) }
+
+	tests := []struct {
+		name     string
+		unit     Unit
+		count    int
+		rolling  bool
+		from, to time.Time
+	}{
+		{"last week", Week, 1, false, date(time.March, 30, 0), date(time.April, 6, 0)},
+		{"last two weeks", Week, 2, false, date(time.March, 23, 0), date(time.April, 6, 0)},
+		{"yesterday", Day, 1, false, date(time.April, 5, 0), date(time.April, 6, 0)},
+		{"last hour", Hour, 1, false, date(time.April, 6, 5), date(time.April, 6, 6)},
+		{"last month", Month, 1, false, date(time.March, 1, 0), date(time.April, 1, 0)},
+		{"last 24 hours", Day, 1, true, date(time.April, 5, 6), date(time.April, 6, 6)},
+		{"last 7 days", Week, 1, true, date(time.March, 30, 6), date(time.April, 6, 6)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := s.Range(at, tt.unit, tt.count, tt.rolling)
+			if !from.Equal(tt.from) || !to.Equal(tt.to) {
+				t.Errorf("Range() = %v – %v, want %v – %v", from, to, tt.from, tt.to)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Package seal encrypts secrets that pass through shared storage, such as the passwords
+// of encrypted exports in the task queue, with a key only the service instances know.
+package seal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the size of the key in bytes, an AES-256 key.
+const KeySize = 32
+
+// Sealer seals secrets with AES-256-GCM.
+type Sealer struct {
+	aead cipher.AEAD
+}
+
+// New returns a sealer with the base64-encoded key.
+func New(key string) (*Sealer, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seal key: %w", err)
+	}
+	if len(raw) != KeySize {
+		return nil, fmt.Errorf("seal key must be %d bytes, got %d", KeySize, len(raw))
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Sealer{aead: aead}, nil
+}
+
+// Seal encrypts the secret and returns it base64-encoded, prefixed with its nonce.
+func (s *Sealer) Seal(secret string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(s.aead.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+// ErrInvalid is returned by Open when the sealed secret is damaged or was sealed with another key.
+var ErrInvalid = errors.New("sealed secret is invalid")
+
+// Open decrypts a secret returned by Seal.
+func (s *Sealer) Open(sealed string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < s.aead.NonceSize() {
+		return "", ErrInvalid
+	}
+	secret, err := s.aead.Open(nil, raw[:s.aead.NonceSize()], raw[s.aead.NonceSize():], nil)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	return string(secret), nil
+}
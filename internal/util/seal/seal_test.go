@@ -0,0 +1,35 @@
+package seal
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSealer(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", KeySize)))
+	s, err := New(key)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	sealed, err := s.Seal("s3cret")
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if strings.Contains(sealed, "s3cret") {
+		t.Errorf("sealed secret %q contains the secret", sealed)
+	}
+	if got, err := s.Open(sealed); err != nil || got != "s3cret" {
+		t.Errorf("Open() = %q, %v", got, err)
+	}
+
+	other, _ := New(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("o", KeySize))))
+	if _, err := other.Open(sealed); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Open() with another key error = %v, want ErrInvalid", err)
+	}
+	if _, err := New(base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Errorf("New() accepts a short key")
+	}
+}
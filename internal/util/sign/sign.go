@@ -0,0 +1,88 @@
+// Package sign signs what the service vouches for, such as the manifests and digests of exports,
+// with an Ed25519 key, optionally certified by an X.509 certificate.
+package sign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// Signer signs with an Ed25519 private key.
+type Signer struct {
+	key ed25519.PrivateKey
+	// identity is the PEM certificate of the key, or its PEM public key without a certificate.
+	identity []byte
+}
+
+// Load reads the PKCS #8 PEM private key at keyFile and, unless certFile is empty,
+// the PEM X.509 certificate of the key at certFile.
+func Load(keyFile, certFile string) (*Signer, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read signing key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", keyFile)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse signing key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key must be an Ed25519 key, got %T", parsed)
+	}
+	if certFile == "" {
+		return New(key, nil)
+	}
+
+	data, err = os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("read signing certificate: %w", err)
+	}
+	if block, _ = pem.Decode(data); block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("signing certificate %s is not a PEM certificate", certFile)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse signing certificate: %w", err)
+	}
+	return New(key, cert)
+}
+
+// New returns a signer with the key. cert, if not nil, must certify the public key of key.
+func New(key ed25519.PrivateKey, cert *x509.Certificate) (*Signer, error) {
+	public := key.Public().(ed25519.PublicKey)
+	if cert == nil {
+		der, err := x509.MarshalPKIXPublicKey(public)
+		if err != nil {
+			return nil, err
+		}
+		return &Signer{key: key, identity: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})}, nil
+	}
+	if certified, ok := cert.PublicKey.(ed25519.PublicKey); !ok || !certified.Equal(public) {
+		return nil, fmt.Errorf("signing certificate does not certify the signing key")
+	}
+	return &Signer{key: key, identity: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})}, nil
+}
+
+// Sign returns the Ed25519 signature of data.
+func (s *Signer) Sign(data []byte) []byte {
+	return ed25519.Sign(s.key, data)
+}
+
+// Verify reports whether sig is a signature of data made by the key of the signer.
+func (s *Signer) Verify(data, sig []byte) bool {
+	return ed25519.Verify(s.key.Public().(ed25519.PublicKey), data, sig)
+}
+
+// Identity returns the PEM certificate of the signing key, or its PEM public key if there is no
+// certificate, which lets anyone verify the signatures.
+func (s *Signer) Identity() []byte {
+	return bytes.Clone(s.identity)
+}
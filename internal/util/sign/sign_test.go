@@ -0,0 +1,76 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writePEM(t *testing.T, name, typ string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	public, key, _ := ed25519.GenerateKey(rand.Reader)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := writePEM(t, "key.pem", "PRIVATE KEY", der)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "media-exporter"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, public, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := writePEM(t, "cert.pem", "CERTIFICATE", certDER)
+
+	s, err := Load(keyFile, certFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	sig := s.Sign([]byte("manifest"))
+	if !s.Verify([]byte("manifest"), sig) || s.Verify([]byte("changed"), sig) {
+		t.Errorf("Verify() does not tell the signed data from changed data")
+	}
+	if !ed25519.Verify(public, []byte("manifest"), sig) {
+		t.Errorf("signature does not verify with the public key")
+	}
+	if !strings.HasPrefix(string(s.Identity()), "-----BEGIN CERTIFICATE-----") {
+		t.Errorf("Identity() = %q, want the certificate", s.Identity())
+	}
+
+	// Without a certificate the public key identifies the signer.
+	s, err = Load(keyFile, "")
+	if err != nil {
+		t.Fatalf("Load() without a certificate error = %v", err)
+	}
+	if !strings.HasPrefix(string(s.Identity()), "-----BEGIN PUBLIC KEY-----") {
+		t.Errorf("Identity() = %q, want the public key", s.Identity())
+	}
+
+	// A certificate of another key is refused.
+	_, other, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ = x509.MarshalPKCS8PrivateKey(other)
+	if _, err := Load(writePEM(t, "other.pem", "PRIVATE KEY", der), certFile); err == nil {
+		t.Errorf("Load() accepts a certificate of another key")
+	}
+}
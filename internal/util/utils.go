@@ -33,18 +33,9 @@ func ResizeImage(path string, width int) error {
 	return imaging.Save(resized, path)
 }
 
-func SavePDFToTemp(path string, pdfBytes []byte) error {
-	err := os.WriteFile(path, pdfBytes, 0644)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// build a new context.Background() with outgoing metadata created from headers map
-func ContextWithHeaders(headers map[string]string) context.Context {
-	ctx := context.Background()
+// ContextWithHeaders derives a context from ctx with outgoing metadata created from headers map.
+// Deadlines and cancellation of ctx are kept.
+func ContextWithHeaders(ctx context.Context, headers map[string]string) context.Context {
 	if len(headers) == 0 {
 		return ctx
 	}
@@ -0,0 +1,54 @@
+package video
+
+import (
+	"context"
+	"time"
+)
+
+// Format is the container of an encoded video.
+type Format string
+
+const (
+	MP4  Format = "mp4"
+	WebM Format = "webm"
+)
+
+// MimeType returns the MIME type of files in the format.
+func (f Format) MimeType() string {
+	switch f {
+	case WebM:
+		return "video/webm"
+	default:
+		return "video/mp4"
+	}
+}
+
+// Frame is a single image of a time-lapse video.
+type Frame struct {
+	Path string
+	// Time is when the image was captured, shown in the timestamp overlay.
+	// Frames with a zero Time get no overlay.
+	Time time.Time
+}
+
+// Options configure the encoding of a time-lapse video.
+type Options struct {
+	Format Format
+	// FrameDuration is how long every frame is shown.
+	FrameDuration time.Duration
+	// Width and Height of the video. Frames are scaled to fit and padded, keeping their aspect ratio.
+	Width  int
+	Height int
+	// Timestamps burns the capture time of every frame into its bottom left corner.
+	Timestamps bool
+	// FontFile is the font of the timestamp overlay, the default font of ffmpeg when empty.
+	FontFile string
+}
+
+// Encoder encodes images into a video file.
+type Encoder interface {
+	// Encode writes the frames, in the given order, to a video at outPath.
+	// onFrame, if not nil, is called as frames are encoded.
+	// Encoding stops with ctx.Err() once ctx is done.
+	Encode(ctx context.Context, frames []Frame, outPath string, opts Options, onFrame func(done, total int)) error
+}
@@ -0,0 +1,200 @@
+package video
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultFFmpegPath = "/usr/bin/ffmpeg"
+
+	timestampLayout = "2006-01-02 15:04:05 MST"
+	// stderrLimit is how much of the ffmpeg diagnostics is kept for the error of a failed run.
+	stderrLimit = 4 * 1024
+)
+
+// FFmpeg encodes videos by running the ffmpeg binary. Frames are fed through the concat demuxer,
+// so every frame keeps its own duration regardless of the size of its image.
+type FFmpeg struct {
+	path    string
+	tempDir string
+}
+
+// NewFFmpeg returns an encoder running the ffmpeg binary at path.
+// Its working files are created in tempDir.
+func NewFFmpeg(path, tempDir string) *FFmpeg {
+	if path == "" {
+		path = DefaultFFmpegPath
+	}
+	return &FFmpeg{path: path, tempDir: tempDir}
+}
+
+func (e *FFmpeg) Encode(ctx context.Context, frames []Frame, outPath string, opts Options, onFrame func(done, total int)) error {
+	if len(frames) == 0 {
+		return fmt.Errorf("no frames to encode")
+	}
+	if opts.FrameDuration <= 0 {
+		return fmt.Errorf("invalid frame duration: %s", opts.FrameDuration)
+	}
+
+	workDir, err := os.MkdirTemp(e.tempDir, "timelapse-")
+	if err != nil {
+		return fmt.Errorf("create work dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(workDir) }()
+
+	listPath := filepath.Join(workDir, "frames.txt")
+	if err := os.WriteFile(listPath, []byte(concatList(frames, opts.FrameDuration)), 0o600); err != nil {
+		return fmt.Errorf("write frame list: %w", err)
+	}
+	filterPath := filepath.Join(workDir, "filter.txt")
+	if err := os.WriteFile(filterPath, []byte(filterGraph(frames, opts)), 0o600); err != nil {
+		return fmt.Errorf("write filter graph: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, e.path, ffmpegArgs(listPath, filterPath, outPath, opts.Format)...)
+	stderr := &tailBuffer{limit: stderrLimit}
+	cmd.Stderr = stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("ffmpeg stdout: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start ffmpeg: %w", err)
+	}
+	readProgress(stdout, len(frames), onFrame)
+
+	if err := cmd.Wait(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// ffmpegArgs returns the command line encoding the frames of the concat list with the filter graph.
+func ffmpegArgs(listPath, filterPath, outPath string, format Format) []string {
+	args := []string{
+		"-hide_banner", "-nostdin", "-nostats", "-y",
+		"-loglevel", "error",
+		"-progress", "pipe:1",
+		"-f", "concat", "-safe", "0", "-i", listPath,
+		"-filter_script:v", filterPath,
+		// One output frame per screenshot instead of duplicating them to a constant rate.
+		"-fps_mode", "vfr",
+		"-an",
+	}
+
+	switch format {
+	case WebM:
+		args = append(args, "-c:v", "libvpx-vp9", "-b:v", "0", "-crf", "33", "-row-mt", "1", "-f", "webm")
+	default:
+		args = append(args, "-c:v", "libx264", "-preset", "veryfast", "-crf", "23", "-movflags", "+faststart", "-f", "mp4")
+	}
+
+	return append(args, outPath)
+}
+
+// concatList describes the frames for the concat demuxer. The last frame is listed twice,
+// otherwise the demuxer ignores its duration.
+func concatList(frames []Frame, frameDuration time.Duration) string {
+	var b strings.Builder
+	b.WriteString("ffconcat version 1.0\n")
+	duration := strconv.FormatFloat(frameDuration.Seconds(), 'f', 3, 64)
+	for _, f := range frames {
+		fmt.Fprintf(&b, "file %s\nduration %s\n", quoteConcatPath(f.Path), duration)
+	}
+	fmt.Fprintf(&b, "file %s\n", quoteConcatPath(frames[len(frames)-1].Path))
+	return b.String()
+}
+
+// filterGraph scales every frame into the video size and, if requested, draws its capture time
+// while it is shown.
+func filterGraph(frames []Frame, opts Options) string {
+	filters := []string{
+		fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease", opts.Width, opts.Height),
+		fmt.Sprintf("pad=%d:%d:(ow-iw)/2:(oh-ih)/2:color=black", opts.Width, opts.Height),
+		"setsar=1",
+		"format=yuv420p",
+	}
+
+	if opts.Timestamps {
+		step := opts.FrameDuration.Seconds()
+		for i, f := range frames {
+			if f.Time.IsZero() {
+				continue
+			}
+			drawtext := []string{
+				"expansion=none",
+				"text=" + escapeFilterValue(f.Time.UTC().Format(timestampLayout)),
+				"x=16", "y=h-th-16",
+				"fontsize=28", "fontcolor=white",
+				"box=1", "boxcolor=black@0.6", "boxborderw=8",
+				fmt.Sprintf("enable='gte(t,%.3f)*lt(t,%.3f)'", float64(i)*step, float64(i+1)*step),
+			}
+			if opts.FontFile != "" {
+				drawtext = append(drawtext, "fontfile="+escapeFilterValue(opts.FontFile))
+			}
+			filters = append(filters, "drawtext="+strings.Join(drawtext, ":"))
+		}
+	}
+
+	return strings.Join(filters, ",\n")
+}
+
+// escapeFilterValue escapes a filter option value for both the option and the filter graph level.
+func escapeFilterValue(s string) string {
+	option := strings.NewReplacer(`\`, `\\`, `:`, `\:`, `'`, `\'`).Replace(s)
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, `[`, `\[`, `]`, `\]`, `,`, `\,`, `;`, `\;`).Replace(option)
+}
+
+// quoteConcatPath quotes a path for a concat list.
+func quoteConcatPath(path string) string {
+	return "'" + strings.ReplaceAll(path, "'", `'\''`) + "'"
+}
+
+// readProgress reports the frame counter of ffmpeg's -progress output until it is closed.
+func readProgress(r io.Reader, total int, onFrame func(done, total int)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "frame=")
+		if !ok || onFrame == nil {
+			continue
+		}
+		done, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		onFrame(min(done, total), total)
+	}
+	_, _ = io.Copy(io.Discard, r)
+}
+
+// tailBuffer keeps the last limit bytes written to it.
+type tailBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf.Write(p)
+	if over := t.buf.Len() - t.limit; over > 0 {
+		t.buf.Next(over)
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	return t.buf.String()
+}
@@ -0,0 +1,100 @@
+package video
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConcatList(t *testing.T) {
+	frames := []Frame{{Path: "/tmp/a.png"}, {Path: "/tmp/agent's.png"}}
+
+	got := concatList(frames, 1500*time.Millisecond)
+	want := "ffconcat version 1.0\n" +
+		"file '/tmp/a.png'\nduration 1.500\n" +
+		"file '/tmp/agent'\\''s.png'\nduration 1.500\n" +
+		"file '/tmp/agent'\\''s.png'\n"
+	if got != want {
+		t.Errorf("concatList() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFilterGraph(t *testing.T) {
+	start := time.Date(2025, 3, 1, 9, 30, 15, 0, time.UTC)
+	frames := []Frame{
+		{Path: "/tmp/1.png", Time: start},
+		{Path: "/tmp/2.png"},
+		{Path: "/tmp/3.png", Time: start.Add(time.Minute)},
+	}
+	opts := Options{FrameDuration: 2 * time.Second, Width: 1280, Height: 720}
+
+	plain := filterGraph(frames, opts)
+	if !strings.HasPrefix(plain, "scale=1280:720:") || strings.Contains(plain, "drawtext") {
+		t.Errorf("unexpected filter graph without timestamps: %s", plain)
+	}
+
+	opts.Timestamps = true
+	graph := filterGraph(frames, opts)
+	if n := strings.Count(graph, "drawtext="); n != 2 {
+		t.Fatalf("filter graph has %d drawtext filters, want 2: %s", n, graph)
+	}
+	for _, want := range []string{
+		`text=2025-03-01 09\\:30\\:15 UTC`,
+		"enable='gte(t,0.000)*lt(t,2.000)'",
+		"enable='gte(t,4.000)*lt(t,6.000)'",
+	} {
+		if !strings.Contains(graph, want) {
+			t.Errorf("filter graph does not contain %q: %s", want, graph)
+		}
+	}
+}
+
+func TestEscapeFilterValue(t *testing.T) {
+	tests := map[string]string{
+		"plain":     "plain",
+		"10:20":     `10\\:20`,
+		"a,b;c[d]":  `a\,b\;c\[d\]`,
+		`it's`:      `it\\\'s`,
+		`C:\fonts`:  `C\\:\\\\fonts`,
+		"two words": "two words",
+	}
+	for in, want := range tests {
+		if got := escapeFilterValue(in); got != want {
+			t.Errorf("escapeFilterValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFFmpegArgs(t *testing.T) {
+	mp4 := ffmpegArgs("/w/frames.txt", "/w/filter.txt", "/out/v.mp4", MP4)
+	if mp4[len(mp4)-1] != "/out/v.mp4" {
+		t.Errorf("output path must be the last argument: %v", mp4)
+	}
+	for _, want := range []string{"libx264", "+faststart", "/w/frames.txt", "/w/filter.txt"} {
+		if !slices.Contains(mp4, want) {
+			t.Errorf("mp4 args miss %q: %v", want, mp4)
+		}
+	}
+
+	webm := ffmpegArgs("/w/frames.txt", "/w/filter.txt", "/out/v.webm", WebM)
+	if !slices.Contains(webm, "libvpx-vp9") || slices.Contains(webm, "libx264") {
+		t.Errorf("webm args use the wrong codec: %v", webm)
+	}
+}
+
+func TestReadProgress(t *testing.T) {
+	out := "frame=1\nfps=0.0\nprogress=continue\nframe=3\nprogress=continue\nframe=5\nprogress=end\n"
+
+	var got []int
+	readProgress(strings.NewReader(out), 4, func(done, total int) {
+		if total != 4 {
+			t.Errorf("total = %d, want 4", total)
+		}
+		got = append(got, done)
+	})
+
+	if want := []int{1, 3, 4}; !slices.Equal(got, want) {
+		t.Errorf("progress = %v, want %v", got, want)
+	}
+}